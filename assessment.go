@@ -0,0 +1,98 @@
+package biolog
+
+import (
+	"sort"
+)
+
+// Pogosti sistemi ocenjevanja ogrozenosti
+const (
+	// SchemeIUCN je Rdeci seznam IUCN
+	SchemeIUCN = "IUCN"
+	// SchemeSloveniaRedList je Rdeci seznam ogrozenih vrst Slovenije
+	SchemeSloveniaRedList = "SI-RL"
+)
+
+// RegionGlobal je obmocje ocene, ki velja za celoten svet
+const RegionGlobal = "global"
+
+// Assessment (ocena ogrozenosti)
+//
+// Status ogrozenosti vrste po dolocenem sistemu (npr. IUCN) za doloceno obmocje in leto. Vrsta ima
+// lahko vec ocen, trenutna je najnovejsa ocena za vsak sistem in obmocje (glej CurrentAssessments)
+//
+// swagger:model assessment
+type Assessment struct {
+	// Identifikator ocene
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Ocenjena vrsta (GBIF kljuc)
+	//
+	// required: true
+	// example: 5231190
+	Species *int `json:"species"`
+
+	// Sistem ocenjevanja
+	//
+	// required: true
+	// max length: 32
+	// example: SI-RL
+	Scheme *string `json:"scheme"`
+
+	// Obmocje, za katerega ocena velja (global ali koda drzave)
+	//
+	// required: true
+	// max length: 32
+	// example: SI
+	Region *string `json:"region"`
+
+	// Status ogrozenosti
+	//
+	// required: true
+	// min: 1
+	// max: 10
+	// example: 4
+	ConservationStatus *int `db:"conservation_status" json:"conservationStatus"`
+
+	// Leto ocene
+	//
+	// required: true
+	// example: 2002
+	Year *int `json:"year"`
+
+	// Vir ocene (publikacija ali URL)
+	//
+	// max length: 255
+	// example: Uradni list RS, st. 82/2002
+	Source *string `json:"source"`
+}
+
+// CurrentAssessments vrne najnovejso oceno za vsak sistem in obmocje, urejeno po sistemu in obmocju.
+// Pri enakem letu velja kasneje vnesena ocena
+func CurrentAssessments(history []Assessment) []Assessment {
+	latest := make(map[[2]string]Assessment)
+	for _, a := range history {
+		if a.Scheme == nil || a.Region == nil || a.Year == nil {
+			continue
+		}
+		key := [2]string{*a.Scheme, *a.Region}
+		current, ok := latest[key]
+		if !ok || *a.Year > *current.Year || (*a.Year == *current.Year && *a.ID > *current.ID) {
+			latest[key] = a
+		}
+	}
+
+	current := make([]Assessment, 0, len(latest))
+	for _, a := range latest {
+		current = append(current, a)
+	}
+	sort.Slice(current, func(i, j int) bool {
+		if *current[i].Scheme != *current[j].Scheme {
+			return *current[i].Scheme < *current[j].Scheme
+		}
+		return *current[i].Region < *current[j].Region
+	})
+	return current
+}
@@ -0,0 +1,29 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// assessment vrne oceno ogrozenosti z dolocenim sistemom, obmocjem, statusom in letom
+func assessment(id int, scheme, region string, status, year int) biolog.Assessment {
+	return biolog.Assessment{ID: &id, Scheme: &scheme, Region: &region, ConservationStatus: &status, Year: &year}
+}
+
+// TestCurrentAssessments preveri izbiro najnovejse ocene za vsak sistem in obmocje
+func TestCurrentAssessments(t *testing.T) {
+	history := []biolog.Assessment{
+		assessment(1, biolog.SchemeSloveniaRedList, "SI", 5, 1992),
+		assessment(2, biolog.SchemeIUCN, biolog.RegionGlobal, 8, 2016),
+		assessment(3, biolog.SchemeSloveniaRedList, "SI", 4, 2002),
+		assessment(4, biolog.SchemeIUCN, "EU", 6, 2015),
+	}
+
+	current := biolog.CurrentAssessments(history)
+	if assert.Len(t, current, 3) {
+		assert.Equal(t, []int{4, 2, 3}, []int{*current[0].ID, *current[1].ID, *current[2].ID})
+	}
+	assert.Len(t, biolog.CurrentAssessments(nil), 0)
+}
@@ -0,0 +1,200 @@
+package biolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// AuditService nudi interface za branje revizijske sledi. Zapise dodajajo ostali servici
+// v isti transakciji kot spremembo, zato jih ni mogoce dodajati ali spreminjati preko tega servica
+type AuditService interface {
+	AuditEntries(f AuditFilter, p Page) ([]AuditEntry, error)
+}
+
+// Actor doloca, kdo izvaja spremembo in v okviru katere zahteve (za revizijsko sled)
+type Actor struct {
+	// Uporabnik, ki izvaja spremembo
+	User int
+	// ID HTTP zahteve (prazen, ce sprememba ne izvira iz zahteve)
+	RequestID string
+}
+
+// Dejanja v revizijski sledi
+const (
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditMerge   = "merge"
+	AuditRevert  = "revert"
+	AuditErase   = "erase"
+)
+
+// Vrste zapisov v revizijski sledi
+const (
+	AuditSpecies     = "species"
+	AuditObservation = "observation"
+	AuditUser        = "user"
+)
+
+// ValidAuditEntity pove ali je vrsta zapisa ena izmed tistih, ki se belezijo v revizijski sledi
+func ValidAuditEntity(entityType string) bool {
+	switch entityType {
+	case AuditSpecies, AuditObservation, AuditUser:
+		return true
+	}
+	return false
+}
+
+// AuditEntry (zapis v revizijski sledi)
+//
+// Ena sprememba zapisa: kdo jo je naredil, kaj je spremenil in v okviru katere zahteve.
+// Zapisi se le dodajajo, nikoli spreminjajo ali brisejo
+//
+// swagger:model auditEntry
+type AuditEntry struct {
+	// Identifikator zapisa
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Uporabnik, ki je naredil spremembo
+	// example: 10000000
+	Actor *int `json:"actor"`
+
+	// Dejanje (update, delete, restore, merge ali revert)
+	//
+	// required: true
+	// example: update
+	Action *string `json:"action"`
+
+	// Vrsta spremenjenega zapisa (species, observation ali user)
+	//
+	// required: true
+	// example: observation
+	EntityType *string `db:"entity_type" json:"entityType"`
+
+	// Identifikator spremenjenega zapisa
+	//
+	// required: true
+	// example: 1
+	EntityID *int `db:"entity_id" json:"entityID"`
+
+	// Spremenjena polja z vrednostmi pred in po spremembi, npr. {"quantity": {"before": 6, "after": 8}}
+	Changes json.RawMessage `json:"changes"`
+
+	// ID HTTP zahteve, v kateri je prislo do spremembe
+	// example: biolog/Ab3dE9xQ-000042
+	RequestID *string `db:"request_id" json:"requestID"`
+
+	// Cas spremembe
+	//
+	// required: true
+	// swagger:strfmt date-time
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+}
+
+// AuditFilter doloca pogoje pri branju revizijske sledi, polja z nil vrednostjo se ne upostevajo
+type AuditFilter struct {
+	// Vrsta zapisa (species, observation ali user)
+	EntityType *string
+	// Identifikator zapisa
+	EntityID *int
+	// Uporabnik, ki je naredil spremembo
+	Actor *int
+}
+
+// AuditMasked je vrednost, s katero se v revizijski sledi zakrije osebni podatek
+const AuditMasked = "***"
+
+// auditMaskedFields so polja z osebnimi podatki po vrstah zapisov. V revizijski sledi se zabelezi le, da se je
+// polje spremenilo, vrednost pa se zakrije
+var auditMaskedFields = map[string][]string{
+	AuditUser: {"email", "givenName", "familyName", "externalID", "picture"},
+}
+
+// AuditMaskedFields vrne polja z osebnimi podatki zapisov vrste entityType
+func AuditMaskedFields(entityType string) []string {
+	return auditMaskedFields[entityType]
+}
+
+// auditChange je vrednost polja pred in po spremembi
+type auditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditChanges vrne razlike med zapisoma vrste entityType before in after kot JSON objekt
+// {"polje": {"before": ..., "after": ...}}. Polja se primerjajo po JSON imenih, nil pomeni, da zapis pred (ali po)
+// spremembi ni obstajal. Vrednosti polj z osebnimi podatki (npr. email uporabnika) so zakrite z AuditMasked
+func AuditChanges(entityType string, before, after interface{}) (json.RawMessage, error) {
+	b, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(b)+len(a))
+	for name := range b {
+		names = append(names, name)
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	masked := make(map[string]bool)
+	for _, name := range auditMaskedFields[entityType] {
+		masked[name] = true
+	}
+	changes := make(map[string]auditChange)
+	for _, name := range names {
+		if bytes.Equal(b[name], a[name]) {
+			continue
+		}
+		if masked[name] {
+			changes[name] = auditChange{Before: maskValue(b[name]), After: maskValue(a[name])}
+		} else {
+			changes[name] = auditChange{Before: nullIfMissing(b[name]), After: nullIfMissing(a[name])}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+// maskValue zakrije vrednost polja z osebnim podatkom, manjkajoca ali prazna vrednost ostane null
+func maskValue(v json.RawMessage) json.RawMessage {
+	if v == nil || bytes.Equal(v, []byte("null")) {
+		return json.RawMessage("null")
+	}
+	masked, _ := json.Marshal(AuditMasked)
+	return masked
+}
+
+// auditFields vrne JSON vrednosti polj zapisa, nil zapis nima polj
+func auditFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return fields, json.Unmarshal(raw, &fields)
+}
+
+// nullIfMissing vrne JSON null za polje, ki ga v zapisu ni
+func nullIfMissing(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
@@ -0,0 +1,51 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditChanges preveri razlike med zapisoma pred in po spremembi
+func TestAuditChanges(t *testing.T) {
+	id, before, after, loc := 1, 3, 8, "46.33061, 15.48705"
+	old := &biolog.Observation{ID: &id, Quantity: &before, SightingLocation: &loc}
+	updated := &biolog.Observation{ID: &id, Quantity: &after, SightingLocation: &loc}
+
+	changes, err := biolog.AuditChanges(biolog.AuditObservation, old, updated)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"quantity": {"before": 3, "after": 8}}`, string(changes))
+	}
+
+	// Nov zapis ima vsa polja le po spremembi, tudi ce je kazalec nil
+	var missing *biolog.Observation
+	changes, err = biolog.AuditChanges(biolog.AuditObservation, missing, &biolog.Observation{ID: &id})
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(changes), `"id":{"before":null,"after":1}`)
+	}
+
+	changes, err = biolog.AuditChanges(biolog.AuditObservation, old, old)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{}`, string(changes))
+	}
+}
+
+// TestAuditChangesMasked preveri, da so osebni podatki uporabnika v revizijski sledi zakriti
+func TestAuditChangesMasked(t *testing.T) {
+	id, name, email, newEmail, given := 10000000, "Janez", "janez@fakemail.com", "novak@fakemail.com", "Janez"
+	old := &biolog.User{ID: &id, DisplayName: &name, Email: &email, GivenName: &given}
+	updated := &biolog.User{ID: &id, DisplayName: &name, Email: &newEmail}
+
+	changes, err := biolog.AuditChanges(biolog.AuditUser, old, updated)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"email": {"before": "***", "after": "***"}, "givenName": {"before": "***", "after": null}}`,
+			string(changes))
+	}
+
+	changes, err = biolog.AuditChanges(biolog.AuditUser, nil, old)
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(changes), email)
+		assert.Contains(t, string(changes), `"displayName":{"before":null,"after":"Janez"}`)
+	}
+}
@@ -12,9 +12,20 @@ type UserService interface {
 	Users() ([]User, error)
 	UserByEmail(email string) (*User, error)
 	CreateUser(u User) (*User, error)
-	DeleteUser(id int) (int64, error)
-	UpdateUser(id int, u User) error
+	DeleteUser(id int, by Actor) (int64, error)
+	RestoreUser(id int, by Actor) error
+	PurgeUsers(before time.Time) (int64, error)
+	EraseUser(id int, keepObservations bool, by Actor) error
+	FollowUser(followerID, userID int) error
+	UnfollowUser(followerID, userID int) error
+	Following(userID int) ([]User, error)
+	UpdateUser(id int, u User, by Actor) error
 	UserByExtID(id string) (*User, error)
+	PersonalData(id int) (*PersonalData, error)
+	CreateExport(e Export) error
+	Export(id string) (*Export, error)
+	UpdateExport(e Export) error
+	DeleteExpiredExports(now time.Time) ([]Export, error)
 
 	AuthProvider(id int) (*AuthProvider, error)
 	AuthProviders() ([]AuthProvider, error)
@@ -85,6 +96,55 @@ type User struct {
 	// Podatki o zunanjem avtentikatorju uporabnika
 	// example: 1
 	ExternalAuthProvider *int `db:"external_auth_provider" json:"-"`
+
+	// Vloga uporabnika (user, moderator ali admin), preko API je ni mogoce spreminjati
+	// example: user
+	Role *string `json:"role"`
+
+	// Cas brisanja racuna (nil, ce racun ni izbrisan), izbrisan racun se po obdobju hrambe trajno odstrani
+	DeletedAt *time.Time `db:"deleted_at" json:"-"`
+
+	// Uporabnik, ki je racun izbrisal
+	DeletedBy *int `db:"deleted_by" json:"-"`
+
+	// Cas trajnega izbrisa racuna (nil, ce racun ni izbrisan). Osebni podatki izbrisanega racuna so odstranjeni,
+	// zapis ostane kot anonimen "izbrisan uporabnik", na katerega se sklicujejo ohranjena opazanja
+	ErasedAt *time.Time `db:"erased_at" json:"erasedAt,omitempty"`
+}
+
+// DeletedUserName je prikazno ime trajno izbrisanega (anonimiziranega) uporabnika
+const DeletedUserName = "Izbrisan uporabnik"
+
+// DeletedAccountError je sporocilo napake UserByEmail za izbrisan racun, ki ga je do trajne odstranitve se mogoce
+// obnoviti. Prijava v tak racun se zavrne (nov racun z istim emailom ni mogoc)
+const DeletedAccountError = "Uporabniski racun je izbrisan"
+
+// Vloge uporabnikov
+const (
+	// RoleUser je navaden uporabnik
+	RoleUser = "user"
+	// RoleModerator lahko ureja in brise vsebino drugih uporabnikov
+	RoleModerator = "moderator"
+	// RoleAdmin ima vse pravice moderatorja
+	RoleAdmin = "admin"
+)
+
+// IsModerator pove ali ima uporabnik pravice moderatorja (moderator ali admin)
+func (u User) IsModerator() bool {
+	return u.Role != nil && (*u.Role == RoleModerator || *u.Role == RoleAdmin)
+}
+
+// IsAdmin pove ali je uporabnik administrator
+func (u User) IsAdmin() bool {
+	return u.Role != nil && *u.Role == RoleAdmin
+}
+
+// Page doloca stran rezultatov pri ostranjevanju (pagination)
+type Page struct {
+	// Najvecje stevilo vrnjenih zapisov
+	Limit int
+	// Stevilo preskocenih zapisov
+	Offset int
 }
 
 // AuthProvider (zunanji avtentikator)
@@ -112,19 +172,90 @@ type SpeciesService interface {
 	Species(id int) (*Species, error)
 	AllSpecies() ([]Species, error)
 	CreateSpecies(sp *Species) (*Species, error)
-	UpdateSpecies(gbifKey int, sp Species) error
-	DeleteSpecies(gbifKey int) error
+	UpdateSpecies(gbifKey int, sp Species, by Actor) error
+	DeleteSpecies(gbifKey int, by Actor) error
+	MergeSpecies(fromKey, intoKey int, by Actor) (*TaxonMerge, error)
 
 	Observation(id int) (*Observation, error)
-	Observations() ([]Observation, error)
+	Observations(f ObservationFilter) ([]Observation, error)
+	ObservationStats(groupBy string, f ObservationFilter) ([]ObservationStat, error)
+	Distribution(cell string, f ObservationFilter) ([]DistributionCell, error)
+	ObservationTile(z, x, y int, f ObservationFilter) ([]byte, error)
 	CreateObservation(o *Observation) (*Observation, error)
-	DeleteObservation(id int) error
-	UpdateObservation(id int, ob Observation) error
+	DeleteObservation(id int, by Actor) error
+	DeletedObservation(id int) (*Observation, error)
+	RestoreObservation(id int, by Actor) error
+	PurgeObservations(before time.Time) (int64, error)
+	UpdateObservation(id int, ob Observation, by Actor) error
+	ObservationRevisions(observationID int) ([]ObservationRevision, error)
+	ObservationRevision(observationID, revision int) (*ObservationRevision, error)
+	RevertObservation(id, revision int, by Actor) error
+
+	Identification(id int) (*Identification, error)
+	Identifications(observationID int) ([]Identification, error)
+	CreateIdentification(i *Identification) (*Identification, error)
+	WithdrawIdentification(id int) error
+
+	Comment(id int) (*Comment, error)
+	Comments(observationID int, p Page) ([]Comment, error)
+	CreateComment(c *Comment) (*Comment, error)
+	UpdateComment(id int, body string) error
+	DeleteComment(id int) error
+
+	Checklist(id int) (*Checklist, error)
+	Checklists(userID int, p Page) ([]Checklist, error)
+	CreateChecklist(c *Checklist) (*Checklist, error)
+	UpdateChecklist(id int, c Checklist) error
+	DeleteChecklist(id int, by Actor) error
+
+	Project(id int) (*Project, error)
+	Projects(p Page) ([]Project, error)
+	CreateProject(p *Project) (*Project, error)
+	UpdateProject(id int, p Project) error
+	DeleteProject(id int) error
+	ProjectMembers(projectID int) ([]ProjectMember, error)
+	SetProjectMember(m ProjectMember) error
+	RemoveProjectMember(projectID, userID int) error
+	ProjectLeaderboard(projectID int, obscure *SensitivityPolicy) ([]LeaderboardEntry, error)
+
+	WatchSpecies(userID, gbifKey int) error
+	UnwatchSpecies(userID, gbifKey int) error
+	WatchedSpecies(userID int) ([]Species, error)
+	Feed(userID int, before FeedCursor, limit int) ([]FeedItem, error)
+
+	Assessment(id int) (*Assessment, error)
+	Assessments(gbifKey int) ([]Assessment, error)
+	CreateAssessment(a *Assessment) (*Assessment, error)
+	UpdateAssessment(id int, a Assessment) error
+	DeleteAssessment(id int) error
 
 	ConservationStatus(id int) (*ConservationStatus, error)
 	ConservationStatuses() ([]ConservationStatus, error)
 }
 
+// ObservationFilter doloca pogoje za iskanje po opazanjih, polja z vrednostjo nil se ne upostevajo
+type ObservationFilter struct {
+	// Stopnja kakovosti opazanja (casual, needs_id ali research)
+	QualityGrade *string
+	// Opazena vrsta (GBIF kljuc)
+	Species *int
+	// Obmocje, v katerem lezi opazanje
+	Region *int
+	// Projekt, katerega pravilom ustreza opazanje
+	Project *int
+	// Ce ni nil, se pri karti razsirjenosti in ploscicah lokacije obcutljivih vrst posplosijo, meja projekta,
+	// obmocje in pravokotnik pa se preverijo na posplosenih lokacijah, da iskanje ne izda natancne lokacije
+	Obscure *SensitivityPolicy
+	// Pravokotnik [minLon, minLat, maxLon, maxLat], v katerem lezi opazanje
+	BBox []float64
+	// Le opazanja z vecjim ID (za nadaljevanje toka opazanj)
+	AfterID *int
+	// Le opazanja, shranjena ob tem casu ali kasneje
+	CreatedSince *time.Time
+	// Ce je vecji od 0, se vrne najvec Limit opazanj z najmanjsim ID, urejenih narascajoce
+	Limit int
+}
+
 // ConservationStatus (seznam kratic ogrozenosti vrste)
 //
 //	Podatki so vnaprej doloceni in sicer 10 statusov
@@ -236,19 +367,26 @@ type Species struct {
 	// example: Passer domesticus
 	CanonicalName *string `db:"canonical_name" json:"canonicalName"`
 
-	// Stanje ogrozenosti vrste
+	// Stanje ogrozenosti vrste (globalno, ohranjeno zaradi zdruzljivosti, podrobnejse so ocene v Assessments)
 	//
 	// required: true
 	// min: 1
 	// max: 10
 	// example: 8
 	ConservationStatus *int `db:"conservation_status" json:"conservationStatus"`
+
+	// Sprejeta vrsta (GBIF kljuc), ce je ta vrsta sinonim. Zahteve za sinonim se preusmerijo na sprejeto vrsto
+	// example: 5231190
+	AcceptedKey *int `db:"accepted_key" json:"acceptedKey"`
+
+	// Trenutne ocene ogrozenosti po sistemih in obmocjih (najnovejsa za vsak sistem in obmocje)
+	Assessments []Assessment `db:"-" json:"assessments,omitempty"`
 }
 
 // Observation (zapis o opazeni vrsti)
 //
-// Predstavlja opazovalni list, na katerem je zapisana ena opazena vrsta,
-// kolicina osebkov ter cas in lokacija
+// Predstavlja zapis o eni opazeni vrsti, kolicini osebkov ter casu in lokaciji.
+// Vec opazanj z enega obiska terena zdruzuje popis (checklist)
 //
 // swagger:model observation
 type Observation struct {
@@ -294,4 +432,38 @@ type Observation struct {
 	// required: true
 	// example: 5231190
 	Species *int `json:"species"`
+
+	// Vrsta, o kateri se je strinjala skupnost (izracuna se iz identifikacij)
+	// example: 5231190
+	CommunityTaxon *int `db:"community_taxon" json:"communityTaxon"`
+
+	// Stopnja kakovosti opazanja (casual, needs_id ali research), izracuna se iz identifikacij
+	// example: research
+	QualityGrade *string `db:"quality_grade" json:"qualityGrade"`
+
+	// Popis, na katerem je opazanje (nil pri samostojnem opazanju)
+	// example: 1
+	Checklist *int `json:"checklist"`
+
+	// Pove, da je lokacija posplosena, ker gre za obcutljivo vrsto (natancna je vidna le avtorju in moderatorjem)
+	// example: false
+	Obscured *bool `db:"-" json:"obscured,omitempty"`
+
+	// Cas, ko je bilo opazanje shranjeno (doloci ga streznik)
+	//
+	// swagger:strfmt date-time
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+
+	// Cas brisanja opazanja (nil, ce opazanje ni izbrisano), izbrisano opazanje se po obdobju hrambe trajno odstrani
+	DeletedAt *time.Time `db:"deleted_at" json:"-"`
+
+	// Uporabnik, ki je opazanje izbrisal
+	DeletedBy *int `db:"deleted_by" json:"-"`
+}
+
+// IsPublic pove ali je opazanje javno: oznaceno je kot javno (PublicVisibility) in njegov avtor owner dovoli
+// javen dostop do svojih opazanj (PublicObservations). Enako pravilo uporablja publicObservation v paketu postgres
+func (o Observation) IsPublic(owner *User) bool {
+	return o.PublicVisibility != nil && *o.PublicVisibility &&
+		owner != nil && owner.PublicObservations != nil && *owner.PublicObservations
 }
@@ -0,0 +1,22 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationIsPublic preveri pravilo javnosti opazanja, ki ga uporabljata obe shrambi
+func TestObservationIsPublic(t *testing.T) {
+	yes, no := true, false
+	public := biolog.Observation{PublicVisibility: &yes}
+
+	assert.True(t, public.IsPublic(&biolog.User{PublicObservations: &yes}))
+	// Avtor ne dovoli javnega dostopa do svojih opazanj
+	assert.False(t, public.IsPublic(&biolog.User{PublicObservations: &no}))
+	assert.False(t, public.IsPublic(&biolog.User{}))
+	assert.False(t, public.IsPublic(nil))
+	assert.False(t, biolog.Observation{PublicVisibility: &no}.IsPublic(&biolog.User{PublicObservations: &yes}))
+	assert.False(t, biolog.Observation{}.IsPublic(&biolog.User{PublicObservations: &yes}))
+}
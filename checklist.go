@@ -0,0 +1,72 @@
+package biolog
+
+import (
+	"time"
+)
+
+// Protokoli popisa (nacin, kako je bil seznam opazanj zbran)
+const (
+	// ProtocolIncidental je priloznostno opazanje med drugo dejavnostjo
+	ProtocolIncidental = "incidental"
+	// ProtocolStationary je popis na enem mestu
+	ProtocolStationary = "stationary"
+	// ProtocolTraveling je popis med premikanjem (npr. po transektu)
+	ProtocolTraveling = "traveling"
+)
+
+// ValidProtocol preveri ali je podan niz eden izmed protokolov popisa
+func ValidProtocol(protocol string) bool {
+	return protocol == ProtocolIncidental || protocol == ProtocolStationary || protocol == ProtocolTraveling
+}
+
+// Checklist (popis)
+//
+// Seznam opazanj z enega obiska terena. Vsako opazanje na popisu je zapis o eni vrsti,
+// cas in lokacija opazanja sta privzeto enaka zacetku in lokaciji popisa
+//
+// swagger:model checklist
+type Checklist struct {
+	// Identifikator popisa
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Uporabnik, ki je opravil popis
+	//
+	// required: true
+	// example: 10000000
+	User *int `db:"biolog_user" json:"user"`
+
+	// Zacetek popisa
+	//
+	// required: true
+	// swagger:strfmt date-time
+	// example: 2018-06-04T07:30:00+00:00
+	StartTime *time.Time `db:"start_time" json:"startTime"`
+
+	// Trajanje popisa v minutah (ni obvezno pri priloznostnem opazanju)
+	//
+	// min: 0
+	// example: 90
+	Duration *int `json:"duration"`
+
+	// Lokacija popisa, tocka v skladu z postGIS geography
+	//
+	// required: true
+	// example: POINT(14.5058 46.0569)
+	Location *string `json:"location"`
+
+	// Protokol popisa (incidental, stationary ali traveling)
+	//
+	// required: true
+	// example: stationary
+	Protocol *string `json:"protocol"`
+
+	// Pove ali je popis popoln (zapisane so vse prepoznane vrste, ne le izbrane)
+	// example: true
+	Complete *bool `json:"complete"`
+
+	// Opazanja na popisu
+	Observations []Observation `db:"-" json:"observations,omitempty"`
+}
@@ -19,19 +19,34 @@ package main
 
 import (
 	"context"
+	"flag"
+	stdhttp "net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/rubinda/biolog"
 	"github.com/rubinda/biolog/http"
+	"github.com/rubinda/biolog/memory"
+	"github.com/rubinda/biolog/metrics"
 	"github.com/rubinda/biolog/postgres"
+	"github.com/rubinda/biolog/tracing"
 	"github.com/spf13/viper"
 
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	// Izbira shrambe podatkov (postgres ali memory)
+	store := flag.String("store", "postgres", "data store to use: postgres or memory")
+	// Uvoz obmocij iz GeoJSON datoteke ob zagonu
+	importRegions := flag.String("import-regions", "", "GeoJSON FeatureCollection with regions to import on startup")
+	regionKind := flag.String("region-kind", "", "kind of the imported regions, e.g. obcina")
+	regionName := flag.String("region-name-property", "name", "feature property holding the region name")
+	regionCode := flag.String("region-code-property", "code", "feature property holding the region code")
+	flag.Parse()
+
 	// Force barve za logrus
 	log.SetFormatter(&log.TextFormatter{ForceColors: true})
 
@@ -44,19 +59,127 @@ func main() {
 		log.Panic("There was a problem with the config file: ", err)
 	}
 
-	// Inicializira povezavo na podatkovno bazo s pomocjo konfiguracijske datoteke
-	db, error := postgres.Open(viper.GetString("database.username"), viper.GetString("database.password"),
-		viper.GetString("database.dbname"), viper.GetString("database.host"), viper.GetString("database.sslmode"),
-		viper.GetInt("database.port"))
-	if error != nil {
-		log.Panic("Error while establishing database connection: ", error)
+	// Sledenje zahtev in poizvedb (OpenTelemetry)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    viper.GetString("tracing.exporter"),
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		ServiceName: viper.GetString("tracing.service-name"),
+		SampleRatio: viper.GetFloat64("tracing.sample-ratio"),
+	})
+	if err != nil {
+		log.Panic("Could not set up tracing: ", err)
+	}
+
+	// Ustvari service glede na izbrano shrambo podatkov
+	var us biolog.UserService
+	var ss biolog.SpeciesService
+	var rs biolog.RegionService
+	var as biolog.AuditService
+	var ws biolog.WebhookService
+	var obs biolog.ObservationStream
+	var rl biolog.RateLimiter
+	// Pravilo za obcutljive vrste, po katerem shramba preveri filtre narocnin na dogodke
+	sensitivity := http.SensitivityPolicy()
+	switch *store {
+	case "postgres":
+		// Inicializira povezavo na podatkovno bazo s pomocjo konfiguracijske datoteke
+		db, error := postgres.Open(viper.GetString("database.username"), viper.GetString("database.password"),
+			viper.GetString("database.dbname"), viper.GetString("database.host"), viper.GetString("database.sslmode"),
+			viper.GetInt("database.port"))
+		if error != nil {
+			log.Panic("Error while establishing database connection: ", error)
+		}
+		// Statistika bazena povezav za Prometheus
+		if err := metrics.RegisterDB(db.DB, viper.GetString("database.dbname")); err != nil {
+			log.Warn("Could not register database metrics: ", err)
+		}
+		us = &postgres.UserService{DB: db}
+		ss = &postgres.SpeciesService{DB: db, Sensitivity: &sensitivity}
+		rs = &postgres.RegionService{DB: db}
+		as = &postgres.AuditService{DB: db}
+		ws = &postgres.WebhookService{DB: db}
+		// Tok novih opazanj potrebuje lastno povezavo za LISTEN
+		obs, error = postgres.NewObservationStream(db, postgres.ConnString(viper.GetString("database.username"),
+			viper.GetString("database.password"), viper.GetString("database.dbname"), viper.GetString("database.host"),
+			viper.GetString("database.sslmode"), viper.GetInt("database.port")))
+		if error != nil {
+			log.Panic("Error while listening for new observations: ", error)
+		}
+		// Stevci v bazi veljajo za vse instance streznika, sicer ima vsaka instanca svoje
+		if viper.GetString("ratelimit.store") == "postgres" {
+			rl = &postgres.RateLimiter{DB: db}
+		} else {
+			rl = &memory.RateLimiter{Store: memory.NewStore()}
+		}
+	case "memory":
+		// Podatki se hranijo le v pomnilniku (za razvoj frontenda brez PostgreSQL)
+		st := memory.NewStore()
+		us = &memory.UserService{Store: st}
+		ss = &memory.SpeciesService{Store: st, Sensitivity: &sensitivity}
+		rs = &memory.RegionService{Store: st}
+		as = &memory.AuditService{Store: st}
+		ws = &memory.WebhookService{Store: st}
+		obs = &memory.ObservationStream{Store: st}
+		rl = &memory.RateLimiter{Store: st}
+		log.Warn("Using in-memory store, data will be lost on shutdown")
+	default:
+		log.Panic("Unknown store: ", *store)
+	}
+
+	if *importRegions != "" {
+		if *regionKind == "" {
+			log.Panic("Missing --region-kind for imported regions")
+		}
+		f, err := os.Open(*importRegions)
+		if err != nil {
+			log.Panic("Could not open regions file: ", err)
+		}
+		regions, err := biolog.RegionsFromGeoJSON(f, *regionKind, *regionName, *regionCode)
+		f.Close()
+		if err != nil {
+			log.Panic("Could not read regions: ", err)
+		}
+		n, err := rs.ImportRegions(regions)
+		if err != nil {
+			log.Panic("Could not import regions: ", err)
+		}
+		log.Infof("Imported %d regions of kind %s", n, *regionKind)
+	}
+
+	// Opravilo za trajno odstranjevanje izbrisanih zapisov po obdobju hrambe
+	if days := viper.GetInt("retention.deleted-days"); days > 0 {
+		interval := viper.GetDuration("retention.purge-interval")
+		if interval <= 0 {
+			interval = 24 * time.Hour
+		}
+		go purgeDeleted(us, ss, time.Duration(days)*24*time.Hour, interval)
+	}
+
+	// Dispecer, ki narocnikom posilja dogodke iz vrste webhookov
+	dispatcher := http.NewWebhookDispatcher(ws, ss)
+	if n := viper.GetInt("webhook.max-attempts"); n > 0 {
+		dispatcher.MaxAttempts = n
+	}
+	if backoff := viper.GetDuration("webhook.backoff"); backoff > 0 {
+		dispatcher.Backoff = backoff
 	}
+	interval := viper.GetDuration("webhook.poll-interval")
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	go dispatcher.Run(interval)
 
-	// Ustvari service in jim nastavi podatkovno povezavo
-	us := &postgres.UserService{DB: db}
-	ss := &postgres.SpeciesService{DB: db}
 	// Dodaj instance service na handlerja
-	h := http.NewRootHandler(us, ss)
+	h := http.NewRootHandler(us, ss, rs, as, ws, obs)
+	if h.TrustedProxies, err = http.ParseTrustedProxies(viper.GetStringSlice("server.trusted-proxies")); err != nil {
+		log.Panic("Invalid trusted proxies: ", err)
+	}
+
+	// Omejevanje stevila zahtev, polna vedra se sproti odstranjujejo
+	if viper.GetBool("ratelimit.enabled") {
+		h.RateLimits = http.NewRateLimits(rl)
+		go h.RateLimits.PruneIdle(10 * time.Minute)
+	}
 
 	// Zazene nov streznik in caka na signal interrupt
 	sAddr := ":" + viper.GetString("server.address")
@@ -64,6 +187,18 @@ func main() {
 	http.Start(s)
 	log.Info("Server is running @ localhost:" + viper.GetString("server.address"))
 
+	// Metrike za Prometheus so na locenem strezniku, ki ni dostopen skozi javni API
+	var ms *stdhttp.Server
+	if addr := viper.GetString("metrics.address"); addr != "" {
+		ms = metrics.NewServer(":" + addr)
+		go func() {
+			if err := ms.ListenAndServe(); err != stdhttp.ErrServerClosed {
+				log.Error("Metrics server stopped: ", err)
+			}
+		}()
+		log.Info("Metrics are available @ localhost:" + addr + "/metrics")
+	}
+
 	// Registrira poslusalca za signalom 'INTERRUPT' (Ctrl-C)
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
@@ -79,5 +214,33 @@ func main() {
 	} else {
 		log.Info("Server stopped")
 	}
+	if ms != nil {
+		ms.Shutdown(ctx)
+	}
+	// Izvozi se neposlane spane
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("Could not flush spans: ", err)
+	}
 
 }
+
+// purgeDeleted vsakih interval trajno odstrani opazanja in uporabnike, ki so bili izbrisani pred vec kot retention.
+// Najprej se odstranijo opazanja, da se nanje ne sklicujejo izbrisani uporabniki
+func purgeDeleted(us biolog.UserService, ss biolog.SpeciesService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		before := time.Now().Add(-retention)
+		if n, err := ss.PurgeObservations(before); err != nil {
+			log.Error("Could not purge deleted observations: ", err)
+		} else if n > 0 {
+			log.Infof("Purged %d deleted observations", n)
+		}
+		if n, err := us.PurgeUsers(before); err != nil {
+			log.Error("Could not purge deleted users: ", err)
+		} else if n > 0 {
+			log.Infof("Purged %d deleted users", n)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,52 @@
+package biolog
+
+import (
+	"time"
+)
+
+// Comment (komentar k opazanju)
+//
+// Uporabniki lahko pod opazanjem postavljajo vprasanja in razpravljajo o zapisu
+//
+// swagger:model comment
+type Comment struct {
+	// Identifikator komentarja
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Opazanje, pod katerim je komentar
+	//
+	// required: true
+	// example: 1
+	Observation *int `json:"observation"`
+
+	// Avtor komentarja
+	//
+	// required: true
+	// example: 10000001
+	User *int `db:"biolog_user" json:"user"`
+
+	// Besedilo komentarja
+	//
+	// required: true
+	// max length: 2000
+	// example: Je bila fotografija posneta pri krmilnici?
+	Body *string `json:"body"`
+
+	// Cas, ko je bil komentar napisan
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:07:37+00:00
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+
+	// Cas zadnjega urejanja komentarja
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:12:01+00:00
+	UpdatedAt *time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// MaxCommentLength je najvecja dovoljena dolzina besedila komentarja
+const MaxCommentLength = 2000
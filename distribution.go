@@ -0,0 +1,56 @@
+package biolog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Vrste mrez za prikaz razsirjenosti vrste
+const (
+	// CellGeohash5 so celice geohash dolzine 5 (priblizno 5 x 5 km)
+	CellGeohash5 = "geohash5"
+	// Cell10km so celice evropske referencne mreze 10 x 10 km (EEA, ETRS89-LAEA oz. EPSG:3035),
+	// kljuc celice je oblike 10kmE447N253
+	Cell10km = "10km"
+)
+
+// ValidDistributionCell preveri ali je podan niz ena izmed podprtih mrez
+func ValidDistributionCell(cell string) bool {
+	return cell == CellGeohash5 || cell == Cell10km
+}
+
+// DistributionCell (celica mreze razsirjenosti)
+//
+// Opazanja vrste, zdruzena v eno celico mreze
+//
+// swagger:model distributionCell
+type DistributionCell struct {
+	// Kljuc celice (geohash ali koda celice referencne mreze)
+	//
+	// required: true
+	// example: u2j6p
+	Cell string `json:"cell"`
+
+	// Geometrija celice kot GeoJSON poligon (WGS84)
+	//
+	// required: true
+	Geometry json.RawMessage `json:"geometry"`
+
+	// Stevilo opazanj v celici
+	//
+	// required: true
+	// example: 4
+	Observations int `json:"observations"`
+
+	// Cas prvega opazanja v celici
+	//
+	// swagger:strfmt date-time
+	// example: 2017-04-12T08:30:00+00:00
+	FirstSighting *time.Time `db:"first_sighting" json:"firstSighting"`
+
+	// Cas zadnjega opazanja v celici
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:07:37+00:00
+	LastSighting *time.Time `db:"last_sighting" json:"lastSighting"`
+}
@@ -0,0 +1,204 @@
+package biolog
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Stanja izvoza osebnih podatkov
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// PersonalData so vsi podatki, ki jih hranimo o uporabniku (za izvoz po GDPR)
+type PersonalData struct {
+	User            User
+	Observations    []Observation
+	Comments        []Comment
+	Identifications []Identification
+}
+
+// Export (izvoz osebnih podatkov)
+//
+// Asinhron izvoz vseh podatkov uporabnika v zip datoteko, ki se prenese preko kratkotrajne povezave.
+// Stanje izvoza hrani UserService, da je izvoz viden vsem instancam streznika
+//
+// swagger:model export
+type Export struct {
+	// Identifikator izvoza, je tudi del povezave za prenos
+	//
+	// required: true
+	// example: 3q2-7wEAAAAAAAAAAAAAAA
+	ID string `json:"id"`
+
+	// Uporabnik, cigar podatki se izvazajo
+	//
+	// required: true
+	// example: 10000000
+	User int `db:"biolog_user" json:"user"`
+
+	// Stanje izvoza (pending, ready ali failed)
+	//
+	// required: true
+	// example: pending
+	Status string `json:"status"`
+
+	// Povezava za prenos zip datoteke, veljavna do expiresAt
+	// example: /api/v1/exports/3q2-7wEAAAAAAAAAAAAAAA
+	DownloadURL string `db:"-" json:"downloadURL"`
+
+	// Cas, ko je bil izvoz zahtevan
+	//
+	// swagger:strfmt date-time
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+
+	// Cas, ko izvoz potece in se odstrani. Ob koncu (uspesnem ali neuspesnem) izvoza se podaljsa, da je
+	// povezava za prenos (ali napaka) na voljo se TTL
+	//
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `db:"expires_at" json:"expiresAt,omitempty"`
+
+	// Pot do pripravljene zip datoteke
+	Path string `json:"-"`
+}
+
+// exportReadme opisuje vsebino izvoza in podatke, ki v njem niso vkljuceni
+const exportReadme = `Izvoz osebnih podatkov aplikacije biolog
+
+profile.json          podatki profila
+observations.csv      opazanja (tudi zasebna), lokacija je razdeljena na geografsko dolzino in sirino
+observations.geojson  opazanja kot GeoJSON tocke
+comments.json         komentarji
+identifications.json  identifikacije
+
+Profilna slika ni vkljucena: hrani jo zunanji ponudnik prijave (npr. Google), v profile.json je le
+njen naslov (picture), preko katerega jo lahko prenesete ali izbrisete pri ponudniku.
+`
+
+// observationCSVHeader so stolpci opazanj v CSV izvozu
+var observationCSVHeader = []string{"id", "sightingTime", "longitude", "latitude", "quantity", "species",
+	"communityTaxon", "qualityGrade", "publicVisibility", "checklist"}
+
+// WriteZip zapise osebne podatke v zip: profil (profile.json), opazanja (observations.csv in
+// observations.geojson), komentarje (comments.json) in identifikacije (identifications.json). README.txt
+// opisuje vsebino in pove, da profilna slika (le njen naslov je v profilu) ni vkljucena
+func (d PersonalData) WriteZip(w io.Writer) error {
+	z := zip.NewWriter(w)
+	files := []struct {
+		Name  string
+		Write func(io.Writer) error
+	}{
+		{"README.txt", func(w io.Writer) error {
+			_, err := io.WriteString(w, exportReadme)
+			return err
+		}},
+		{"profile.json", jsonWriter(d.User)},
+		{"observations.csv", d.writeObservationsCSV},
+		{"observations.geojson", d.writeObservationsGeoJSON},
+		{"comments.json", jsonWriter(d.Comments)},
+		{"identifications.json", jsonWriter(d.Identifications)},
+	}
+	for _, f := range files {
+		fw, err := z.Create(f.Name)
+		if err != nil {
+			return err
+		}
+		if err := f.Write(fw); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// jsonWriter vrne funkcijo, ki v w zapise v zamaknjen JSON
+func jsonWriter(v interface{}) func(io.Writer) error {
+	return func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+// writeObservationsCSV zapise opazanja kot CSV, lokacija je razdeljena na geografsko dolzino in sirino
+func (d PersonalData) writeObservationsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(observationCSVHeader); err != nil {
+		return err
+	}
+	for _, o := range d.Observations {
+		lon, lat := "", ""
+		if o.SightingLocation != nil {
+			if x, y, ok := ParsePoint(*o.SightingLocation); ok {
+				lon, lat = strconv.FormatFloat(x, 'f', -1, 64), strconv.FormatFloat(y, 'f', -1, 64)
+			}
+		}
+		sightingTime := ""
+		if o.SightingTime != nil {
+			sightingTime = o.SightingTime.Format(time.RFC3339)
+		}
+		record := []string{csvInt(o.ID), sightingTime, lon, lat, csvInt(o.Quantity), csvInt(o.Species),
+			csvInt(o.CommunityTaxon), csvString(o.QualityGrade), csvBool(o.PublicVisibility), csvInt(o.Checklist)}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeObservationsGeoJSON zapise opazanja kot GeoJSON FeatureCollection tock, lastnosti so ostala polja opazanja
+func (d PersonalData) writeObservationsGeoJSON(w io.Writer) error {
+	type geometry struct {
+		Type        string     `json:"type"`
+		Coordinates [2]float64 `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string      `json:"type"`
+		Geometry   *geometry   `json:"geometry"`
+		Properties Observation `json:"properties"`
+	}
+	fc := struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{Type: "FeatureCollection", Features: []feature{}}
+
+	for _, o := range d.Observations {
+		f := feature{Type: "Feature", Properties: o}
+		if o.SightingLocation != nil {
+			if lon, lat, ok := ParsePoint(*o.SightingLocation); ok {
+				f.Geometry = &geometry{Type: "Point", Coordinates: [2]float64{lon, lat}}
+			}
+		}
+		f.Properties.SightingLocation = nil
+		fc.Features = append(fc.Features, f)
+	}
+	return jsonWriter(fc)(w)
+}
+
+// Pomozne funkcije za zapis vrednosti v CSV, nil je prazna celica
+func csvInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
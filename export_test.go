@@ -0,0 +1,91 @@
+package biolog_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// readZipFile vrne vsebino datoteke name iz zip arhiva
+func readZipFile(t *testing.T, z *zip.Reader, name string) []byte {
+	for _, f := range z.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			b, err := ioutil.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		}
+	}
+	t.Fatal("Datoteka ni v arhivu: ", name)
+	return nil
+}
+
+// TestPersonalDataWriteZip preveri vsebino zip izvoza: opis, profil, opazanja kot CSV in GeoJSON ter komentarje
+func TestPersonalDataWriteZip(t *testing.T) {
+	userID, dn := 10000000, "ms. River Tam"
+	id, quantity, species, public := 1, 3, 5231190, true
+	loc, grade := "POINT(15.48705 46.33061)", biolog.GradeNeedsID
+	commentID, text := 2, "Lep primerek"
+	d := biolog.PersonalData{
+		User: biolog.User{ID: &userID, DisplayName: &dn},
+		Observations: []biolog.Observation{{ID: &id, Quantity: &quantity, Species: &species, PublicVisibility: &public,
+			SightingLocation: &loc, QualityGrade: &grade, User: &userID}},
+		Comments: []biolog.Comment{{ID: &commentID, Observation: &id, User: &userID, Body: &text}},
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, d.WriteZip(&buf)) {
+		return
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var profile biolog.User
+	assert.NoError(t, json.Unmarshal(readZipFile(t, z, "profile.json"), &profile))
+	assert.Equal(t, dn, *profile.DisplayName)
+
+	records, err := csv.NewReader(bytes.NewReader(readZipFile(t, z, "observations.csv"))).ReadAll()
+	if assert.NoError(t, err) && assert.Len(t, records, 2) {
+		assert.Equal(t, []string{"id", "sightingTime", "longitude", "latitude", "quantity", "species",
+			"communityTaxon", "qualityGrade", "publicVisibility", "checklist"}, records[0])
+		assert.Equal(t, []string{"1", "", "15.48705", "46.33061", "3", "5231190", "", "needs_id", "true", ""}, records[1])
+	}
+
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates []float64
+			}
+			Properties map[string]interface{}
+		}
+	}
+	assert.NoError(t, json.Unmarshal(readZipFile(t, z, "observations.geojson"), &fc))
+	if assert.Equal(t, "FeatureCollection", fc.Type) && assert.Len(t, fc.Features, 1) {
+		assert.Equal(t, []float64{15.48705, 46.33061}, fc.Features[0].Geometry.Coordinates)
+		// Lokacija je le v geometriji
+		assert.Nil(t, fc.Features[0].Properties["sightingLocation"])
+	}
+
+	var comments []biolog.Comment
+	assert.NoError(t, json.Unmarshal(readZipFile(t, z, "comments.json"), &comments))
+	assert.Len(t, comments, 1)
+	assert.NotEmpty(t, readZipFile(t, z, "identifications.json"))
+	// Izvoz izrecno pove, da profilna slika ni vkljucena
+	assert.Contains(t, string(readZipFile(t, z, "README.txt")), "Profilna slika ni vkljucena")
+}
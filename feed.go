@@ -0,0 +1,115 @@
+package biolog
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vrste dogodkov v viru dejavnosti
+const (
+	FeedObservation    = "observation"
+	FeedIdentification = "identification"
+	FeedComment        = "comment"
+)
+
+// FeedItem (dogodek v viru dejavnosti)
+//
+// Novo javno opazanje, identifikacija ali komentar uporabnika, ki ga prijavljeni uporabnik spremlja,
+// oziroma opazanje ali identifikacija vrste, ki jo opazuje
+//
+// swagger:model feedItem
+type FeedItem struct {
+	// Vrsta dogodka (observation, identification ali comment)
+	//
+	// required: true
+	// example: observation
+	Type string `json:"type"`
+
+	// Cas dogodka
+	//
+	// required: true
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Uporabnik, ki je ustvaril dogodek
+	//
+	// required: true
+	// example: 10000000
+	User int `json:"user"`
+
+	// Opazanje, na katerega se nanasa dogodek
+	//
+	// required: true
+	Observation *Observation `json:"observation"`
+
+	// Identifikacija (le pri dogodkih vrste identification)
+	Identification *Identification `json:"identification,omitempty"`
+
+	// Komentar (le pri dogodkih vrste comment)
+	Comment *Comment `json:"comment,omitempty"`
+
+	// Polozaj dogodka v viru, uporabi se kot parameter cursor za naslednjo stran
+	//
+	// required: true
+	// example: 2020-05-01T10:00:00.123456Z,observation,123
+	Cursor string `json:"cursor"`
+}
+
+// FeedCursor je polozaj v viru dejavnosti. Dogodki so urejeni po casu, vrsti in ID zapisa (vse padajoce), zato
+// polozaj enolicno doloci naslednjo stran tudi, ko ima vec dogodkov enak cas
+type FeedCursor struct {
+	CreatedAt time.Time
+	// Vrsta dogodka, prazna vrsta pomeni vse dogodke pred CreatedAt
+	Type string
+	// ID opazanja, identifikacije ali komentarja
+	Item int
+}
+
+// Position vrne polozaj dogodka v viru
+func (i FeedItem) Position() FeedCursor {
+	c := FeedCursor{CreatedAt: i.CreatedAt, Type: i.Type}
+	switch {
+	case i.Type == FeedIdentification && i.Identification != nil:
+		c.Item = *i.Identification.ID
+	case i.Type == FeedComment && i.Comment != nil:
+		c.Item = *i.Comment.ID
+	case i.Observation != nil:
+		c.Item = *i.Observation.ID
+	}
+	return c
+}
+
+// Before pove, ali je polozaj c v viru pred polozajem o (dogodek na c je novejsi)
+func (c FeedCursor) Before(o FeedCursor) bool {
+	if !c.CreatedAt.Equal(o.CreatedAt) {
+		return c.CreatedAt.After(o.CreatedAt)
+	}
+	if c.Type != o.Type {
+		return c.Type > o.Type
+	}
+	return c.Item > o.Item
+}
+
+// String vrne polozaj v obliki cas (RFC 3339),vrsta,ID
+func (c FeedCursor) String() string {
+	return c.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + c.Type + "," + strconv.Itoa(c.Item)
+}
+
+// ParseFeedCursor prebere polozaj, zapisan s FeedCursor.String
+func ParseFeedCursor(s string) (FeedCursor, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return FeedCursor{}, errors.New("Neveljaven polozaj v viru dejavnosti")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return FeedCursor{}, errors.New("Neveljaven polozaj v viru dejavnosti")
+	}
+	item, err := strconv.Atoi(parts[2])
+	if err != nil || (parts[1] != FeedObservation && parts[1] != FeedIdentification && parts[1] != FeedComment) {
+		return FeedCursor{}, errors.New("Neveljaven polozaj v viru dejavnosti")
+	}
+	return FeedCursor{CreatedAt: createdAt, Type: parts[1], Item: item}, nil
+}
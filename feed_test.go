@@ -0,0 +1,37 @@
+package biolog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeedCursor preveri zapis in vrstni red polozajev v viru dejavnosti
+func TestFeedCursor(t *testing.T) {
+	created := time.Date(2020, 5, 1, 10, 0, 0, 123456000, time.UTC)
+	id := 42
+	item := biolog.FeedItem{Type: biolog.FeedIdentification, CreatedAt: created,
+		Observation: &biolog.Observation{ID: &id}, Identification: &biolog.Identification{ID: &id}}
+	c := item.Position()
+	assert.Equal(t, "2020-05-01T10:00:00.123456Z,identification,42", c.String())
+	parsed, err := biolog.ParseFeedCursor(c.String())
+	if assert.NoError(t, err) {
+		assert.Equal(t, c, parsed)
+	}
+	for _, s := range []string{"", "2020-05-01T10:00:00Z", "vceraj,comment,1", "2020-05-01T10:00:00Z,like,1",
+		"2020-05-01T10:00:00Z,comment,x"} {
+		_, err := biolog.ParseFeedCursor(s)
+		assert.Error(t, err, s)
+	}
+
+	// Pri enakem casu odloca vrsta dogodka in nato ID, vse padajoce
+	assert.True(t, c.Before(biolog.FeedCursor{CreatedAt: created, Type: biolog.FeedIdentification, Item: 41}))
+	assert.True(t, c.Before(biolog.FeedCursor{CreatedAt: created, Type: biolog.FeedComment, Item: 99}))
+	assert.False(t, c.Before(biolog.FeedCursor{CreatedAt: created, Type: biolog.FeedObservation, Item: 1}))
+	assert.False(t, c.Before(c))
+	// Polozaj brez vrste (parameter before) izpusti vse dogodke ob tem casu
+	assert.False(t, biolog.FeedCursor{CreatedAt: created}.Before(c))
+	assert.True(t, biolog.FeedCursor{CreatedAt: created.Add(time.Microsecond)}.Before(c))
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// AssessmentID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezno oceno ogrozenosti
+// swagger:parameters updateAssessment deleteAssessment
+type AssessmentID struct {
+	// in: path
+	// required: true
+	ID int `json:"assessmentID"`
+}
+
+// AssessmentBodyParams model.
+//
+// Ocena ogrozenosti v telesu zahtevka
+// swagger:parameters createAssessment updateAssessment
+type AssessmentBodyParams struct {
+	// in: body
+	// required: true
+	Payload *biolog.Assessment `json:"assessment"`
+}
+
+// decodeAssessment prebere oceno ogrozenosti iz telesa zahtevka. ID in vrsto doloci pot
+func decodeAssessment(w http.ResponseWriter, r *http.Request) (*biolog.Assessment, bool) {
+	var a biolog.Assessment
+	decErr := json.NewDecoder(r.Body).Decode(&a)
+	if decErr != nil {
+		switch decErr {
+		case io.EOF:
+			respondWithError(w, http.StatusBadRequest, "Telo zahtevka pri oceni ne more biti prazno")
+		default:
+			respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		}
+		return nil, true
+	}
+	a.ID, a.Species = nil, nil
+
+	return &a, false
+}
+
+// speciesAssessment pridobi oceno ogrozenosti iz poti in preveri, da pripada vrsti iz poti.
+// Ce pride do napake obvesti odjemalca in vrne true
+func (sh *SpeciesHandler) speciesAssessment(w http.ResponseWriter, r *http.Request) (*biolog.Assessment, bool) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return nil, true
+	}
+	id, parseErr := getIDFromURL(w, r, "assessmentID")
+	if parseErr {
+		return nil, true
+	}
+	a, err := speciesService(r, sh.SpeciesService).Assessment(id)
+	if err != nil || *a.Species != gbifKey {
+		respondWithError(w, http.StatusNotFound, "Ocena s tem ID ne obstaja")
+		return nil, true
+	}
+
+	return a, false
+}
+
+// GetAssessments vrne vse ocene ogrozenosti vrste, vkljucno s preteklimi
+func (sh *SpeciesHandler) GetAssessments(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	if _, err := speciesService(r, sh.SpeciesService).Species(gbifKey); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	assessments, err := speciesService(r, sh.SpeciesService).Assessments(gbifKey)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, assessments)
+}
+
+// CreateAssessment doda oceno ogrozenosti vrsti iz poti, obmocje je privzeto global
+func (sh *SpeciesHandler) CreateAssessment(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	if _, failed := sh.requireModerator(w, r); failed {
+		return
+	}
+	a, failed := decodeAssessment(w, r)
+	if failed {
+		return
+	}
+	if a.Scheme == nil || a.ConservationStatus == nil || a.Year == nil {
+		respondWithError(w, http.StatusBadRequest, "Ocena mora imeti sistem, status ogrozenosti in leto")
+		return
+	}
+	if a.Region == nil {
+		region := biolog.RegionGlobal
+		a.Region = &region
+	}
+	a.Species = &gbifKey
+
+	newAssessment, err := speciesService(r, sh.SpeciesService).CreateAssessment(a)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newAssessment)
+}
+
+// UpdateAssessment posodobi oceno ogrozenosti
+func (sh *SpeciesHandler) UpdateAssessment(w http.ResponseWriter, r *http.Request) {
+	if _, failed := sh.requireModerator(w, r); failed {
+		return
+	}
+	existing, failed := sh.speciesAssessment(w, r)
+	if failed {
+		return
+	}
+	a, failed := decodeAssessment(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).UpdateAssessment(*existing.ID, *a); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// DeleteAssessment zbrise oceno ogrozenosti
+func (sh *SpeciesHandler) DeleteAssessment(w http.ResponseWriter, r *http.Request) {
+	if _, failed := sh.requireModerator(w, r); failed {
+		return
+	}
+	existing, failed := sh.speciesAssessment(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).DeleteAssessment(*existing.ID); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
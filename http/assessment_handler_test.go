@@ -0,0 +1,44 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssessments preveri, da ocene ogrozenosti ureja le moderator in da ocena pripada vrsti iz poti
+func TestAssessments(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) { return testSpecies(), nil }
+	ss.AssessmentsFn = func(gbifKey int) ([]biolog.Assessment, error) { return []biolog.Assessment{}, nil }
+	ss.AssessmentFn = func(id int) (*biolog.Assessment, error) {
+		species := 5231190
+		return &biolog.Assessment{ID: &id, Species: &species}, nil
+	}
+	ss.CreateAssessmentFn = func(a *biolog.Assessment) (*biolog.Assessment, error) {
+		assert.Equal(t, 5231190, *a.Species)
+		assert.Equal(t, biolog.RegionGlobal, *a.Region)
+		return a, nil
+	}
+	ss.UpdateAssessmentFn = func(id int, a biolog.Assessment) error { return nil }
+	ss.DeleteAssessmentFn = func(id int) error { return nil }
+
+	body := `{"scheme": "IUCN", "conservationStatus": 8, "year": 2016, "species": 1}`
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/5231190/assessments", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/species/5231190/assessments", body, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/species/5231190/assessments/1", "", auth).Code)
+
+	role := biolog.RoleModerator
+	user.Role = &role
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/5231190/assessments", body, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/5231190/assessments", `{"scheme": "IUCN"}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/5231190/assessments/1", `{"year": 2018}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/5231190/assessments/1", "", auth).Code)
+	// Ocena ne pripada vrsti iz poti
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/species/2480537/assessments/1", "", auth).Code)
+}
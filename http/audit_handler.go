@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// AuditHandler je http handler za branje revizijske sledi (le za administratorje)
+type AuditHandler struct {
+	AuditService biolog.AuditService
+	UserService  biolog.UserService
+	*chi.Mux
+}
+
+// AuditParams model.
+//
+// Filter pri branju revizijske sledi
+// swagger:parameters getAuditEntries
+type AuditParams struct {
+	// Vrsta zapisa (species, observation ali user)
+	// in: query
+	EntityType string `json:"entityType"`
+
+	// Identifikator zapisa
+	// in: query
+	EntityID int `json:"entityID"`
+
+	// Uporabnik, ki je naredil spremembo
+	// in: query
+	Actor int `json:"actor"`
+
+	// Najvecje stevilo vrnjenih zapisov
+	// in: query
+	Limit int `json:"limit"`
+
+	// Stevilo preskocenih zapisov
+	// in: query
+	Offset int `json:"offset"`
+}
+
+// NewAuditHandler kreira novega handlerja za revizijsko sled
+func NewAuditHandler() *AuditHandler {
+	ah := &AuditHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/audit
+
+	// swagger:route GET /audit audit getAuditEntries
+	//
+	// Pridobi zapise revizijske sledi, od najnovejsega naprej (administrator)
+	//
+	// Responses:
+	//		200: []auditEntry
+	ah.Get("/", ah.GetAuditEntries)
+
+	return ah
+}
+
+// GetAuditEntries vrne stran zapisov revizijske sledi
+// Mozni parametri so:
+// 	- entityType ... vrsta zapisa (species, observation ali user)
+// 	- entityID ... identifikator zapisa
+// 	- actor ... uporabnik, ki je naredil spremembo
+// 	- limit, offset ... ostranjevanje
+func (ah *AuditHandler) GetAuditEntries(w http.ResponseWriter, r *http.Request) {
+	me, err := currentUser(r, ah.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Revizijsko sled lahko bere le administrator")
+		return
+	}
+
+	f, parseErr := getAuditFilter(w, r)
+	if parseErr {
+		return
+	}
+	p, parseErr := getPage(w, r)
+	if parseErr {
+		return
+	}
+
+	entries, err := ah.AuditService.AuditEntries(f, p)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju revizijske sledi")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// getAuditFilter prebere filter revizijske sledi iz query parametrov. Ce je kateri neveljaven
+// obvesti odjemalca in vrne true
+func getAuditFilter(w http.ResponseWriter, r *http.Request) (biolog.AuditFilter, bool) {
+	var f biolog.AuditFilter
+	q := r.URL.Query()
+	if entityType := q.Get("entityType"); entityType != "" {
+		if !biolog.ValidAuditEntity(entityType) {
+			respondWithError(w, http.StatusBadRequest, "Neveljavna vrsta zapisa")
+			return f, true
+		}
+		f.EntityType = &entityType
+	}
+	if entity := q.Get("entityID"); entity != "" {
+		entityID, err := strconv.Atoi(entity)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven ID zapisa")
+			return f, true
+		}
+		f.EntityID = &entityID
+	}
+	if actor := q.Get("actor"); actor != "" {
+		actorID, err := strconv.Atoi(actor)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven ID uporabnika")
+			return f, true
+		}
+		f.Actor = &actorID
+	}
+
+	return f, false
+}
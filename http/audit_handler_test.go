@@ -0,0 +1,49 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditEntries preveri branje revizijske sledi (le administrator) in filtre v query parametrih
+func TestAuditEntries(t *testing.T) {
+	h, us, _ := newTestHandler()
+	as := h.AuditHandler.AuditService.(*mock.AuditService)
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	id, actor, action, entity := 1, 10000001, biolog.AuditDelete, biolog.AuditObservation
+	as.AuditEntriesFn = func(f biolog.AuditFilter, p biolog.Page) ([]biolog.AuditEntry, error) {
+		if assert.NotNil(t, f.EntityType) && assert.NotNil(t, f.EntityID) {
+			assert.Equal(t, entity, *f.EntityType)
+			assert.Equal(t, 7, *f.EntityID)
+		}
+		assert.Nil(t, f.Actor)
+		assert.Equal(t, 5, p.Limit)
+		return []biolog.AuditEntry{{ID: &id, Actor: &actor, Action: &action, EntityType: &entity, EntityID: &id,
+			Changes: json.RawMessage(`{"quantity":{"before":3,"after":null}}`)}}, nil
+	}
+
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "GET", "/audit", "", auth).Code)
+
+	role := biolog.RoleAdmin
+	user.Role = &role
+	rec := doRequest(h, "GET", "/audit?entityType=observation&entityID=7&limit=5", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var entries []map[string]interface{}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&entries))
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, "delete", entries[0]["action"])
+			assert.Equal(t, float64(3), entries[0]["changes"].(map[string]interface{})["quantity"].(map[string]interface{})["before"])
+		}
+	}
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/audit?entityType=comment", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/audit?actor=river", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/audit?entityID=x", "", auth).Code)
+}
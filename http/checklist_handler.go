@@ -0,0 +1,316 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// ChecklistHandler je http handler za popise (vec opazanj z enega obiska terena)
+type ChecklistHandler struct {
+	SpeciesService biolog.SpeciesService
+	// UserService se uporablja za iskanje prijavljenega uporabnika
+	UserService biolog.UserService
+	// Sensitivity doloca vrste, katerih lokacije se drugim uporabnikom posplosijo
+	Sensitivity biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// ChecklistID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezen popis
+// swagger:parameters getChecklistByID updateChecklist deleteChecklist
+type ChecklistID struct {
+	// in: path
+	// required: true
+	ID int `json:"id"`
+}
+
+// ChecklistBodyParams model.
+//
+// Popis (pri kreiranju skupaj z opazanji) v telesu zahtevka
+// swagger:parameters createChecklist updateChecklist
+type ChecklistBodyParams struct {
+	// in: body
+	// required: true
+	Payload *biolog.Checklist `json:"checklist"`
+}
+
+// NewChecklistHandler kreira novega handlerja za popise
+func NewChecklistHandler() *ChecklistHandler {
+	ch := &ChecklistHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/checklists
+
+	// swagger:route GET /checklists checklists getChecklists
+	//
+	// Pridobi stran popisov prijavljenega uporabnika (brez opazanj)
+	//
+	// Responses:
+	//		200: []checklist
+	ch.Get("/", ch.GetChecklists)
+
+	// swagger:route POST /checklists checklists createChecklist
+	//
+	// Ustvari popis skupaj z opazanji v eni transakciji
+	//
+	// Responses:
+	//		201: checklist
+	ch.Post("/", ch.CreateChecklist)
+
+	ch.Route("/{id:[0-9]+}", func(r chi.Router) {
+		// swagger:route GET /checklists/{id} checklists getChecklistByID
+		//
+		// Pridobi popis z opazanji, ki jih uporabnik lahko vidi
+		//
+		// Responses:
+		//		200: checklist
+		r.Get("/", ch.GetChecklistByID)
+
+		// swagger:route PATCH /checklists/{id} checklists updateChecklist
+		//
+		// Posodobi podatke o popisu (avtor ali moderator)
+		//
+		// Responses:
+		//		204:
+		r.Patch("/", ch.UpdateChecklist)
+
+		// swagger:route DELETE /checklists/{id} checklists deleteChecklist
+		//
+		// Zbrise popis skupaj z opazanji (avtor ali moderator)
+		//
+		// Responses:
+		//		204:
+		r.Delete("/", ch.DeleteChecklist)
+	})
+
+	return ch
+}
+
+// decodeChecklist prebere popis iz telesa zahtevka in preveri vrednosti, ki so podane.
+// Polj, ki jih doloci streznik (ID, avtor in opazanja), ni mogoce nastaviti
+func decodeChecklist(w http.ResponseWriter, r *http.Request) (*biolog.Checklist, bool) {
+	var c biolog.Checklist
+	decErr := json.NewDecoder(r.Body).Decode(&c)
+	if decErr != nil {
+		switch decErr {
+		case io.EOF:
+			respondWithError(w, http.StatusBadRequest, "Telo zahtevka pri popisu ne more biti prazno")
+		default:
+			respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		}
+		return nil, true
+	}
+	if c.Protocol != nil && !biolog.ValidProtocol(*c.Protocol) {
+		respondWithError(w, http.StatusBadRequest, "Neveljaven protokol popisa (incidental, stationary ali traveling)")
+		return nil, true
+	}
+	if c.Duration != nil && *c.Duration < 0 {
+		respondWithError(w, http.StatusBadRequest, "Trajanje popisa ne more biti negativno")
+		return nil, true
+	}
+	c.ID, c.User = nil, nil
+
+	return &c, false
+}
+
+// editableChecklist pridobi popis iz poti in preveri, da ga prijavljen uporabnik lahko ureja
+// (avtor ali moderator). Ce pride do napake obvesti odjemalca in vrne true
+func (ch *ChecklistHandler) editableChecklist(w http.ResponseWriter, r *http.Request) (*biolog.Checklist, bool) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return nil, true
+	}
+	c, err := speciesService(r, ch.SpeciesService).Checklist(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return nil, true
+	}
+	u, err := currentUser(r, ch.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return nil, true
+	}
+	if *c.User != *u.ID && !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "Popis lahko ureja le avtor ali moderator")
+		return nil, true
+	}
+
+	return c, false
+}
+
+// GetChecklists vrne stran popisov prijavljenega uporabnika
+func (ch *ChecklistHandler) GetChecklists(w http.ResponseWriter, r *http.Request) {
+	u, err := currentUser(r, ch.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	p, failed := getPage(w, r)
+	if failed {
+		return
+	}
+
+	checklists, err := speciesService(r, ch.SpeciesService).Checklists(*u.ID, p)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, checklists)
+}
+
+// CreateChecklist shrani popis prijavljenega uporabnika in njegova opazanja. Cas in lokacija opazanja
+// sta privzeto enaka zacetku in lokaciji popisa, opazanja so privzeto javna
+func (ch *ChecklistHandler) CreateChecklist(w http.ResponseWriter, r *http.Request) {
+	u, err := currentUser(r, ch.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	c, failed := decodeChecklist(w, r)
+	if failed {
+		return
+	}
+	if c.StartTime == nil || c.Location == nil || c.Protocol == nil {
+		respondWithError(w, http.StatusBadRequest, "Popis mora imeti zacetek, lokacijo in protokol")
+		return
+	}
+	if *c.Protocol != biolog.ProtocolIncidental && c.Duration == nil {
+		respondWithError(w, http.StatusBadRequest, "Popis na mestu ali med premikanjem mora imeti trajanje")
+		return
+	}
+	c.User = u.ID
+
+	public := true
+	for i := range c.Observations {
+		o := &c.Observations[i]
+		o.ID, o.User, o.Checklist, o.Obscured = nil, u.ID, nil, nil
+		if o.SightingTime == nil {
+			o.SightingTime = c.StartTime
+		}
+		if o.SightingLocation == nil {
+			o.SightingLocation = c.Location
+		}
+		if o.PublicVisibility == nil {
+			o.PublicVisibility = &public
+		}
+	}
+
+	newChecklist, err := speciesService(r, ch.SpeciesService).CreateChecklist(c)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	metrics.ObservationsCreated.WithLabelValues("checklist").Add(float64(len(newChecklist.Observations)))
+	respondWithJSON(w, http.StatusCreated, newChecklist)
+}
+
+// GetChecklistByID vrne popis z opazanji, ki jih prijavljen uporabnik lahko vidi. Popis, na katerem uporabnik
+// ne vidi nobenega opazanja, je viden le avtorju in moderatorjem. Ce je katero izmed opazanj (vidnih ali ne)
+// obcutljive vrste, se posplosi tudi lokacija popisa
+func (ch *ChecklistHandler) GetChecklistByID(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	c, err := speciesService(r, ch.SpeciesService).Checklist(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	u, err := currentUser(r, ch.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	// Obcutljivost se preveri na vseh opazanjih, da lokacija popisa ne izda lokacije skritega opazanja
+	if err := obscureSensitive(speciesService(r, ch.SpeciesService), ch.Sensitivity, u, c.Observations); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju statusa ogrozenosti vrste")
+		return
+	}
+	visible := []biolog.Observation{}
+	obscure := false
+	// Avtorji opazanj, da se vsak prebere le enkrat
+	owners := make(map[int]*biolog.User)
+	for i := range c.Observations {
+		o := &c.Observations[i]
+		if o.Obscured != nil && *o.Obscured {
+			obscure = true
+		}
+		owner, ok := owners[*o.User]
+		if !ok {
+			owner = observationOwner(userService(r, ch.UserService), o)
+			owners[*o.User] = owner
+		}
+		if canSeeObservation(o, owner, u) {
+			visible = append(visible, *o)
+		}
+	}
+	owner := c.User != nil && u.ID != nil && *c.User == *u.ID
+	if len(visible) == 0 && !owner && !u.IsModerator() {
+		respondWithError(w, http.StatusNotFound, "Popis ne obstaja")
+		return
+	}
+	c.Observations = visible
+	if obscure && c.Location != nil {
+		if loc, ok := biolog.ObscureLocation(*c.Location); ok {
+			c.Location = &loc
+		} else {
+			c.Location = nil
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, c)
+}
+
+// UpdateChecklist posodobi podatke o popisu
+func (ch *ChecklistHandler) UpdateChecklist(w http.ResponseWriter, r *http.Request) {
+	existing, failed := ch.editableChecklist(w, r)
+	if failed {
+		return
+	}
+	c, failed := decodeChecklist(w, r)
+	if failed {
+		return
+	}
+	// Opazanja se urejajo preko /species/observations
+	c.Observations = nil
+
+	if err := speciesService(r, ch.SpeciesService).UpdateChecklist(*existing.ID, *c); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// DeleteChecklist zbrise popis, opazanja na njem se izbrisejo mehko (do trajne odstranitve jih je mogoce obnoviti)
+func (ch *ChecklistHandler) DeleteChecklist(w http.ResponseWriter, r *http.Request) {
+	existing, failed := ch.editableChecklist(w, r)
+	if failed {
+		return
+	}
+	u, err := currentUser(r, ch.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := speciesService(r, ch.SpeciesService).DeleteChecklist(*existing.ID, auditActor(r, u)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
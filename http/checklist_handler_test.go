@@ -0,0 +1,152 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testChecklist vrne popis uporabnika author z javnim opazanjem testObservation
+func testChecklist(author int) *biolog.Checklist {
+	id, loc, protocol := 1, "POINT(14.5058 46.0569)", biolog.ProtocolIncidental
+	return &biolog.Checklist{ID: &id, User: &author, Location: &loc, Protocol: &protocol,
+		Observations: []biolog.Observation{*testObservation()}}
+}
+
+// TestCreateChecklist preveri preverjanje popisa in privzete vrednosti opazanj
+func TestCreateChecklist(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.CreateChecklistFn = func(c *biolog.Checklist) (*biolog.Checklist, error) {
+		assert.Equal(t, 10000000, *c.User)
+		if assert.Len(t, c.Observations, 1) {
+			o := c.Observations[0]
+			assert.Equal(t, 10000000, *o.User)
+			assert.Equal(t, *c.Location, *o.SightingLocation)
+			assert.Equal(t, *c.StartTime, *o.SightingTime)
+			assert.True(t, *o.PublicVisibility)
+		}
+		return c, nil
+	}
+
+	body := `{"startTime": "2018-06-04T07:30:00Z", "duration": 90, "location": "POINT(14.5058 46.0569)",
+		"protocol": "stationary", "complete": true, "observations": [{"species": 5231190, "quantity": 3, "user": 1}]}`
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/checklists", body, auth).Code)
+
+	for name, bad := range map[string]string{
+		"empty":            "",
+		"unknown protocol": `{"startTime": "2018-06-04T07:30:00Z", "location": "POINT(14.5 46)", "protocol": "driving"}`,
+		"no duration":      `{"startTime": "2018-06-04T07:30:00Z", "location": "POINT(14.5 46)", "protocol": "traveling"}`,
+		"negative":         `{"startTime": "2018-06-04T07:30:00Z", "location": "POINT(14.5 46)", "protocol": "traveling", "duration": -5}`,
+		"no location":      `{"startTime": "2018-06-04T07:30:00Z", "protocol": "incidental"}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/checklists", bad, auth).Code, name)
+	}
+}
+
+// TestChecklistByID preveri vidnost opazanj na popisu in urejanje le za avtorja ali moderatorja
+func TestChecklistByID(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	// Prijavljen je uporabnik 10000001, popis pripada uporabniku 10000000
+	viewer := testUser()
+	other := 10000001
+	viewer.ID = &other
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return viewer, nil }
+	c := testChecklist(10000000)
+	ss.ChecklistFn = func(id int) (*biolog.Checklist, error) {
+		copied := *c
+		copied.Observations = append([]biolog.Observation{}, c.Observations...)
+		return &copied, nil
+	}
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) { return testSpecies(), nil }
+	ss.UpdateChecklistFn = func(id int, c biolog.Checklist) error { return nil }
+	ss.DeleteChecklistFn = func(id int, by biolog.Actor) error {
+		assert.Equal(t, other, by.User)
+		return nil
+	}
+
+	var got biolog.Checklist
+	rec := doRequest(h, "GET", "/checklists/1", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Len(t, got.Observations, 1)
+	}
+
+	// Zasebna opazanja drugega uporabnika se ne prikazejo, popis brez vidnih opazanj pa ni viden
+	private := false
+	hidden := *testObservation()
+	hidden.PublicVisibility = &private
+	c.Observations = append(c.Observations, hidden)
+	rec = doRequest(h, "GET", "/checklists/1", "", auth)
+	got = biolog.Checklist{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Len(t, got.Observations, 1)
+	c.Observations = c.Observations[1:]
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/checklists/1", "", auth).Code)
+
+	// Javna opazanja uporabnika, ki ne dovoli javnega dostopa do svojih opazanj, se ne prikazejo
+	c.Observations = []biolog.Observation{*testObservation()}
+	us.UserFn = func(id int) (*biolog.User, error) {
+		owner := testOwner(id)
+		owner.PublicObservations = &private
+		return owner, nil
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/checklists/1", "", auth).Code)
+
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/checklists/1", `{"complete": true}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/checklists/1", "", auth).Code)
+
+	// Moderator lahko ureja in brise
+	moderator := biolog.RoleModerator
+	viewer.Role = &moderator
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/checklists/1", `{"complete": true}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/checklists/1", `{"protocol": "driving"}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/checklists/1", "", auth).Code)
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/checklists/1", "", auth).Code)
+}
+
+// TestChecklistSensitiveLocation preveri, da se lokacija popisa posplosi tudi, ce je obcutljivo le opazanje,
+// ki ga uporabnik ne vidi
+func TestChecklistSensitiveLocation(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	viewer := testUser()
+	other := 10000001
+	viewer.ID = &other
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return viewer, nil }
+	sensitive, private := 2480528, false
+	h.ChecklistHandler.Sensitivity = biolog.DefaultSensitivityPolicy([]int{sensitive})
+	hidden := *testObservation()
+	hidden.Species = &sensitive
+	hidden.PublicVisibility = &private
+	c := testChecklist(10000000)
+	c.Observations = append(c.Observations, hidden)
+	exact := *c.Location
+	ss.ChecklistFn = func(id int) (*biolog.Checklist, error) {
+		copied := *c
+		copied.Observations = append([]biolog.Observation{}, c.Observations...)
+		return &copied, nil
+	}
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) { return testSpecies(), nil }
+
+	var got biolog.Checklist
+	rec := doRequest(h, "GET", "/checklists/1", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Len(t, got.Observations, 1)
+		assert.NotEqual(t, exact, *got.Location)
+	}
+
+	// Avtor vidi natancno lokacijo
+	viewer.ID = c.User
+	got = biolog.Checklist{}
+	rec = doRequest(h, "GET", "/checklists/1", "", auth)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, exact, *got.Location)
+	assert.Len(t, got.Observations, 2)
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// CommentID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezen komentar
+// swagger:parameters updateComment deleteComment
+type CommentID struct {
+	// in: path
+	// required: true
+	ID int `json:"commentID"`
+}
+
+// CommentBodyParams model.
+//
+// Besedilo komentarja v telesu zahtevka
+// swagger:parameters createComment updateComment
+type CommentBodyParams struct {
+	// in: body
+	// required: true
+	Payload *biolog.Comment `json:"comment"`
+}
+
+// PageParams model.
+//
+// Parametra za ostranjevanje
+// swagger:parameters getComments
+type PageParams struct {
+	// Najvecje stevilo vrnjenih zapisov (privzeto 20, najvec 100)
+	// in: query
+	Limit int `json:"limit"`
+	// Stevilo preskocenih zapisov
+	// in: query
+	Offset int `json:"offset"`
+}
+
+// canSeeObservation pove ali uporabnik lahko vidi opazanje in vse, kar je z njim povezano. Javno je le opazanje,
+// katerega avtor owner dovoli javen dostop do svojih opazanj (glej biolog.Observation.IsPublic), zasebna
+// opazanja vidita le lastnik in moderator
+func canSeeObservation(ob *biolog.Observation, owner, u *biolog.User) bool {
+	if ob.IsPublic(owner) {
+		return true
+	}
+	return *ob.User == *u.ID || u.IsModerator()
+}
+
+// observationOwner vrne avtorja opazanja oz. nil, ce ga ni mogoce prebrati (opazanje potem ni javno)
+func observationOwner(us biolog.UserService, ob *biolog.Observation) *biolog.User {
+	if ob.User == nil {
+		return nil
+	}
+	owner, err := us.User(*ob.User)
+	if err != nil {
+		return nil
+	}
+	return owner
+}
+
+// visibleObservation pridobi opazanje iz poti in prijavljenega uporabnika ter preveri, da lahko
+// uporabnik vidi opazanje (in njegove komentarje ali revizije). Ce pride do napake obvesti odjemalca in vrne true
+func (sh *SpeciesHandler) visibleObservation(w http.ResponseWriter, r *http.Request) (*biolog.Observation, *biolog.User, bool) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return nil, nil, true
+	}
+
+	ob, err := speciesService(r, sh.SpeciesService).Observation(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Opazanje s tem ID ne obstaja")
+		return nil, nil, true
+	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return nil, nil, true
+	}
+	if !canSeeObservation(ob, observationOwner(userService(r, sh.UserService), ob), u) {
+		respondWithError(w, http.StatusForbidden, "Opazanje je zasebno")
+		return nil, nil, true
+	}
+
+	return ob, u, false
+}
+
+// decodeCommentBody prebere besedilo komentarja iz telesa zahtevka in preveri njegovo dolzino
+func decodeCommentBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var c biolog.Comment
+	decErr := json.NewDecoder(r.Body).Decode(&c)
+	if decErr != nil {
+		switch decErr {
+		case io.EOF:
+			respondWithError(w, http.StatusBadRequest, "Telo zahtevka pri komentarju ne more biti prazno")
+		default:
+			respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		}
+		return "", true
+	}
+	if c.Body == nil || strings.TrimSpace(*c.Body) == "" {
+		respondWithError(w, http.StatusBadRequest, "Komentar mora vsebovati besedilo")
+		return "", true
+	}
+	if len([]rune(*c.Body)) > biolog.MaxCommentLength {
+		respondWithError(w, http.StatusBadRequest, "Komentar je predolg")
+		return "", true
+	}
+
+	return *c.Body, false
+}
+
+// GetComments vrne stran komentarjev za opazanje
+func (sh *SpeciesHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	ob, _, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+	p, failed := getPage(w, r)
+	if failed {
+		return
+	}
+
+	comments, err := speciesService(r, sh.SpeciesService).Comments(*ob.ID, p)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, comments)
+}
+
+// CreateComment shrani komentar prijavljenega uporabnika pod opazanjem
+func (sh *SpeciesHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+	body, failed := decodeCommentBody(w, r)
+	if failed {
+		return
+	}
+
+	c, err := speciesService(r, sh.SpeciesService).CreateComment(&biolog.Comment{Observation: ob.ID, User: u.ID, Body: &body})
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, c)
+}
+
+// editableComment pridobi komentar iz poti in preveri, da ga prijavljen uporabnik lahko ureja
+// (avtor ali moderator). Ce pride do napake obvesti odjemalca in vrne true
+func (sh *SpeciesHandler) editableComment(w http.ResponseWriter, r *http.Request) (*biolog.Comment, bool) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return nil, true
+	}
+	id, parseErr := getIDFromURL(w, r, "commentID")
+	if parseErr {
+		return nil, true
+	}
+
+	c, err := speciesService(r, sh.SpeciesService).Comment(id)
+	if err != nil || *c.Observation != *ob.ID {
+		respondWithError(w, http.StatusNotFound, "Komentar za to opazanje ne obstaja")
+		return nil, true
+	}
+	if *c.User != *u.ID && !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "Komentar lahko ureja le avtor ali moderator")
+		return nil, true
+	}
+
+	return c, false
+}
+
+// UpdateComment zamenja besedilo komentarja
+func (sh *SpeciesHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	c, failed := sh.editableComment(w, r)
+	if failed {
+		return
+	}
+	body, failed := decodeCommentBody(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).UpdateComment(*c.ID, body); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// DeleteComment zbrise komentar
+func (sh *SpeciesHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	c, failed := sh.editableComment(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).DeleteComment(*c.ID); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
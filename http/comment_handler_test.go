@@ -0,0 +1,134 @@
+package http_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testComment vrne komentar uporabnika author pod opazanjem 1
+func testComment(id, author int) *biolog.Comment {
+	observation, body := 1, "Je bila fotografija posneta pri krmilnici?"
+	return &biolog.Comment{ID: &id, Observation: &observation, User: &author, Body: &body}
+}
+
+// TestGetComments preveri ostranjevanje in vidnost komentarjev zasebnih opazanj ter opazanj zasebnih racunov
+func TestGetComments(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	// Prijavljen je uporabnik 10000001, opazanje pripada uporabniku 10000000
+	us.UserByEmailFn = func(email string) (*biolog.User, error) {
+		u := testUser()
+		other := 10000001
+		u.ID = &other
+		return u, nil
+	}
+	ob := testObservation()
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return ob, nil }
+	var page biolog.Page
+	ss.CommentsFn = func(observationID int, p biolog.Page) ([]biolog.Comment, error) {
+		page = p
+		return []biolog.Comment{*testComment(1, 10000000)}, nil
+	}
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1/comments", "", auth).Code)
+	assert.Equal(t, biolog.Page{Limit: 20}, page)
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1/comments?limit=5&offset=10", "", auth).Code)
+	assert.Equal(t, biolog.Page{Limit: 5, Offset: 10}, page)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/observations/1/comments?limit=1000", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/observations/1/comments?offset=-1", "", auth).Code)
+
+	// Javno opazanje uporabnika, ki ne dovoli javnega dostopa do svojih opazanj, je zasebno
+	private := false
+	us.UserFn = func(id int) (*biolog.User, error) {
+		owner := testOwner(id)
+		owner.PublicObservations = &private
+		return owner, nil
+	}
+	for _, path := range []string{"comments", "identifications", "revisions"} {
+		assert.Equal(t, http.StatusForbidden, doRequest(h, "GET", "/species/observations/1/"+path, "", auth).Code, path)
+	}
+	us.UserFn = func(id int) (*biolog.User, error) { return testOwner(id), nil }
+
+	// Zasebno opazanje drugega uporabnika
+	ob.PublicVisibility = &private
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "GET", "/species/observations/1/comments", "", auth).Code)
+
+	// Moderator vidi tudi komentarje zasebnih opazanj
+	us.UserByEmailFn = func(email string) (*biolog.User, error) {
+		u := testUser()
+		id, role := 10000001, biolog.RoleModerator
+		u.ID, u.Role = &id, &role
+		return u, nil
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1/comments", "", auth).Code)
+}
+
+// TestCreateComment preveri kreiranje komentarja
+func TestCreateComment(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	ss.CreateCommentFn = func(c *biolog.Comment) (*biolog.Comment, error) {
+		assert.Equal(t, 1, *c.Observation)
+		assert.Equal(t, 10000000, *c.User)
+		return c, nil
+	}
+
+	body := `{"body": "Je bila fotografija posneta pri krmilnici?", "user": 10000005}`
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/observations/1/comments", body, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/comments", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/comments", `{"body": "  "}`, auth).Code)
+	long := `{"body": "` + strings.Repeat("a", biolog.MaxCommentLength+1) + `"}`
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/comments", long, auth).Code)
+}
+
+// TestEditComment preveri, da lahko komentar ureja in brise le avtor ali moderator
+func TestEditComment(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	// Komentar 2 je napisal prijavljen uporabnik, komentar 3 nekdo drug
+	ss.CommentFn = func(id int) (*biolog.Comment, error) {
+		if id == 3 {
+			return testComment(id, 10000001), nil
+		}
+		return testComment(id, 10000000), nil
+	}
+	ss.UpdateCommentFn = func(id int, body string) error {
+		assert.Equal(t, "Ne, v gozdu.", body)
+		return nil
+	}
+	deleted := 0
+	ss.DeleteCommentFn = func(id int) error {
+		deleted = id
+		return nil
+	}
+
+	body := `{"body": "Ne, v gozdu."}`
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/observations/1/comments/2", body, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/species/observations/1/comments/3", body, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/species/observations/1/comments/3", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/observations/1/comments/2", "", auth).Code)
+	assert.Equal(t, 2, deleted)
+
+	// Moderator lahko zbrise tuj komentar
+	role := biolog.RoleModerator
+	user.Role = &role
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/observations/1/comments/3", "", auth).Code)
+	assert.Equal(t, 3, deleted)
+
+	// Komentar pripada drugemu opazanju
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) {
+		ob := testObservation()
+		ob.ID = &id
+		return ob, nil
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/species/observations/2/comments/2", "", auth).Code)
+}
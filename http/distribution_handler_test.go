@@ -0,0 +1,31 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetDistribution preveri parametre za karto razsirjenosti vrste
+func TestGetDistribution(t *testing.T) {
+	h, _, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	var cell string
+	ss.DistributionFn = func(c string, f biolog.ObservationFilter) ([]biolog.DistributionCell, error) {
+		cell = c
+		// Vrsta iz poti ima prednost pred query parametrom
+		assert.Equal(t, 5231190, *f.Species)
+		return []biolog.DistributionCell{{Cell: "u2j6p", Geometry: []byte(`{"type":"Polygon"}`), Observations: 4}}, nil
+	}
+
+	rec := doRequest(h, "GET", "/species/5231190/distribution", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, biolog.CellGeohash5, cell)
+		assert.Contains(t, rec.Body.String(), `"geometry":{"type":"Polygon"}`)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/5231190/distribution?cell=10km&species=1", "", auth).Code)
+	assert.Equal(t, biolog.Cell10km, cell)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/5231190/distribution?cell=1km", "", auth).Code)
+}
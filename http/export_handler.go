@@ -0,0 +1,169 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// ExportHandler izvaja asinhrone izvoze osebnih podatkov in streze pripravljene zip datoteke.
+// Povezava za prenos je javna (brez JWT), zato je identifikator izvoza nakljucen in velja le TTL.
+// Stanje izvozov hrani UserService, pri vec instancah streznika mora biti Dir skupna mapa
+type ExportHandler struct {
+	UserService biolog.UserService
+	// Mapa, kamor se shranijo zip datoteke
+	Dir string
+	// Cas veljavnosti povezave za prenos od konca izvoza (in najdaljse trajanje izvoza)
+	TTL time.Duration
+	*chi.Mux
+}
+
+// ExportID parameter model.
+//
+// Identifikator izvoza osebnih podatkov
+// swagger:parameters getExport
+type ExportID struct {
+	// Identifikator izvoza
+	//
+	// in: path
+	// required: true
+	ExportID string `json:"exportID"`
+}
+
+// NewExportHandler kreira novega handlerja za prenos izvozov osebnih podatkov
+func NewExportHandler() *ExportHandler {
+	eh := &ExportHandler{
+		Dir: os.TempDir(),
+		TTL: time.Hour,
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/exports
+
+	// swagger:route GET /exports/{exportID} exports getExport
+	//
+	// Prenese zip datoteko z osebnimi podatki, dokler je izvoz v pripravi vrne njegovo stanje
+	//
+	// Produces:
+	// - application/zip
+	// - application/json
+	//
+	// Responses:
+	//		404: description: Izvoz ne obstaja ali je povezava potekla
+	//		202: export
+	//		200: description: Zip datoteka z osebnimi podatki
+	eh.Get("/{exportID}", eh.GetExport)
+
+	return eh
+}
+
+// StartExport zacne izvoz osebnih podatkov uporabnika v ozadju in vrne njegovo zacetno stanje.
+// Izvoz, ki se ne konca v TTL (npr. ker se je streznik ustavil), potece
+func (eh *ExportHandler) StartExport(userID int) (*biolog.Export, error) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	now := time.Now()
+	expires := now.Add(eh.TTL)
+	export := biolog.Export{ID: base64.RawURLEncoding.EncodeToString(b), User: userID, Status: biolog.ExportPending,
+		CreatedAt: now, ExpiresAt: &expires}
+
+	eh.removeExpired()
+	if err := eh.UserService.CreateExport(export); err != nil {
+		return nil, err
+	}
+
+	go eh.run(export)
+	export.DownloadURL = downloadURL(export.ID)
+	return &export, nil
+}
+
+// downloadURL vrne povezavo za prenos izvoza
+func downloadURL(id string) string {
+	return "/api/v1/exports/" + id
+}
+
+// run zbere osebne podatke in jih zapise v zip datoteko, na koncu posodobi stanje izvoza. Tudi neuspesen
+// izvoz potece po TTL, da se odstrani
+func (eh *ExportHandler) run(export biolog.Export) {
+	path, err := eh.writeExport(export.User)
+	expires := time.Now().Add(eh.TTL)
+	export.ExpiresAt = &expires
+	if err != nil {
+		log.Error("Personal data export failed for user ", export.User, ": ", err)
+		export.Status = biolog.ExportFailed
+	} else {
+		export.Status, export.Path = biolog.ExportReady, path
+	}
+
+	if err := eh.UserService.UpdateExport(export); err != nil {
+		// Izvoz je medtem potekel, datoteke ne bo nihce prenesel
+		log.Error("Could not save personal data export ", export.ID, ": ", err)
+		if path != "" {
+			os.Remove(path)
+		}
+	}
+}
+
+// writeExport zapise osebne podatke uporabnika v novo zip datoteko in vrne njeno pot
+func (eh *ExportHandler) writeExport(userID int) (string, error) {
+	data, err := eh.UserService.PersonalData(userID)
+	if err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile(eh.Dir, "biolog-export-*.zip")
+	if err != nil {
+		return "", err
+	}
+	if err := data.WriteZip(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// removeExpired odstrani potekle izvoze in njihove datoteke
+func (eh *ExportHandler) removeExpired() {
+	expired, err := eh.UserService.DeleteExpiredExports(time.Now())
+	if err != nil {
+		log.Error("Could not remove expired personal data exports: ", err)
+		return
+	}
+	for _, e := range expired {
+		if e.Path != "" {
+			os.Remove(e.Path)
+		}
+	}
+}
+
+// GetExport vrne zip datoteko pripravljenega izvoza, sicer njegovo stanje
+func (eh *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	eh.removeExpired()
+	export, err := userService(r, eh.UserService).Export(chi.URLParam(r, "exportID"))
+	if err != nil || (export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt)) {
+		respondWithError(w, http.StatusNotFound, "Izvoz ne obstaja ali je povezava potekla")
+		return
+	}
+	export.DownloadURL = downloadURL(export.ID)
+	switch export.Status {
+	case biolog.ExportPending:
+		respondWithJSON(w, http.StatusAccepted, export)
+	case biolog.ExportFailed:
+		respondWithError(w, http.StatusInternalServerError, "Pri izvozu osebnih podatkov je prislo do napake")
+	default:
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="biolog-export.zip"`)
+		http.ServeFile(w, r, export.Path)
+	}
+}
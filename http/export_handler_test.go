@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockExports nastavi us, da izvoze hrani v mapi (enako kot memory.UserService)
+func mockExports(us *mock.UserService) map[string]biolog.Export {
+	var mu sync.Mutex
+	exports := make(map[string]biolog.Export)
+	us.CreateExportFn = func(e biolog.Export) error {
+		mu.Lock()
+		defer mu.Unlock()
+		exports[e.ID] = e
+		return nil
+	}
+	us.ExportFn = func(id string) (*biolog.Export, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		e, ok := exports[id]
+		if !ok {
+			return nil, errors.New("Izvoz ne obstaja")
+		}
+		return &e, nil
+	}
+	us.UpdateExportFn = func(e biolog.Export) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := exports[e.ID]; !ok {
+			return errors.New("Izvoz ne obstaja")
+		}
+		exports[e.ID] = e
+		return nil
+	}
+	us.DeleteExpiredExportsFn = func(now time.Time) ([]biolog.Export, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		expired := []biolog.Export{}
+		for id, e := range exports {
+			if e.ExpiresAt.Before(now) {
+				expired = append(expired, e)
+				delete(exports, id)
+			}
+		}
+		return expired, nil
+	}
+	return exports
+}
+
+// waitForExport pocaka, da izvoz ni vec v pripravi, in vrne odgovor povezave za prenos
+func waitForExport(t *testing.T, h http.Handler, export biolog.Export) int {
+	for i := 0; i < 100; i++ {
+		rec := doRequest(h, "GET", "/exports/"+export.ID, "", "")
+		if rec.Code != http.StatusAccepted {
+			return rec.Code
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Izvoz se ni koncal")
+	return 0
+}
+
+// TestExportPersonalData preveri izvoz lastnih podatkov, prenos zip datoteke in pravice za izvoz drugih uporabnikov
+func TestExportPersonalData(t *testing.T) {
+	h, us, _ := newTestHandler()
+	mockExports(us)
+	auth := "Bearer " + validToken()
+	me := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return me, nil }
+	us.UserFn = func(id int) (*biolog.User, error) { return testUser(), nil }
+	us.PersonalDataFn = func(id int) (*biolog.PersonalData, error) {
+		if id != 10000000 {
+			return nil, errors.New("Uporabnik s tem ID ne obstaja")
+		}
+		return &biolog.PersonalData{User: *testUser(), Observations: []biolog.Observation{*testObservation()}}, nil
+	}
+
+	var export biolog.Export
+	rec := doRequest(h, "POST", "/users/me/export", "", auth)
+	if !assert.Equal(t, http.StatusAccepted, rec.Code) || !assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export)) {
+		return
+	}
+	assert.Equal(t, 10000000, export.User)
+	assert.Equal(t, "/api/v1/exports/"+export.ID, export.DownloadURL)
+
+	if assert.Equal(t, http.StatusOK, waitForExport(t, h, export)) {
+		rec = doRequest(h, "GET", "/exports/"+export.ID, "", "")
+		assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+		z, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		if assert.NoError(t, err) {
+			assert.Len(t, z.File, 6)
+			assert.Equal(t, "README.txt", z.File[0].Name)
+		}
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/exports/unknown", "", "").Code)
+
+	// Podatke drugega uporabnika lahko izvozi le administrator
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/users/10000001/export", "", auth).Code)
+	role := biolog.RoleAdmin
+	me.Role = &role
+	rec = doRequest(h, "POST", "/users/10000001/export", "", auth)
+	if assert.Equal(t, http.StatusAccepted, rec.Code) && assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export)) {
+		// Neuspesen izvoz se javi ob prenosu
+		assert.Equal(t, http.StatusInternalServerError, waitForExport(t, h, export))
+	}
+}
+
+// TestExportExpires preveri, da izvozi (tudi neuspesni) po TTL potecejo in se odstranijo
+func TestExportExpires(t *testing.T) {
+	h, us, _ := newTestHandler()
+	exports := mockExports(us)
+	h.ExportHandler.TTL = 50 * time.Millisecond
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	us.PersonalDataFn = func(id int) (*biolog.PersonalData, error) { return nil, errors.New("Napaka baze") }
+
+	var export biolog.Export
+	rec := doRequest(h, "POST", "/users/me/export", "", "Bearer "+validToken())
+	if !assert.Equal(t, http.StatusAccepted, rec.Code) || !assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export)) {
+		return
+	}
+	assert.NotNil(t, export.ExpiresAt)
+	assert.Equal(t, http.StatusInternalServerError, waitForExport(t, h, export))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/exports/"+export.ID, "", "").Code)
+	assert.Empty(t, exports)
+}
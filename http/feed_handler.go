@@ -0,0 +1,116 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// FeedHandler je http handler za vir dejavnosti prijavljenega uporabnika
+type FeedHandler struct {
+	SpeciesService biolog.SpeciesService
+	UserService    biolog.UserService
+	Sensitivity    biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// FeedParams model.
+//
+// Ostranjevanje vira dejavnosti
+// swagger:parameters getFeed
+type FeedParams struct {
+	// Vrne le dogodke pred tem casom, privzeto trenutni cas
+	// in: query
+	// swagger:strfmt date-time
+	Before string `json:"before"`
+
+	// Vrne le dogodke za tem polozajem (cursor zadnjega dogodka prejsnje strani), ima prednost pred before
+	// in: query
+	Cursor string `json:"cursor"`
+
+	// Najvecje stevilo vrnjenih dogodkov
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// NewFeedHandler kreira novega handlerja za vir dejavnosti
+func NewFeedHandler() *FeedHandler {
+	fh := &FeedHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/feed
+
+	// swagger:route GET /feed feed getFeed
+	//
+	// Pridobi najnovejse javne dogodke spremljanih uporabnikov in opazovanih vrst, od najnovejsega naprej
+	//
+	// Responses:
+	//		400: description: Prislo je do napake
+	//		200: []feedItem
+	fh.Get("/", fh.GetFeed)
+
+	return fh
+}
+
+// GetFeed vrne stran vira dejavnosti. Stran se doloci s polozajem zadnjega dogodka namesto z zamikom,
+// zato so tudi starejse strani hitre in se ne zamaknejo, ko pridejo novi dogodki
+// Mozni parametri so:
+// 	- before ... vrne le dogodke pred tem casom (RFC 3339)
+// 	- cursor ... vrne le dogodke za tem polozajem (cursor zadnjega dogodka prejsnje strani)
+// 	- limit ... najvecje stevilo dogodkov (privzeto 20, najvec 100)
+func (fh *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	u, err := currentUser(r, fh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	q := r.URL.Query()
+	before := biolog.FeedCursor{CreatedAt: time.Now()}
+	if b := q.Get("before"); b != "" {
+		if before.CreatedAt, err = time.Parse(time.RFC3339Nano, b); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven cas before, pricakovan je RFC 3339")
+			return
+		}
+	}
+	if c := q.Get("cursor"); c != "" {
+		if before, err = biolog.ParseFeedCursor(c); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	limit := defaultPageLimit
+	if l := q.Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 || limit > maxPageLimit {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven limit: dovoljene vrednosti so od 1 do "+strconv.Itoa(maxPageLimit))
+			return
+		}
+	}
+
+	items, err := speciesService(r, fh.SpeciesService).Feed(*u.ID, before, limit)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju vira dejavnosti")
+		return
+	}
+
+	// Lokacije obcutljivih vrst se posplosijo enako kot v seznamu opazanj
+	obs := make([]biolog.Observation, len(items))
+	for i := range items {
+		obs[i] = *items[i].Observation
+	}
+	if err := obscureSensitive(speciesService(r, fh.SpeciesService), fh.Sensitivity, u, obs); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju statusa ogrozenosti vrste")
+		return
+	}
+	for i := range items {
+		items[i].Observation = &obs[i]
+		items[i].Cursor = items[i].Position().String()
+	}
+
+	respondWithJSON(w, http.StatusOK, items)
+}
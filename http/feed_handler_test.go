@@ -0,0 +1,101 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFollowAndWatch preveri spremljanje uporabnikov in opazovanje vrst za prijavljenega uporabnika
+func TestFollowAndWatch(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	followed := map[int]bool{}
+	us.FollowUserFn = func(followerID, userID int) error {
+		if followerID == userID {
+			return errors.New("Uporabnik ne more spremljati samega sebe")
+		}
+		followed[userID] = true
+		return nil
+	}
+	us.UnfollowUserFn = func(followerID, userID int) error {
+		if !followed[userID] {
+			return errors.New("Uporabnik tega uporabnika ne spremlja")
+		}
+		delete(followed, userID)
+		return nil
+	}
+	us.FollowingFn = func(userID int) ([]biolog.User, error) { return []biolog.User{*testUser()}, nil }
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/users/10000001/follow", "", "").Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/users/10000001/follow", "", auth).Code)
+	assert.True(t, followed[10000001])
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/users/10000000/follow", "", auth).Code)
+	rec := doRequest(h, "GET", "/users/me/following", "", auth)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/users/10000001/follow", "", auth).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/users/10000001/follow", "", auth).Code)
+
+	var watched []int
+	ss.WatchSpeciesFn = func(userID, gbifKey int) error {
+		watched = append(watched, gbifKey)
+		return nil
+	}
+	ss.UnwatchSpeciesFn = func(userID, gbifKey int) error { return errors.New("Uporabnik te vrste ne opazuje") }
+	ss.WatchedSpeciesFn = func(userID int) ([]biolog.Species, error) { return []biolog.Species{*testSpecies()}, nil }
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/species/5231190/watch", "", auth).Code)
+	assert.Equal(t, []int{5231190}, watched)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/species/5231190/watch", "", auth).Code)
+	var sps []biolog.Species
+	rec = doRequest(h, "GET", "/species/watched", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) && assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sps)) {
+		assert.Len(t, sps, 1)
+	}
+}
+
+// TestGetFeed preveri branje parametrov ostranjevanja vira dejavnosti
+func TestGetFeed(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.SpeciesFn = func(gbifKey int) (*biolog.Species, error) { return testSpecies(), nil }
+	var gotBefore biolog.FeedCursor
+	var gotLimit int
+	ss.FeedFn = func(userID int, before biolog.FeedCursor, limit int) ([]biolog.FeedItem, error) {
+		gotBefore, gotLimit = before, limit
+		return []biolog.FeedItem{{Type: biolog.FeedObservation, CreatedAt: before.CreatedAt.Add(-time.Minute), User: 10000001,
+			Observation: testObservation()}}, nil
+	}
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/feed", "", "").Code)
+	var items []biolog.FeedItem
+	rec := doRequest(h, "GET", "/feed", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) && assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items)) {
+		assert.Len(t, items, 1)
+		assert.Equal(t, 20, gotLimit)
+		assert.WithinDuration(t, time.Now(), gotBefore.CreatedAt, time.Minute)
+		assert.Equal(t, items[0].Position().String(), items[0].Cursor)
+	}
+
+	rec = doRequest(h, "GET", "/feed?before=2020-05-01T10:00:00Z&limit=5", "", auth)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 5, gotLimit)
+	assert.True(t, gotBefore.CreatedAt.Equal(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)))
+	assert.Empty(t, gotBefore.Type)
+
+	// Polozaj zadnjega dogodka doloci naslednjo stran tudi pri dogodkih z enakim casom
+	rec = doRequest(h, "GET", "/feed?cursor=2020-05-01T10:00:00.5Z,identification,42", "", auth)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, biolog.FeedCursor{CreatedAt: time.Date(2020, 5, 1, 10, 0, 0, 500000000, time.UTC),
+		Type: biolog.FeedIdentification, Item: 42}, gotBefore)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/feed?cursor=2020-05-01T10:00:00Z,like,1", "", auth).Code)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/feed?before=vceraj", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/feed?limit=1000", "", auth).Code)
+}
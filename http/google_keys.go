@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rubinda/biolog/tracing"
+)
+
+// Naslov javnih kljucev, s katerimi Google podpisuje ID tokene
+const googleCertsURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// Cas hranjenja kljucev, ce Google ne poslje Cache-Control max-age
+const defaultGoogleCertsTTL = time.Hour
+
+// Najkrajsi razmik med prenosi zaradi neznanega kid, da tokeni z izmisljenim kid ne prozijo prenosa ob vsaki prijavi
+const minGoogleCertsRefresh = time.Minute
+
+// googleKeyCache hrani javne kljuce Google do izteka, ki ga doloca Cache-Control odgovora,
+// da prijava ne prenasa kljucev ob vsaki zahtevi
+type googleKeyCache struct {
+	mu      sync.Mutex
+	url     string
+	client  *http.Client
+	keys    []GoogleKey
+	fetched time.Time
+	expires time.Time
+}
+
+// Odjemalec za klice Google (kljuci, OAuth2 in podatki o uporabniku), vsak klic ustvari span
+var googleClient = &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)}
+
+// Kljuci Google, skupni vsem prijavam
+var googleKeys = &googleKeyCache{url: googleCertsURL, client: googleClient}
+
+// key vrne kljuc z identifikatorjem kid. Ce ga med shranjenimi ni (Google je zamenjal kljuce) ali so
+// ti potekli, kljuce prenese ponovno v kontekstu ctx (zahteve prijave)
+func (c *googleKeyCache) key(ctx context.Context, kid string) (*GoogleKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Before(c.expires) {
+		if k := findGoogleKey(c.keys, kid); k != nil {
+			return k, nil
+		}
+		if time.Since(c.fetched) < minGoogleCertsRefresh {
+			return nil, errors.New("Google kid and token kid do not match")
+		}
+	}
+	if err := c.fetch(ctx); err != nil {
+		return nil, err
+	}
+	if k := findGoogleKey(c.keys, kid); k != nil {
+		return k, nil
+	}
+	return nil, errors.New("Google kid and token kid do not match")
+}
+
+// fetch prenese kljuce in nastavi cas izteka, klicatelj mora drzati kljucavnico
+func (c *googleKeyCache) fetch(ctx context.Context) error {
+	req, err := http.NewRequest("GET", c.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("Google certs returned " + resp.Status)
+	}
+
+	var keys map[string][]GoogleKey
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return err
+	}
+	c.keys, c.fetched = keys["keys"], time.Now()
+	c.expires = time.Now().Add(maxAge(resp.Header.Get("Cache-Control"), defaultGoogleCertsTTL))
+	return nil
+}
+
+// findGoogleKey poisce kljuc z identifikatorjem kid
+func findGoogleKey(keys []GoogleKey, kid string) *GoogleKey {
+	for i := range keys {
+		if keys[i].Kid == kid {
+			return &keys[i]
+		}
+	}
+	return nil
+}
+
+// maxAge prebere max-age iz glave Cache-Control, sicer vrne def
+func maxAge(cacheControl string, def time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if strings.HasPrefix(directive, "max-age=") {
+			if s, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && s >= 0 {
+				return time.Duration(s) * time.Second
+			}
+		}
+	}
+	return def
+}
@@ -6,8 +6,8 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"math/big"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,6 +18,7 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
 	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/metrics"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -43,9 +44,23 @@ type JWTToken struct {
 
 // Handler je kolekcija vseh nasih service handler
 type Handler struct {
-	UserHandler    *UserHandler
-	SpeciesHandler *SpeciesHandler
-	OAuthConf      *oauth2.Config
+	UserHandler      *UserHandler
+	SpeciesHandler   *SpeciesHandler
+	StatsHandler     *StatsHandler
+	TileHandler      *TileHandler
+	RegionHandler    *RegionHandler
+	ChecklistHandler *ChecklistHandler
+	ProjectHandler   *ProjectHandler
+	AuditHandler     *AuditHandler
+	WebhookHandler   *WebhookHandler
+	StreamHandler    *StreamHandler
+	ExportHandler    *ExportHandler
+	FeedHandler      *FeedHandler
+	OAuthConf        *oauth2.Config
+	// Omejitve stevila zahtev, nil pomeni brez omejitev (nastavijo se v cmd/biolog/main.go)
+	RateLimits *RateLimits
+	// Posredniki, katerih glavam X-Forwarded-For in X-Real-IP se zaupa (server.trusted-proxies)
+	TrustedProxies []*net.IPNet
 	*chi.Mux
 }
 
@@ -93,7 +108,8 @@ type GoogleKey struct {
 }
 
 // NewRootHandler ustvari starsa vseh ostalih handlerjev, nosi tudi primarni Router
-func NewRootHandler(us biolog.UserService, ss biolog.SpeciesService) *Handler {
+func NewRootHandler(us biolog.UserService, ss biolog.SpeciesService, rs biolog.RegionService,
+	as biolog.AuditService, ws biolog.WebhookService, obs biolog.ObservationStream) *Handler {
 	h := &Handler{
 		Mux: chi.NewRouter(),
 	}
@@ -118,7 +134,7 @@ func NewRootHandler(us biolog.UserService, ss biolog.SpeciesService) *Handler {
 		//AllowOriginFunc: func(r *http.Request, origin string) bool { return true },
 		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	})
@@ -126,14 +142,18 @@ func NewRootHandler(us biolog.UserService, ss biolog.SpeciesService) *Handler {
 
 	// A good base middleware stack
 	h.Use(middleware.RequestID)
-	h.Use(middleware.RealIP)
+	h.Use(h.realIP)
+	h.Use(traceRequests)
+	h.Use(measureRequests)
 	h.Use(middleware.Logger)
 	h.Use(middleware.Recoverer)
 
-	// Timeout na zahteve
-	h.Use(middleware.Timeout(60 * time.Second))
+	// Timeout na zahteve (razen na tokove dogodkov, ki ostanejo odprti)
+	h.Use(requestTimeout(60 * time.Second))
 	// Nastavimo predpono za api
 	h.Route("/api/v1", func(r chi.Router) {
+		// Vse zahteve z enega IP naslova se stejejo pred preverjanjem JWT, da se stejejo tudi neveljavne
+		r.Use(h.limitClients)
 
 		// Podpoti za endpoint '/users'
 		h.UserHandler = NewUserHandler()
@@ -141,28 +161,149 @@ func NewRootHandler(us biolog.UserService, ss biolog.SpeciesService) *Handler {
 		// Ustvari nov router z 'fresh middleware stack'
 		r.Group(func(r chi.Router) {
 			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
 			r.Mount("/users", h.UserHandler)
 		})
 
+		// Podpoti za endpoint '/exports' (javne, dostop omogoca nakljucen identifikator izvoza)
+		h.ExportHandler = NewExportHandler()
+		h.ExportHandler.UserService = us
+		if dir := viper.GetString("export.dir"); dir != "" {
+			h.ExportHandler.Dir = dir
+		}
+		if ttl := viper.GetDuration("export.link-ttl"); ttl > 0 {
+			h.ExportHandler.TTL = ttl
+		}
+		h.UserHandler.Exports = h.ExportHandler
+		r.Group(func(r chi.Router) {
+			r.Use(h.limitRequests)
+			r.Mount("/exports", h.ExportHandler)
+		})
+
 		// Podpoti za endpoint '/species'
 		h.SpeciesHandler = NewSpeciesHandler()
 		h.SpeciesHandler.SpeciesService = ss
+		h.SpeciesHandler.UserService = us
+		h.SpeciesHandler.Sensitivity = SensitivityPolicy()
 		r.Group(func(r chi.Router) {
 			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
 			r.Mount("/species", h.SpeciesHandler)
 		})
 
+		// Podpoti za endpoint '/checklists'
+		h.ChecklistHandler = NewChecklistHandler()
+		h.ChecklistHandler.SpeciesService = ss
+		h.ChecklistHandler.UserService = us
+		h.ChecklistHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/checklists", h.ChecklistHandler)
+		})
+
+		// Podpoti za endpoint '/projects'
+		h.ProjectHandler = NewProjectHandler()
+		h.ProjectHandler.SpeciesService = ss
+		h.ProjectHandler.UserService = us
+		h.ProjectHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/projects", h.ProjectHandler)
+		})
+
+		// Podpoti za endpoint '/feed'
+		h.FeedHandler = NewFeedHandler()
+		h.FeedHandler.SpeciesService = ss
+		h.FeedHandler.UserService = us
+		h.FeedHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/feed", h.FeedHandler)
+		})
+
+		// Podpoti za endpoint '/stats'
+		h.StatsHandler = NewStatsHandler()
+		h.StatsHandler.SpeciesService = ss
+		h.StatsHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/stats", h.StatsHandler)
+		})
+
+		// Podpoti za endpoint '/tiles' (javne, brez JWT)
+		h.TileHandler = NewTileHandler()
+		h.TileHandler.SpeciesService = ss
+		h.TileHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(h.limitRequests)
+			r.Mount("/tiles", h.TileHandler)
+		})
+
+		// Podpoti za endpoint '/stream' (javne, brez JWT)
+		h.StreamHandler = NewStreamHandler()
+		h.StreamHandler.SpeciesService = ss
+		h.StreamHandler.ObservationStream = obs
+		h.StreamHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(h.limitRequests)
+			r.Mount("/stream", h.StreamHandler)
+		})
+
+		// Podpoti za endpoint '/regions'
+		h.RegionHandler = NewRegionHandler()
+		h.RegionHandler.RegionService = rs
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/regions", h.RegionHandler)
+		})
+
+		// Podpoti za endpoint '/audit'
+		h.AuditHandler = NewAuditHandler()
+		h.AuditHandler.AuditService = as
+		h.AuditHandler.UserService = us
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/audit", h.AuditHandler)
+		})
+
+		// Podpoti za endpoint '/webhooks'
+		h.WebhookHandler = NewWebhookHandler()
+		h.WebhookHandler.WebhookService = ws
+		h.WebhookHandler.UserService = us
+		h.WebhookHandler.SpeciesService = ss
+		h.WebhookHandler.Sensitivity = h.SpeciesHandler.Sensitivity
+		r.Group(func(r chi.Router) {
+			r.Use(JWTAuthMiddleware)
+			r.Use(h.limitRequests)
+			r.Mount("/webhooks", h.WebhookHandler)
+		})
+
 		// Podpoti za preusmeranje prijav na ponudnika avtentikacije
 		r.Route("/login", func(r chi.Router) {
-
+			r.Use(h.limitLogin)
 			r.Post("/google", h.GoogleLoginHandler)
 		})
 
 		// Podpoti za callback od ponudnikov avtentikacije
 		r.Route("/authenticate", func(r chi.Router) {
+			r.Use(h.limitLogin)
 			r.Get("/", h.AuthHandler)
 		})
 
+		// Dokumentacija API (OpenAPI 3 dokument in Swagger UI)
+		r.Group(func(r chi.Router) {
+			r.Use(h.limitRequests)
+			r.Get("/openapi.json", h.OpenAPIHandler)
+			r.Get("/docs", h.DocsHandler)
+			r.Get("/docs/{file}", h.DocsAssetsHandler)
+		})
+
 	})
 
 	return h
@@ -210,11 +351,82 @@ func getIDFromURL(w http.ResponseWriter, r *http.Request, parameter string) (int
 	return id, false
 }
 
+// Privzeta in najvecja velikost strani pri ostranjevanju
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// GetPage prebere parametra za ostranjevanje (?limit=&offset=) iz zahteve, ce pride do napake obvesti odjemalca
+// Vraca stran in vrednost ali je prislo do napake (enako kot getIDFromURL)
+func getPage(w http.ResponseWriter, r *http.Request) (biolog.Page, bool) {
+	p := biolog.Page{Limit: defaultPageLimit}
+	q := r.URL.Query()
+
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven limit: dovoljene vrednosti so od 1 do "+strconv.Itoa(maxPageLimit))
+			return p, true
+		}
+		p.Limit = limit
+	}
+	if o := q.Get("offset"); o != "" {
+		offset, err := strconv.Atoi(o)
+		if err != nil || offset < 0 {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven offset")
+			return p, true
+		}
+		p.Offset = offset
+	}
+
+	return p, false
+}
+
 // GetUserEmail iz Context-a zahteve pobere uporabnikov email in ga vrne kot string
 func getUserEmail(r *http.Request) string {
 	return r.Context().Value(contextEmailKey("userEmail")).(string)
 }
 
+// CurrentUser pridobi prijavljenega uporabnika preko emaila iz Context-a zahteve
+func currentUser(r *http.Request, us biolog.UserService) (*biolog.User, error) {
+	return userService(r, us).UserByEmail(getUserEmail(r))
+}
+
+// requestTimeout prekine zahteve, ki trajajo dlje od timeout. Tokovi dogodkov pod /api/v1/stream
+// so izvzeti, saj ostanejo odprti, dokler se odjemalec ne odjavi
+func requestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withTimeout := middleware.Timeout(timeout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/api/v1/stream/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withTimeout.ServeHTTP(w, r)
+		})
+	}
+}
+
+// auditActor vrne izvajalca spremembe za revizijsko sled: uporabnika u in ID trenutne zahteve
+func auditActor(r *http.Request, u *biolog.User) biolog.Actor {
+	return biolog.Actor{User: *u.ID, RequestID: middleware.GetReqID(r.Context())}
+}
+
+// SensitivityPolicy prebere pravilo za obcutljive vrste, dodatne vrste so v konfiguraciji pod privacy.sensitive-species
+func SensitivityPolicy() biolog.SensitivityPolicy {
+	var species []int
+	for _, key := range viper.GetStringSlice("privacy.sensitive-species") {
+		gbifKey, err := strconv.Atoi(key)
+		if err != nil {
+			log.Warn("Invalid GBIF key in privacy.sensitive-species: ", key)
+			continue
+		}
+		species = append(species, gbifKey)
+	}
+	return biolog.DefaultSensitivityPolicy(species)
+}
+
 // Vzeto iz https://skarlso.github.io/2016/06/12/google-signin-with-go/,
 // preveri za state odgovora in zahteve, kar zasciti pred CSRF napadi
 func (h *Handler) getLoginURL(state string) string {
@@ -244,49 +456,36 @@ func (h *Handler) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) {
 		Token string `json:"token"`
 	}{}
 	if err := decoder.Decode(&tokStr); err != nil {
+		metrics.Logins.WithLabelValues("google", "failure").Inc()
 		respondWithError(w, 400, "Please include a token in the request body")
+		return
 	}
 
 	// Parsaj token, hkrati se preveri tudi Google podpis
 	tok, err := jwt.Parse(tokStr.Token, func(token *jwt.Token) (interface{}, error) {
-		// Get the Google certificates
-		// TODO: -cache the certificate for the specified time
-		resp, err := http.Get("https://www.googleapis.com/oauth2/v3/certs")
+		// Javni kljuci Google se hranijo do izteka, ki ga doloci Google
+		kid, _ := token.Header["kid"].(string)
+		v, err := googleKeys.key(r.Context(), kid)
 		if err != nil {
 			return nil, err
 		}
 
-		// Prebere telo odgovora v keys objekt
-		defer resp.Body.Close()
-		var keys map[string][]GoogleKey
-		decoder = json.NewDecoder(resp.Body)
-		if err := decoder.Decode(&keys); err != nil {
-			return nil, err
-		}
-
-		// Preglej kateri 'kid' se ujema z nasim tokecom in na podlagi
-		// N (modulus) in E (eksponent) zgradi javni kljuc
-		for _, v := range keys["keys"] {
-			if v.Kid == token.Header["kid"] {
-				pubKey := &rsa.PublicKey{N: new(big.Int), E: 0}
-				// FIXME: ne preverja za napake pri dekodiranju
-				nByte, _ := base64.RawURLEncoding.DecodeString(v.N)
-				eData, _ := base64.RawURLEncoding.DecodeString(v.E)
-				eBig := new(big.Int)
-				eBig.SetBytes(eData)
-				pubKey.E = int(eBig.Int64())
-				pubKey.N.SetBytes(nByte)
-
-				return pubKey, nil
-			}
-		}
-
-		// 'kid' v tokenu se ni ujemal z nobenim
-		return nil, errors.New("Google kid and token kid do not match")
+		// Na podlagi N (modulus) in E (eksponent) zgradi javni kljuc
+		pubKey := &rsa.PublicKey{N: new(big.Int), E: 0}
+		// FIXME: ne preverja za napake pri dekodiranju
+		nByte, _ := base64.RawURLEncoding.DecodeString(v.N)
+		eData, _ := base64.RawURLEncoding.DecodeString(v.E)
+		eBig := new(big.Int)
+		eBig.SetBytes(eData)
+		pubKey.E = int(eBig.Int64())
+		pubKey.N.SetBytes(nByte)
+
+		return pubKey, nil
 	})
 	// Preveri ce je prislo do napake med parsanjem
 	if err != nil {
 		log.Error("Problem Google tokeca: ", err)
+		metrics.Logins.WithLabelValues("google", "failure").Inc()
 		respondWithError(w, 400, "Problem pri parsanju Google tokeca")
 		return
 	}
@@ -305,7 +504,7 @@ func (h *Handler) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) {
 		gu.Picture = claims["picture"].(string)
 
 		// Preveri ali uporabnik obstaja (unique email)
-		u, err = h.UserHandler.UserService.UserByEmail(gu.Email)
+		u, err = userService(r, h.UserHandler.UserService).UserByEmail(gu.Email)
 		if err != nil {
 			// Prislo je do napake, ali pa uporabnik ne obstaja
 			if err.Error() == "Not found" {
@@ -320,12 +519,17 @@ func (h *Handler) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) {
 					Picture:              &gu.Picture,
 					ExternalAuthProvider: &googleAuth,
 				}
-				u, err = h.UserHandler.UserService.CreateUser(*u)
+				u, err = userService(r, h.UserHandler.UserService).CreateUser(*u)
 				if err != nil {
 					log.Error("Uporabnika ni bilo mogoce kreirati: ", err)
 					respondWithError(w, http.StatusInternalServerError, "Napaka pri kreiranju uporabnika")
 					return
 				}
+			} else if err.Error() == biolog.DeletedAccountError {
+				// Izbrisan racun lahko do trajne odstranitve obnovi le administrator
+				metrics.Logins.WithLabelValues("google", "failure").Inc()
+				respondWithError(w, http.StatusForbidden, "Uporabniski racun je izbrisan, obnovi ga lahko administrator")
+				return
 			} else {
 				// Prislo je do druge napake pri iskanju uporabnika
 				log.Error("Iskanje uporabnika po emailu:", err)
@@ -334,6 +538,7 @@ func (h *Handler) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	} else {
+		metrics.Logins.WithLabelValues("google", "failure").Inc()
 		respondWithError(w, 400, "Google tokec ni veljaven")
 		return
 	}
@@ -359,6 +564,7 @@ func (h *Handler) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) {
 	ssJSON, _ := json.Marshal(map[string]string{"token": ss})
 
 	// Odgovori z JWT v telesu zahtevka
+	metrics.Logins.WithLabelValues("google", "success").Inc()
 	w.WriteHeader(http.StatusOK)
 	w.Write(ssJSON)
 }
@@ -383,13 +589,16 @@ func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
 	sc, err := r.Cookie("originalState")
 	if err != nil || sc.Value != r.FormValue("state") {
 		// Stanje se ne ujema ali pa je prislo do napake, odgovori z 401
-		log.Error(err.Error())
+		log.Error("Neveljavno stanje v odgovoru: ", err)
+		metrics.Logins.WithLabelValues("google", "failure").Inc()
 		http.Error(w, "Neveljavno stanje v odgovoru", http.StatusUnauthorized)
 		return
 	}
 
 	// Zamenjaj avtorizacijsko kodo pridobljeno iz prvotne preusmeritve za Token, s katerim lahko pridobimo podrobnosti o uporabniku
-	tok, err := h.OAuthConf.Exchange(oauth2.NoContext, r.FormValue("code"))
+	// Klici Google gredo preko googleClient, da so spani otroci zahteve
+	ctx := context.WithValue(r.Context(), oauth2.HTTPClient, googleClient)
+	tok, err := h.OAuthConf.Exchange(ctx, r.FormValue("code"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
@@ -401,8 +610,13 @@ func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Preko klienta poslji zahtevek s tokenom na naslov za pridobivanje osnovnih podatkov o uporabniku
-	client := h.OAuthConf.Client(oauth2.NoContext, tok)
-	userResponse, err := client.Get("https://www.googleapis.com/oauth2/v3/userinfo")
+	client := h.OAuthConf.Client(ctx, tok)
+	userRequest, err := http.NewRequest("GET", "https://www.googleapis.com/oauth2/v3/userinfo", nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	userResponse, err := client.Do(userRequest.WithContext(ctx))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
@@ -418,7 +632,7 @@ func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Preveri ali uporabnik ze obstaja (preko unique emaila), ce ne ga shrani
 	var u *biolog.User
-	u, err = h.UserHandler.UserService.UserByEmail(gu.Email)
+	u, err = userService(r, h.UserHandler.UserService).UserByEmail(gu.Email)
 
 	if err != nil {
 		if err.Error() == "Not found" {
@@ -433,11 +647,15 @@ func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
 				Picture:              &gu.Picture,
 				ExternalAuthProvider: &googleAuth,
 			}
-			u, err = h.UserHandler.UserService.CreateUser(*u)
+			u, err = userService(r, h.UserHandler.UserService).CreateUser(*u)
 			if err != nil {
 				log.Error("Uporabnika ni bilo mogoce kreirati: ", err)
 				respondWithError(w, http.StatusInternalServerError, "Napaka pri kreiranju uporabnika")
 			}
+		} else if err.Error() == biolog.DeletedAccountError {
+			metrics.Logins.WithLabelValues("google", "failure").Inc()
+			respondWithError(w, http.StatusForbidden, "Uporabniski racun je izbrisan, obnovi ga lahko administrator")
+			return
 		} else {
 			log.Error(err.Error())
 			respondWithError(w, http.StatusInternalServerError, "Neznana napaka pri kreiranju uporabnika")
@@ -459,6 +677,7 @@ func (h *Handler) AuthHandler(w http.ResponseWriter, r *http.Request) {
 	ssJSON, _ := json.Marshal(map[string]string{"token": ss})
 
 	// Odgovori z JWT v telesu zahtevka
+	metrics.Logins.WithLabelValues("google", "success").Inc()
 	w.WriteHeader(http.StatusOK)
 	w.Write(ssJSON)
 	// TODO:
@@ -484,17 +703,31 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 		// Token loci od polja 'Bearer ' in ga sparsaj
 		reqAuth := r.Header.Get("Authorization")
 		if reqAuth == "" {
+			metrics.JWTFailures.WithLabelValues("missing").Inc()
 			respondWithError(w, 400, "Authorization header missing on the request")
 			return
 		}
-		tokStr := strings.Split(reqAuth, "Bearer ")[1]
+		// Glava mora biti oblike 'Bearer <token>'
+		if !strings.HasPrefix(reqAuth, "Bearer ") {
+			metrics.JWTFailures.WithLabelValues("malformed").Inc()
+			respondWithError(w, http.StatusBadRequest, "Tokec ni veljavne oblike")
+			return
+		}
+		tokStr := strings.TrimPrefix(reqAuth, "Bearer ")
 		token, err := jwt.ParseWithClaims(tokStr, &EmailClaims{}, func(token *jwt.Token) (interface{}, error) {
 			return []byte(viper.GetString("jwt.key")), nil
 		})
 
-		if claims, ok := token.Claims.(*EmailClaims); ok && token.Valid {
+		// Pri tokenu napacne oblike parser ne vrne tokena
+		var claims *EmailClaims
+		if token != nil {
+			claims, _ = token.Claims.(*EmailClaims)
+		}
+
+		if claims != nil && token.Valid {
 			// Token je veljaven, prav tako smo iz Claims pridobili Email uporabnika ki prozi zahtevo
 			if claims.Email == "" {
+				metrics.JWTFailures.WithLabelValues("no_email").Inc()
 				respondWithError(w, http.StatusBadRequest, "Tokec nima polja email")
 				return
 			}
@@ -507,22 +740,27 @@ func JWTAuthMiddleware(next http.Handler) http.Handler {
 
 			if ve.Errors&jwt.ValidationErrorMalformed != 0 {
 				// Token ni pravilne oblike
+				metrics.JWTFailures.WithLabelValues("malformed").Inc()
 				respondWithError(w, http.StatusBadRequest, "Tokec ni veljavne oblike")
 
 			} else if ve.Errors&(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) != 0 {
 				// Token je bodisi potekel, ali pa se ni veljaven
+				metrics.JWTFailures.WithLabelValues("expired").Inc()
 				respondWithError(w, http.StatusBadRequest, "Tokec vam je potekel")
 
 			} else if ve.Errors&(jwt.ValidationErrorSignatureInvalid) != 0 {
 				// Token nima veljavnega podpisa (nekdo ga je spreminjal)
+				metrics.JWTFailures.WithLabelValues("signature").Inc()
 				respondWithError(w, http.StatusBadRequest, "Tokec nima veljavnega podpisa")
 
 			} else {
 				log.Info("Something is wrong with the JWT token:", err)
+				metrics.JWTFailures.WithLabelValues("invalid").Inc()
 				respondWithError(w, http.StatusBadRequest, "Napaka pri obdelavi tokeca")
 			}
 		} else {
 			log.Info("Couldn't handle this JWT token:", err)
+			metrics.JWTFailures.WithLabelValues("invalid").Inc()
 			respondWithError(w, http.StatusBadRequest, "Napaka pri obdelavi tokeca")
 		}
 	})
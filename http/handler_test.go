@@ -0,0 +1,129 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/rubinda/biolog"
+	biohttp "github.com/rubinda/biolog/http"
+	"github.com/rubinda/biolog/mock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// Kljuc za podpisovanje JWT v testih in email prijavljenega uporabnika
+const (
+	testJWTKey = "biolog-test-key"
+	testEmail  = "river.tam@fakemail.com"
+)
+
+func TestMain(m *testing.M) {
+	// JWTAuthMiddleware kljuc prebere iz konfiguracije ob vsaki zahtevi
+	viper.Set("jwt.key", testJWTKey)
+	os.Exit(m.Run())
+}
+
+// newTestHandler ustvari root handler z mock servici, ki jih test nato napolni s funkcijami
+func newTestHandler() (*biohttp.Handler, *mock.UserService, *mock.SpeciesService) {
+	// Avtorji opazanj privzeto dovolijo javen dostop do svojih opazanj
+	us := &mock.UserService{UserFn: func(id int) (*biolog.User, error) { return testOwner(id), nil }}
+	ss := &mock.SpeciesService{}
+	return biohttp.NewRootHandler(us, ss, &mock.RegionService{}, &mock.AuditService{}, &mock.WebhookService{},
+		&mock.ObservationStream{}), us, ss
+}
+
+// testOwner vrne uporabnika z ID id, ki dovoli javen dostop do svojih opazanj
+func testOwner(id int) *biolog.User {
+	u, public := testUser(), true
+	u.ID, u.PublicObservations = &id, &public
+	return u
+}
+
+// newToken podpise JWT s podanim emailom, casom poteka in kljucem
+func newToken(email string, expiresAt int64, key string) string {
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, &biohttp.EmailClaims{
+		Email: email,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt,
+			Issuer:    "biolog-app",
+		},
+	})
+	ss, _ := tok.SignedString([]byte(key))
+	return ss
+}
+
+// validToken vrne veljaven JWT za testnega uporabnika
+func validToken() string {
+	return newToken(testEmail, time.Now().Unix()+3600, testJWTKey)
+}
+
+// doRequest poslje zahtevo na handler, pri cemer je auth vrednost glave Authorization (prazna ce je ni)
+func doRequest(h http.Handler, method, path, body, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, apiPrefix+path, strings.NewReader(body))
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestJWTAuthMiddleware preveri vse poti skozi preverjanje JWT tokena
+func TestJWTAuthMiddleware(t *testing.T) {
+	h, us, ss := newTestHandler()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	ss.DeleteObservationFn = func(id int, by biolog.Actor) error { return nil }
+
+	cases := []struct {
+		Name    string
+		Auth    string
+		Code    int
+		Message string
+	}{
+		{"missing header", "", http.StatusBadRequest, "Authorization header missing"},
+		{"not bearer", "Basic dXNlcjpwYXNz", http.StatusBadRequest, "Tokec ni veljavne oblike"},
+		{"malformed", "Bearer not-a-token", http.StatusBadRequest, "Tokec ni veljavne oblike"},
+		{"expired", "Bearer " + newToken(testEmail, time.Now().Unix()-60, testJWTKey), http.StatusBadRequest, "Tokec vam je potekel"},
+		{"bad signature", "Bearer " + newToken(testEmail, time.Now().Unix()+3600, "other-key"), http.StatusBadRequest, "Tokec nima veljavnega podpisa"},
+		{"no email", "Bearer " + newToken("", time.Now().Unix()+3600, testJWTKey), http.StatusBadRequest, "Tokec nima polja email"},
+		{"valid", "Bearer " + validToken(), http.StatusNoContent, ""},
+	}
+	for _, c := range cases {
+		rec := doRequest(h, "DELETE", "/species/observations/1", "", c.Auth)
+		assert.Equal(t, c.Code, rec.Code, c.Name)
+		assert.Contains(t, rec.Body.String(), c.Message, c.Name)
+	}
+}
+
+// TestGoogleLoginHandler preveri napake pri prijavi z Google tokenom (brez klicev na Google)
+func TestGoogleLoginHandler(t *testing.T) {
+	h, _, _ := newTestHandler()
+
+	rec := doRequest(h, "POST", "/login/google", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Please include a token")
+
+	rec = doRequest(h, "POST", "/login/google", `{"token": "not-a-token"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Problem pri parsanju Google tokeca")
+}
+
+// TestAuthHandler preveri, da callback brez ustreznega stanja zavrne zahtevo
+func TestAuthHandler(t *testing.T) {
+	h, _, _ := newTestHandler()
+
+	rec := doRequest(h, "GET", "/authenticate?state=abc&code=123", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest("GET", apiPrefix+"/authenticate?state=abc&code=123", nil)
+	req.AddCookie(&http.Cookie{Name: "originalState", Value: "other"})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// IdentificationID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezen predlog vrste
+// swagger:parameters withdrawIdentification
+type IdentificationID struct {
+	// in: path
+	// required: true
+	ID int `json:"identificationID"`
+}
+
+// IdentificationBodyParams model.
+//
+// Podatki o predlogu vrste v telesu zahtevka
+// swagger:parameters createIdentification
+type IdentificationBodyParams struct {
+	// in: body
+	// required: true
+	Payload *biolog.Identification `json:"identification"`
+}
+
+// GetIdentifications vrne vse predloge vrst (tudi umaknjene) za opazanje, ki ga uporabnik lahko vidi
+func (sh *SpeciesHandler) GetIdentifications(w http.ResponseWriter, r *http.Request) {
+	ob, _, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+
+	ids, err := speciesService(r, sh.SpeciesService).Identifications(*ob.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, ids)
+}
+
+// CreateIdentification shrani predlog vrste prijavljenega uporabnika za opazanje, ki ga uporabnik lahko vidi
+func (sh *SpeciesHandler) CreateIdentification(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+
+	var i biolog.Identification
+	decErr := json.NewDecoder(r.Body).Decode(&i)
+	if decErr != nil {
+		switch decErr {
+		case io.EOF:
+			respondWithError(w, http.StatusBadRequest, "Telo zahtevka pri predlogu vrste ne more biti prazno")
+		default:
+			respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		}
+		return
+	}
+	if i.Species == nil {
+		respondWithError(w, http.StatusBadRequest, "Predlog mora vsebovati vrsto")
+		return
+	}
+
+	// Predlog vedno pripada prijavljenemu uporabniku in opazanju iz poti
+	i.ID, i.Current, i.CreatedAt = nil, nil, nil
+	i.Observation = ob.ID
+	i.User = u.ID
+
+	newID, err := speciesService(r, sh.SpeciesService).CreateIdentification(&i)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newID)
+}
+
+// WithdrawIdentification umakne predlog vrste, to lahko stori le avtor predloga na opazanju, ki ga vidi
+func (sh *SpeciesHandler) WithdrawIdentification(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+	id, parseErr := getIDFromURL(w, r, "identificationID")
+	if parseErr {
+		return
+	}
+
+	i, err := speciesService(r, sh.SpeciesService).Identification(id)
+	if err != nil || *i.Observation != *ob.ID {
+		respondWithError(w, http.StatusNotFound, "Identifikacija za to opazanje ne obstaja")
+		return
+	}
+	if *u.ID != *i.User {
+		respondWithError(w, http.StatusForbidden, "Predlog lahko umakne le avtor")
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).WithdrawIdentification(id); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,83 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdentifications preveri pridobivanje in kreiranje predlogov vrst
+func TestIdentifications(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	ss.IdentificationsFn = func(observationID int) ([]biolog.Identification, error) {
+		assert.Equal(t, 1, observationID)
+		return []biolog.Identification{}, nil
+	}
+	ss.CreateIdentificationFn = func(i *biolog.Identification) (*biolog.Identification, error) {
+		// Uporabnik in opazanje se vzameta iz tokena in poti, ne iz telesa
+		assert.Equal(t, 1, *i.Observation)
+		assert.Equal(t, 10000000, *i.User)
+		assert.Equal(t, 5231190, *i.Species)
+		return i, nil
+	}
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1/identifications", "", auth).Code)
+
+	body := `{"species": 5231190, "user": 10000005, "observation": 7}`
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/observations/1/identifications", body, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/identifications", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/identifications", "{}", auth).Code)
+
+	ss.CreateIdentificationFn = func(i *biolog.Identification) (*biolog.Identification, error) {
+		return nil, errors.New("Opazanje s tem ID ne obstaja")
+	}
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations/1/identifications", body, auth).Code)
+
+	// Zasebnega opazanja drugega uporabnika ni mogoce videti ne predlagati vrste zanj
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) {
+		ob, private, other := testObservation(), false, 10000001
+		ob.PublicVisibility, ob.User = &private, &other
+		return ob, nil
+	}
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "GET", "/species/observations/1/identifications", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/species/observations/1/identifications", body, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/species/observations/1/identifications/2", "", auth).Code)
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return nil, errors.New("Not found") }
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/species/observations/1/identifications", "", auth).Code)
+}
+
+// TestWithdrawIdentification preveri, da lahko predlog umakne le avtor
+func TestWithdrawIdentification(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) {
+		ob := testObservation()
+		ob.ID = &id
+		return ob, nil
+	}
+	ss.IdentificationFn = func(id int) (*biolog.Identification, error) {
+		observation, species, author := 1, 5231190, 10000000
+		if id == 3 {
+			author = 10000001
+		}
+		return &biolog.Identification{ID: &id, Observation: &observation, User: &author, Species: &species}, nil
+	}
+	withdrawn := 0
+	ss.WithdrawIdentificationFn = func(id int) error {
+		withdrawn = id
+		return nil
+	}
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/observations/1/identifications/2", "", auth).Code)
+	assert.Equal(t, 2, withdrawn)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/species/observations/1/identifications/3", "", auth).Code)
+	// Identifikacija pripada drugemu opazanju
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/species/observations/2/identifications/2", "", auth).Code)
+}
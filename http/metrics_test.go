@@ -0,0 +1,51 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequestMetrics preveri stetje zahtev po vzorcu poti, zavrnjenih JWT tokenov in novih opazanj
+func TestRequestMetrics(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserFn = func(id int) (*biolog.User, error) { return testUser(), nil }
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.CreateObservationFn = func(o *biolog.Observation) (*biolog.Observation, error) { return testObservation(), nil }
+
+	// Identifikator v poti ni del oznake
+	requests := metrics.HTTPRequests.WithLabelValues("GET", `/api/v1/users/{id:\d{8}}/`, "200")
+	before := testutil.ToFloat64(requests)
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/users/10000000", "", auth).Code)
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/users/10000001", "", auth).Code)
+	assert.Equal(t, before+2, testutil.ToFloat64(requests))
+	// Neznana pot se steje pod vzorcem API
+	notFound := metrics.HTTPRequests.WithLabelValues("GET", "/api/v1/*", "404")
+	before = testutil.ToFloat64(notFound)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/ni-poti", "", "").Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(notFound))
+
+	missing, malformed := metrics.JWTFailures.WithLabelValues("missing"), metrics.JWTFailures.WithLabelValues("malformed")
+	beforeMissing, beforeMalformed := testutil.ToFloat64(missing), testutil.ToFloat64(malformed)
+	doRequest(h, "GET", "/users/10000000", "", "")
+	doRequest(h, "GET", "/users/10000000", "", "Bearer x")
+	assert.Equal(t, beforeMissing+1, testutil.ToFloat64(missing))
+	assert.Equal(t, beforeMalformed+1, testutil.ToFloat64(malformed))
+
+	created := metrics.ObservationsCreated.WithLabelValues("observation")
+	before = testutil.ToFloat64(created)
+	body, _ := json.Marshal(testObservation())
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/observations", string(body), auth).Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(created))
+
+	failures := metrics.Logins.WithLabelValues("google", "failure")
+	before = testutil.ToFloat64(failures)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/login/google", "", "").Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(failures))
+}
@@ -0,0 +1,550 @@
+package http
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// apiOperation opisuje eno operacijo (pot + metoda) v OpenAPI dokumentu.
+// Vsaka pot, ki jo registriramo na routerju, mora imeti svoj zapis v apiOperations,
+// kar preverja tudi test v openapi_test.go
+type apiOperation struct {
+	Method  string
+	Path    string
+	ID      string
+	Tag     string
+	Summary string
+
+	// Public pove, da operacija ne zahteva JWT tokena
+	Public bool
+
+	// Query parametri operacije (parametri v poti se preberejo iz Path)
+	Query []apiParam
+
+	// Parametri v poti, ki so nizi (ostali so cela stevila)
+	StringParams []string
+
+	// Primer vrednosti za telo zahtevka, iz katere se zgradi shema (nil ce telesa ni)
+	Body interface{}
+
+	// Koda uspesnega odgovora in primer vrednosti za njegovo telo (nil ce telesa ni)
+	Status   int
+	Response interface{}
+
+	// Vrsta vsebine odgovora, privzeto application/json
+	ContentType string
+}
+
+// apiParam opisuje query parameter operacije
+type apiParam struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// apiOperations je seznam vseh operacij, ki jih nudi API pod /api/v1
+var apiOperations = []apiOperation{
+	// Uporabniki
+	{Method: "GET", Path: "/users", ID: "getUsers", Tag: "users", Summary: "Pridobi vse uporabnike",
+		Status: http.StatusOK, Response: []biolog.User{}},
+	{Method: "GET", Path: "/users/me", ID: "meDetails", Tag: "users", Summary: "Pridobi podrobnosti o trenutno prijavljenem uporabniku",
+		Status: http.StatusOK, Response: biolog.User{}},
+	{Method: "GET", Path: "/users/{id}", ID: "getUserByID", Tag: "users", Summary: "Pridobi podrobnosti o uporabniku",
+		Status: http.StatusOK, Response: biolog.User{}},
+	{Method: "PATCH", Path: "/users/{id}", ID: "updateUser", Tag: "users", Summary: "Posodobi podatke o uporabniku",
+		Body: biolog.User{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/users/{id}", ID: "deleteUser", Tag: "users", Summary: "Zbrise uporabniski racun (uporabnik sam ali administrator)",
+		Status: http.StatusNoContent},
+	{Method: "POST", Path: "/users/{id}/restore", ID: "restoreUser", Tag: "users", Summary: "Obnovi izbrisan uporabniski racun (administrator)",
+		Status: http.StatusNoContent},
+	{Method: "POST", Path: "/users/{id}/erase", ID: "eraseUser", Tag: "users", Summary: "Trajno izbrise uporabniski racun in osebne podatke (uporabnik sam ali administrator)",
+		Query: eraseParams, Status: http.StatusNoContent},
+	{Method: "GET", Path: "/users/me/following", ID: "getFollowing", Tag: "users", Summary: "Pridobi uporabnike, ki jih prijavljeni uporabnik spremlja",
+		Status: http.StatusOK, Response: []biolog.User{}},
+	{Method: "POST", Path: "/users/{id}/follow", ID: "followUser", Tag: "users", Summary: "Zacne spremljati uporabnika (njegovi dogodki so v viru dejavnosti)",
+		Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/users/{id}/follow", ID: "unfollowUser", Tag: "users", Summary: "Preneha spremljati uporabnika",
+		Status: http.StatusNoContent},
+	{Method: "POST", Path: "/users/me/export", ID: "exportMe", Tag: "users", Summary: "Zacne izvoz osebnih podatkov prijavljenega uporabnika v zip datoteko",
+		Status: http.StatusAccepted, Response: biolog.Export{}},
+	{Method: "POST", Path: "/users/{id}/export", ID: "exportUser", Tag: "users", Summary: "Zacne izvoz osebnih podatkov uporabnika (uporabnik sam ali administrator)",
+		Status: http.StatusAccepted, Response: biolog.Export{}},
+	{Method: "GET", Path: "/users/auth_providers", ID: "getAuthProviders", Tag: "authproviders", Summary: "Pridobi vse mozne ponudnike avtentikacije",
+		Status: http.StatusOK, Response: []biolog.AuthProvider{}},
+	{Method: "GET", Path: "/users/auth_providers/{id}", ID: "getAuthProviderByID", Tag: "authproviders", Summary: "Pridobi podrobnosti o ponudniku avtentikacije",
+		Status: http.StatusOK, Response: biolog.AuthProvider{}},
+
+	// Vrste
+	{Method: "GET", Path: "/species", ID: "getSpecies", Tag: "species", Summary: "Pridobi vse lokalno shranjene vrste",
+		Status: http.StatusOK, Response: []biolog.Species{}},
+	{Method: "POST", Path: "/species", ID: "createSpecies", Tag: "species", Summary: "Ustvari nov zapis o vrsti",
+		Body: biolog.Species{}, Status: http.StatusCreated, Response: biolog.Species{}},
+	{Method: "GET", Path: "/species/watched", ID: "getWatchedSpecies", Tag: "species", Summary: "Pridobi vrste, ki jih prijavljeni uporabnik opazuje",
+		Status: http.StatusOK, Response: []biolog.Species{}},
+	{Method: "POST", Path: "/species/{gbifKey}/watch", ID: "watchSpecies", Tag: "species", Summary: "Zacne opazovati vrsto (njena opazanja in identifikacije so v viru dejavnosti)",
+		Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/species/{gbifKey}/watch", ID: "unwatchSpecies", Tag: "species", Summary: "Preneha opazovati vrsto",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/species/{gbifKey}", ID: "getSpeciesByGbifKey", Tag: "species", Summary: "Pridobi podrobnosti o vrsti preko GBIF kljuca (sinonim preusmeri na sprejeto vrsto)",
+		Status: http.StatusOK, Response: biolog.Species{}},
+	{Method: "PATCH", Path: "/species/{gbifKey}", ID: "updateSpecies", Tag: "species", Summary: "Posodobi podatke o shranjeni vrsti",
+		Body: biolog.Species{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/species/{gbifKey}", ID: "deleteSpecies", Tag: "species", Summary: "Zbrise shranjeno vrsto",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/species/{gbifKey}/distribution", ID: "getSpeciesDistribution", Tag: "species", Summary: "Pridobi javna opazanja vrste, zdruzena v celice mreze",
+		Query: distributionParams, Status: http.StatusOK, Response: []biolog.DistributionCell{}},
+	{Method: "POST", Path: "/species/{gbifKey}/merge", ID: "mergeSpecies", Tag: "species", Summary: "Zdruzi vrsto v sprejeto vrsto in preusmeri vsa opazanja nanjo (moderator)",
+		Body: biolog.TaxonMerge{}, Status: http.StatusCreated, Response: biolog.TaxonMerge{}},
+	{Method: "GET", Path: "/species/{gbifKey}/assessments", ID: "getAssessments", Tag: "assessments", Summary: "Pridobi vse ocene ogrozenosti vrste, vkljucno s preteklimi",
+		Status: http.StatusOK, Response: []biolog.Assessment{}},
+	{Method: "POST", Path: "/species/{gbifKey}/assessments", ID: "createAssessment", Tag: "assessments", Summary: "Doda oceno ogrozenosti vrste (moderator)",
+		Body: biolog.Assessment{}, Status: http.StatusCreated, Response: biolog.Assessment{}},
+	{Method: "PATCH", Path: "/species/{gbifKey}/assessments/{assessmentID}", ID: "updateAssessment", Tag: "assessments", Summary: "Posodobi oceno ogrozenosti (moderator)",
+		Body: biolog.Assessment{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/species/{gbifKey}/assessments/{assessmentID}", ID: "deleteAssessment", Tag: "assessments", Summary: "Zbrise oceno ogrozenosti (moderator)",
+		Status: http.StatusNoContent},
+
+	// Opazanja
+	{Method: "GET", Path: "/species/observations", ID: "getObservations", Tag: "observations", Summary: "Pridobi vsa javna opazanja",
+		Query: observationFilterParams, Status: http.StatusOK, Response: []biolog.Observation{}},
+	{Method: "POST", Path: "/species/observations", ID: "createObservation", Tag: "observations", Summary: "Ustvari nov zapis o opazeni vrsti",
+		Body: biolog.Observation{}, Status: http.StatusCreated, Response: biolog.Observation{}},
+	{Method: "GET", Path: "/species/observations/{id}", ID: "getObservationByID", Tag: "observations", Summary: "Pridobi opazanje s podanim IDjem",
+		Status: http.StatusOK, Response: biolog.Observation{}},
+	{Method: "PATCH", Path: "/species/observations/{id}", ID: "updateObservation", Tag: "observations", Summary: "Posodobi podatke o opazovalnem listu",
+		Body: biolog.Observation{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/species/observations/{id}", ID: "deleteObservation", Tag: "observations", Summary: "Zbrise podatek o opazeni vrsti (avtor ali moderator)",
+		Status: http.StatusNoContent},
+	{Method: "POST", Path: "/species/observations/{id}/restore", ID: "restoreObservation", Tag: "observations", Summary: "Obnovi izbrisan podatek o opazeni vrsti (avtor ali moderator)",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/species/observations/{id}/revisions", ID: "getObservationRevisions", Tag: "revisions", Summary: "Pridobi vse revizije opazanja, od najstarejse naprej",
+		Status: http.StatusOK, Response: []biolog.ObservationRevision{}},
+	{Method: "GET", Path: "/species/observations/{id}/revisions/{revision}", ID: "getObservationRevision", Tag: "revisions", Summary: "Pridobi doloceno revizijo opazanja",
+		Status: http.StatusOK, Response: biolog.ObservationRevision{}},
+	{Method: "POST", Path: "/species/observations/{id}/revisions/{revision}/revert", ID: "revertObservation", Tag: "revisions", Summary: "Povrne opazanje na stanje iz revizije (le avtor)",
+		Status: http.StatusNoContent},
+
+	// Identifikacije
+	{Method: "GET", Path: "/species/observations/{id}/identifications", ID: "getIdentifications", Tag: "identifications", Summary: "Pridobi vse predloge vrst za opazanje",
+		Status: http.StatusOK, Response: []biolog.Identification{}},
+	{Method: "POST", Path: "/species/observations/{id}/identifications", ID: "createIdentification", Tag: "identifications", Summary: "Predlaga vrsto za opazanje",
+		Body: biolog.Identification{}, Status: http.StatusCreated, Response: biolog.Identification{}},
+	{Method: "DELETE", Path: "/species/observations/{id}/identifications/{identificationID}", ID: "withdrawIdentification", Tag: "identifications", Summary: "Umakne predlog vrste",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/species/observations/{id}/comments", ID: "getComments", Tag: "comments", Summary: "Pridobi stran komentarjev za opazanje",
+		Query: pageParams, Status: http.StatusOK, Response: []biolog.Comment{}},
+	{Method: "POST", Path: "/species/observations/{id}/comments", ID: "createComment", Tag: "comments", Summary: "Doda komentar pod opazanje",
+		Body: biolog.Comment{}, Status: http.StatusCreated, Response: biolog.Comment{}},
+	{Method: "PATCH", Path: "/species/observations/{id}/comments/{commentID}", ID: "updateComment", Tag: "comments", Summary: "Uredi besedilo komentarja (avtor ali moderator)",
+		Body: biolog.Comment{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/species/observations/{id}/comments/{commentID}", ID: "deleteComment", Tag: "comments", Summary: "Zbrise komentar (avtor ali moderator)",
+		Status: http.StatusNoContent},
+
+	// Popisi
+	{Method: "GET", Path: "/checklists", ID: "getChecklists", Tag: "checklists", Summary: "Pridobi stran popisov prijavljenega uporabnika (brez opazanj)",
+		Query: pageParams, Status: http.StatusOK, Response: []biolog.Checklist{}},
+	{Method: "POST", Path: "/checklists", ID: "createChecklist", Tag: "checklists", Summary: "Ustvari popis skupaj z opazanji v eni transakciji",
+		Body: biolog.Checklist{}, Status: http.StatusCreated, Response: biolog.Checklist{}},
+	{Method: "GET", Path: "/checklists/{id}", ID: "getChecklistByID", Tag: "checklists", Summary: "Pridobi popis z opazanji, ki jih uporabnik lahko vidi",
+		Status: http.StatusOK, Response: biolog.Checklist{}},
+	{Method: "PATCH", Path: "/checklists/{id}", ID: "updateChecklist", Tag: "checklists", Summary: "Posodobi podatke o popisu (avtor ali moderator)",
+		Body: biolog.Checklist{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/checklists/{id}", ID: "deleteChecklist", Tag: "checklists", Summary: "Zbrise popis skupaj z opazanji (avtor ali moderator)",
+		Status: http.StatusNoContent},
+
+	// Projekti
+	{Method: "GET", Path: "/projects", ID: "getProjects", Tag: "projects", Summary: "Pridobi stran projektov (brez meje)",
+		Query: pageParams, Status: http.StatusOK, Response: []biolog.Project{}},
+	{Method: "POST", Path: "/projects", ID: "createProject", Tag: "projects", Summary: "Ustvari projekt, avtor postane njegov administrator",
+		Body: biolog.Project{}, Status: http.StatusCreated, Response: biolog.Project{}},
+	{Method: "GET", Path: "/projects/{id}", ID: "getProjectByID", Tag: "projects", Summary: "Pridobi projekt skupaj z mejo",
+		Status: http.StatusOK, Response: biolog.Project{}},
+	{Method: "PATCH", Path: "/projects/{id}", ID: "updateProject", Tag: "projects", Summary: "Posodobi projekt (administrator projekta ali moderator)",
+		Body: biolog.Project{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/projects/{id}", ID: "deleteProject", Tag: "projects", Summary: "Zbrise projekt, opazanja ostanejo (administrator projekta ali moderator)",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/projects/{id}/members", ID: "getProjectMembers", Tag: "projects", Summary: "Pridobi clane projekta",
+		Status: http.StatusOK, Response: []biolog.ProjectMember{}},
+	{Method: "POST", Path: "/projects/{id}/members", ID: "setProjectMember", Tag: "projects", Summary: "Pridruzi prijavljenega uporabnika projektu ali doda clana oz. mu spremeni vlogo",
+		Body: biolog.ProjectMember{}, Status: http.StatusNoContent},
+	{Method: "DELETE", Path: "/projects/{id}/members/{userID}", ID: "removeProjectMember", Tag: "projects", Summary: "Odstrani clana iz projekta (clan sam, administrator projekta ali moderator)",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/projects/{id}/leaderboard", ID: "getProjectLeaderboard", Tag: "projects", Summary: "Pridobi lestvico uporabnikov po stevilu opazanj v projektu",
+		Status: http.StatusOK, Response: []biolog.LeaderboardEntry{}},
+	{Method: "GET", Path: "/projects/{id}/species", ID: "getProjectSpecies", Tag: "projects", Summary: "Presteje opazanja in osebke posameznih vrst v projektu",
+		Status: http.StatusOK, Response: []biolog.ObservationStat{}},
+
+	// Statistika
+	{Method: "GET", Path: "/stats/observations", ID: "getObservationStats", Tag: "stats", Summary: "Presteje javna opazanja in sesteje kolicino osebkov po skupinah",
+		Query: statsParams, Status: http.StatusOK, Response: []biolog.ObservationStat{}},
+
+	// Vektorske ploscice
+	{Method: "GET", Path: "/tiles/observations/{z}/{x}/{y}.mvt", ID: "getObservationTile", Tag: "tiles", Summary: "Pridobi vektorsko ploscico z javnimi opazanji",
+		Public: true, Query: observationFilterParams, Status: http.StatusOK, Response: []byte{}, ContentType: "application/vnd.mapbox-vector-tile"},
+
+	// Tokovi dogodkov
+	{Method: "GET", Path: "/stream/observations", ID: "streamObservations", Tag: "stream", Summary: "Tok novih javnih opazanj (Server-Sent Events), z glavo Last-Event-ID nadaljuje za zadnjim prejetim",
+		Public: true, Query: streamParams, Status: http.StatusOK, Response: []byte{}, ContentType: "text/event-stream"},
+
+	// Obmocja
+	{Method: "GET", Path: "/regions", ID: "getRegions", Tag: "regions", Summary: "Pridobi seznam obmocij (brez geometrije)",
+		Query: regionParams, Status: http.StatusOK, Response: []biolog.Region{}},
+	{Method: "GET", Path: "/regions/{id}", ID: "getRegionByID", Tag: "regions", Summary: "Pridobi obmocje skupaj z GeoJSON geometrijo",
+		Status: http.StatusOK, Response: biolog.Region{}},
+
+	// Vir dejavnosti
+	{Method: "GET", Path: "/feed", ID: "getFeed", Tag: "feed", Summary: "Pridobi najnovejse javne dogodke spremljanih uporabnikov in opazovanih vrst",
+		Query: feedParams, Status: http.StatusOK, Response: []biolog.FeedItem{}},
+
+	// Revizijska sled
+	{Method: "GET", Path: "/audit", ID: "getAuditEntries", Tag: "audit", Summary: "Pridobi zapise revizijske sledi, od najnovejsega naprej (administrator)",
+		Query: auditParams, Status: http.StatusOK, Response: []biolog.AuditEntry{}},
+
+	// Narocnine na dogodke
+	{Method: "GET", Path: "/webhooks", ID: "getWebhooks", Tag: "webhooks", Summary: "Pridobi vse narocnine na dogodke (administrator)",
+		Status: http.StatusOK, Response: []biolog.Webhook{}},
+	{Method: "POST", Path: "/webhooks", ID: "createWebhook", Tag: "webhooks", Summary: "Ustvari narocnino, odgovor edini vsebuje skrivnost za podpise (administrator)",
+		Body: biolog.Webhook{}, Status: http.StatusCreated, Response: biolog.Webhook{}},
+	{Method: "GET", Path: "/webhooks/{id}", ID: "getWebhook", Tag: "webhooks", Summary: "Pridobi narocnino (administrator)",
+		Status: http.StatusOK, Response: biolog.Webhook{}},
+	{Method: "DELETE", Path: "/webhooks/{id}", ID: "deleteWebhook", Tag: "webhooks", Summary: "Izbrise narocnino skupaj z dnevnikom posiljanj (administrator)",
+		Status: http.StatusNoContent},
+	{Method: "GET", Path: "/webhooks/{id}/deliveries", ID: "getWebhookDeliveries", Tag: "webhooks", Summary: "Pridobi dnevnik posiljanj narocnine, od najnovejsega naprej (administrator)",
+		Query: pageParams, Status: http.StatusOK, Response: []biolog.WebhookDelivery{}},
+
+	// Izvoz osebnih podatkov
+	{Method: "GET", Path: "/exports/{exportID}", ID: "getExport", Tag: "exports", Summary: "Prenese zip datoteko izvoza osebnih podatkov (dokler je v pripravi vrne 202)",
+		Public: true, StringParams: []string{"exportID"}, Status: http.StatusOK, Response: []byte{}, ContentType: "application/zip"},
+
+	// Prijava
+	{Method: "POST", Path: "/login/google", ID: "googleLogin", Tag: "login", Summary: "Prijava z Google ID tokenom, vrne JWT",
+		Public: true, Body: struct {
+			Token string `json:"token"`
+		}{}, Status: http.StatusOK, Response: struct {
+			Token string `json:"token"`
+		}{}},
+	{Method: "GET", Path: "/authenticate", ID: "authenticate", Tag: "login", Summary: "Callback pri avtentikaciji z zunanjim ponudnikom",
+		Public: true, Status: http.StatusOK, Response: struct {
+			Token string `json:"token"`
+		}{}},
+
+	// Dokumentacija
+	{Method: "GET", Path: "/openapi.json", ID: "getOpenAPI", Tag: "docs", Summary: "OpenAPI 3 dokument za ta API",
+		Public: true, Status: http.StatusOK},
+	{Method: "GET", Path: "/docs", ID: "getDocs", Tag: "docs", Summary: "Swagger UI za ta API",
+		Public: true, Status: http.StatusOK, ContentType: "text/html"},
+	{Method: "GET", Path: "/docs/{file}", ID: "getDocsAsset", Tag: "docs", Summary: "Datoteke Swagger UI (skripte in slogi)",
+		Public: true, StringParams: []string{"file"}, Status: http.StatusOK, Response: []byte{}, ContentType: "application/javascript"},
+}
+
+// pageParams so query parametri za ostranjevanje (glej getPage)
+var pageParams = []apiParam{
+	{Name: "limit", Type: "integer", Description: "Najvecje stevilo vrnjenih zapisov (privzeto 20, najvec 100)"},
+	{Name: "offset", Type: "integer", Description: "Stevilo preskocenih zapisov"},
+}
+
+// statsParams so query parametri za statistiko opazanj (nacin zdruzevanja in filtri seznama opazanj)
+var statsParams = append([]apiParam{
+	{Name: "groupBy", Type: "string", Description: "Nacin zdruzevanja (species, month, year, user, conservationStatus, region)"},
+}, observationFilterParams...)
+
+// distributionParams so query parametri za karto razsirjenosti (vrsta je ze v poti)
+var distributionParams = []apiParam{
+	{Name: "cell", Type: "string", Description: "Vrsta mreze (geohash5 ali 10km), privzeto geohash5"},
+	{Name: "qualityGrade", Type: "string", Description: "Stopnja kakovosti (casual, needs_id, research)"},
+	{Name: "region", Type: "integer", Description: "ID obmocja, v katerem lezi opazanje"},
+	{Name: "project", Type: "integer", Description: "ID projekta, katerega pravilom ustreza opazanje"},
+}
+
+// regionParams so query parametri za seznam obmocij
+var regionParams = []apiParam{
+	{Name: "kind", Type: "string", Description: "Vrsta obmocja (npr. obcina), privzeto vse vrste"},
+}
+
+// feedParams so query parametri za ostranjevanje vira dejavnosti (s casom namesto z zamikom)
+var feedParams = []apiParam{
+	{Name: "before", Type: "string", Description: "Vrne le dogodke pred tem casom (createdAt zadnjega dogodka prejsnje strani)"},
+	{Name: "limit", Type: "integer", Description: "Najvecje stevilo vrnjenih dogodkov (privzeto 20, najvec 100)"},
+}
+
+// streamParams so query parametri za filtriranje toka novih opazanj
+var streamParams = []apiParam{
+	{Name: "species", Type: "integer", Description: "GBIF kljuc opazene vrste"},
+	{Name: "bbox", Type: "string", Description: "Pravokotnik minLon,minLat,maxLon,maxLat, v katerem morajo lezati opazanja"},
+}
+
+// eraseParams so query parametri za trajni izbris uporabniskega racuna
+var eraseParams = []apiParam{
+	{Name: "observations", Type: "string", Description: "Javna opazanja se ohranijo pod anonimnim uporabnikom (keep) ali zbrisejo (delete)"},
+}
+
+// auditParams so query parametri za branje revizijske sledi (filter in ostranjevanje)
+var auditParams = append([]apiParam{
+	{Name: "entityType", Type: "string", Description: "Vrsta zapisa (species, observation ali user)"},
+	{Name: "entityID", Type: "integer", Description: "Identifikator zapisa"},
+	{Name: "actor", Type: "integer", Description: "Uporabnik, ki je naredil spremembo"},
+}, pageParams...)
+
+// observationFilterParams so query parametri za filtriranje opazanj (glej biolog.ObservationFilter)
+var observationFilterParams = []apiParam{
+	{Name: "qualityGrade", Type: "string", Description: "Stopnja kakovosti (casual, needs_id, research)"},
+	{Name: "species", Type: "integer", Description: "GBIF kljuc opazene vrste"},
+	{Name: "region", Type: "integer", Description: "ID obmocja, v katerem lezi opazanje"},
+	{Name: "project", Type: "integer", Description: "ID projekta, katerega pravilom ustreza opazanje"},
+}
+
+// Poisce parametre v poti, npr. {gbifKey}
+var pathParamRegexp = regexp.MustCompile(`{([^}]+)}`)
+
+// openAPIDocument zgradi OpenAPI 3 dokument iz seznama apiOperations
+func openAPIDocument() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+
+	for _, op := range apiOperations {
+		item, ok := paths[op.Path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[op.Path] = item
+		}
+
+		var params []interface{}
+		for _, m := range pathParamRegexp.FindAllStringSubmatch(op.Path, -1) {
+			paramType := "integer"
+			for _, name := range op.StringParams {
+				if name == m[1] {
+					paramType = "string"
+				}
+			}
+			params = append(params, map[string]interface{}{
+				"name":     m[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": paramType},
+			})
+		}
+		for _, q := range op.Query {
+			params = append(params, map[string]interface{}{
+				"name":        q.Name,
+				"in":          "query",
+				"description": q.Description,
+				"schema":      map[string]interface{}{"type": q.Type},
+			})
+		}
+
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success := map[string]interface{}{"description": http.StatusText(op.Status)}
+		if op.Response != nil {
+			schema := schemaFor(reflect.TypeOf(op.Response), schemas)
+			// Binarni odgovor (npr. vektorska ploscica) ni kodiran z base64
+			if _, ok := op.Response.([]byte); ok && contentType != "application/json" {
+				schema = map[string]interface{}{"type": "string", "format": "binary"}
+			}
+			success["content"] = map[string]interface{}{contentType: map[string]interface{}{"schema": schema}}
+		}
+		responses := map[string]interface{}{
+			strconv.Itoa(op.Status): success,
+			"400":                   map[string]interface{}{"$ref": "#/components/responses/error"},
+			// Vse zahteve so lahko omejene (glej RateLimits)
+			"429": map[string]interface{}{"$ref": "#/components/responses/tooManyRequests"},
+		}
+
+		operation := map[string]interface{}{
+			"operationId": op.ID,
+			"tags":        []string{op.Tag},
+			"summary":     op.Summary,
+			"responses":   responses,
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Body != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(op.Body), schemas)},
+				},
+			}
+		}
+		if op.Public {
+			operation["security"] = []interface{}{}
+		} else {
+			responses["401"] = map[string]interface{}{"$ref": "#/components/responses/error"}
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Biolog API",
+			"description": "Aplikacija nudi podporo pri popisu vrst po Sloveniji",
+			"version":     "1.0.0",
+			"contact": map[string]interface{}{
+				"name":  "David Rubin",
+				"email": "david.rubin95@gmail.com",
+			},
+		},
+		"servers":  []interface{}{map[string]interface{}{"url": "/api/v1"}},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+			"responses": map[string]interface{}{
+				"tooManyRequests": map[string]interface{}{
+					"description": "Prevec zahtev, ponovni poskus je mozen po Retry-After sekundah",
+					"headers": map[string]interface{}{
+						"Retry-After": map[string]interface{}{"schema": map[string]interface{}{"type": "integer"}},
+					},
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"type":       "object",
+								"properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+							},
+						},
+					},
+				},
+				"error": map[string]interface{}{
+					"description": "Prislo je do napake",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"type":       "object",
+								"properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// schemaFor preko reflect zgradi JSON shemo za podan tip. Poimenovane strukture
+// (npr. biolog.Species) se shranijo v schemas in na njih se sklicujemo z $ref
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == reflect.TypeOf(json.RawMessage{}):
+		// Poljuben JSON (npr. GeoJSON geometrija)
+		return map[string]interface{}{"type": "object"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case t.Kind() == reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := strings.ToLower(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := schemas[name]; !ok {
+			// Zasede ime pred rekurzijo, da se izognemo neskoncni zanki pri gnezdenih tipih
+			schemas[name] = nil
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema zgradi objektno shemo iz polj strukture, imena polj vzame iz json tagov
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	props := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		// Vgnezdene strukture brez imena polja prispevajo svoja polja
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range structSchema(f.Type, schemas)["properties"].(map[string]interface{}) {
+				props[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = schemaFor(f.Type, schemas)
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// Swagger UI (swagger-ui-dist 5.18.2, licenca Apache 2.0) je vkljucen v program, da dokumentacija ne
+// nalaga skript iz zunanjih streznikov
+//
+//go:embed swaggerui/swagger-ui-bundle.js swaggerui/swagger-ui.css
+var swaggerAssets embed.FS
+
+// swaggerUI je HTML stran, ki nalozi Swagger UI iz /api/v1/docs/ in mu poda nas OpenAPI dokument
+const swaggerUI = `<!DOCTYPE html>
+<html lang="sl">
+<head>
+  <meta charset="utf-8">
+  <title>Biolog API</title>
+  <link rel="stylesheet" href="docs/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="docs/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// OpenAPIHandler vrne OpenAPI 3 dokument, ki opisuje ta API
+func (h *Handler) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, openAPIDocument())
+}
+
+// DocsHandler vrne Swagger UI, ki prikazuje dokument iz OpenAPIHandler
+func (h *Handler) DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(swaggerUI))
+}
+
+// DocsAssetsHandler servira datoteke Swagger UI, ki so vkljucene v program
+func (h *Handler) DocsAssetsHandler(w http.ResponseWriter, r *http.Request) {
+	assets, _ := fs.Sub(swaggerAssets, "swaggerui")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.StripPrefix("/api/v1/docs/", http.FileServer(http.FS(assets))).ServeHTTP(w, r)
+}
@@ -0,0 +1,159 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	biohttp "github.com/rubinda/biolog/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// Predpona, pod katero so registrirane vse poti API
+const apiPrefix = "/api/v1"
+
+// Parametri v poti, ki jih pri zahtevkih zamenjamo z veljavno vrednostjo
+var pathParamValues = regexp.MustCompile(`{[^}]+}`)
+
+// normalizeRoute pretvori vzorec poti iz chi v obliko, ki jo uporablja OpenAPI dokument.
+// Odstrani predpono API, '/*' od vgnezdenih routerjev, regex pri parametrih ({id:\d{8}} -> {id})
+// in koncno posevnico
+func normalizeRoute(route string) string {
+	route = strings.TrimPrefix(route, apiPrefix)
+	route = strings.Replace(route, "/*", "", -1)
+
+	var b strings.Builder
+	depth := 0
+	skip := false
+	for _, c := range route {
+		switch {
+		case c == '{':
+			depth++
+			if depth == 1 {
+				b.WriteRune(c)
+				continue
+			}
+		case c == '}':
+			depth--
+			if depth == 0 {
+				skip = false
+				b.WriteRune(c)
+				continue
+			}
+		case c == ':' && depth == 1:
+			skip = true
+		}
+		if !skip {
+			b.WriteRune(c)
+		}
+	}
+
+	route = strings.TrimSuffix(b.String(), "/")
+	if route == "" {
+		return "/"
+	}
+	return route
+}
+
+// getOpenAPIDocument pridobi dokument preko HTTP, tako kot bi ga odjemalec
+func getOpenAPIDocument(t *testing.T, h http.Handler) map[string]interface{} {
+	req := httptest.NewRequest("GET", apiPrefix+"/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		t.FailNow()
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal("OpenAPI dokument ni veljaven JSON: ", err)
+	}
+	return doc
+}
+
+// TestOpenAPIMatchesRoutes preveri, da ima vsaka registrirana pot zapis v OpenAPI dokumentu
+// in da za vsako pot v dokumentu obstaja tudi pot na routerju
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	h := biohttp.NewRootHandler(nil, nil, nil, nil, nil, nil)
+	doc := getOpenAPIDocument(t, h)
+	paths := doc["paths"].(map[string]interface{})
+
+	routed := make(map[string]bool)
+	walkErr := chi.Walk(h, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		path := normalizeRoute(route)
+		method = strings.ToLower(method)
+		routed[method+" "+path] = true
+
+		item, ok := paths[path].(map[string]interface{})
+		if !assert.True(t, ok, "Pot %s nima zapisa v OpenAPI dokumentu", path) {
+			return nil
+		}
+		op, ok := item[method].(map[string]interface{})
+		if !assert.True(t, ok, "Operacija %s %s nima zapisa v OpenAPI dokumentu", method, path) {
+			return nil
+		}
+
+		// Operacije z varnostnimi zahtevami morajo biti za JWTAuthMiddleware
+		if _, public := op["security"]; !public {
+			req := httptest.NewRequest(strings.ToUpper(method), apiPrefix+pathParamValues.ReplaceAllString(path, "10000000"), nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Contains(t, rec.Body.String(), "Authorization header missing", "Operacija %s %s ni zascitena z JWT", method, path)
+		}
+		return nil
+	})
+	assert.NoError(t, walkErr)
+
+	for path, item := range paths {
+		for method := range item.(map[string]interface{}) {
+			assert.True(t, routed[method+" "+path], "Operacija %s %s iz OpenAPI dokumenta nima poti", method, path)
+		}
+	}
+}
+
+// TestOpenAPISchemas preveri, da se vse reference v dokumentu nanasajo na obstojece sheme
+func TestOpenAPISchemas(t *testing.T) {
+	doc := getOpenAPIDocument(t, biohttp.NewRootHandler(nil, nil, nil, nil, nil, nil))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	components := doc["components"].(map[string]interface{})
+	schemas := components["schemas"].(map[string]interface{})
+	for _, name := range []string{"user", "species", "observation", "authProvider"} {
+		assert.Contains(t, schemas, name)
+	}
+
+	raw, _ := json.Marshal(doc)
+	for _, part := range strings.Split(string(raw), `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.Index(part, `"`)]
+		assert.Contains(t, schemas, name, "Referenca na neobstojeco shemo")
+	}
+}
+
+// TestDocs preveri, da se Swagger UI servira brez JWT
+func TestDocs(t *testing.T) {
+	req := httptest.NewRequest("GET", apiPrefix+"/docs", nil)
+	rec := httptest.NewRecorder()
+	biohttp.NewRootHandler(nil, nil, nil, nil, nil, nil).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "openapi.json")
+	assert.NotContains(t, rec.Body.String(), "https://", "Swagger UI se ne sme nalagati z zunanjih streznikov")
+
+	// Skripte in slogi so vkljuceni v program
+	for _, file := range []string{"swagger-ui-bundle.js", "swagger-ui.css"} {
+		req = httptest.NewRequest("GET", apiPrefix+"/docs/"+file, nil)
+		rec = httptest.NewRecorder()
+		biohttp.NewRootHandler(nil, nil, nil, nil, nil, nil).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, file)
+		assert.NotEmpty(t, rec.Body.Len(), file)
+	}
+	req = httptest.NewRequest("GET", apiPrefix+"/docs/ni-datoteke.js", nil)
+	rec = httptest.NewRecorder()
+	biohttp.NewRootHandler(nil, nil, nil, nil, nil, nil).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
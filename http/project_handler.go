@@ -0,0 +1,482 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// ProjectHandler je http handler za projekte (bioblitz, atlasi) in njihove clane
+type ProjectHandler struct {
+	SpeciesService biolog.SpeciesService
+	// UserService se uporablja za iskanje prijavljenega uporabnika
+	UserService biolog.UserService
+	// Sensitivity doloca vrste, katerih opazanja se v meje projekta stejejo po posplosenih lokacijah
+	Sensitivity biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// ProjectID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezen projekt
+// swagger:parameters getProjectByID updateProject deleteProject getProjectMembers setProjectMember getProjectLeaderboard getProjectSpecies
+type ProjectID struct {
+	// in: path
+	// required: true
+	ID int `json:"id"`
+}
+
+// ProjectMemberID parameter model.
+//
+// Uporabnik, ki se odstrani iz projekta
+// swagger:parameters removeProjectMember
+type ProjectMemberID struct {
+	// in: path
+	// required: true
+	ID int `json:"id"`
+
+	// in: path
+	// required: true
+	UserID int `json:"userID"`
+}
+
+// ProjectBodyParams model.
+//
+// Projekt v telesu zahtevka
+// swagger:parameters createProject updateProject
+type ProjectBodyParams struct {
+	// in: body
+	// required: true
+	Payload *biolog.Project `json:"project"`
+}
+
+// ProjectMemberBodyParams model.
+//
+// Clan projekta v telesu zahtevka (uporabnik je privzeto prijavljen uporabnik, vloga privzeto member)
+// swagger:parameters setProjectMember
+type ProjectMemberBodyParams struct {
+	// in: body
+	Payload *biolog.ProjectMember `json:"member"`
+}
+
+// NewProjectHandler kreira novega handlerja za projekte
+func NewProjectHandler() *ProjectHandler {
+	ph := &ProjectHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/projects
+
+	// swagger:route GET /projects projects getProjects
+	//
+	// Pridobi stran projektov (brez meje)
+	//
+	// Responses:
+	//		200: []project
+	ph.Get("/", ph.GetProjects)
+
+	// swagger:route POST /projects projects createProject
+	//
+	// Ustvari projekt, avtor postane njegov administrator
+	//
+	// Responses:
+	//		201: project
+	ph.Post("/", ph.CreateProject)
+
+	ph.Route("/{id:[0-9]+}", func(r chi.Router) {
+		// swagger:route GET /projects/{id} projects getProjectByID
+		//
+		// Pridobi projekt skupaj z mejo
+		//
+		// Responses:
+		//		200: project
+		r.Get("/", ph.GetProjectByID)
+
+		// swagger:route PATCH /projects/{id} projects updateProject
+		//
+		// Posodobi projekt (administrator projekta ali moderator)
+		//
+		// Responses:
+		//		204:
+		r.Patch("/", ph.UpdateProject)
+
+		// swagger:route DELETE /projects/{id} projects deleteProject
+		//
+		// Zbrise projekt, opazanja ostanejo (administrator projekta ali moderator)
+		//
+		// Responses:
+		//		204:
+		r.Delete("/", ph.DeleteProject)
+
+		// swagger:route GET /projects/{id}/members projects getProjectMembers
+		//
+		// Pridobi clane projekta
+		//
+		// Responses:
+		//		200: []projectMember
+		r.Get("/members", ph.GetProjectMembers)
+
+		// swagger:route POST /projects/{id}/members projects setProjectMember
+		//
+		// Pridruzi prijavljenega uporabnika projektu ali (kot administrator projekta) doda clana oz. mu spremeni vlogo
+		//
+		// Responses:
+		//		204:
+		r.Post("/members", ph.SetProjectMember)
+
+		// swagger:route DELETE /projects/{id}/members/{userID} projects removeProjectMember
+		//
+		// Odstrani clana iz projekta (clan sam, administrator projekta ali moderator)
+		//
+		// Responses:
+		//		204:
+		r.Delete("/members/{userID:[0-9]+}", ph.RemoveProjectMember)
+
+		// swagger:route GET /projects/{id}/leaderboard projects getProjectLeaderboard
+		//
+		// Pridobi lestvico uporabnikov po stevilu opazanj v projektu
+		//
+		// Responses:
+		//		200: []leaderboardEntry
+		r.Get("/leaderboard", ph.GetProjectLeaderboard)
+
+		// swagger:route GET /projects/{id}/species projects getProjectSpecies
+		//
+		// Presteje opazanja in osebke posameznih vrst v projektu
+		//
+		// Responses:
+		//		200: []observationStat
+		r.Get("/species", ph.GetProjectSpecies)
+	})
+
+	return ph
+}
+
+// decodeProject prebere projekt iz telesa zahtevka in preveri vrednosti, ki so podane.
+// Polj, ki jih doloci streznik (ID in avtor), ni mogoce nastaviti
+func decodeProject(w http.ResponseWriter, r *http.Request) (*biolog.Project, bool) {
+	var p biolog.Project
+	decErr := json.NewDecoder(r.Body).Decode(&p)
+	if decErr != nil {
+		switch decErr {
+		case io.EOF:
+			respondWithError(w, http.StatusBadRequest, "Telo zahtevka pri projektu ne more biti prazno")
+		default:
+			respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		}
+		return nil, true
+	}
+	if p.TaxonRank != nil && !biolog.ValidTaxonRank(*p.TaxonRank) {
+		respondWithError(w, http.StatusBadRequest, "Neveljavna taksonomska stopnja (kingdom, phylum, class, order, family ali genus)")
+		return nil, true
+	}
+	if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+		respondWithError(w, http.StatusBadRequest, "Projekt se ne more koncati pred zacetkom")
+		return nil, true
+	}
+	p.ID, p.CreatedBy = nil, nil
+
+	return &p, false
+}
+
+// isProjectAdmin pove ali je uporabnik administrator projekta ali moderator
+func isProjectAdmin(ss biolog.SpeciesService, projectID int, u *biolog.User) (bool, error) {
+	if u.IsModerator() {
+		return true, nil
+	}
+	members, err := ss.ProjectMembers(projectID)
+	if err != nil {
+		return false, err
+	}
+	for _, m := range members {
+		if *m.User == *u.ID && *m.Role == biolog.ProjectRoleAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// managedProject pridobi projekt iz poti in preveri, da ga prijavljen uporabnik lahko ureja
+// (administrator projekta ali moderator). Ce pride do napake obvesti odjemalca in vrne true
+func (ph *ProjectHandler) managedProject(w http.ResponseWriter, r *http.Request) (*biolog.Project, bool) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return nil, true
+	}
+	p, err := speciesService(r, ph.SpeciesService).Project(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return nil, true
+	}
+	u, err := currentUser(r, ph.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return nil, true
+	}
+	admin, err := isProjectAdmin(speciesService(r, ph.SpeciesService), id, u)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju clanov projekta")
+		return nil, true
+	}
+	if !admin {
+		respondWithError(w, http.StatusForbidden, "Projekt lahko ureja le administrator projekta ali moderator")
+		return nil, true
+	}
+
+	return p, false
+}
+
+// GetProjects vrne stran projektov
+func (ph *ProjectHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	p, failed := getPage(w, r)
+	if failed {
+		return
+	}
+
+	projects, err := speciesService(r, ph.SpeciesService).Projects(p)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, projects)
+}
+
+// CreateProject shrani projekt, prijavljen uporabnik postane njegov administrator
+func (ph *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	u, err := currentUser(r, ph.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	p, failed := decodeProject(w, r)
+	if failed {
+		return
+	}
+	if p.Name == nil || p.StartDate == nil {
+		respondWithError(w, http.StatusBadRequest, "Projekt mora imeti ime in zacetek")
+		return
+	}
+	if (p.TaxonRank == nil) != (p.TaxonName == nil) {
+		respondWithError(w, http.StatusBadRequest, "Skupina vrst mora imeti taksonomsko stopnjo in ime")
+		return
+	}
+	p.CreatedBy = u.ID
+
+	newProject, err := speciesService(r, ph.SpeciesService).CreateProject(p)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, newProject)
+}
+
+// GetProjectByID vrne projekt skupaj z mejo
+func (ph *ProjectHandler) GetProjectByID(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+
+	p, err := speciesService(r, ph.SpeciesService).Project(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, p)
+}
+
+// UpdateProject posodobi podatke o projektu in njegova pravila
+func (ph *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	existing, failed := ph.managedProject(w, r)
+	if failed {
+		return
+	}
+	p, failed := decodeProject(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, ph.SpeciesService).UpdateProject(*existing.ID, *p); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// DeleteProject zbrise projekt
+func (ph *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	existing, failed := ph.managedProject(w, r)
+	if failed {
+		return
+	}
+
+	if err := speciesService(r, ph.SpeciesService).DeleteProject(*existing.ID); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// GetProjectMembers vrne clane projekta
+func (ph *ProjectHandler) GetProjectMembers(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	if _, err := speciesService(r, ph.SpeciesService).Project(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	members, err := speciesService(r, ph.SpeciesService).ProjectMembers(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, members)
+}
+
+// SetProjectMember doda clana v projekt. Vsak uporabnik se lahko pridruzi projektu kot navaden clan,
+// druge uporabnike in vloge doloca le administrator projekta ali moderator
+func (ph *ProjectHandler) SetProjectMember(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	if _, err := speciesService(r, ph.SpeciesService).Project(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	u, err := currentUser(r, ph.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	var m biolog.ProjectMember
+	if decErr := json.NewDecoder(r.Body).Decode(&m); decErr != nil && decErr != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Napaka pri pretvarjanju JSONa iz telesa zahtevka")
+		return
+	}
+	if m.User == nil {
+		m.User = u.ID
+	}
+	if m.Role == nil {
+		role := biolog.ProjectRoleMember
+		m.Role = &role
+	}
+	if !biolog.ValidProjectRole(*m.Role) {
+		respondWithError(w, http.StatusBadRequest, "Neveljavna vloga clana projekta (admin ali member)")
+		return
+	}
+	m.Project = &id
+
+	if *m.User != *u.ID || *m.Role != biolog.ProjectRoleMember {
+		admin, err := isProjectAdmin(speciesService(r, ph.SpeciesService), id, u)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Napaka pri branju clanov projekta")
+			return
+		}
+		if !admin {
+			respondWithError(w, http.StatusForbidden, "Clane in vloge doloca le administrator projekta ali moderator")
+			return
+		}
+	}
+
+	if err := speciesService(r, ph.SpeciesService).SetProjectMember(m); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// RemoveProjectMember odstrani clana iz projekta. Clan lahko projekt zapusti sam,
+// ostale clane odstrani administrator projekta ali moderator
+func (ph *ProjectHandler) RemoveProjectMember(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	userID, parseErr := getIDFromURL(w, r, "userID")
+	if parseErr {
+		return
+	}
+	u, err := currentUser(r, ph.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if userID != *u.ID {
+		admin, err := isProjectAdmin(speciesService(r, ph.SpeciesService), id, u)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Napaka pri branju clanov projekta")
+			return
+		}
+		if !admin {
+			respondWithError(w, http.StatusForbidden, "Clane odstrani le administrator projekta ali moderator")
+			return
+		}
+	}
+
+	if err := speciesService(r, ph.SpeciesService).RemoveProjectMember(id, userID); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// GetProjectLeaderboard vrne lestvico uporabnikov po stevilu javnih opazanj v projektu
+func (ph *ProjectHandler) GetProjectLeaderboard(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	if _, err := speciesService(r, ph.SpeciesService).Project(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Lestvica je enaka za vse uporabnike, zato se meja vedno preveri na posplosenih lokacijah
+	leaderboard, err := speciesService(r, ph.SpeciesService).ProjectLeaderboard(id, &ph.Sensitivity)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, leaderboard)
+}
+
+// GetProjectSpecies vrne stevilo javnih opazanj in sestevek osebkov za vsako vrsto v projektu
+func (ph *ProjectHandler) GetProjectSpecies(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	if _, err := speciesService(r, ph.SpeciesService).Project(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	f := biolog.ObservationFilter{Project: &id, Obscure: &ph.Sensitivity}
+	stats, err := speciesService(r, ph.SpeciesService).ObservationStats(biolog.StatsBySpecies, f)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stats)
+}
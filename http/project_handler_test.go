@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProject vrne projekt, katerega administrator je uporabnik admin
+func testProject() *biolog.Project {
+	id, name, createdBy := 1, "Bioblitz", 10000000
+	start := time.Date(2018, 6, 2, 0, 0, 0, 0, time.UTC)
+	return &biolog.Project{ID: &id, Name: &name, StartDate: &start, CreatedBy: &createdBy}
+}
+
+// TestCreateProject preveri preverjanje projekta in nastavitev avtorja
+func TestCreateProject(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.CreateProjectFn = func(p *biolog.Project) (*biolog.Project, error) {
+		assert.Equal(t, 10000000, *p.CreatedBy)
+		assert.Nil(t, p.ID)
+		return p, nil
+	}
+
+	body := `{"id": 7, "name": "Bioblitz", "startDate": "2018-06-02T00:00:00Z", "endDate": "2018-06-03T00:00:00Z",
+		"taxonRank": "class", "taxonName": "Aves", "createdBy": 1}`
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/projects", body, auth).Code)
+
+	for name, bad := range map[string]string{
+		"empty":      "",
+		"no name":    `{"startDate": "2018-06-02T00:00:00Z"}`,
+		"no start":   `{"name": "Bioblitz"}`,
+		"bad rank":   `{"name": "Bioblitz", "startDate": "2018-06-02T00:00:00Z", "taxonRank": "species", "taxonName": "Aves"}`,
+		"no taxon":   `{"name": "Bioblitz", "startDate": "2018-06-02T00:00:00Z", "taxonRank": "class"}`,
+		"ends early": `{"name": "Bioblitz", "startDate": "2018-06-02T00:00:00Z", "endDate": "2018-06-01T00:00:00Z"}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/projects", bad, auth).Code, name)
+	}
+}
+
+// TestProjectMembers preveri, da se uporabnik lahko pridruzi sam, vloge pa doloca le administrator projekta
+func TestProjectMembers(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	// Prijavljen je uporabnik 10000001, administrator projekta je 10000000
+	viewer := testUser()
+	other := 10000001
+	viewer.ID = &other
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return viewer, nil }
+	ss.ProjectFn = func(id int) (*biolog.Project, error) { return testProject(), nil }
+	adminID, role := 10000000, biolog.ProjectRoleAdmin
+	ss.ProjectMembersFn = func(projectID int) ([]biolog.ProjectMember, error) {
+		return []biolog.ProjectMember{{Project: &projectID, User: &adminID, Role: &role}}, nil
+	}
+	var set []biolog.ProjectMember
+	ss.SetProjectMemberFn = func(m biolog.ProjectMember) error {
+		set = append(set, m)
+		return nil
+	}
+	ss.RemoveProjectMemberFn = func(projectID, userID int) error { return nil }
+	ss.UpdateProjectFn = func(id int, p biolog.Project) error { return nil }
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/projects/1/members", "", auth).Code)
+	if assert.Len(t, set, 1) {
+		assert.Equal(t, other, *set[0].User)
+		assert.Equal(t, biolog.ProjectRoleMember, *set[0].Role)
+	}
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/projects/1/members", `{"role": "admin"}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/projects/1/members", `{"user": 10000002}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/projects/1/members", `{"role": "owner"}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/projects/1/members/10000001", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/projects/1/members/10000000", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/projects/1", `{"name": "Atlas"}`, auth).Code)
+
+	// Administrator projekta lahko doloca vloge in ureja projekt
+	viewer.ID = &adminID
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/projects/1/members", `{"user": 10000001, "role": "admin"}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/projects/1", `{"name": "Atlas"}`, auth).Code)
+}
+
+// TestProjectLeaderboard preveri lestvico in stevilo opazanj po vrstah v projektu
+func TestProjectLeaderboard(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ProjectFn = func(id int) (*biolog.Project, error) { return testProject(), nil }
+	ss.ProjectLeaderboardFn = func(projectID int, obscure *biolog.SensitivityPolicy) ([]biolog.LeaderboardEntry, error) {
+		assert.NotNil(t, obscure)
+		return []biolog.LeaderboardEntry{{User: 10000000, Observations: 3, Species: 2}}, nil
+	}
+	ss.ObservationStatsFn = func(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error) {
+		assert.Equal(t, biolog.StatsBySpecies, groupBy)
+		assert.Equal(t, 1, *f.Project)
+		assert.NotNil(t, f.Obscure)
+		return []biolog.ObservationStat{{Key: "5231190", Observations: 3, Quantity: 7}}, nil
+	}
+
+	rec := doRequest(h, "GET", "/projects/1/leaderboard", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.JSONEq(t, `[{"user": 10000000, "observations": 3, "species": 2}]`, rec.Body.String())
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/projects/1/species", "", auth).Code)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// RateLimits doloca omejitve stevila zahtev za posamezne skupine zahtev. Prijava in vse zahteve skupaj (Client)
+// se stejejo po IP naslovu, ostale zahteve po prijavljenem uporabniku (za JWT) ali po IP naslovu.
+// Nenastavljena omejitev ne velja
+type RateLimits struct {
+	Limiter biolog.RateLimiter
+	// Vse zahteve z enega IP naslova, stejejo se pred preverjanjem JWT
+	Client biolog.RateLimit
+	// Prijava in callback zunanjega avtentikatorja
+	Login biolog.RateLimit
+	// Zahteve, ki spreminjajo podatke (POST, PATCH, DELETE)
+	Write biolog.RateLimit
+	// Ostale zahteve
+	Read biolog.RateLimit
+}
+
+// NewRateLimits prebere omejitve iz konfiguracije pod ratelimit (requests zahtev na period za vsako skupino)
+func NewRateLimits(limiter biolog.RateLimiter) *RateLimits {
+	limit := func(group string) biolog.RateLimit {
+		return biolog.RateLimit{
+			Burst:  viper.GetInt("ratelimit." + group + ".requests"),
+			Period: viper.GetDuration("ratelimit." + group + ".period"),
+		}
+	}
+	return &RateLimits{Limiter: limiter, Client: limit("client"), Login: limit("login"), Write: limit("write"),
+		Read: limit("read")}
+}
+
+// PruneIdle vsakih interval odstrani vedra, ki so ze polna (niso bila uporabljena dlje od najdaljse periode)
+func (rl *RateLimits) PruneIdle(interval time.Duration) {
+	idle := rl.Login.Period
+	for _, l := range []biolog.RateLimit{rl.Client, rl.Write, rl.Read} {
+		if l.Period > idle {
+			idle = l.Period
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := rl.Limiter.Prune(time.Now().Add(-idle)); err != nil {
+			log.Error("Could not prune rate limit buckets: ", err)
+		}
+	}
+}
+
+// limitClients omeji stevilo vseh zahtev z enega IP naslova. Mora biti pred JWTAuthMiddleware, da se stejejo
+// tudi zahteve z neveljavnim JWT
+func (h *Handler) limitClients(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl := h.RateLimits; rl != nil && !rl.take(w, "client:"+ipKey(r), rl.Client) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitLogin omeji stevilo prijav z enega IP naslova
+func (h *Handler) limitLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl := h.RateLimits; rl != nil && !rl.take(w, "login:"+clientKey(r), rl.Login) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitRequests omeji stevilo zahtev uporabnika, spreminjanje podatkov se steje posebej.
+// Na poteh z JWT mora slediti JWTAuthMiddleware, da se zahteve stejejo po preverjenem uporabniku
+func (h *Handler) limitRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl := h.RateLimits; rl != nil {
+			group, limit := "read", rl.Read
+			if r.Method != "GET" && r.Method != "HEAD" && r.Method != "OPTIONS" {
+				group, limit = "write", rl.Write
+			}
+			if !rl.take(w, group+":"+clientKey(r), limit) {
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take porabi zeton iz vedra key (skupina in odjemalec zahteve) in nastavi glave RateLimit-*. Ce zahteva ni
+// dovoljena, odgovori s 429 in vrne false. Ob napaki omejevalnika se zahteva dovoli
+func (rl *RateLimits) take(w http.ResponseWriter, key string, limit biolog.RateLimit) bool {
+	if rl.Limiter == nil || !limit.Enabled() {
+		return true
+	}
+	res, err := rl.Limiter.Take(key, limit)
+	if err != nil {
+		log.Error("Rate limiter error: ", err)
+		return true
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	if !res.Allowed {
+		retry := ceilSeconds(res.RetryAfter)
+		w.Header().Set("Retry-After", strconv.Itoa(retry))
+		respondWithError(w, http.StatusTooManyRequests, "Prevec zahtev, poskusite znova cez "+strconv.Itoa(retry)+" s")
+		return false
+	}
+	return true
+}
+
+// clientKey vrne kljuc odjemalca: email prijavljenega uporabnika ali IP naslov
+func clientKey(r *http.Request) string {
+	if email, ok := r.Context().Value(contextEmailKey("userEmail")).(string); ok && email != "" {
+		return "user:" + email
+	}
+	return ipKey(r)
+}
+
+// ipKey vrne kljuc IP naslova odjemalca (za zaupanja vrednimi posredniki ga nastavi realIP)
+func ipKey(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return "ip:" + ip
+}
+
+// ceilSeconds zaokrozi trajanje navzgor na cele sekunde
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,137 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	biohttp "github.com/rubinda/biolog/http"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRateLimiter vrne mock omejevalnika, ki vedra hrani v mapi, in seznam uporabljenih kljucev
+func newTestRateLimiter() (*mock.RateLimiter, *[]string) {
+	buckets := make(map[string]*biolog.TokenBucket)
+	var keys []string
+	return &mock.RateLimiter{TakeFn: func(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error) {
+		keys = append(keys, key)
+		if buckets[key] == nil {
+			buckets[key] = &biolog.TokenBucket{}
+		}
+		return buckets[key].Take(limit, time.Now()), nil
+	}}, &keys
+}
+
+// TestRateLimitLogin preveri omejitev prijav po IP naslovu, glave RateLimit-* in odgovor 429
+func TestRateLimitLogin(t *testing.T) {
+	h, _, _ := newTestHandler()
+	limiter, keys := newTestRateLimiter()
+	h.RateLimits = &biohttp.RateLimits{Limiter: limiter, Login: biolog.RateLimit{Burst: 2, Period: time.Minute}}
+
+	for _, remaining := range []string{"1", "0"} {
+		rec := doRequest(h, "POST", "/login/google", "", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, rec.Header().Get("RateLimit-Remaining"))
+	}
+	rec := doRequest(h, "POST", "/login/google", "", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "login:ip:192.0.2.1", (*keys)[0])
+
+	// Glava X-Real-IP se uposteva le od zaupanja vrednega posrednika
+	req := httptest.NewRequest("POST", apiPrefix+"/login/google", nil)
+	req.Header.Set("X-Real-IP", "198.51.100.7")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "login:ip:192.0.2.1", (*keys)[len(*keys)-1])
+
+	// Drug IP naslov (za posrednikom) ima svoje vedro
+	h.TrustedProxies, _ = biohttp.ParseTrustedProxies([]string{"192.0.2.0/24"})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "login:ip:198.51.100.7", (*keys)[len(*keys)-1])
+}
+
+// TestRealIP preveri branje naslova odjemalca iz X-Forwarded-For za zaupanja vrednimi posredniki
+func TestRealIP(t *testing.T) {
+	h, _, _ := newTestHandler()
+	limiter, keys := newTestRateLimiter()
+	h.RateLimits = &biohttp.RateLimits{Limiter: limiter, Login: biolog.RateLimit{Burst: 100, Period: time.Minute}}
+	var err error
+	h.TrustedProxies, err = biohttp.ParseTrustedProxies([]string{"192.0.2.1", "10.0.0.0/8"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	login := func(remoteAddr string, forwardedFor ...string) string {
+		req := httptest.NewRequest("POST", apiPrefix+"/login/google", nil)
+		req.RemoteAddr = remoteAddr
+		for _, f := range forwardedFor {
+			req.Header.Add("X-Forwarded-For", f)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		return (*keys)[len(*keys)-1]
+	}
+
+	// Odjemalec je prvi naslov z desne, ki ni posrednik, lazni naslovi na levi se ne upostevajo
+	assert.Equal(t, "login:ip:198.51.100.7", login("192.0.2.1:1234", "203.0.113.9, 198.51.100.7, 10.1.2.3"))
+	assert.Equal(t, "login:ip:198.51.100.7", login("192.0.2.1:1234", "203.0.113.9", "198.51.100.7"))
+	assert.Equal(t, "login:ip:10.1.2.3", login("192.0.2.1:1234", "10.1.2.3"))
+	// Neposredni odjemalec glave ne more ponarediti
+	assert.Equal(t, "login:ip:203.0.113.5", login("203.0.113.5:1234", "198.51.100.7"))
+
+	_, err = biohttp.ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = biohttp.ParseTrustedProxies([]string{"posrednik"})
+	assert.Error(t, err)
+}
+
+// TestRateLimitClients preveri, da se po IP naslovu stejejo vse zahteve, tudi tiste z neveljavnim JWT
+func TestRateLimitClients(t *testing.T) {
+	h, _, _ := newTestHandler()
+	limiter, keys := newTestRateLimiter()
+	h.RateLimits = &biohttp.RateLimits{Limiter: limiter, Client: biolog.RateLimit{Burst: 2, Period: time.Minute}}
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species", "", "Bearer x").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species", "", "Bearer y").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(h, "GET", "/species", "", "Bearer z").Code)
+	assert.Equal(t, []string{"client:ip:192.0.2.1", "client:ip:192.0.2.1", "client:ip:192.0.2.1"}, *keys)
+}
+
+// TestRateLimitRequests preveri, da se zahteve z JWT stejejo po uporabniku, spreminjanje pa posebej
+func TestRateLimitRequests(t *testing.T) {
+	h, us, ss := newTestHandler()
+	limiter, keys := newTestRateLimiter()
+	h.RateLimits = &biohttp.RateLimits{Limiter: limiter, Read: biolog.RateLimit{Burst: 1, Period: time.Minute},
+		Write: biolog.RateLimit{Burst: 1, Period: time.Minute}}
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.AllSpeciesFn = func() ([]biolog.Species, error) { return []biolog.Species{}, nil }
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species", "", auth).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(h, "GET", "/species", "", auth).Code)
+	assert.Equal(t, "read:user:"+testEmail, (*keys)[0])
+	// Spreminjanje ima svoje vedro (telo ni veljavno, zato 400)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species", "{", auth).Code)
+	assert.Equal(t, "write:user:"+testEmail, (*keys)[2])
+
+	// Neveljaven JWT se zavrne pred stetjem
+	n := len(*keys)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species", "", "Bearer x").Code)
+	assert.Len(t, *keys, n)
+
+	// Ob napaki omejevalnika se zahteva dovoli
+	limiter.TakeFn = func(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error) {
+		return biolog.RateLimitResult{}, errors.New("baza ni dosegljiva")
+	}
+	rec := doRequest(h, "GET", "/species", "", auth)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies prebere naslove zaupanja vrednih posrednikov, podane kot CIDR (10.0.0.0/8) ali posamezen IP
+func ParseTrustedProxies(addrs []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(addrs))
+	for _, a := range addrs {
+		a = strings.TrimSpace(a)
+		if !strings.Contains(a, "/") {
+			ip := net.ParseIP(a)
+			if ip == nil {
+				return nil, errors.New("Neveljaven naslov posrednika: " + a)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(a)
+		if err != nil {
+			return nil, errors.New("Neveljaven naslov posrednika: " + a)
+		}
+		proxies = append(proxies, n)
+	}
+	return proxies, nil
+}
+
+// realIP nastavi RemoteAddr na naslov odjemalca iz X-Forwarded-For oz. X-Real-IP, a le ce je zahtevo poslal
+// zaupanja vreden posrednik (TrustedProxies). Sicer bi odjemalec z lazno glavo lahko obsel omejitve zahtev
+func (h *Handler) realIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := h.clientIP(r); ip != "" {
+			r.RemoteAddr = ip
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP vrne naslov odjemalca za zaupanja vrednimi posredniki oz. prazen niz, ce ga glave ne dolocajo.
+// X-Forwarded-For se bere od desne, prvi naslov, ki ni posrednik, je odjemalec (levi del glave lahko ponaredi)
+func (h *Handler) clientIP(r *http.Request) string {
+	if !h.trustedProxy(remoteIP(r.RemoteAddr)) {
+		return ""
+	}
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		client := ""
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip.String()
+			if !h.trustedProxy(ip) {
+				break
+			}
+		}
+		return client
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// trustedProxy pove, ali je ip naslov zaupanja vrednega posrednika
+func (h *Handler) trustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range h.TrustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP vrne IP naslov iz RemoteAddr (host:port ali le host)
+func remoteIP(addr string) net.IP {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+)
+
+// RegionHandler je http handler za obmocja (obcine, statisticne regije ...)
+type RegionHandler struct {
+	RegionService biolog.RegionService
+	*chi.Mux
+}
+
+// RegionID model.
+//
+// ID obmocja v poti
+// swagger:parameters getRegionByID
+type RegionID struct {
+	// in: path
+	// required: true
+	ID int `json:"id"`
+}
+
+// RegionParams model.
+//
+// Filter pri seznamu obmocij
+// swagger:parameters getRegions
+type RegionParams struct {
+	// Vrsta obmocja (npr. obcina), privzeto vse vrste
+	// in: query
+	Kind string `json:"kind"`
+}
+
+// NewRegionHandler kreira novega handlerja za obmocja
+func NewRegionHandler() *RegionHandler {
+	rh := &RegionHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/regions
+
+	// swagger:route GET /regions regions getRegions
+	//
+	// Pridobi seznam obmocij (brez geometrije)
+	//
+	// Responses:
+	//		200: []region
+	rh.Get("/", rh.GetRegions)
+
+	// swagger:route GET /regions/{id} regions getRegionByID
+	//
+	// Pridobi obmocje skupaj z GeoJSON geometrijo
+	//
+	// Responses:
+	//		200: region
+	rh.Get("/{id:[0-9]+}", rh.GetRegionByID)
+
+	return rh
+}
+
+// GetRegions vrne vsa obmocja, z ?kind= le obmocja dolocene vrste
+func (rh *RegionHandler) GetRegions(w http.ResponseWriter, r *http.Request) {
+	regions, err := rh.RegionService.Regions(r.URL.Query().Get("kind"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, regions)
+}
+
+// GetRegionByID vrne obmocje z geometrijo
+func (rh *RegionHandler) GetRegionByID(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+
+	region, err := rh.RegionService.Region(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, region)
+}
@@ -0,0 +1,54 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegions preveri seznam obmocij in posamezno obmocje z geometrijo
+func TestRegions(t *testing.T) {
+	h, us, ss := newTestHandler()
+	rs := h.RegionHandler.RegionService.(*mock.RegionService)
+	auth := "Bearer " + validToken()
+	id, kind, code, name := 1, "obcina", "61", "Ljubljana"
+	rs.RegionsFn = func(k string) ([]biolog.Region, error) {
+		assert.Equal(t, kind, k)
+		return []biolog.Region{{ID: &id, Kind: &kind, Code: &code, Name: &name}}, nil
+	}
+	rs.RegionFn = func(regionID int) (*biolog.Region, error) {
+		if regionID != id {
+			return nil, assert.AnError
+		}
+		return &biolog.Region{ID: &id, Kind: &kind, Code: &code, Name: &name,
+			Geometry: json.RawMessage(`{"type":"MultiPolygon","coordinates":[]}`)}, nil
+	}
+
+	rec := doRequest(h, "GET", "/regions?kind=obcina", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.NotContains(t, rec.Body.String(), "geometry")
+	}
+
+	rec = doRequest(h, "GET", "/regions/1", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var region map[string]interface{}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&region))
+		assert.Equal(t, "MultiPolygon", region["geometry"].(map[string]interface{})["type"])
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/regions/2", "", auth).Code)
+
+	// Filter po obmocju pri seznamu opazanj
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationsFn = func(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+		if assert.NotNil(t, f.Region) {
+			assert.Equal(t, id, *f.Region)
+		}
+		return []biolog.Observation{}, nil
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations?region=1", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/observations?region=ljubljana", "", auth).Code)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/rubinda/biolog"
+)
+
+// RevisionID parameter model.
+//
+// Se uporablja za vire, ki se nanasajo na posamezno revizijo opazanja
+// swagger:parameters getObservationRevision revertObservation
+type RevisionID struct {
+	// in: path
+	// required: true
+	Revision int `json:"revision"`
+}
+
+// GetObservationRevisions vrne vse revizije opazanja, od najstarejse naprej
+func (sh *SpeciesHandler) GetObservationRevisions(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+
+	revisions, err := speciesService(r, sh.SpeciesService).ObservationRevisions(*ob.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := sh.obscureRevisions(r, u, revisions); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju statusa ogrozenosti vrste")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, revisions)
+}
+
+// GetObservationRevision vrne doloceno revizijo opazanja
+func (sh *SpeciesHandler) GetObservationRevision(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+	revision, parseErr := getIDFromURL(w, r, "revision")
+	if parseErr {
+		return
+	}
+
+	rev, err := speciesService(r, sh.SpeciesService).ObservationRevision(*ob.ID, revision)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	revisions := []biolog.ObservationRevision{*rev}
+	if err := sh.obscureRevisions(r, u, revisions); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju statusa ogrozenosti vrste")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, revisions[0])
+}
+
+// RevertObservation povrne opazanje na stanje iz dolocene revizije, povrne ga lahko le avtor
+func (sh *SpeciesHandler) RevertObservation(w http.ResponseWriter, r *http.Request) {
+	ob, u, failed := sh.visibleObservation(w, r)
+	if failed {
+		return
+	}
+	revision, parseErr := getIDFromURL(w, r, "revision")
+	if parseErr {
+		return
+	}
+	if *ob.User != *u.ID {
+		respondWithError(w, http.StatusForbidden, "Opazanje lahko povrne le avtor")
+		return
+	}
+
+	if _, err := speciesService(r, sh.SpeciesService).ObservationRevision(*ob.ID, revision); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err := speciesService(r, sh.SpeciesService).RevertObservation(*ob.ID, revision, auditActor(r, u)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// obscureRevisions posplosi lokacije obcutljivih vrst v stanjih opazanja iz revizij (enako kot pri opazanjih)
+func (sh *SpeciesHandler) obscureRevisions(r *http.Request, u *biolog.User, revisions []biolog.ObservationRevision) error {
+	snapshots := make([]biolog.Observation, len(revisions))
+	for i := range revisions {
+		snapshots[i] = *revisions[i].Snapshot
+	}
+	if err := obscureSensitive(speciesService(r, sh.SpeciesService), sh.Sensitivity, u, snapshots); err != nil {
+		return err
+	}
+	for i := range revisions {
+		revisions[i].Snapshot = &snapshots[i]
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRevision vrne revizijo opazanja 1 s kolicino quantity
+func testRevision(revision, quantity int) biolog.ObservationRevision {
+	snapshot := testObservation()
+	snapshot.Quantity = &quantity
+	return biolog.ObservationRevision{Observation: snapshot.ID, Revision: &revision, User: snapshot.User, Snapshot: snapshot}
+}
+
+// TestObservationRevisions preveri branje revizij, posplositev lokacij in povrnitev (le avtor)
+func TestObservationRevisions(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	viewer := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return viewer, nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	ss.ObservationRevisionsFn = func(observationID int) ([]biolog.ObservationRevision, error) {
+		return []biolog.ObservationRevision{testRevision(1, 6), testRevision(2, 12)}, nil
+	}
+	ss.ObservationRevisionFn = func(observationID, revision int) (*biolog.ObservationRevision, error) {
+		if revision != 1 {
+			return nil, errors.New("Revizija opazanja ne obstaja")
+		}
+		r := testRevision(1, 6)
+		return &r, nil
+	}
+	reverted := 0
+	ss.RevertObservationFn = func(id, revision int, by biolog.Actor) error {
+		assert.Equal(t, 1, revision)
+		assert.Equal(t, 10000000, by.User)
+		reverted++
+		return nil
+	}
+	status := 5
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) {
+		sp := testSpecies()
+		sp.ConservationStatus = &status
+		return sp, nil
+	}
+	ss.ConservationStatusFn = func(id int) (*biolog.ConservationStatus, error) {
+		return &biolog.ConservationStatus{ID: 5, Acronym: "VU"}, nil
+	}
+
+	var revisions []biolog.ObservationRevision
+	rec := doRequest(h, "GET", "/species/observations/1/revisions", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) && assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &revisions)) {
+		assert.Len(t, revisions, 2)
+		assert.Equal(t, 12, *revisions[1].Snapshot.Quantity)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1/revisions/1", "", auth).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/species/observations/1/revisions/3", "", auth).Code)
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/species/observations/1/revisions/1/revert", "", auth).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "POST", "/species/observations/1/revisions/3/revert", "", auth).Code)
+	assert.Equal(t, 1, reverted)
+
+	// Drug uporabnik vidi posplosene lokacije ranljive vrste in opazanja ne more povrniti
+	exact := *testObservation().SightingLocation
+	otherID := 10000001
+	viewer.ID = &otherID
+	var revision biolog.ObservationRevision
+	rec = doRequest(h, "GET", "/species/observations/1/revisions/1", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) && assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &revision)) {
+		assert.NotEqual(t, exact, *revision.Snapshot.SightingLocation)
+	}
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/species/observations/1/revisions/1/revert", "", auth).Code)
+	assert.Equal(t, 1, reverted)
+}
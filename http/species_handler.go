@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/metrics"
 	log "github.com/sirupsen/logrus"
 	//	log "github.com/sirupsen/logrus"
 )
@@ -14,13 +18,17 @@ import (
 // SpeciesHandler je http handler za SpeciesService
 type SpeciesHandler struct {
 	SpeciesService biolog.SpeciesService
+	// UserService se uporablja za iskanje prijavljenega uporabnika
+	UserService biolog.UserService
+	// Sensitivity doloca vrste, katerih lokacije se drugim uporabnikom posplosijo
+	Sensitivity biolog.SensitivityPolicy
 	*chi.Mux
 }
 
 // SpeciesGbifKey model
 //
 // Za iskanje po lokalno shranjenih vrstah
-// swagger:parameters getSpeciesbyGbifKey deleteSpecies updateSpecies
+// swagger:parameters getSpeciesbyGbifKey deleteSpecies updateSpecies mergeSpecies
 type SpeciesGbifKey struct {
 	// in: path
 	// required: true
@@ -40,7 +48,7 @@ type SpeciesBodyParams struct {
 // ObservationID model.
 //
 // Se uporablja za vire, ki se navezeujejo na opazanja preko IDjev
-// swagger:parameters getObservationByID deleteObservation updateObservation
+// swagger:parameters getObservationByID deleteObservation restoreObservation updateObservation getObservationRevisions getObservationRevision revertObservation
 type ObservationID struct {
 	// in: path
 	// required: true
@@ -86,6 +94,14 @@ func NewSpeciesHandler() *SpeciesHandler {
 	// Zdruzi vse podoperacije, ki zahtevajo GBIF Key v URL
 	// TODO:
 	//	- pridobi ID iz URL preko middleware
+	// swagger:route GET /species/watched species getWatchedSpecies
+	//
+	// Pridobi vrste, ki jih prijavljeni uporabnik opazuje (njihova opazanja so v viru dejavnosti)
+	//
+	// Responses:
+	//		200: []species
+	sh.Get("/watched", sh.GetWatchedSpecies)
+
 	sh.Route("/{gbifKey:[0-9]+}", func(r chi.Router) {
 		// swagger:route GET /species/{gbifKey} species getSpeciesByGbifKey
 		//
@@ -110,6 +126,73 @@ func NewSpeciesHandler() *SpeciesHandler {
 		// Responses:
 		//		204:
 		r.Delete("/", sh.DeleteSpecies)
+
+		// swagger:route POST /species/{gbifKey}/merge species mergeSpecies
+		//
+		// Zdruzi vrsto v sprejeto vrsto in preusmeri vsa opazanja nanjo (moderator)
+		//
+		// Responses:
+		//		201: taxonMerge
+		r.Post("/merge", sh.MergeSpecies)
+
+		// swagger:route POST /species/{gbifKey}/watch species watchSpecies
+		//
+		// Prijavljeni uporabnik zacne opazovati vrsto, nova opazanja in identifikacije vrste so v viru dejavnosti
+		//
+		// Responses:
+		//		204:
+		r.Post("/watch", sh.WatchSpecies)
+
+		// swagger:route DELETE /species/{gbifKey}/watch species unwatchSpecies
+		//
+		// Prijavljeni uporabnik preneha opazovati vrsto
+		//
+		// Responses:
+		//		204:
+		r.Delete("/watch", sh.UnwatchSpecies)
+
+		// swagger:route GET /species/{gbifKey}/distribution species getSpeciesDistribution
+		//
+		// Pridobi javna opazanja vrste, zdruzena v celice mreze (za karte razsirjenosti)
+		//
+		// Responses:
+		//		200: []distributionCell
+		r.Get("/distribution", sh.GetDistribution)
+
+		// Ocene ogrozenosti vrste po sistemih in obmocjih
+		r.Route("/assessments", func(r chi.Router) {
+			// swagger:route GET /species/{gbifKey}/assessments assessments getAssessments
+			//
+			// Pridobi vse ocene ogrozenosti vrste, vkljucno s preteklimi
+			//
+			// Responses:
+			//		200: []assessment
+			r.Get("/", sh.GetAssessments)
+
+			// swagger:route POST /species/{gbifKey}/assessments assessments createAssessment
+			//
+			// Doda oceno ogrozenosti vrste (moderator)
+			//
+			// Responses:
+			//		201: assessment
+			r.Post("/", sh.CreateAssessment)
+
+			// swagger:route PATCH /species/{gbifKey}/assessments/{assessmentID} assessments updateAssessment
+			//
+			// Posodobi oceno ogrozenosti (moderator)
+			//
+			// Responses:
+			//		204:
+			r.Patch("/{assessmentID:[0-9]+}", sh.UpdateAssessment)
+
+			// swagger:route DELETE /species/{gbifKey}/assessments/{assessmentID} assessments deleteAssessment
+			//
+			// Zbrise oceno ogrozenosti (moderator)
+			//
+			// Responses:
+			//		204:
+			r.Delete("/{assessmentID:[0-9]+}", sh.DeleteAssessment)
+		})
 	})
 
 	// Podpoti na /observations
@@ -151,11 +234,108 @@ func NewSpeciesHandler() *SpeciesHandler {
 
 			// swagger:route DELETE /species/observations/{id} observations deleteObservation
 			//
-			// Zbrise podatek o opazeni vrsti
+			// Zbrise podatek o opazeni vrsti (avtor ali moderator), do trajne odstranitve ga je mogoce obnoviti
 			//
 			// Responses:
 			// 		204:
 			r.Delete("/", sh.DeleteObservation)
+
+			// swagger:route POST /species/observations/{id}/restore observations restoreObservation
+			//
+			// Obnovi izbrisan podatek o opazeni vrsti (avtor ali moderator)
+			//
+			// Responses:
+			// 		204:
+			r.Post("/restore", sh.RestoreObservation)
+
+			// Revizije opazanja (stanje po vsaki posodobitvi)
+			r.Route("/revisions", func(r chi.Router) {
+				// swagger:route GET /species/observations/{id}/revisions revisions getObservationRevisions
+				//
+				// Pridobi vse revizije opazanja, od najstarejse naprej
+				//
+				// Responses:
+				//		200: []observationRevision
+				r.Get("/", sh.GetObservationRevisions)
+
+				// swagger:route GET /species/observations/{id}/revisions/{revision} revisions getObservationRevision
+				//
+				// Pridobi doloceno revizijo opazanja
+				//
+				// Responses:
+				//		200: observationRevision
+				r.Get("/{revision:[0-9]+}", sh.GetObservationRevision)
+
+				// swagger:route POST /species/observations/{id}/revisions/{revision}/revert revisions revertObservation
+				//
+				// Povrne opazanje na stanje iz revizije (le avtor)
+				//
+				// Responses:
+				//		204:
+				r.Post("/{revision:[0-9]+}/revert", sh.RevertObservation)
+			})
+
+			// Predlogi vrst (identifikacije) za opazanje
+			r.Route("/identifications", func(r chi.Router) {
+				// swagger:route GET /species/observations/{id}/identifications identifications getIdentifications
+				//
+				// Pridobi vse predloge vrst za opazanje
+				//
+				// Responses:
+				//		200: []identification
+				r.Get("/", sh.GetIdentifications)
+
+				// swagger:route POST /species/observations/{id}/identifications identifications createIdentification
+				//
+				// Predlaga vrsto za opazanje v imenu prijavljenega uporabnika
+				//
+				// Responses:
+				//		201: identification
+				r.Post("/", sh.CreateIdentification)
+
+				// swagger:route DELETE /species/observations/{id}/identifications/{identificationID} identifications withdrawIdentification
+				//
+				// Umakne predlog vrste (le avtor predloga)
+				//
+				// Responses:
+				//		204:
+				r.Delete("/{identificationID:[0-9]+}", sh.WithdrawIdentification)
+			})
+
+			// Komentarji pod opazanjem
+			r.Route("/comments", func(r chi.Router) {
+				// swagger:route GET /species/observations/{id}/comments comments getComments
+				//
+				// Pridobi stran komentarjev za opazanje
+				//
+				// Responses:
+				//		200: []comment
+				r.Get("/", sh.GetComments)
+
+				// swagger:route POST /species/observations/{id}/comments comments createComment
+				//
+				// Doda komentar pod opazanje v imenu prijavljenega uporabnika
+				//
+				// Responses:
+				//		201: comment
+				r.Post("/", sh.CreateComment)
+
+				// swagger:route PATCH /species/observations/{id}/comments/{commentID} comments updateComment
+				//
+				// Uredi besedilo komentarja (avtor ali moderator)
+				//
+				// Responses:
+				//		204:
+				r.Patch("/{commentID:[0-9]+}", sh.UpdateComment)
+
+				// swagger:route DELETE /species/observations/{id}/comments/{commentID} comments deleteComment
+				//
+				// Zbrise komentar (avtor ali moderator)
+				//
+				// Responses:
+				//		204:
+				r.Delete("/{commentID:[0-9]+}", sh.DeleteComment)
+			})
 		})
 
 	})
@@ -171,7 +351,7 @@ func (sh *SpeciesHandler) GetAllSpecies(w http.ResponseWriter, r *http.Request)
 	// q := r.URL.Query()
 	// family := q.Get("family")
 
-	sps, err := sh.SpeciesService.AllSpecies()
+	sps, err := speciesService(r, sh.SpeciesService).AllSpecies()
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
@@ -181,22 +361,128 @@ func (sh *SpeciesHandler) GetAllSpecies(w http.ResponseWriter, r *http.Request)
 
 }
 
-// GetSpeciesByGBIFKey vrne podrobnosti o vrsti shranjene pri nas preko kljuca od GBIF
+// GetSpeciesByGBIFKey vrne podrobnosti o vrsti shranjene pri nas preko kljuca od GBIF.
+// Zahteve za sinonim se preusmerijo na sprejeto vrsto
 func (sh *SpeciesHandler) GetSpeciesByGBIFKey(w http.ResponseWriter, r *http.Request) {
 	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
 	if parseErr {
 		return
 	}
 
-	sp, err := sh.SpeciesService.Species(gbifKey)
+	sp, err := speciesService(r, sh.SpeciesService).Species(gbifKey)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if sp.AcceptedKey != nil {
+		accepted := path.Join(path.Dir(strings.TrimSuffix(r.URL.Path, "/")), strconv.Itoa(*sp.AcceptedKey))
+		http.Redirect(w, r, accepted, http.StatusMovedPermanently)
+		return
+	}
 
 	respondWithJSON(w, http.StatusOK, sp)
 }
 
+// MergeSpecies zdruzi vrsto iz poti v sprejeto vrsto iz telesa zahtevka ({"into": gbifKey})
+func (sh *SpeciesHandler) MergeSpecies(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	u, failed := sh.requireModerator(w, r)
+	if failed {
+		return
+	}
+
+	var m biolog.TaxonMerge
+	if decErr := json.NewDecoder(r.Body).Decode(&m); decErr != nil || m.Into == nil {
+		respondWithError(w, http.StatusBadRequest, "Telo zahtevka mora vsebovati sprejeto vrsto (into)")
+		return
+	}
+
+	merge, err := speciesService(r, sh.SpeciesService).MergeSpecies(gbifKey, *m.Into, auditActor(r, u))
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, merge)
+}
+
+// GetWatchedSpecies vrne vrste, ki jih prijavljeni uporabnik opazuje
+func (sh *SpeciesHandler) GetWatchedSpecies(w http.ResponseWriter, r *http.Request) {
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	sps, err := speciesService(r, sh.SpeciesService).WatchedSpecies(*u.ID)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju opazovanih vrst")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sps)
+}
+
+// WatchSpecies doda vrsto med vrste, ki jih prijavljeni uporabnik opazuje
+func (sh *SpeciesHandler) WatchSpecies(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).WatchSpecies(*u.ID, gbifKey); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// UnwatchSpecies odstrani vrsto iz vrst, ki jih prijavljeni uporabnik opazuje
+func (sh *SpeciesHandler) UnwatchSpecies(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).UnwatchSpecies(*u.ID, gbifKey); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// requireModerator preveri, da je prijavljen uporabnik moderator in ga vrne. Ce ni ali pride do napake
+// obvesti odjemalca in vrne true
+func (sh *SpeciesHandler) requireModerator(w http.ResponseWriter, r *http.Request) (*biolog.User, bool) {
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return nil, true
+	}
+	if !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "To operacijo lahko izvede le moderator")
+		return nil, true
+	}
+	return u, false
+}
+
 // CreateSpecies kreira nov zapis o neki vrsti v naso podatkovno bazo
 func (sh *SpeciesHandler) CreateSpecies(w http.ResponseWriter, r *http.Request) {
 	var sp biolog.Species
@@ -217,7 +503,7 @@ func (sh *SpeciesHandler) CreateSpecies(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Shrani podatke o novi vrsti
-	newSp, err := sh.SpeciesService.CreateSpecies(&sp)
+	newSp, err := speciesService(r, sh.SpeciesService).CreateSpecies(&sp)
 
 	// Napaka pri kreiranju
 	if err != nil {
@@ -253,9 +539,13 @@ func (sh *SpeciesHandler) UpdateLocalSpecies(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err := sh.SpeciesService.UpdateSpecies(gbifKey, sp)
-
+	u, err := currentUser(r, sh.UserService)
 	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).UpdateSpecies(gbifKey, sp, auditActor(r, u)); err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -270,7 +560,12 @@ func (sh *SpeciesHandler) DeleteSpecies(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := sh.SpeciesService.DeleteSpecies(gbifKey); err != nil {
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if err := speciesService(r, sh.SpeciesService).DeleteSpecies(gbifKey, auditActor(r, u)); err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -278,18 +573,112 @@ func (sh *SpeciesHandler) DeleteSpecies(w http.ResponseWriter, r *http.Request)
 	respondWithJSON(w, http.StatusNoContent, nil)
 }
 
+// GetDistribution vrne celice mreze, v katerih je bila vrsta opazena
+// Mozni parametri so:
+// 	- cell ... vrsta mreze (geohash5 ali 10km), privzeto geohash5
+// 	- vsi filtri iz seznama opazanj (vrsta se vzame iz poti)
+func (sh *SpeciesHandler) GetDistribution(w http.ResponseWriter, r *http.Request) {
+	gbifKey, parseErr := getIDFromURL(w, r, "gbifKey")
+	if parseErr {
+		return
+	}
+	cell := r.URL.Query().Get("cell")
+	if cell == "" {
+		cell = biolog.CellGeohash5
+	}
+	if !biolog.ValidDistributionCell(cell) {
+		respondWithError(w, http.StatusBadRequest, "Neveljavna mreza (cell)")
+		return
+	}
+	f, parseErr := getObservationFilter(w, r)
+	if parseErr {
+		return
+	}
+	f.Species = &gbifKey
+	// Karta razsirjenosti je enaka za vse uporabnike, zato so lokacije obcutljivih vrst vedno posplosene
+	f.Obscure = &sh.Sensitivity
+
+	cells, err := speciesService(r, sh.SpeciesService).Distribution(cell, f)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cells)
+}
+
 // GetObservations vrne vse opazovalne liste
+// Mozni parametri so:
+// 	- qualityGrade ... stopnja kakovosti (casual, needs_id, research)
+// 	- species ... GBIF kljuc opazene vrste
+// 	- project ... ID projekta, katerega pravilom ustreza opazanje
 func (sh *SpeciesHandler) GetObservations(w http.ResponseWriter, r *http.Request) {
-	obs, err := sh.SpeciesService.Observations()
+	f, parseErr := getObservationFilter(w, r)
+	if parseErr {
+		return
+	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	// Meja projekta se preveri na lokacijah, ki jih uporabnik vidi (moderatorji vidijo natancne)
+	if !u.IsModerator() {
+		f.Obscure = &sh.Sensitivity
+	}
+
+	obs, err := speciesService(r, sh.SpeciesService).Observations(f)
 
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if sh.obscureLocations(w, r, obs) {
+		return
+	}
 
 	respondWithJSON(w, http.StatusOK, obs)
 }
 
+// GetObservationFilter prebere pogoje za iskanje po opazanjih iz query parametrov, ce pride do napake obvesti odjemalca
+// Vraca filter in vrednost ali je prislo do napake (enako kot getIDFromURL)
+func getObservationFilter(w http.ResponseWriter, r *http.Request) (biolog.ObservationFilter, bool) {
+	var f biolog.ObservationFilter
+	if grade := r.URL.Query().Get("qualityGrade"); grade != "" {
+		if !biolog.ValidQualityGrade(grade) {
+			respondWithError(w, http.StatusBadRequest, "Neveljavna stopnja kakovosti")
+			return f, true
+		}
+		f.QualityGrade = &grade
+	}
+	if species := r.URL.Query().Get("species"); species != "" {
+		gbifKey, err := strconv.Atoi(species)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven GBIF kljuc vrste")
+			return f, true
+		}
+		f.Species = &gbifKey
+	}
+	if region := r.URL.Query().Get("region"); region != "" {
+		regionID, err := strconv.Atoi(region)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven ID obmocja")
+			return f, true
+		}
+		f.Region = &regionID
+	}
+	if project := r.URL.Query().Get("project"); project != "" {
+		projectID, err := strconv.Atoi(project)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven ID projekta")
+			return f, true
+		}
+		f.Project = &projectID
+	}
+
+	return f, false
+}
+
 // GetObservationByID vrne tocno dolocen opazovalni list
 func (sh *SpeciesHandler) GetObservationByID(w http.ResponseWriter, r *http.Request) {
 	id, parseErr := getIDFromURL(w, r, "id")
@@ -297,13 +686,77 @@ func (sh *SpeciesHandler) GetObservationByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	ob, err := sh.SpeciesService.Observation(id)
+	ob, err := speciesService(r, sh.SpeciesService).Observation(id)
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	obs := []biolog.Observation{*ob}
+	if sh.obscureLocations(w, r, obs) {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, obs[0])
+}
+
+// obscureLocations posplosi lokacije obcutljivih vrst v opazanjih, ki niso od prijavljenega uporabnika.
+// Vraca vrednost ali je prislo do napake (enako kot getIDFromURL)
+func (sh *SpeciesHandler) obscureLocations(w http.ResponseWriter, r *http.Request, obs []biolog.Observation) bool {
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return true
+	}
+	if err := obscureSensitive(speciesService(r, sh.SpeciesService), sh.Sensitivity, u, obs); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju statusa ogrozenosti vrste")
+		return true
+	}
+	return false
+}
+
+// obscureSensitive posplosi lokacije obcutljivih vrst v opazanjih, ki niso od uporabnika u
+// (moderatorji vidijo vse lokacije natancno)
+func obscureSensitive(ss biolog.SpeciesService, policy biolog.SensitivityPolicy, u *biolog.User, obs []biolog.Observation) error {
+	if u.IsModerator() {
+		return nil
+	}
 
-	respondWithJSON(w, http.StatusOK, ob)
+	// Kratice statusov ogrozenosti po vrstah, da se vsaka vrsta prebere le enkrat
+	statuses := make(map[int]string)
+	for i := range obs {
+		o := &obs[i]
+		if o.Species == nil || (o.User != nil && u.ID != nil && *o.User == *u.ID) {
+			continue
+		}
+		status, ok := statuses[*o.Species]
+		if !ok {
+			var err error
+			if status, err = conservationAcronym(ss, *o.Species); err != nil {
+				return err
+			}
+			statuses[*o.Species] = status
+		}
+		if policy.Sensitive(*o.Species, status) {
+			o.Obscure()
+		}
+	}
+	return nil
+}
+
+// conservationAcronym vrne kratico statusa ogrozenosti vrste (prazno, ce vrsta statusa nima)
+func conservationAcronym(ss biolog.SpeciesService, gbifKey int) (string, error) {
+	sp, err := ss.Species(gbifKey)
+	if err != nil {
+		return "", err
+	}
+	if sp.ConservationStatus == nil {
+		return "", nil
+	}
+	cs, err := ss.ConservationStatus(*sp.ConservationStatus)
+	if err != nil {
+		return "", err
+	}
+	return cs.Acronym, nil
 }
 
 // CreateObservation ustvari nov Observation za doloceno vrsto
@@ -325,8 +778,30 @@ func (sh *SpeciesHandler) CreateObservation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Oznake posplosene lokacije ni mogoce nastaviti preko API
+	ob.Obscured = nil
+
+	// Avtor opazanja je vedno prijavljen uporabnik, popis mora biti njegov
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	ob.User = u.ID
+	if ob.Checklist != nil {
+		c, err := speciesService(r, sh.SpeciesService).Checklist(*ob.Checklist)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Popis s tem ID ne obstaja")
+			return
+		}
+		if *c.User != *u.ID {
+			respondWithError(w, http.StatusForbidden, "Opazanje je mogoce dodati le v popis avtorja opazanja")
+			return
+		}
+	}
+
 	// Shrani podatke o novi vrsti
-	newOb, err := sh.SpeciesService.CreateObservation(&ob)
+	newOb, err := speciesService(r, sh.SpeciesService).CreateObservation(&ob)
 
 	// Napaka pri kreiranju
 	if err != nil {
@@ -335,10 +810,12 @@ func (sh *SpeciesHandler) CreateObservation(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Opazovanje vrste uspesno shranjeno, vrni novo shranjene podatke
+	metrics.ObservationsCreated.WithLabelValues("observation").Inc()
 	respondWithJSON(w, http.StatusCreated, newOb)
 }
 
-// UpdateObservation posodobi dolocen opazovalni list
+// UpdateObservation posodobi dolocen opazovalni list. Posodobi ga lahko le avtor ali moderator, avtorja ni
+// mogoce spremeniti, opazanje pa je mogoce dodati le v popis avtorja opazanja
 func (sh *SpeciesHandler) UpdateObservation(w http.ResponseWriter, r *http.Request) {
 	id, parseErr := getIDFromURL(w, r, "id")
 	if parseErr {
@@ -361,8 +838,40 @@ func (sh *SpeciesHandler) UpdateObservation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err := sh.SpeciesService.UpdateObservation(id, ob)
+	// Oznake posplosene lokacije ni mogoce nastaviti preko API
+	ob.Obscured = nil
+
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	existing, err := speciesService(r, sh.SpeciesService).Observation(id)
 	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Opazanje s tem ID ne obstaja")
+		return
+	}
+	if *existing.User != *u.ID && !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "Opazanje lahko ureja le avtor ali moderator")
+		return
+	}
+	if ob.User != nil && *ob.User != *existing.User {
+		respondWithError(w, http.StatusBadRequest, "Avtorja opazanja ni mogoce spremeniti")
+		return
+	}
+	if ob.Checklist != nil {
+		c, err := speciesService(r, sh.SpeciesService).Checklist(*ob.Checklist)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Popis s tem ID ne obstaja")
+			return
+		}
+		if *c.User != *existing.User {
+			respondWithError(w, http.StatusForbidden, "Opazanje je mogoce dodati le v popis avtorja opazanja")
+			return
+		}
+	}
+
+	if err := speciesService(r, sh.SpeciesService).UpdateObservation(id, ob, auditActor(r, u)); err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -370,14 +879,58 @@ func (sh *SpeciesHandler) UpdateObservation(w http.ResponseWriter, r *http.Reque
 	respondWithJSON(w, http.StatusNoContent, nil)
 }
 
-// DeleteObservation izbrise dolocen opazovalni list
+// DeleteObservation izbrise dolocen opazovalni list (mehko, do trajne odstranitve ga je mogoce obnoviti).
+// Izbrise ga lahko le avtor ali moderator
 func (sh *SpeciesHandler) DeleteObservation(w http.ResponseWriter, r *http.Request) {
 	id, parseErr := getIDFromURL(w, r, "id")
 	if parseErr {
 		return
 	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	ob, err := speciesService(r, sh.SpeciesService).Observation(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Opazanje s tem ID ne obstaja")
+		return
+	}
+	if *ob.User != *u.ID && !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "Opazanje lahko izbrise le avtor ali moderator")
+		return
+	}
+
+	if err := speciesService(r, sh.SpeciesService).DeleteObservation(id, auditActor(r, u)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// RestoreObservation obnovi izbrisan opazovalni list, obnovi ga lahko le avtor ali moderator
+func (sh *SpeciesHandler) RestoreObservation(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	u, err := currentUser(r, sh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	ob, err := speciesService(r, sh.SpeciesService).DeletedObservation(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if *ob.User != *u.ID && !u.IsModerator() {
+		respondWithError(w, http.StatusForbidden, "Opazanje lahko obnovi le avtor ali moderator")
+		return
+	}
 
-	if err := sh.SpeciesService.DeleteObservation(id); err != nil {
+	if err := speciesService(r, sh.SpeciesService).RestoreObservation(id, auditActor(r, u)); err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -0,0 +1,357 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSpecies vrne vrsto Passer domesticus (enako kot scripts/sample-data.sql)
+func testSpecies() *biolog.Species {
+	id, name, genus := 5231190, "Passer domesticus", "Passer"
+	return &biolog.Species{ID: &id, Species: &name, CanonicalName: &name, Genus: &genus}
+}
+
+// testObservation vrne javno opazanje vrste testSpecies
+func testObservation() *biolog.Observation {
+	id, quantity, user, species, public := 1, 6, 10000000, 5231190, true
+	loc := "POINT(-71.060316 48.432044)"
+	return &biolog.Observation{ID: &id, Quantity: &quantity, User: &user, Species: &species,
+		PublicVisibility: &public, SightingLocation: &loc}
+}
+
+// TestGetAllSpecies preveri pridobivanje vseh vrst
+func TestGetAllSpecies(t *testing.T) {
+	h, _, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+
+	ss.AllSpeciesFn = func() ([]biolog.Species, error) { return []biolog.Species{*testSpecies()}, nil }
+	rec := doRequest(h, "GET", "/species", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var sps []biolog.Species
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sps))
+		assert.Equal(t, []biolog.Species{*testSpecies()}, sps)
+	}
+
+	ss.AllSpeciesFn = func() ([]biolog.Species, error) { return nil, errors.New("db down") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species", "", auth).Code)
+}
+
+// TestCreateSpecies preveri kreiranje vrste
+func TestCreateSpecies(t *testing.T) {
+	h, _, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	ss.CreateSpeciesFn = func(sp *biolog.Species) (*biolog.Species, error) {
+		assert.Equal(t, testSpecies(), sp)
+		return sp, nil
+	}
+
+	body, _ := json.Marshal(testSpecies())
+	rec := doRequest(h, "POST", "/species", string(body), auth)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, string(body), rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species", "[", auth).Code)
+
+	ss.CreateSpeciesFn = func(sp *biolog.Species) (*biolog.Species, error) { return nil, errors.New("duplicate") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species", string(body), auth).Code)
+}
+
+// TestSpeciesByGBIFKey preveri pridobivanje, posodabljanje in brisanje vrste po GBIF kljucu
+func TestSpeciesByGBIFKey(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) {
+		if id == 5231190 {
+			return testSpecies(), nil
+		}
+		return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	ss.UpdateSpeciesFn = func(gbifKey int, sp biolog.Species, by biolog.Actor) error {
+		assert.Equal(t, 5231190, gbifKey)
+		assert.Equal(t, "Passerus", *sp.Genus)
+		return nil
+	}
+	ss.DeleteSpeciesFn = func(gbifKey int, by biolog.Actor) error {
+		if gbifKey == 5231190 {
+			return errors.New("species has observations")
+		}
+		return nil
+	}
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/5231190", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/1", "", auth).Code)
+	// Prevelik kljuc ne sme povzrociti napake pri pretvarjanju
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/99999999999", "", auth).Code)
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/5231190", `{"genus": "Passerus"}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/species/5231190", "", auth).Code)
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "DELETE", "/species/5231190", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/1", "", auth).Code)
+}
+
+// TestObservations preveri pridobivanje in kreiranje opazanj
+func TestObservations(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationsFn = func(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+		if f.QualityGrade != nil {
+			assert.Equal(t, biolog.GradeResearch, *f.QualityGrade)
+		}
+		return []biolog.Observation{*testObservation()}, nil
+	}
+	ss.CreateObservationFn = func(o *biolog.Observation) (*biolog.Observation, error) {
+		assert.Equal(t, 6, *o.Quantity)
+		return testObservation(), nil
+	}
+
+	rec := doRequest(h, "GET", "/species/observations", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var obs []biolog.Observation
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &obs))
+		assert.Equal(t, []biolog.Observation{*testObservation()}, obs)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations?qualityGrade=research", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/observations?qualityGrade=great", "", auth).Code)
+
+	body, _ := json.Marshal(testObservation())
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/observations", string(body), auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations", "", auth).Code)
+
+	ss.CreateObservationFn = func(o *biolog.Observation) (*biolog.Observation, error) { return nil, errors.New("fk violation") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/observations", string(body), auth).Code)
+}
+
+// TestCreateObservationAuthor preveri, da je avtor novega opazanja prijavljen uporabnik in popis njegov
+func TestCreateObservationAuthor(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ChecklistFn = func(id int) (*biolog.Checklist, error) {
+		switch id {
+		case 1:
+			return testChecklist(10000000), nil
+		case 2:
+			return testChecklist(10000001), nil
+		}
+		return nil, errors.New("ni popisa")
+	}
+	ss.CreateObservationFn = func(o *biolog.Observation) (*biolog.Observation, error) {
+		assert.Equal(t, 10000000, *o.User)
+		return testObservation(), nil
+	}
+
+	// Avtorja iz telesa zahtevka se ne uposteva
+	ob, other := testObservation(), 10000001
+	ob.User = &other
+	body, _ := json.Marshal(ob)
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/observations", string(body), auth).Code)
+
+	for checklist, code := range map[int]int{1: http.StatusCreated, 2: http.StatusForbidden, 3: http.StatusBadRequest} {
+		ob.Checklist = &checklist
+		body, _ = json.Marshal(ob)
+		assert.Equal(t, code, doRequest(h, "POST", "/species/observations", string(body), auth).Code, "popis %d", checklist)
+	}
+}
+
+// TestObservationByID preveri pridobivanje, posodabljanje in brisanje opazanja po ID
+func TestObservationByID(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) {
+		if id == 1 {
+			return testObservation(), nil
+		}
+		return nil, errors.New("not found")
+	}
+	ss.UpdateObservationFn = func(id int, ob biolog.Observation, by biolog.Actor) error {
+		assert.Equal(t, 1, id)
+		assert.Equal(t, 12, *ob.Quantity)
+		return nil
+	}
+	ss.DeleteObservationFn = func(id int, by biolog.Actor) error {
+		assert.Equal(t, 10000000, by.User)
+		return nil
+	}
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/observations/1", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/species/observations/2", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/observations/1", `{"quantity": 12}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/species/observations/1", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/observations/1", "", auth).Code)
+
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "PATCH", "/species/observations/2", `{"quantity": 12}`, auth).Code)
+	ss.DeleteObservationFn = func(id int, by biolog.Actor) error { return errors.New("delete failed") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "DELETE", "/species/observations/1", "", auth).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "DELETE", "/species/observations/2", "", auth).Code)
+}
+
+// TestUpdateObservationPermissions preveri, da opazanje ureja le avtor ali moderator, da avtorja ni mogoce
+// spremeniti in da je opazanje mogoce dodati le v avtorjev popis
+func TestUpdateObservationPermissions(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	ss.ChecklistFn = func(id int) (*biolog.Checklist, error) {
+		if id == 1 {
+			return testChecklist(10000000), nil
+		}
+		return testChecklist(10000001), nil
+	}
+	updated := 0
+	ss.UpdateObservationFn = func(id int, ob biolog.Observation, by biolog.Actor) error {
+		updated++
+		return nil
+	}
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/observations/1", `{"checklist": 1}`, auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/observations/1", `{"user": 10000000}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/species/observations/1", `{"user": 10000001}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/species/observations/1", `{"checklist": 2}`, auth).Code)
+
+	// Tuje opazanje lahko ureja le moderator
+	otherID := 10000001
+	user.ID = &otherID
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/species/observations/1", `{"quantity": 2}`, auth).Code)
+	role := biolog.RoleModerator
+	user.Role = &role
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/species/observations/1", `{"quantity": 2}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/species/observations/1", `{"checklist": 2}`, auth).Code)
+	assert.Equal(t, 3, updated)
+}
+
+// TestDeleteRestoreObservation preveri, da opazanje izbrise in obnovi le avtor ali moderator
+func TestDeleteRestoreObservation(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	other := testObservation()
+	otherID := 10000001
+	other.User = &otherID
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return other, nil }
+	ss.DeletedObservationFn = func(id int) (*biolog.Observation, error) {
+		if id == 1 {
+			return other, nil
+		}
+		return nil, errors.New("Izbrisano opazanje s tem ID ne obstaja")
+	}
+	ss.DeleteObservationFn = func(id int, by biolog.Actor) error { return nil }
+	restored := 0
+	ss.RestoreObservationFn = func(id int, by biolog.Actor) error {
+		restored++
+		return nil
+	}
+
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/species/observations/1", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/species/observations/1/restore", "", auth).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "POST", "/species/observations/2/restore", "", auth).Code)
+
+	role := biolog.RoleModerator
+	user.Role = &role
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/species/observations/1", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/species/observations/1/restore", "", auth).Code)
+	assert.Equal(t, 1, restored)
+}
+
+// TestObscuredLocations preveri posplositev lokacij obcutljivih vrst za druge uporabnike
+func TestObscuredLocations(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	viewer := testUser()
+	otherID := 10000001
+	viewer.ID = &otherID
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return viewer, nil }
+	ss.ObservationsFn = func(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+		return []biolog.Observation{*testObservation()}, nil
+	}
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return testObservation(), nil }
+	status := 5
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) {
+		sp := testSpecies()
+		sp.ConservationStatus = &status
+		return sp, nil
+	}
+	ss.ConservationStatusFn = func(id int) (*biolog.ConservationStatus, error) {
+		return &biolog.ConservationStatus{ID: 5, Acronym: "VU"}, nil
+	}
+
+	exact := *testObservation().SightingLocation
+	getObservations := func() []biolog.Observation {
+		var obs []biolog.Observation
+		rec := doRequest(h, "GET", "/species/observations", "", auth)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &obs))
+		return obs
+	}
+
+	// Ranljiva vrsta drugega uporabnika
+	obs := getObservations()
+	if assert.Len(t, obs, 1) && assert.NotNil(t, obs[0].Obscured) {
+		assert.True(t, *obs[0].Obscured)
+		assert.NotEqual(t, exact, *obs[0].SightingLocation)
+	}
+	var ob biolog.Observation
+	rec := doRequest(h, "GET", "/species/observations/1", "", auth)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ob))
+	assert.NotEqual(t, exact, *ob.SightingLocation)
+
+	// Moderator vidi natancno lokacijo
+	moderator := biolog.RoleModerator
+	viewer.Role = &moderator
+	obs = getObservations()
+	assert.Equal(t, exact, *obs[0].SightingLocation)
+	assert.Nil(t, obs[0].Obscured)
+
+	// Vrsta, ki ni ogrozena
+	viewer.Role = nil
+	ss.ConservationStatusFn = func(id int) (*biolog.ConservationStatus, error) {
+		return &biolog.ConservationStatus{ID: 8, Acronym: "LC"}, nil
+	}
+	obs = getObservations()
+	assert.Equal(t, exact, *obs[0].SightingLocation)
+}
+
+// TestMergeSpecies preveri zdruzevanje vrst (le moderator) in preusmeritev sinonima na sprejeto vrsto
+func TestMergeSpecies(t *testing.T) {
+	h, us, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) {
+		sp := testSpecies()
+		if id != *sp.ID {
+			sp.ID, sp.AcceptedKey = &id, sp.ID
+		}
+		return sp, nil
+	}
+	ss.MergeSpeciesFn = func(fromKey, intoKey int, by biolog.Actor) (*biolog.TaxonMerge, error) {
+		assert.Equal(t, 5231191, fromKey)
+		assert.Equal(t, 5231190, intoKey)
+		assert.Equal(t, *user.ID, by.User)
+		return &biolog.TaxonMerge{From: &fromKey, Into: &intoKey, User: &by.User}, nil
+	}
+
+	rec := doRequest(h, "GET", "/species/5231191", "", auth)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/api/v1/species/5231190", rec.Header().Get("Location"))
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/species/5231190", "", auth).Code)
+
+	body := `{"into": 5231190}`
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/species/5231191/merge", body, auth).Code)
+	role := biolog.RoleModerator
+	user.Role = &role
+	assert.Equal(t, http.StatusCreated, doRequest(h, "POST", "/species/5231191/merge", body, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/species/5231191/merge", `{}`, auth).Code)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+)
+
+// StatsHandler je http handler za statistiko opazanj
+type StatsHandler struct {
+	SpeciesService biolog.SpeciesService
+	// Sensitivity doloca vrste, katerih opazanja se v meje projekta stejejo po posplosenih lokacijah
+	Sensitivity biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// StatsParams model.
+//
+// Nacin zdruzevanja pri statistiki opazanj
+// swagger:parameters getObservationStats
+type StatsParams struct {
+	// Zdruzi po species, month, year, user, conservationStatus ali region
+	// in: query
+	// required: true
+	GroupBy string `json:"groupBy"`
+}
+
+// NewStatsHandler kreira novega handlerja za statistiko
+func NewStatsHandler() *StatsHandler {
+	sh := &StatsHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/stats
+
+	// swagger:route GET /stats/observations stats getObservationStats
+	//
+	// Presteje javna opazanja in sesteje kolicino osebkov po skupinah
+	//
+	// Responses:
+	//		200: []observationStat
+	sh.Get("/observations", sh.GetObservationStats)
+
+	return sh
+}
+
+// GetObservationStats vrne stevilo opazanj in sestevek kolicine po skupinah
+// Mozni parametri so:
+// 	- groupBy ... nacin zdruzevanja (species, month, year, user, conservationStatus, region)
+// 	- vsi filtri iz seznama opazanj (glej getObservationFilter)
+func (sh *StatsHandler) GetObservationStats(w http.ResponseWriter, r *http.Request) {
+	groupBy := r.URL.Query().Get("groupBy")
+	if !biolog.ValidStatsGroup(groupBy) {
+		respondWithError(w, http.StatusBadRequest, "Neveljaven nacin zdruzevanja (groupBy)")
+		return
+	}
+	f, parseErr := getObservationFilter(w, r)
+	if parseErr {
+		return
+	}
+	// Statistika je enaka za vse uporabnike, zato se meja projekta preveri na posplosenih lokacijah
+	f.Obscure = &sh.Sensitivity
+
+	stats, err := speciesService(r, sh.SpeciesService).ObservationStats(groupBy, f)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stats)
+}
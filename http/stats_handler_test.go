@@ -0,0 +1,34 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetObservationStats preveri parametre za statistiko opazanj
+func TestGetObservationStats(t *testing.T) {
+	h, _, ss := newTestHandler()
+	auth := "Bearer " + validToken()
+	ss.ObservationStatsFn = func(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error) {
+		assert.Equal(t, biolog.StatsByMonth, groupBy)
+		if assert.NotNil(t, f.QualityGrade) {
+			assert.Equal(t, biolog.GradeResearch, *f.QualityGrade)
+		}
+		return []biolog.ObservationStat{{Key: "2018-06", Observations: 12, Quantity: 57}}, nil
+	}
+
+	rec := doRequest(h, "GET", "/stats/observations?groupBy=month&qualityGrade=research", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var stats []biolog.ObservationStat
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&stats))
+		assert.Equal(t, 57, stats[0].Quantity)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stats/observations", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stats/observations?groupBy=day", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stats/observations?groupBy=year&qualityGrade=great", "", auth).Code)
+}
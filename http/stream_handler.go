@@ -0,0 +1,256 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// Najvecje stevilo opazanj za zadnjim prejetim, ki se ob nadaljevanju toka z Last-Event-ID posljejo za nazaj.
+// Ce jih je vec, se tok po zadnjem poslanem konca in odjemalec ob ponovni povezavi nadaljuje od njega
+const maxStreamReplay = 500
+
+// Opazanje dobi ID ob shranjevanju, v tok pa pride ob potrditvi transakcije, zato je lahko opazanje z manjsim ID
+// potrjeno za zadnjim prejetim. Ob nadaljevanju toka se zato ponovno preberejo opazanja, shranjena do toliko
+// pred zadnjim prejetim in z ID med zadnjimi streamReorderIDs pred njegovim
+const (
+	streamReorderWindow = time.Minute
+	streamReorderIDs    = 500
+)
+
+// Razmik med komentarji, ki ohranjajo povezavo odprto skozi posrednike
+const streamKeepAlive = 25 * time.Second
+
+// StreamHandler je http handler za tokove dogodkov (Server-Sent Events). Tokovi so javni (brez JWT),
+// saj EventSource v brskalniku ne more poslati glave Authorization, vsebujejo pa le javna opazanja
+type StreamHandler struct {
+	SpeciesService    biolog.SpeciesService
+	ObservationStream biolog.ObservationStream
+	Sensitivity       biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// StreamParams model.
+//
+// Filter toka novih opazanj
+// swagger:parameters streamObservations
+type StreamParams struct {
+	// Opazena vrsta (GBIF kljuc)
+	// in: query
+	Species int `json:"species"`
+
+	// Pravokotnik minLon,minLat,maxLon,maxLat
+	// in: query
+	BBox string `json:"bbox"`
+
+	// ID zadnjega prejetega opazanja, poslana so opazanja, shranjena za njim oz. najvec minuto pred njim
+	// (ta so lahko ze prejeta, odjemalec jih prepozna po ID), urejena po ID. Ce jih je vec kot 500, se tok
+	// po zadnjem poslanem konca. EventSource ga ob ponovni povezavi doda sam
+	// in: header
+	LastEventID int `json:"Last-Event-ID"`
+}
+
+// NewStreamHandler kreira novega handlerja za tokove dogodkov
+func NewStreamHandler() *StreamHandler {
+	st := &StreamHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/stream
+
+	// swagger:route GET /stream/observations stream streamObservations
+	//
+	// Tok novih javnih opazanj (text/event-stream), vsak dogodek observation ima za ID identifikator opazanja
+	//
+	// Produces:
+	// - text/event-stream
+	//
+	// Responses:
+	//		400: description: Neveljaven filter
+	//		200: description: Tok dogodkov z opazanji
+	st.Get("/observations", st.StreamObservations)
+
+	return st
+}
+
+// StreamObservations posilja nova javna opazanja, dokler se odjemalec ne odjavi.
+// Ce zahteva vsebuje Last-Event-ID, najprej poslje opazanja, shranjena po tem opazanju (glej streamReorderWindow),
+// narascajoce po ID. Ce jih je vec kot maxStreamReplay, se tok konca, odjemalec pa nadaljuje od zadnjega poslanega
+// Mozni parametri so:
+// 	- species ... GBIF kljuc opazene vrste
+// 	- bbox ... pravokotnik minLon,minLat,maxLon,maxLat
+func (st *StreamHandler) StreamObservations(w http.ResponseWriter, r *http.Request) {
+	// Pravokotnik se preveri na posplosenih lokacijah, ki jih tok poslje
+	f := biolog.ObservationFilter{Obscure: &st.Sensitivity}
+	q := r.URL.Query()
+	if species := q.Get("species"); species != "" {
+		key, err := strconv.Atoi(species)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven GBIF kljuc vrste")
+			return
+		}
+		f.Species = &key
+	}
+	if bbox := q.Get("bbox"); bbox != "" {
+		var ok bool
+		if f.BBox, ok = parseBBox(bbox); !ok {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven pravokotnik, pricakovan je minLon,minLat,maxLon,maxLat")
+			return
+		}
+	}
+	lastID := 0
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		id, err := strconv.Atoi(last)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Neveljaven Last-Event-ID")
+			return
+		}
+		lastID = id
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streznik ne podpira tokov dogodkov")
+		return
+	}
+
+	// Prijava pred branjem zamujenih opazanj, da se med branjem ne izgubi nobeno novo
+	observations, unsubscribe := st.ObservationStream.Subscribe()
+	defer unsubscribe()
+	var replay []biolog.Observation
+	// Ze poslana opazanja, ki jih tok ob prijavi lahko poslje se enkrat
+	replayed := make(map[int]bool)
+	// Ali je opazanj za nazaj vec, kot jih tok poslje
+	truncated := false
+	if lastID > 0 {
+		ss := speciesService(r, st.SpeciesService)
+		rf := f
+		if last, err := ss.Observation(lastID); err == nil && last.CreatedAt != nil {
+			// Opazanj z ID do lastID je v strani najvec streamReorderIDs, zato polna stran vedno seze cez lastID
+			since, after := last.CreatedAt.Add(-streamReorderWindow), lastID-streamReorderIDs
+			if after < 0 {
+				after = 0
+			}
+			rf.CreatedSince, rf.AfterID, rf.Limit = &since, &after, streamReorderIDs+maxStreamReplay
+			replayed[lastID] = true
+		} else {
+			rf.AfterID, rf.Limit = &lastID, maxStreamReplay
+		}
+		var err error
+		if replay, err = ss.Observations(rf); err != nil {
+			log.Error(err)
+			respondWithError(w, http.StatusInternalServerError, "Napaka pri branju opazanj")
+			return
+		}
+		truncated = len(replay) == rf.Limit
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	for _, o := range replay {
+		if replayed[*o.ID] {
+			continue
+		}
+		o, ok := st.obscure(o)
+		if !ok || !st.writeObservation(w, o) {
+			return
+		}
+		replayed[*o.ID] = true
+	}
+	flusher.Flush()
+	// Opazanja za zadnjim poslanim se posljejo ob ponovni povezavi, ki nadaljuje od njega
+	if truncated {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case o, ok := <-observations:
+			// Zaprt kanal pomeni, da odjemalec ne sledi, ob ponovni povezavi nadaljuje z Last-Event-ID
+			if !ok {
+				return
+			}
+			if replayed[*o.ID] {
+				continue
+			}
+			o, ok = st.obscure(o)
+			if !ok {
+				return
+			}
+			if !matchesStream(o, f) {
+				continue
+			}
+			if !st.writeObservation(w, o) {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// obscure poslosi lokacijo obcutljive vrste enako kot za neprijavljene uporabnike. Ob napaki vrne false
+func (st *StreamHandler) obscure(o biolog.Observation) (biolog.Observation, bool) {
+	obs := []biolog.Observation{o}
+	if err := obscureSensitive(st.SpeciesService, st.Sensitivity, &biolog.User{}, obs); err != nil {
+		log.Error(err)
+		return o, false
+	}
+	return obs[0], true
+}
+
+// writeObservation zapise opazanje kot dogodek observation. Ob napaki vrne false
+func (st *StreamHandler) writeObservation(w http.ResponseWriter, o biolog.Observation) bool {
+	data, err := json.Marshal(o)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: observation\ndata: %s\n\n", *o.ID, data)
+	return err == nil
+}
+
+// matchesStream preveri ali novo opazanje ustreza filtru toka, pravokotnik se preveri na ze posploseni lokaciji
+func matchesStream(o biolog.Observation, f biolog.ObservationFilter) bool {
+	if f.Species != nil && (o.Species == nil || *o.Species != *f.Species) {
+		return false
+	}
+	if len(f.BBox) == 4 {
+		if o.SightingLocation == nil {
+			return false
+		}
+		lon, lat, ok := biolog.ParsePoint(*o.SightingLocation)
+		return ok && biolog.InBBox(f.BBox, lon, lat)
+	}
+	return true
+}
+
+// parseBBox prebere pravokotnik v obliki minLon,minLat,maxLon,maxLat
+func parseBBox(s string) ([]float64, bool) {
+	parts := strings.Split(s, ",")
+	bbox := make([]float64, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, false
+		}
+		bbox[i] = v
+	}
+	return bbox, validBBox(bbox)
+}
@@ -0,0 +1,203 @@
+package http_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// readEvent prebere en dogodek toka (vrstice do prazne vrstice) in vrne njegova polja
+func readEvent(r *bufio.Reader) (map[string]string, error) {
+	event := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return event, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event, nil
+		}
+		if i := strings.Index(line, ": "); i > 0 {
+			event[line[:i]] = line[i+2:]
+		}
+	}
+}
+
+// TestStreamObservations preveri nadaljevanje toka z Last-Event-ID, filtre in posiljanje novih opazanj
+func TestStreamObservations(t *testing.T) {
+	h, _, ss := newTestHandler()
+	stream := h.StreamHandler.ObservationStream.(*mock.ObservationStream)
+	live := make(chan biolog.Observation, 8)
+	stream.SubscribeFn = func() (<-chan biolog.Observation, func()) { return live, func() {} }
+	ss.SpeciesFn = func(gbifKey int) (*biolog.Species, error) { return testSpecies(), nil }
+	observation := func(id int, loc string) biolog.Observation {
+		o := testObservation()
+		o.ID, o.SightingLocation = &id, &loc
+		return *o
+	}
+	inside, outside := "POINT(-71.060316 48.432044)", "POINT(14.5058 46.0569)"
+	created := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) {
+		o := observation(id, inside)
+		o.CreatedAt = &created
+		return &o, nil
+	}
+	ss.ObservationsFn = func(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+		// Ponovno se preberejo tudi opazanja, shranjena malo pred zadnjim prejetim
+		if assert.NotNil(t, f.AfterID) {
+			assert.Equal(t, 0, *f.AfterID)
+		}
+		if assert.NotNil(t, f.CreatedSince) {
+			assert.True(t, created.Add(-time.Minute).Equal(*f.CreatedSince))
+		}
+		assert.Equal(t, 1000, f.Limit)
+		assert.NotNil(t, f.Obscure)
+		assert.Equal(t, 5231190, *f.Species)
+		assert.Equal(t, []float64{-72, 48, -70, 49}, f.BBox)
+		return []biolog.Observation{observation(1, inside), observation(2, inside), observation(3, inside)}, nil
+	}
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+	req, _ := http.NewRequest("GET", server.URL+apiPrefix+"/stream/observations?species=5231190&bbox=-72,48,-70,49", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	r := bufio.NewReader(resp.Body)
+
+	event, _ := readEvent(r)
+	assert.Equal(t, "5000", event["retry"])
+	// Zamujena opazanja se posljejo po vrsti, zadnje prejeto se ne ponovi
+	for _, id := range []string{"2", "3"} {
+		event, _ = readEvent(r)
+		assert.Equal(t, id, event["id"])
+		assert.Equal(t, "observation", event["event"])
+		assert.Contains(t, event["data"], `"id":`+id)
+	}
+
+	// Ze poslano opazanje in opazanje izven pravokotnika se preskocita, kasneje potrjeno opazanje z manjsim ID ne
+	live <- observation(3, inside)
+	live <- observation(6, outside)
+	live <- observation(5, inside)
+	live <- observation(4, inside)
+	for _, id := range []string{"5", "4"} {
+		event, _ = readEvent(r)
+		assert.Equal(t, id, event["id"])
+	}
+
+	// Zaprt kanal konca tok
+	close(live)
+	_, err = readEvent(r)
+	assert.Error(t, err)
+}
+
+// TestStreamReplayLimit preveri, da se tok po polni strani opazanj za nazaj konca, da odjemalec nadaljuje
+// od zadnjega poslanega brez vrzeli
+func TestStreamReplayLimit(t *testing.T) {
+	h, _, ss := newTestHandler()
+	stream := h.StreamHandler.ObservationStream.(*mock.ObservationStream)
+	live := make(chan biolog.Observation, 1)
+	stream.SubscribeFn = func() (<-chan biolog.Observation, func()) { return live, func() {} }
+	ss.SpeciesFn = func(gbifKey int) (*biolog.Species, error) { return testSpecies(), nil }
+	ss.ObservationFn = func(id int) (*biolog.Observation, error) { return nil, errors.New("ni opazanja") }
+	ss.ObservationsFn = func(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+		if assert.NotNil(t, f.AfterID) {
+			assert.Equal(t, 10, *f.AfterID)
+		}
+		var obs []biolog.Observation
+		for id := *f.AfterID + 1; id <= *f.AfterID+f.Limit; id++ {
+			id, o := id, testObservation()
+			o.ID = &id
+			obs = append(obs, *o)
+		}
+		return obs, nil
+	}
+	live <- *testObservation()
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+	req, _ := http.NewRequest("GET", server.URL+apiPrefix+"/stream/observations", nil)
+	req.Header.Set("Last-Event-ID", "10")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	readEvent(r)
+
+	var last string
+	n := 0
+	for {
+		event, err := readEvent(r)
+		if err != nil {
+			break
+		}
+		last = event["id"]
+		n++
+	}
+	assert.Equal(t, 500, n)
+	assert.Equal(t, "510", last)
+}
+
+// TestStreamObscuredBBox preveri, da se pravokotnik preveri na posploseni lokaciji obcutljive vrste
+func TestStreamObscuredBBox(t *testing.T) {
+	h, _, ss := newTestHandler()
+	h.StreamHandler.Sensitivity = biolog.DefaultSensitivityPolicy([]int{5231190})
+	stream := h.StreamHandler.ObservationStream.(*mock.ObservationStream)
+	live := make(chan biolog.Observation, 8)
+	stream.SubscribeFn = func() (<-chan biolog.Observation, func()) { return live, func() {} }
+	ss.SpeciesFn = func(gbifKey int) (*biolog.Species, error) { return testSpecies(), nil }
+
+	// Natancna lokacija je v pravokotniku, posplosena (sredisce celice) pa ne
+	sensitive := testObservation()
+	lon, lat, _ := biolog.ParsePoint(*sensitive.SightingLocation)
+	obscured, _ := biolog.ObscureLocation(*sensitive.SightingLocation)
+	olon, olat, _ := biolog.ParsePoint(obscured)
+	if !assert.False(t, biolog.InBBox([]float64{lon - 0.001, lat - 0.001, lon + 0.001, lat + 0.001}, olon, olat)) {
+		return
+	}
+	other := testObservation()
+	id, species := 2, 1
+	other.ID, other.Species = &id, &species
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+	resp, err := http.Get(fmt.Sprintf("%s%s/stream/observations?bbox=%f,%f,%f,%f", server.URL, apiPrefix,
+		lon-0.001, lat-0.001, lon+0.001, lat+0.001))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	readEvent(r)
+
+	live <- *sensitive
+	live <- *other
+	event, _ := readEvent(r)
+	assert.Equal(t, "2", event["id"])
+	close(live)
+}
+
+// TestStreamObservationsInvalid preveri zavrnitev neveljavnih filtrov, tok je dostopen brez JWT
+func TestStreamObservationsInvalid(t *testing.T) {
+	h, _, _ := newTestHandler()
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stream/observations?bbox=16.6,45.4,13.3,46.9", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stream/observations?bbox=1,2,3", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/stream/observations?species=vrabec", "", "").Code)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+)
+
+// Cas, ko lahko odjemalci in posredniki hranijo ploscico (v sekundah)
+const tileMaxAge = 300
+
+// TileHandler je http handler za vektorske ploscice (Mapbox Vector Tile)
+type TileHandler struct {
+	SpeciesService biolog.SpeciesService
+	// Sensitivity doloca vrste, katerih lokacije so na ploscicah posplosene
+	Sensitivity biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// TileCoordinates model.
+//
+// Koordinate ploscice
+// swagger:parameters getObservationTile
+type TileCoordinates struct {
+	// in: path
+	// required: true
+	Z int `json:"z"`
+	// in: path
+	// required: true
+	X int `json:"x"`
+	// in: path
+	// required: true
+	Y int `json:"y"`
+}
+
+// NewTileHandler kreira novega handlerja za vektorske ploscice
+func NewTileHandler() *TileHandler {
+	th := &TileHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/tiles. Ploscice vsebujejo le javna opazanja, zato ne zahtevajo
+	// JWT tokena in jih lahko hranijo tudi posredniki (CDN)
+
+	// swagger:route GET /tiles/observations/{z}/{x}/{y}.mvt tiles getObservationTile
+	//
+	// Pridobi vektorsko ploscico z javnimi opazanji
+	//
+	// Produces:
+	//	- application/vnd.mapbox-vector-tile
+	//
+	// Responses:
+	//		200:
+	th.Get("/observations/{z:[0-9]+}/{x:[0-9]+}/{y:[0-9]+}.mvt", th.GetObservationTile)
+
+	return th
+}
+
+// GetObservationTile vrne vektorsko ploscico z opazanji, prazna ploscica ima prazno telo
+// Mozni parametri so vsi filtri iz seznama opazanj (glej getObservationFilter)
+func (th *TileHandler) GetObservationTile(w http.ResponseWriter, r *http.Request) {
+	var zxy [3]int
+	for i, param := range []string{"z", "x", "y"} {
+		v, parseErr := getIDFromURL(w, r, param)
+		if parseErr {
+			return
+		}
+		zxy[i] = v
+	}
+	if !biolog.ValidTile(zxy[0], zxy[1], zxy[2]) {
+		respondWithError(w, http.StatusNotFound, "Ploscica ne obstaja")
+		return
+	}
+	f, parseErr := getObservationFilter(w, r)
+	if parseErr {
+		return
+	}
+	// Ploscice so javne, zato so lokacije obcutljivih vrst vedno posplosene
+	f.Obscure = &th.Sensitivity
+
+	tile, err := speciesService(r, th.SpeciesService).ObservationTile(zxy[0], zxy[1], zxy[2], f)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ETag omogoci odjemalcu, da po preteku ploscico preveri brez ponovnega prenosa
+	sum := sha1.Sum(tile)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(tileMaxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+	w.WriteHeader(http.StatusOK)
+	w.Write(tile)
+}
@@ -0,0 +1,40 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetObservationTile preveri, da je ploscica javna, da ima glave za predpomnjenje in da se preverijo koordinate
+func TestGetObservationTile(t *testing.T) {
+	h, _, ss := newTestHandler()
+	ss.ObservationTileFn = func(z, x, y int, f biolog.ObservationFilter) ([]byte, error) {
+		assert.Equal(t, []int{14, 8892, 5800}, []int{z, x, y})
+		assert.NotNil(t, f.Obscure)
+		return []byte{0x1a, 0x00}, nil
+	}
+
+	// Brez JWT tokena
+	rec := doRequest(h, "GET", "/tiles/observations/14/8892/5800.mvt", "", "")
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		assert.Equal(t, "application/vnd.mapbox-vector-tile", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, []byte{0x1a, 0x00}, rec.Body.Bytes())
+	}
+
+	// Ploscica se ni spremenila
+	req := httptest.NewRequest("GET", "/api/v1/tiles/observations/14/8892/5800.mvt", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	notModified := httptest.NewRecorder()
+	h.ServeHTTP(notModified, req)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.Bytes())
+
+	// Ploscica izven obsega
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/tiles/observations/1/2/0.mvt", "", "").Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/tiles/observations/23/0/0.mvt", "", "").Code)
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Atribut spana z ID zahteve (middleware.RequestID), ki je zapisan tudi v dnevniku in revizijski sledi
+const requestIDAttribute = attribute.Key("request.id")
+
+// traceRequests za vsako zahtevo ustvari span, poimenovan po metodi in vzorcu poti (npr. GET /api/v1/species/{gbifKey}).
+// Ce odjemalec poslje traceparent, je span njegov otrok. Mora slediti middleware.RequestID
+func traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Tracer().Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				requestIDAttribute.String(middleware.GetReqID(ctx)),
+			))
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		// Vzorec poti je znan sele po usmerjanju
+		route := routePattern(r)
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// routePattern vrne vzorec poti, po kateri je chi usmeril zahtevo, oz. "unmatched"
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return "unmatched"
+}
+
+// contextUserService in contextSpeciesService implementirajo servici, ki poizvedbe lahko izvedejo v
+// kontekstu zahteve (npr. postgres), da so spani poizvedb otroci spana zahteve
+type contextUserService interface {
+	WithContext(ctx context.Context) biolog.UserService
+}
+
+type contextSpeciesService interface {
+	WithContext(ctx context.Context) biolog.SpeciesService
+}
+
+// userService vrne us vezan na kontekst zahteve r, ce ga us podpira
+func userService(r *http.Request, us biolog.UserService) biolog.UserService {
+	if cs, ok := us.(contextUserService); ok {
+		return cs.WithContext(r.Context())
+	}
+	return us
+}
+
+// speciesService vrne ss vezan na kontekst zahteve r, ce ga ss podpira
+func speciesService(r *http.Request, ss biolog.SpeciesService) biolog.SpeciesService {
+	if cs, ok := ss.(contextSpeciesService); ok {
+		return cs.WithContext(r.Context())
+	}
+	return ss
+}
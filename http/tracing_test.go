@@ -0,0 +1,110 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	spanExporter = tracetest.NewInMemoryExporter()
+	setupSpans   sync.Once
+)
+
+// recordSpans nastavi globalnega ponudnika, ki spane shrani v pomnilnik, in izprazni ze shranjene
+func recordSpans() *tracetest.InMemoryExporter {
+	setupSpans.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	spanExporter.Reset()
+	return spanExporter
+}
+
+// findSpan vrne span z imenom name
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	require.Failf(t, "span not found", "%s not in %v", name, spans)
+	return tracetest.SpanStub{}
+}
+
+// spanAttribute vrne vrednost atributa key
+func spanAttribute(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// contextUserService si zapomni kontekst, v katerem handler izvaja poizvedbe
+type contextUserService struct {
+	*mock.UserService
+	ctx context.Context
+}
+
+func (s *contextUserService) WithContext(ctx context.Context) biolog.UserService {
+	s.ctx = ctx
+	return s.UserService
+}
+
+// TestTraceRequests preveri span zahteve: ime po vzorcu poti, ID zahteve in status ter kontekst poizvedb
+func TestTraceRequests(t *testing.T) {
+	spans := recordSpans()
+	h, us, _ := newTestHandler()
+	us.UserFn = func(id int) (*biolog.User, error) { return testUser(), nil }
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	cus := &contextUserService{UserService: us}
+	h.UserHandler.UserService = cus
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/users/10000000", "", "Bearer "+validToken()).Code)
+	span := findSpan(t, spans.GetSpans(), `GET /api/v1/users/{id:\d{8}}/`)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.NotEmpty(t, spanAttribute(span, "request.id").AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttribute(span, "http.response.status_code").AsInt64())
+	assert.Equal(t, `/api/v1/users/{id:\d{8}}/`, spanAttribute(span, "http.route").AsString())
+	// Poizvedbe se izvedejo v kontekstu spana zahteve
+	require.NotNil(t, cus.ctx)
+	assert.Equal(t, span.SpanContext, trace.SpanContextFromContext(cus.ctx))
+
+	// Napaka streznika se oznaci na spanu
+	spans.Reset()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return nil, assert.AnError }
+	assert.Equal(t, http.StatusInternalServerError, doRequest(h, "GET", "/users/me", "", "Bearer "+validToken()).Code)
+	span = findSpan(t, spans.GetSpans(), "GET /api/v1/users/me")
+	assert.Equal(t, codes.Error, span.Status.Code)
+}
+
+// TestTraceParent preveri, da se zahteva s traceparent prikljuci sledi odjemalca
+func TestTraceParent(t *testing.T) {
+	spans := recordSpans()
+	h, _, _ := newTestHandler()
+
+	req := httptest.NewRequest("GET", apiPrefix+"/ni-poti", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	span := findSpan(t, spans.GetSpans(), "GET /api/v1/*")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+	assert.True(t, span.Parent.IsRemote())
+}
@@ -14,13 +14,14 @@ import (
 // chi Subrouter za ustrezne endpointe
 type UserHandler struct {
 	UserService biolog.UserService
+	Exports     *ExportHandler
 	*chi.Mux
 }
 
 // UserID parameter model.
 //
 // Uporablja se za operacije, ki pricakujejo ID uporabnika v poti
-// swagger:parameters getUserByID deleteUser updateUser
+// swagger:parameters getUserByID deleteUser restoreUser updateUser exportUser eraseUser followUser unfollowUser
 type UserID struct {
 	// ID uporabnika
 	//
@@ -31,6 +32,20 @@ type UserID struct {
 	ID int32
 }
 
+// EraseParams model.
+//
+// Izbira pri trajnem izbrisu racuna
+// swagger:parameters eraseUser
+type EraseParams struct {
+	// Ali se javna opazanja ohranijo pod anonimnim izbrisanim uporabnikom (keep) ali zbrisejo skupaj z vso
+	// vsebino uporabnika (delete)
+	//
+	// in: query
+	// required: true
+	// enum: keep,delete
+	Observations string `json:"observations"`
+}
+
 // UserExtID parameter model.
 //
 // Uporablja se za operacije, ki se sklicujejo na uporabnike glede na id zunajnega avtentikatorja
@@ -105,6 +120,25 @@ func NewUserHandler() *UserHandler {
 	//		200: []user
 	u.Get("/me", u.MeDetails)
 
+	// swagger:route GET /users/me/following user getFollowing
+	//
+	// Pridobi uporabnike, ki jih prijavljeni uporabnik spremlja
+	//
+	// Responses:
+	//		400: description: Prislo je do napake
+	//		200: []user
+	u.Get("/me/following", u.GetFollowing)
+
+	// swagger:route POST /users/me/export user exportMe
+	//
+	// Zacne izvoz vseh osebnih podatkov prijavljenega uporabnika v zip datoteko (profil, opazanja
+	// kot CSV in GeoJSON, komentarji in identifikacije), ki se prenese preko kratkotrajne povezave
+	//
+	// Responses:
+	//		400: description: Prislo je do napake
+	//		202: export
+	u.Post("/me/export", u.ExportMe)
+
 	// TODO:
 	//	- pridobi ID iz URL preko middleware
 	u.Route("/{id:\\d{8}}", func(r chi.Router) {
@@ -128,12 +162,59 @@ func NewUserHandler() *UserHandler {
 
 		// swagger:route DELETE /users/{id} users deleteUser
 		//
-		// Zbrise uproabniski racun in vse zapise o uporabniku
+		// Zbrise uporabniski racun (uporabnik sam ali administrator), do trajne odstranitve ga je mogoce obnoviti
 		//
 		// Responses:
 		//		400: description: Prislo je do napake
 		//		204:
 		r.Delete("/", u.DeleteUser)
+
+		// swagger:route POST /users/{id}/restore users restoreUser
+		//
+		// Obnovi izbrisan uporabniski racun (administrator)
+		//
+		// Responses:
+		//		400: description: Prislo je do napake
+		//		204:
+		r.Post("/restore", u.RestoreUser)
+
+		// swagger:route POST /users/{id}/erase users eraseUser
+		//
+		// Trajno izbrise uporabniski racun (uporabnik sam ali administrator). Osebni podatki se odstranijo, javna
+		// opazanja pa se ohranijo pod anonimnim izbrisanim uporabnikom ali zbrisejo, kot izbere uporabnik
+		//
+		// Responses:
+		//		400: description: Prislo je do napake
+		//		204:
+		r.Post("/erase", u.EraseUser)
+
+		// swagger:route POST /users/{id}/follow users followUser
+		//
+		// Prijavljeni uporabnik zacne spremljati uporabnika, njegova javna opazanja, identifikacije
+		// in komentarji so v viru dejavnosti
+		//
+		// Responses:
+		//		400: description: Prislo je do napake
+		//		204:
+		r.Post("/follow", u.FollowUser)
+
+		// swagger:route DELETE /users/{id}/follow users unfollowUser
+		//
+		// Prijavljeni uporabnik preneha spremljati uporabnika
+		//
+		// Responses:
+		//		404: description: Uporabnik tega uporabnika ne spremlja
+		//		204:
+		r.Delete("/follow", u.UnfollowUser)
+
+		// swagger:route POST /users/{id}/export users exportUser
+		//
+		// Zacne izvoz vseh osebnih podatkov uporabnika (uporabnik sam ali administrator)
+		//
+		// Responses:
+		//		400: description: Prislo je do napake
+		//		202: export
+		r.Post("/export", u.ExportUser)
 	})
 
 	// Metode za ponudnike zunanje avtentikacije
@@ -169,7 +250,7 @@ func (u *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Pridobi uporabnika preko baze
-	usr, err := u.UserService.User(id)
+	usr, err := userService(r, u.UserService).User(id)
 
 	// Preveri napake pri pridobivanju iz PB in ustrezno obvesti odjemalca
 	if err != nil {
@@ -186,7 +267,7 @@ func (u *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 // 	- paginacija
 func (u *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	// Pridobi podatke o vseh uporabnikih, ki so na voljo
-	usrs, err := u.UserService.Users()
+	usrs, err := userService(r, u.UserService).Users()
 
 	// Preveri ali je prislo do napake
 	if err != nil {
@@ -203,7 +284,7 @@ func (u *UserHandler) MeDetails(w http.ResponseWriter, r *http.Request) {
 	// Iz zahtebve pridobi podatke o Emailu uporabnika
 	email := getUserEmail(r)
 
-	usr, err := u.UserService.UserByEmail(email)
+	usr, err := userService(r, u.UserService).UserByEmail(email)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -212,17 +293,26 @@ func (u *UserHandler) MeDetails(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, usr)
 }
 
-// UpdateUser posodobi podatke o dolocenem uporabniku
+// UpdateUser posodobi podatke o dolocenem uporabniku. Racun lahko ureja le uporabnik sam ali administrator,
+// email in zunanjo identiteto (s katerima se uporabnik prijavi) pa le administrator
 // FIXME:
 // 	- branje ID iz telesa in ID iz URL
 // TODO:
 // 	- javljanje napak (neveljavni znaki za polja?)
-//  - uporabnik lahko posodablja le lasten racun
 func (u *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id, parseErr := getIDFromURL(w, r, "id")
 	if parseErr {
 		return
 	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if *me.ID != id && !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Racun lahko ureja le uporabnik sam ali administrator")
+		return
+	}
 
 	var usr biolog.User
 	// Pridobi podatke o uporabniku iz telesa zahtevka
@@ -237,7 +327,14 @@ func (u *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	usr.ID = &id
-	if updErr := u.UserService.UpdateUser(id, usr); updErr != nil {
+	// Vloge in trajnega izbrisa ni mogoce spremeniti preko API
+	usr.Role, usr.ErasedAt = nil, nil
+	// Prijava poteka preko emaila in zunanje identitete, zato bi njuna sprememba omogocila prevzem racuna
+	if !me.IsAdmin() && (usr.Email != nil || usr.ExternalID != nil) {
+		respondWithError(w, http.StatusForbidden, "Emaila in zunanje identitete ni mogoce spremeniti")
+		return
+	}
+	if updErr := userService(r, u.UserService).UpdateUser(id, usr, auditActor(r, me)); updErr != nil {
 		respondWithError(w, http.StatusBadRequest, updErr.Error())
 		return
 	}
@@ -245,19 +342,24 @@ func (u *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusNoContent, nil)
 }
 
-// DeleteUser zbrise dolocenega uporabnika
-// (!) Zbrisejo se tudi vsi povezani zapisi (ExternalUser, Observations ...).
-// TODO:
-//	- zbrise se naj se ExternalUser
-// 	- preveri da uporabnik lahko zbrise le svoj racun
-//  - javljanje napak (unauthorized, non-existent)
+// DeleteUser zbrise dolocenega uporabnika (mehko, do trajne odstranitve ga je mogoce obnoviti).
+// Racun lahko izbrise le uporabnik sam ali administrator
 func (u *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	id, parseErr := getIDFromURL(w, r, "id")
 	if parseErr {
 		return
 	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if *me.ID != id && !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Racun lahko izbrise le uporabnik sam ali administrator")
+		return
+	}
 
-	_, err := u.UserService.DeleteUser(id)
+	_, err = userService(r, u.UserService).DeleteUser(id, auditActor(r, me))
 
 	// Preveri ce je prislo do napake
 	if err != nil {
@@ -270,10 +372,172 @@ func (u *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// RestoreUser obnovi izbrisan uporabniski racun, obnovi ga lahko le administrator
+func (u *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Racun lahko obnovi le administrator")
+		return
+	}
+
+	if err := userService(r, u.UserService).RestoreUser(id, auditActor(r, me)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// EraseUser trajno izbrise uporabniski racun, zahteva ga lahko le uporabnik sam ali administrator.
+// Parameter observations (keep ali delete) pove, ali se javna opazanja ohranijo
+func (u *UserHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	var keepObservations bool
+	switch r.URL.Query().Get("observations") {
+	case "keep":
+		keepObservations = true
+	case "delete":
+		keepObservations = false
+	default:
+		respondWithError(w, http.StatusBadRequest, "Parameter observations mora biti keep ali delete")
+		return
+	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if *me.ID != id && !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Racun lahko izbrise le uporabnik sam ali administrator")
+		return
+	}
+
+	if err := userService(r, u.UserService).EraseUser(id, keepObservations, auditActor(r, me)); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// GetFollowing vrne uporabnike, ki jih prijavljeni uporabnik spremlja
+func (u *UserHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	us, err := userService(r, u.UserService).Following(*me.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Pri poizvedbi nad spremljanimi uporabniki je prislo do napake")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, us)
+}
+
+// FollowUser doda uporabnika med uporabnike, ki jih prijavljeni uporabnik spremlja
+func (u *UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := userService(r, u.UserService).FollowUser(*me.ID, id); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// UnfollowUser odstrani uporabnika iz uporabnikov, ki jih prijavljeni uporabnik spremlja
+func (u *UserHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	if err := userService(r, u.UserService).UnfollowUser(*me.ID, id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// ExportMe zacne izvoz osebnih podatkov prijavljenega uporabnika
+func (u *UserHandler) ExportMe(w http.ResponseWriter, r *http.Request) {
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+
+	export, err := u.Exports.StartExport(*me.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Izvoza osebnih podatkov ni bilo mogoce zaceti")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, export)
+}
+
+// ExportUser zacne izvoz osebnih podatkov dolocenega uporabnika, zahteva ga lahko le uporabnik sam ali administrator
+func (u *UserHandler) ExportUser(w http.ResponseWriter, r *http.Request) {
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	me, err := currentUser(r, u.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return
+	}
+	if *me.ID != id && !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Izvoz podatkov lahko zahteva le uporabnik sam ali administrator")
+		return
+	}
+	if _, err := userService(r, u.UserService).User(id); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export, err := u.Exports.StartExport(id)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Izvoza osebnih podatkov ni bilo mogoce zaceti")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, export)
+}
+
 // GetAuthProviders pridobi in izpise vse shranjene zunanje avtentikatorje
 func (u *UserHandler) GetAuthProviders(w http.ResponseWriter, r *http.Request) {
 	// Pridobi podatke o vseh ponudnikih avtentikacije
-	ps, err := u.UserService.AuthProviders()
+	ps, err := userService(r, u.UserService).AuthProviders()
 
 	// Preveri ali je prislo do napake
 	if err != nil {
@@ -292,7 +556,7 @@ func (u *UserHandler) GetAuthProvider(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	p, err := u.UserService.AuthProvider(id)
+	p, err := userService(r, u.UserService).AuthProvider(id)
 
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
@@ -0,0 +1,196 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// testUser vrne uporabnika z ID in emailom testnega uporabnika
+func testUser() *biolog.User {
+	id, dn, email := 10000000, "ms. River Tam", testEmail
+	return &biolog.User{ID: &id, DisplayName: &dn, Email: &email}
+}
+
+// TestGetUsers preveri pridobivanje vseh uporabnikov
+func TestGetUsers(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+
+	us.UsersFn = func() ([]biolog.User, error) { return []biolog.User{*testUser()}, nil }
+	rec := doRequest(h, "GET", "/users", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var users []biolog.User
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &users))
+		assert.Equal(t, []biolog.User{*testUser()}, users)
+	}
+
+	us.UsersFn = func() ([]biolog.User, error) { return nil, errors.New("db down") }
+	rec = doRequest(h, "GET", "/users", "", auth)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// TestMeDetails preveri, da se uporabnik poisce po emailu iz JWT
+func TestMeDetails(t *testing.T) {
+	h, us, _ := newTestHandler()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) {
+		assert.Equal(t, testEmail, email)
+		return testUser(), nil
+	}
+
+	rec := doRequest(h, "GET", "/users/me", "", "Bearer "+validToken())
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var u biolog.User
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &u))
+		assert.Equal(t, *testUser(), u)
+	}
+}
+
+// TestGetUserByID preveri pridobivanje uporabnika po ID in validacijo ID v poti
+func TestGetUserByID(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserFn = func(id int) (*biolog.User, error) {
+		if id == 10000000 {
+			return testUser(), nil
+		}
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/users/10000000", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/users/10000001", "", auth).Code)
+	// ID mora biti 8 mestno stevilo
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/users/123", "", auth).Code)
+}
+
+// TestUpdateUser preveri posodabljanje uporabnika
+func TestUpdateUser(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return testUser(), nil }
+	var updated biolog.User
+	us.UpdateUserFn = func(id int, u biolog.User, by biolog.Actor) error {
+		assert.Equal(t, 10000000, id)
+		// Sprememba se v revizijski sledi poveze s prijavljenim uporabnikom in zahtevo
+		assert.Equal(t, 10000000, by.User)
+		assert.NotEmpty(t, by.RequestID)
+		updated = u
+		return nil
+	}
+
+	rec := doRequest(h, "PATCH", "/users/10000000", `{"displayName": "River"}`, auth)
+	if assert.Equal(t, http.StatusNoContent, rec.Code) {
+		assert.Equal(t, "River", *updated.DisplayName)
+		assert.Equal(t, 10000000, *updated.ID)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/users/10000000", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/users/10000000", "{", auth).Code)
+
+	us.UpdateUserFn = func(id int, u biolog.User, by biolog.Actor) error { return errors.New("update failed") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "PATCH", "/users/10000000", "{}", auth).Code)
+}
+
+// TestUpdateUserPermissions preveri, da lahko tuj racun ter email in zunanjo identiteto ureja le administrator
+func TestUpdateUserPermissions(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	updates := 0
+	us.UpdateUserFn = func(id int, u biolog.User, by biolog.Actor) error {
+		updates++
+		return nil
+	}
+
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/users/10000001", `{"displayName": "Kaylee"}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/users/10000000", `{"email": "mal@fakemail.com"}`, auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "PATCH", "/users/10000000", `{"externalID": "1"}`, auth).Code)
+	assert.Equal(t, 0, updates)
+
+	admin := biolog.RoleAdmin
+	user.Role = &admin
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "PATCH", "/users/10000001", `{"email": "kaylee@fakemail.com"}`, auth).Code)
+	assert.Equal(t, 1, updates)
+}
+
+// TestDeleteUser preveri brisanje uporabnika
+func TestDeleteUser(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	us.DeleteUserFn = func(id int, by biolog.Actor) (int64, error) { return 1, nil }
+	us.RestoreUserFn = func(id int, by biolog.Actor) error { return nil }
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/users/10000000", "", auth).Code)
+
+	us.DeleteUserFn = func(id int, by biolog.Actor) (int64, error) { return -1, errors.New("has observations") }
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "DELETE", "/users/10000000", "", auth).Code)
+
+	// Tujega racuna lahko izbrise in obnovi le administrator
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "DELETE", "/users/10000001", "", auth).Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/users/10000001/restore", "", auth).Code)
+	role := biolog.RoleAdmin
+	user.Role = &role
+	us.DeleteUserFn = func(id int, by biolog.Actor) (int64, error) {
+		assert.Equal(t, 10000001, id)
+		assert.Equal(t, 10000000, by.User)
+		return 1, nil
+	}
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "DELETE", "/users/10000001", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/users/10000001/restore", "", auth).Code)
+}
+
+// TestAuthProviders preveri pridobivanje ponudnikov avtentikacije
+func TestAuthProviders(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+	google := biolog.AuthProvider{ID: 1, Name: "Google"}
+	us.AuthProvidersFn = func() ([]biolog.AuthProvider, error) { return []biolog.AuthProvider{google}, nil }
+	us.AuthProviderFn = func(id int) (*biolog.AuthProvider, error) {
+		if id == 1 {
+			return &google, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	rec := doRequest(h, "GET", "/users/auth_providers", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var ps []biolog.AuthProvider
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ps))
+		assert.Equal(t, []biolog.AuthProvider{google}, ps)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(h, "GET", "/users/auth_providers/1", "", auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "GET", "/users/auth_providers/2", "", auth).Code)
+}
+
+// TestEraseUser preveri trajni izbris racuna: izbiro ohranitve opazanj in pravice
+func TestEraseUser(t *testing.T) {
+	h, us, _ := newTestHandler()
+	auth := "Bearer " + validToken()
+	me := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return me, nil }
+	var keep []bool
+	us.EraseUserFn = func(id int, keepObservations bool, by biolog.Actor) error {
+		assert.Equal(t, 10000000, by.User)
+		keep = append(keep, keepObservations)
+		return nil
+	}
+
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/users/10000000/erase?observations=keep", "", auth).Code)
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/users/10000000/erase?observations=delete", "", auth).Code)
+	assert.Equal(t, []bool{true, false}, keep)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/users/10000000/erase", "", auth).Code)
+
+	// Racun drugega uporabnika lahko izbrise le administrator
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/users/10000001/erase?observations=keep", "", auth).Code)
+	role := biolog.RoleAdmin
+	me.Role = &role
+	assert.Equal(t, http.StatusNoContent, doRequest(h, "POST", "/users/10000001/erase?observations=keep", "", auth).Code)
+	assert.Len(t, keep, 3)
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// Najdaljsi razmik med poskusi posiljanja
+const maxWebhookBackoff = 6 * time.Hour
+
+// WebhookDispatcher posilja dogodke iz vrste narocnikom. Vsak dogodek se poslje kot POST z JSON telesom,
+// podpisanim s HMAC-SHA256 (glava X-Biolog-Signature). Neuspesna posiljanja se ponovijo z eksponentno
+// rastocim razmikom, po MaxAttempts poskusih pa ostanejo v dnevniku kot neuspesna
+type WebhookDispatcher struct {
+	WebhookService biolog.WebhookService
+	SpeciesService biolog.SpeciesService
+	Sensitivity    biolog.SensitivityPolicy
+	Client         *http.Client
+	// Najvecje stevilo poskusov posiljanja enega dogodka
+	MaxAttempts int
+	// Razmik pred prvo ponovitvijo, vsaka naslednja caka dvakrat dlje
+	Backoff time.Duration
+	// Cas, za katerega si dispecer prevzame posiljanja (po izteku jih lahko ponovi drug dispecer)
+	Lease time.Duration
+	// Najvecje stevilo posiljanj v enem prehodu
+	BatchSize int
+}
+
+// NewWebhookDispatcher kreira dispecerja s privzetimi nastavitvami
+func NewWebhookDispatcher(ws biolog.WebhookService, ss biolog.SpeciesService) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		WebhookService: ws,
+		SpeciesService: ss,
+		Sensitivity:    SensitivityPolicy(),
+		Client:         &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts:    8,
+		Backoff:        30 * time.Second,
+		Lease:          time.Minute,
+		BatchSize:      50,
+	}
+}
+
+// Run vsakih interval poslje vsa posiljanja, katerih cas je potekel
+func (d *WebhookDispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(); err != nil {
+			log.Error("Could not deliver webhooks: ", err)
+		}
+		<-ticker.C
+	}
+}
+
+// DeliverDue prevzame in poslje posiljanja, katerih cas je potekel, dokler jih je v vrsti. Vrne stevilo poskusov
+func (d *WebhookDispatcher) DeliverDue() (int, error) {
+	n := 0
+	for {
+		ds, err := d.WebhookService.ClaimDeliveries(d.BatchSize, d.Lease)
+		if err != nil {
+			return n, err
+		}
+		for _, delivery := range ds {
+			if err := d.WebhookService.RecordAttempt(*delivery.ID, d.deliver(delivery)); err != nil {
+				log.Error("Could not record webhook delivery ", *delivery.ID, ": ", err)
+			}
+		}
+		n += len(ds)
+		if len(ds) < d.BatchSize {
+			return n, nil
+		}
+	}
+}
+
+// deliver poslje dogodek narocniku in vrne izid poskusa z casom naslednjega poskusa ob napaki
+func (d *WebhookDispatcher) deliver(delivery biolog.WebhookDelivery) biolog.WebhookAttempt {
+	var a biolog.WebhookAttempt
+	status, err := d.send(delivery)
+	a.StatusCode = status
+	if err == nil {
+		return a
+	}
+
+	a.Error = err.Error()
+	attempt := *delivery.Attempts + 1
+	if attempt < d.MaxAttempts {
+		backoff := d.Backoff << uint(attempt-1)
+		if backoff > maxWebhookBackoff || backoff <= 0 {
+			backoff = maxWebhookBackoff
+		}
+		next := time.Now().Add(backoff)
+		a.NextAttemptAt = &next
+	}
+	return a
+}
+
+// send pripravi in podpise telo dogodka ter ga poslje, vrne HTTP status odgovora
+func (d *WebhookDispatcher) send(delivery biolog.WebhookDelivery) (int, error) {
+	body, err := d.payload(delivery)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest("POST", delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "biolog-webhook")
+	req.Header.Set("X-Biolog-Event", *delivery.Event)
+	req.Header.Set("X-Biolog-Delivery", strconv.Itoa(*delivery.ID))
+	req.Header.Set("X-Biolog-Signature", biolog.SignWebhook(delivery.Secret, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("Narocnik je vrnil HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// payload vrne telo dogodka, v katerem je lokacija obcutljive vrste posplosena enako kot za neprijavljene uporabnike.
+// Status ogrozenosti se prebere ob posiljanju, zato velja trenutna ocena vrste
+func (d *WebhookDispatcher) payload(delivery biolog.WebhookDelivery) ([]byte, error) {
+	return obscurePayload(d.SpeciesService, d.Sensitivity, delivery.Payload)
+}
+
+// obscurePayload posplosi lokacijo obcutljive vrste v telesu dogodka (WebhookEvent) po pravilu policy
+func obscurePayload(ss biolog.SpeciesService, policy biolog.SensitivityPolicy, payload []byte) ([]byte, error) {
+	var event biolog.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.Observation != nil {
+		obs := []biolog.Observation{*event.Observation}
+		if err := obscureSensitive(ss, policy, &biolog.User{}, obs); err != nil {
+			return nil, err
+		}
+		event.Observation = &obs[0]
+	}
+	return json.Marshal(event)
+}
@@ -0,0 +1,281 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+	"github.com/rubinda/biolog"
+	log "github.com/sirupsen/logrus"
+)
+
+// WebhookHandler je http handler za narocnine partnerskih organizacij na dogodke (le za administratorje)
+type WebhookHandler struct {
+	WebhookService biolog.WebhookService
+	UserService    biolog.UserService
+	SpeciesService biolog.SpeciesService
+	// Sensitivity doloca vrste, katerih lokacije se v dnevniku posiljanj posplosijo
+	Sensitivity biolog.SensitivityPolicy
+	*chi.Mux
+}
+
+// WebhookID parameter model.
+//
+// Identifikator narocnine
+// swagger:parameters getWebhook deleteWebhook getWebhookDeliveries
+type WebhookID struct {
+	// Identifikator narocnine
+	//
+	// in: path
+	// required: true
+	ID int `json:"id"`
+}
+
+// WebhookParam parameter model.
+//
+// Nova narocnina
+// swagger:parameters createWebhook
+type WebhookParam struct {
+	// Naslov, opis in filtri narocnine (skrivnost doloci streznik)
+	//
+	// in: body
+	// required: true
+	Webhook biolog.Webhook `json:"webhook"`
+}
+
+// DeliveriesParams model.
+//
+// Ostranjevanje dnevnika posiljanj
+// swagger:parameters getWebhookDeliveries
+type DeliveriesParams struct {
+	// Najvecje stevilo vrnjenih zapisov
+	// in: query
+	Limit int `json:"limit"`
+
+	// Stevilo preskocenih zapisov
+	// in: query
+	Offset int `json:"offset"`
+}
+
+// NewWebhookHandler kreira novega handlerja za narocnine na dogodke
+func NewWebhookHandler() *WebhookHandler {
+	wh := &WebhookHandler{
+		Mux: chi.NewRouter(),
+	}
+
+	// Prefix do tukaj je ze /api/v1/webhooks
+
+	// swagger:route GET /webhooks webhooks getWebhooks
+	//
+	// Pridobi vse narocnine na dogodke (administrator)
+	//
+	// Responses:
+	//		200: []webhook
+	wh.Get("/", wh.GetWebhooks)
+
+	// swagger:route POST /webhooks webhooks createWebhook
+	//
+	// Ustvari narocnino, odgovor edini vsebuje skrivnost za preverjanje podpisov (administrator)
+	//
+	// Responses:
+	//		400: description: Neveljavna narocnina
+	//		201: webhook
+	wh.Post("/", wh.CreateWebhook)
+
+	wh.Route("/{id:[0-9]+}", func(r chi.Router) {
+		// swagger:route GET /webhooks/{id} webhooks getWebhook
+		//
+		// Pridobi narocnino (administrator)
+		//
+		// Responses:
+		//		404: description: Narocnina ne obstaja
+		//		200: webhook
+		r.Get("/", wh.GetWebhook)
+
+		// swagger:route DELETE /webhooks/{id} webhooks deleteWebhook
+		//
+		// Izbrise narocnino skupaj z dnevnikom posiljanj (administrator)
+		//
+		// Responses:
+		//		404: description: Narocnina ne obstaja
+		//		204:
+		r.Delete("/", wh.DeleteWebhook)
+
+		// swagger:route GET /webhooks/{id}/deliveries webhooks getWebhookDeliveries
+		//
+		// Pridobi dnevnik posiljanj narocnine, od najnovejsega naprej (administrator)
+		//
+		// Responses:
+		//		404: description: Narocnina ne obstaja
+		//		200: []webhookDelivery
+		r.Get("/deliveries", wh.GetWebhookDeliveries)
+	})
+
+	return wh
+}
+
+// requireAdmin preveri, da je prijavljen uporabnik administrator in ga vrne. Ce ni ali pride do napake
+// obvesti odjemalca in vrne true
+func (wh *WebhookHandler) requireAdmin(w http.ResponseWriter, r *http.Request) (*biolog.User, bool) {
+	me, err := currentUser(r, wh.UserService)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Prijavljen uporabnik ne obstaja")
+		return nil, true
+	}
+	if !me.IsAdmin() {
+		respondWithError(w, http.StatusForbidden, "Narocnine na dogodke ureja le administrator")
+		return nil, true
+	}
+	return me, false
+}
+
+// GetWebhooks vrne vse narocnine
+func (wh *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if _, failed := wh.requireAdmin(w, r); failed {
+		return
+	}
+	whs, err := wh.WebhookService.Webhooks()
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju narocnin")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, whs)
+}
+
+// GetWebhook vrne narocnino z dolocenim ID
+func (wh *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	if _, failed := wh.requireAdmin(w, r); failed {
+		return
+	}
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+
+	found, err := wh.WebhookService.Webhook(id)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, found)
+}
+
+// CreateWebhook preveri in shrani novo narocnino z nakljucno skrivnostjo
+func (wh *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	me, failed := wh.requireAdmin(w, r)
+	if failed {
+		return
+	}
+	var newWebhook biolog.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&newWebhook); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Neveljavno telo zahteve")
+		return
+	}
+	defer r.Body.Close()
+	if msg := validateWebhook(newWebhook); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	b := make([]byte, 32)
+	rand.Read(b)
+	secret := hex.EncodeToString(b)
+	newWebhook.ID, newWebhook.Secret, newWebhook.CreatedBy, newWebhook.CreatedAt = nil, &secret, me.ID, nil
+
+	created, err := wh.WebhookService.CreateWebhook(&newWebhook)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusBadRequest, "Narocnine ni bilo mogoce shraniti")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, created)
+}
+
+// validateWebhook preveri naslov in filtre narocnine, vrne opis napake ali prazen niz
+func validateWebhook(wh biolog.Webhook) string {
+	if wh.URL == nil {
+		return "Manjka naslov narocnine"
+	}
+	u, err := url.Parse(*wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "Naslov narocnine mora biti absoluten http ali https URL"
+	}
+	if len(wh.BBox) > 0 && !validBBox(wh.BBox) {
+		return "Neveljaven pravokotnik, pricakovane so 4 koordinate: minLon, minLat, maxLon, maxLat"
+	}
+	return ""
+}
+
+// validBBox preveri, da ima pravokotnik 4 koordinate v obsegu WGS84 in da minimumi niso vecji od maksimumov
+func validBBox(bbox []float64) bool {
+	if len(bbox) != 4 {
+		return false
+	}
+	minLon, minLat, maxLon, maxLat := bbox[0], bbox[1], bbox[2], bbox[3]
+	return minLon >= -180 && maxLon <= 180 && minLat >= -90 && maxLat <= 90 && minLon <= maxLon && minLat <= maxLat
+}
+
+// DeleteWebhook izbrise narocnino z dolocenim ID
+func (wh *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if _, failed := wh.requireAdmin(w, r); failed {
+		return
+	}
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+
+	if err := wh.WebhookService.DeleteWebhook(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusNoContent, nil)
+}
+
+// GetWebhookDeliveries vrne stran dnevnika posiljanj narocnine
+// Mozni parametri so:
+// 	- limit, offset ... ostranjevanje
+func (wh *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if _, failed := wh.requireAdmin(w, r); failed {
+		return
+	}
+	id, parseErr := getIDFromURL(w, r, "id")
+	if parseErr {
+		return
+	}
+	p, parseErr := getPage(w, r)
+	if parseErr {
+		return
+	}
+	if _, err := wh.WebhookService.Webhook(id); err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	ds, err := wh.WebhookService.WebhookDeliveries(id, p)
+	if err != nil {
+		log.Error(err)
+		respondWithError(w, http.StatusInternalServerError, "Napaka pri branju dnevnika posiljanj")
+		return
+	}
+	// Posiljanja, dodana v vrsto pred posplosevanjem ob shranjevanju, imajo lahko v telesu natancno lokacijo
+	for i := range ds {
+		payload, err := obscurePayload(wh.SpeciesService, wh.Sensitivity, ds[i].Payload)
+		if err != nil {
+			log.Error(err)
+			respondWithError(w, http.StatusInternalServerError, "Napaka pri branju dnevnika posiljanj")
+			return
+		}
+		ds[i].Payload = payload
+	}
+
+	respondWithJSON(w, http.StatusOK, ds)
+}
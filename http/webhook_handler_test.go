@@ -0,0 +1,147 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	biohttp "github.com/rubinda/biolog/http"
+	"github.com/rubinda/biolog/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhooks preveri urejanje narocnin (le administrator) in preverjanje naslova ter pravokotnika
+func TestWebhooks(t *testing.T) {
+	h, us, ss := newTestHandler()
+	ws := h.WebhookHandler.WebhookService.(*mock.WebhookService)
+	auth := "Bearer " + validToken()
+	user := testUser()
+	us.UserByEmailFn = func(email string) (*biolog.User, error) { return user, nil }
+	var created *biolog.Webhook
+	ws.CreateWebhookFn = func(wh *biolog.Webhook) (*biolog.Webhook, error) {
+		id := 1
+		created = wh
+		created.ID = &id
+		return created, nil
+	}
+	ws.WebhookFn = func(id int) (*biolog.Webhook, error) {
+		if id != 1 {
+			return nil, errors.New("Narocnina s tem ID ne obstaja")
+		}
+		return &biolog.Webhook{ID: &id}, nil
+	}
+	// Posiljanje, dodano v vrsto z natancno lokacijo obcutljive vrste
+	payload, _ := json.Marshal(biolog.WebhookEvent{Event: biolog.WebhookObservationCreated, CreatedAt: time.Now(),
+		Observation: testObservation()})
+	ws.WebhookDeliveriesFn = func(webhookID int, p biolog.Page) ([]biolog.WebhookDelivery, error) {
+		assert.Equal(t, 5, p.Limit)
+		return []biolog.WebhookDelivery{{Payload: payload}}, nil
+	}
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) { return testSpecies(), nil }
+	h.WebhookHandler.Sensitivity = biolog.DefaultSensitivityPolicy([]int{5231190})
+	body := `{"url": "https://example.org/hook", "species": [5231190], "bbox": [13.3, 45.4, 16.6, 46.9]}`
+
+	assert.Equal(t, http.StatusForbidden, doRequest(h, "POST", "/webhooks", body, auth).Code)
+
+	role := biolog.RoleAdmin
+	user.Role = &role
+	rec := doRequest(h, "POST", "/webhooks", body, auth)
+	if assert.Equal(t, http.StatusCreated, rec.Code) && assert.NotNil(t, created) {
+		// Skrivnost doloci streznik in jo vrne le ob kreiranju
+		assert.Len(t, *created.Secret, 64)
+		assert.Equal(t, 10000000, *created.CreatedBy)
+		assert.Contains(t, rec.Body.String(), *created.Secret)
+	}
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/webhooks", `{"url": "ftp://example.org"}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(h, "POST", "/webhooks", `{"url": "/hook"}`, auth).Code)
+	assert.Equal(t, http.StatusBadRequest,
+		doRequest(h, "POST", "/webhooks", `{"url": "https://example.org", "bbox": [16.6, 45.4, 13.3, 46.9]}`, auth).Code)
+
+	rec = doRequest(h, "GET", "/webhooks/1/deliveries?limit=5", "", auth)
+	if assert.Equal(t, http.StatusOK, rec.Code) {
+		var ds []struct{ Payload biolog.WebhookEvent }
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &ds)) && assert.Len(t, ds, 1) {
+			assert.True(t, *ds[0].Payload.Observation.Obscured)
+			assert.NotEqual(t, *testObservation().SightingLocation, *ds[0].Payload.Observation.SightingLocation)
+		}
+	}
+	assert.Equal(t, http.StatusNotFound, doRequest(h, "GET", "/webhooks/2/deliveries", "", auth).Code)
+}
+
+// TestWebhookDispatcher preveri podpis, posplositev lokacije obcutljive vrste in ponovne poskuse
+// posiljanja na lokalnega prejemnika
+func TestWebhookDispatcher(t *testing.T) {
+	secret := "skrivnost"
+	status := http.StatusServiceUnavailable
+	var received []biolog.WebhookEvent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, biolog.SignWebhook(secret, body), r.Header.Get("X-Biolog-Signature"))
+		assert.Equal(t, biolog.WebhookObservationCreated, r.Header.Get("X-Biolog-Event"))
+		assert.Equal(t, "7", r.Header.Get("X-Biolog-Delivery"))
+		var event biolog.WebhookEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	payload, _ := json.Marshal(biolog.WebhookEvent{Event: biolog.WebhookObservationCreated, CreatedAt: time.Now(),
+		Observation: testObservation()})
+	id, webhook, event, attempts := 7, 1, biolog.WebhookObservationCreated, 0
+	delivery := biolog.WebhookDelivery{ID: &id, Webhook: &webhook, Event: &event, Payload: payload, Attempts: &attempts,
+		URL: receiver.URL, Secret: secret}
+	ws := &mock.WebhookService{}
+	ws.ClaimDeliveriesFn = func(limit int, lease time.Duration) ([]biolog.WebhookDelivery, error) {
+		return []biolog.WebhookDelivery{delivery}, nil
+	}
+	var recorded []biolog.WebhookAttempt
+	ws.RecordAttemptFn = func(id int, a biolog.WebhookAttempt) error {
+		recorded = append(recorded, a)
+		return nil
+	}
+	ss := &mock.SpeciesService{}
+	ss.SpeciesFn = func(id int) (*biolog.Species, error) { return testSpecies(), nil }
+
+	d := biohttp.NewWebhookDispatcher(ws, ss)
+	d.Sensitivity = biolog.DefaultSensitivityPolicy([]int{5231190})
+	d.Backoff = time.Minute
+
+	// Neuspesno posiljanje se ponovi po razmiku Backoff
+	n, err := d.DeliverDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	if assert.Len(t, recorded, 1) && assert.NotNil(t, recorded[0].NextAttemptAt) {
+		assert.Equal(t, http.StatusServiceUnavailable, recorded[0].StatusCode)
+		assert.NotEmpty(t, recorded[0].Error)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *recorded[0].NextAttemptAt, 5*time.Second)
+	}
+	if assert.Len(t, received, 1) {
+		assert.NotEqual(t, *testObservation().SightingLocation, *received[0].Observation.SightingLocation)
+	}
+
+	// Vsak naslednji poskus caka dvakrat dlje, po MaxAttempts poskusih ga ni vec
+	attempts = 2
+	d.DeliverDue()
+	if assert.Len(t, recorded, 2) && assert.NotNil(t, recorded[1].NextAttemptAt) {
+		assert.WithinDuration(t, time.Now().Add(4*time.Minute), *recorded[1].NextAttemptAt, 5*time.Second)
+	}
+	attempts = d.MaxAttempts - 1
+	d.DeliverDue()
+	if assert.Len(t, recorded, 3) {
+		assert.Nil(t, recorded[2].NextAttemptAt)
+		assert.NotEmpty(t, recorded[2].Error)
+	}
+
+	status = http.StatusNoContent
+	d.DeliverDue()
+	if assert.Len(t, recorded, 4) {
+		assert.Empty(t, recorded[3].Error)
+		assert.Equal(t, http.StatusNoContent, recorded[3].StatusCode)
+	}
+}
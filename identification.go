@@ -0,0 +1,115 @@
+package biolog
+
+import (
+	"time"
+)
+
+// Stopnje kakovosti opazanja (quality grade)
+const (
+	// GradeCasual je opazanje, ki ga skupnost ne more preveriti (npr. ni javno)
+	GradeCasual = "casual"
+	// GradeNeedsID je opazanje, pri katerem se skupnost se ni strinjala o vrsti
+	GradeNeedsID = "needs_id"
+	// GradeResearch je opazanje, pri katerem se je skupnost strinjala o vrsti
+	GradeResearch = "research"
+)
+
+// Pravilo za soglasje skupnosti: vsaj toliko identifikacij se mora strinjati,
+// ki morajo predstavljati vec kot 2/3 vseh trenutnih identifikacij
+const consensusMinAgreements = 2
+
+// ValidQualityGrade preveri ali je podan niz ena izmed stopenj kakovosti
+func ValidQualityGrade(grade string) bool {
+	return grade == GradeCasual || grade == GradeNeedsID || grade == GradeResearch
+}
+
+// Identification (predlog vrste za opazanje)
+//
+// Uporabniki predlagajo vrsto za opazanje drugih uporabnikov, iz predlogov se izracuna
+// vrsta skupnosti (community taxon) in stopnja kakovosti opazanja
+//
+// swagger:model identification
+type Identification struct {
+	// Identifikator predloga
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Opazanje, za katerega je predlog podan
+	//
+	// required: true
+	// example: 1
+	Observation *int `json:"observation"`
+
+	// Uporabnik, ki je podal predlog
+	//
+	// required: true
+	// example: 10000001
+	User *int `db:"biolog_user" json:"user"`
+
+	// Predlagana vrsta (GBIF kljuc)
+	//
+	// required: true
+	// example: 5231190
+	Species *int `json:"species"`
+
+	// Komentar k predlogu
+	//
+	// max length: 255
+	// example: Samec, lepo se vidi crna ovratnica
+	Comment *string `json:"comment,omitempty"`
+
+	// Pove ali je to trenutni predlog uporabnika (prejsnji in umaknjeni predlogi niso trenutni)
+	// example: true
+	Current *bool `json:"current"`
+
+	// Cas, ko je bil predlog podan
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:07:37+00:00
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Consensus izracuna vrsto skupnosti in stopnjo kakovosti za opazanje. Vrsta, ki jo je vnesel
+// uporabnik ob kreiranju opazanja, steje kot njegova identifikacija, razen ce je uporabnik
+// kasneje podal svojo identifikacijo. Uposteva le trenutne identifikacije (Current).
+//
+// Opazanje, ki ni javno, je vedno casual. Vrsta skupnosti obstaja, ce se z njo strinja vsaj
+// consensusMinAgreements identifikacij in vec kot 2/3 vseh identifikacij, takrat je opazanje research.
+func Consensus(ob Observation, ids []Identification) (*int, string) {
+	// Glas vsakega uporabnika (uporabnik -> vrsta)
+	votes := make(map[int]int)
+	if ob.User != nil && ob.Species != nil {
+		votes[*ob.User] = *ob.Species
+	}
+	for _, id := range ids {
+		if id.Current == nil || !*id.Current || id.User == nil || id.Species == nil {
+			continue
+		}
+		votes[*id.User] = *id.Species
+	}
+
+	// Presteje glasove za vsako vrsto
+	counts := make(map[int]int)
+	for _, species := range votes {
+		counts[species]++
+	}
+
+	var taxon *int
+	for species, count := range counts {
+		if count >= consensusMinAgreements && 3*count > 2*len(votes) {
+			s := species
+			taxon = &s
+		}
+	}
+
+	switch {
+	case ob.PublicVisibility == nil || !*ob.PublicVisibility:
+		return taxon, GradeCasual
+	case taxon != nil:
+		return taxon, GradeResearch
+	default:
+		return nil, GradeNeedsID
+	}
+}
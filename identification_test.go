@@ -0,0 +1,54 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// identification vrne trenutno identifikacijo uporabnika za vrsto
+func identification(user, species int, current bool) biolog.Identification {
+	return biolog.Identification{User: &user, Species: &species, Current: &current}
+}
+
+// TestConsensus preveri izracun vrste skupnosti in stopnje kakovosti
+func TestConsensus(t *testing.T) {
+	owner, sparrow, tree := 10000000, 5231190, 5231198
+	public, private := true, false
+	ob := biolog.Observation{User: &owner, Species: &sparrow, PublicVisibility: &public}
+
+	cases := []struct {
+		Name  string
+		Ob    biolog.Observation
+		IDs   []biolog.Identification
+		Taxon *int
+		Grade string
+	}{
+		{"only owner", ob, nil, nil, biolog.GradeNeedsID},
+		{"one agreement", ob, []biolog.Identification{identification(10000001, sparrow, true)},
+			&sparrow, biolog.GradeResearch},
+		{"disagreement", ob, []biolog.Identification{identification(10000001, tree, true)},
+			nil, biolog.GradeNeedsID},
+		{"two thirds is not enough", ob, []biolog.Identification{
+			identification(10000001, sparrow, true), identification(10000002, tree, true)},
+			nil, biolog.GradeNeedsID},
+		{"community overrules owner", ob, []biolog.Identification{
+			identification(10000001, tree, true), identification(10000002, tree, true),
+			identification(10000003, tree, true)},
+			&tree, biolog.GradeResearch},
+		{"withdrawn is ignored", ob, []biolog.Identification{identification(10000001, sparrow, false)},
+			nil, biolog.GradeNeedsID},
+		{"owner changes mind", ob, []biolog.Identification{
+			identification(owner, tree, true), identification(10000001, tree, true)},
+			&tree, biolog.GradeResearch},
+		{"private is casual", biolog.Observation{User: &owner, Species: &sparrow, PublicVisibility: &private},
+			[]biolog.Identification{identification(10000001, sparrow, true)}, &sparrow, biolog.GradeCasual},
+	}
+
+	for _, c := range cases {
+		taxon, grade := biolog.Consensus(c.Ob, c.IDs)
+		assert.Equal(t, c.Taxon, taxon, c.Name)
+		assert.Equal(t, c.Grade, grade, c.Name)
+	}
+}
@@ -0,0 +1,137 @@
+package biolog
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ewkbSRID je zastavica v tipu geometrije EWKB, ki pove, da zapis vsebuje SRID
+const ewkbSRID = 0x20000000
+
+// ParsePoint prebere lokacijo opazanja, ki je tocka v obliki WKT (POINT(lon lat), kot jo sprejme
+// ST_GeomFromText) ali heksadecimalni EWKB (kot jo vrne PostGIS pri SELECT stolpca geography)
+func ParsePoint(location string) (float64, float64, bool) {
+	location = strings.TrimSpace(location)
+	if lon, lat, ok := parseWKTPoint(location); ok {
+		return lon, lat, true
+	}
+	return parseEWKBPoint(location)
+}
+
+// InBBox pove ali tocka lezi v pravokotniku [minLon, minLat, maxLon, maxLat]. Prazen pravokotnik vsebuje vse tocke
+func InBBox(bbox []float64, lon, lat float64) bool {
+	if len(bbox) != 4 {
+		return true
+	}
+	return lon >= bbox[0] && lat >= bbox[1] && lon <= bbox[2] && lat <= bbox[3]
+}
+
+// parseWKTPoint prebere tocko v obliki WKT
+func parseWKTPoint(wkt string) (float64, float64, bool) {
+	wkt = strings.ToUpper(wkt)
+	if !strings.HasPrefix(wkt, "POINT(") || !strings.HasSuffix(wkt, ")") {
+		return 0, 0, false
+	}
+	coords := strings.Fields(wkt[len("POINT(") : len(wkt)-1])
+	if len(coords) != 2 {
+		return 0, 0, false
+	}
+	lon, lonErr := strconv.ParseFloat(coords[0], 64)
+	lat, latErr := strconv.ParseFloat(coords[1], 64)
+	if lonErr != nil || latErr != nil {
+		return 0, 0, false
+	}
+	return lon, lat, true
+}
+
+// parseEWKBPoint prebere 2D tocko v heksadecimalnem EWKB zapisu
+func parseEWKBPoint(s string) (float64, float64, bool) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) < 21 {
+		return 0, 0, false
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if b[0] == 1 {
+		order = binary.LittleEndian
+	}
+	typ := order.Uint32(b[1:5])
+	b = b[5:]
+	if typ&ewkbSRID != 0 {
+		b = b[4:]
+	}
+	if typ&0xffff != 1 || len(b) != 16 {
+		return 0, 0, false
+	}
+	lon := math.Float64frombits(order.Uint64(b[0:8]))
+	lat := math.Float64frombits(order.Uint64(b[8:16]))
+	return lon, lat, true
+}
+
+// formatPoint zapise tocko v enaki obliki, kot je bila podana lokacija (WKT ali EWKB s SRID 4326)
+func formatPoint(like string, lon, lat float64) string {
+	if _, _, ok := parseWKTPoint(strings.TrimSpace(like)); ok {
+		return fmt.Sprintf("POINT(%s %s)", strconv.FormatFloat(lon, 'f', -1, 64), strconv.FormatFloat(lat, 'f', -1, 64))
+	}
+	b := make([]byte, 25)
+	b[0] = 1
+	binary.LittleEndian.PutUint32(b[1:5], ewkbSRID|1)
+	binary.LittleEndian.PutUint32(b[5:9], 4326)
+	binary.LittleEndian.PutUint64(b[9:17], math.Float64bits(lon))
+	binary.LittleEndian.PutUint64(b[17:25], math.Float64bits(lat))
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+// Parametri projekcije ETRS89-LAEA (EPSG:3035) na elipsoidu GRS80
+var laea = func() (p struct{ a, e, e2, lon0, fe, fn, qp, sinB0, cosB0, rq, d float64 }) {
+	p.a = 6378137.0
+	f := 1 / 298.257222101
+	p.e2 = 2*f - f*f
+	p.e = math.Sqrt(p.e2)
+	p.lon0, p.fe, p.fn = 10*math.Pi/180, 4321000, 3210000
+	lat0 := 52 * math.Pi / 180
+
+	p.qp = laeaQ(1, p.e, p.e2)
+	b0 := math.Asin(laeaQ(math.Sin(lat0), p.e, p.e2) / p.qp)
+	p.sinB0, p.cosB0 = math.Sin(b0), math.Cos(b0)
+	p.rq = p.a * math.Sqrt(p.qp/2)
+	p.d = p.a * math.Cos(lat0) / (math.Sqrt(1-p.e2*math.Sin(lat0)*math.Sin(lat0)) * p.rq * p.cosB0)
+	return p
+}()
+
+// laeaQ je pomozna funkcija q(sin fi) iz enacb za Lambertovo azimutno projekcijo (EPSG Guidance Note 7-2)
+func laeaQ(sinLat, e, e2 float64) float64 {
+	return (1 - e2) * (sinLat/(1-e2*sinLat*sinLat) - 1/(2*e)*math.Log((1-e*sinLat)/(1+e*sinLat)))
+}
+
+// ToLAEA pretvori WGS84 koordinate (v stopinjah) v EPSG:3035 (v metrih)
+func ToLAEA(lon, lat float64) (float64, float64) {
+	p := laea
+	sinLat := math.Sin(lat * math.Pi / 180)
+	beta := math.Asin(laeaQ(sinLat, p.e, p.e2) / p.qp)
+	dLon := lon*math.Pi/180 - p.lon0
+	b := p.rq * math.Sqrt(2/(1+p.sinB0*math.Sin(beta)+p.cosB0*math.Cos(beta)*math.Cos(dLon)))
+	x := p.fe + b*p.d*math.Cos(beta)*math.Sin(dLon)
+	y := p.fn + b/p.d*(p.cosB0*math.Sin(beta)-p.sinB0*math.Cos(beta)*math.Cos(dLon))
+	return x, y
+}
+
+// FromLAEA pretvori EPSG:3035 koordinate (v metrih) nazaj v WGS84 (v stopinjah)
+func FromLAEA(x, y float64) (float64, float64) {
+	p := laea
+	dx, dy := x-p.fe, y-p.fn
+	rho := math.Hypot(dx/p.d, p.d*dy)
+	if rho == 0 {
+		return p.lon0 * 180 / math.Pi, 52
+	}
+	c := 2 * math.Asin(rho/(2*p.rq))
+	beta := math.Asin(math.Cos(c)*p.sinB0 + p.d*dy*math.Sin(c)*p.cosB0/rho)
+	lon := p.lon0 + math.Atan2(dx*math.Sin(c), p.d*rho*p.cosB0*math.Cos(c)-p.d*p.d*dy*p.sinB0*math.Sin(c))
+	e4, e6 := p.e2*p.e2, p.e2*p.e2*p.e2
+	lat := beta + (p.e2/3+31*e4/180+517*e6/5040)*math.Sin(2*beta) +
+		(23*e4/360+251*e6/3780)*math.Sin(4*beta) + (761*e6/45360)*math.Sin(6*beta)
+	return lon * 180 / math.Pi, lat * 180 / math.Pi
+}
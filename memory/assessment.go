@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// Assessment vrne oceno ogrozenosti z dolocenim ID
+func (s *SpeciesService) Assessment(id int) (*biolog.Assessment, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	a, ok := s.Store.assessments[id]
+	if !ok {
+		return nil, errors.New("Ocena s tem ID ne obstaja")
+	}
+	return clone(a).(*biolog.Assessment), nil
+}
+
+// Assessments vrne vse ocene ogrozenosti vrste (zgodovino), urejene po sistemu, obmocju in od najnovejse naprej
+func (s *SpeciesService) Assessments(gbifKey int) ([]biolog.Assessment, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	return s.assessments(gbifKey), nil
+}
+
+// assessments vrne urejeno zgodovino ocen vrste, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) assessments(gbifKey int) []biolog.Assessment {
+	all := []biolog.Assessment{}
+	for _, a := range s.Store.assessments {
+		if *a.Species == gbifKey {
+			all = append(all, *clone(a).(*biolog.Assessment))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if *a.Scheme != *b.Scheme {
+			return *a.Scheme < *b.Scheme
+		}
+		if *a.Region != *b.Region {
+			return *a.Region < *b.Region
+		}
+		if *a.Year != *b.Year {
+			return *a.Year > *b.Year
+		}
+		return *a.ID > *b.ID
+	})
+	return all
+}
+
+// checkAssessment preveri status ogrozenosti in unikatnost ocene (UNIQUE v bazi), klicatelj mora drzati kljucavnico
+func (s *SpeciesService) checkAssessment(id int, a *biolog.Assessment) error {
+	if _, ok := s.Store.statuses[*a.ConservationStatus]; !ok {
+		return errors.New("Status ogrozenosti ne obstaja")
+	}
+	for existingID, existing := range s.Store.assessments {
+		if existingID != id && *existing.Species == *a.Species && *existing.Scheme == *a.Scheme &&
+			*existing.Region == *a.Region && *existing.Year == *a.Year {
+			return errors.New("Ocena za ta sistem, obmocje in leto ze obstaja")
+		}
+	}
+	return nil
+}
+
+// CreateAssessment shrani novo oceno ogrozenosti vrste
+func (s *SpeciesService) CreateAssessment(a *biolog.Assessment) (*biolog.Assessment, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if a.Species == nil || a.Scheme == nil || a.ConservationStatus == nil || a.Year == nil {
+		return nil, errors.New("Manjkajo obvezni podatki o oceni")
+	}
+	if _, ok := s.Store.species[*a.Species]; !ok {
+		return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	assessment := clone(a).(*biolog.Assessment)
+	if assessment.Region == nil {
+		region := biolog.RegionGlobal
+		assessment.Region = &region
+	}
+	if err := s.checkAssessment(0, assessment); err != nil {
+		return nil, err
+	}
+
+	assessment.ID = intPtr(s.Store.nextAssessmentID)
+	s.Store.nextAssessmentID++
+	s.Store.assessments[*assessment.ID] = assessment
+	return clone(assessment).(*biolog.Assessment), nil
+}
+
+// UpdateAssessment posodobi podana polja ocene ogrozenosti, vrste ocene ni mogoce spremeniti
+func (s *SpeciesService) UpdateAssessment(id int, a biolog.Assessment) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.assessments[id]
+	if !ok {
+		return errors.New("Ocena s tem ID ne obstaja")
+	}
+	a.Species = nil
+	merged := clone(existing).(*biolog.Assessment)
+	mergeNonNil(merged, &a)
+	if err := s.checkAssessment(id, merged); err != nil {
+		return err
+	}
+	s.Store.assessments[id] = merged
+	return nil
+}
+
+// DeleteAssessment zbrise oceno ogrozenosti
+func (s *SpeciesService) DeleteAssessment(id int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.assessments[id]; !ok {
+		return errors.New("Ocena s tem ID ne obstaja")
+	}
+	delete(s.Store.assessments, id)
+	return nil
+}
@@ -0,0 +1,52 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssessments preveri zgodovino ocen ogrozenosti in trenutne ocene pri vrsti
+func TestAssessments(t *testing.T) {
+	ss := &memory.SpeciesService{Store: memory.NewStore()}
+	createTestSpecies(t, ss)
+
+	scheme, region := biolog.SchemeSloveniaRedList, "SI"
+	for _, a := range []struct{ status, year int }{{5, 1992}, {4, 2002}} {
+		status, year := a.status, a.year
+		_, err := ss.CreateAssessment(&biolog.Assessment{Species: &testSpeciesID, Scheme: &scheme, Region: &region,
+			ConservationStatus: &status, Year: &year})
+		assert.NoError(t, err)
+	}
+	iucn, status, year := biolog.SchemeIUCN, 8, 2016
+	global, err := ss.CreateAssessment(&biolog.Assessment{Species: &testSpeciesID, Scheme: &iucn, ConservationStatus: &status, Year: &year})
+	if assert.NoError(t, err) {
+		assert.Equal(t, biolog.RegionGlobal, *global.Region)
+	}
+
+	// Ocena za isti sistem, obmocje in leto ze obstaja
+	_, err = ss.CreateAssessment(&biolog.Assessment{Species: &testSpeciesID, Scheme: &iucn, ConservationStatus: &status, Year: &year})
+	assert.Error(t, err)
+	unknown := 42
+	_, err = ss.CreateAssessment(&biolog.Assessment{Species: &testSpeciesID, Scheme: &iucn, ConservationStatus: &unknown, Year: &year})
+	assert.Error(t, err)
+
+	history, _ := ss.Assessments(testSpeciesID)
+	assert.Len(t, history, 3)
+	sp, err := ss.Species(testSpeciesID)
+	if assert.NoError(t, err) && assert.Len(t, sp.Assessments, 2) {
+		assert.Equal(t, 8, *sp.Assessments[0].ConservationStatus)
+		assert.Equal(t, 4, *sp.Assessments[1].ConservationStatus)
+	}
+
+	critical := 3
+	assert.NoError(t, ss.UpdateAssessment(*global.ID, biolog.Assessment{ConservationStatus: &critical}))
+	got, _ := ss.Assessment(*global.ID)
+	assert.Equal(t, critical, *got.ConservationStatus)
+
+	assert.NoError(t, ss.DeleteSpecies(testSpeciesID, biolog.Actor{}))
+	history, _ = ss.Assessments(testSpeciesID)
+	assert.Len(t, history, 0)
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// AuditService predstavlja implementacijo biolog.AuditService v pomnilniku
+type AuditService struct {
+	Store *Store
+}
+
+// Preveri ali AuditService implementira vse metode
+var _ biolog.AuditService = &AuditService{}
+
+// AuditEntries vrne stran zapisov revizijske sledi, ki ustrezajo filtru, od najnovejsega naprej
+func (s *AuditService) AuditEntries(f biolog.AuditFilter, p biolog.Page) ([]biolog.AuditEntry, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	entries := []biolog.AuditEntry{}
+	skipped := 0
+	for i := len(s.Store.auditLog) - 1; i >= 0 && len(entries) < p.Limit; i-- {
+		e := s.Store.auditLog[i]
+		if (f.EntityType != nil && *e.EntityType != *f.EntityType) || (f.EntityID != nil && *e.EntityID != *f.EntityID) ||
+			(f.Actor != nil && *e.Actor != *f.Actor) {
+			continue
+		}
+		if skipped < p.Offset {
+			skipped++
+			continue
+		}
+		entries = append(entries, *clone(e).(*biolog.AuditEntry))
+	}
+	return entries, nil
+}
+
+// audit doda spremembo zapisa v revizijsko sled, before in after sta zapisa pred in po spremembi
+// (nil, ce zapis ni obstajal). Klicatelj mora drzati kljucavnico, da je zapis del iste spremembe
+func (st *Store) audit(by biolog.Actor, action, entityType string, id int, before, after interface{}) {
+	// Zapisi so sestavljeni iz osnovnih tipov, zato pretvorba v JSON ne more spodleteti
+	changes, _ := biolog.AuditChanges(entityType, before, after)
+	now := time.Now()
+	e := &biolog.AuditEntry{ID: intPtr(len(st.auditLog) + 1), Actor: intPtr(by.User), Action: &action,
+		EntityType: &entityType, EntityID: intPtr(id), Changes: changes, CreatedAt: &now}
+	if by.RequestID != "" {
+		requestID := by.RequestID
+		e.RequestID = &requestID
+	}
+	st.auditLog = append(st.auditLog, e)
+}
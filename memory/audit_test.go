@@ -0,0 +1,79 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditEntries preveri, da spremembe zapisejo revizijsko sled in filtriranje po zapisu in uporabniku
+func TestAuditEntries(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	as := &memory.AuditService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	o := createTestObservation(t, ss, *u.ID, true)
+	by := biolog.Actor{User: *u.ID, RequestID: "test/1"}
+
+	quantity := 8
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Quantity: &quantity}, by))
+	assert.NoError(t, ss.DeleteObservation(*o.ID, by))
+	// Neuspesna sprememba ne sme pustiti sledi
+	assert.Error(t, ss.DeleteObservation(*o.ID, by))
+	genus := "Passerus"
+	assert.NoError(t, ss.UpdateSpecies(testSpeciesID, biolog.Species{Genus: &genus}, biolog.Actor{User: 1}))
+
+	page := biolog.Page{Limit: 10}
+	entries, err := as.AuditEntries(biolog.AuditFilter{}, page)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	entity := biolog.AuditObservation
+	entries, err = as.AuditEntries(biolog.AuditFilter{EntityType: &entity, EntityID: o.ID, Actor: u.ID}, page)
+	if assert.NoError(t, err) && assert.Len(t, entries, 2) {
+		// Najnovejsi zapis je prvi
+		assert.Equal(t, biolog.AuditDelete, *entries[0].Action)
+		assert.Equal(t, "test/1", *entries[0].RequestID)
+
+		update := entries[1]
+		assert.Equal(t, biolog.AuditUpdate, *update.Action)
+		var changes map[string]struct{ Before, After interface{} }
+		assert.NoError(t, json.Unmarshal(update.Changes, &changes))
+		assert.Len(t, changes, 1)
+		assert.Equal(t, float64(3), changes["quantity"].Before)
+		assert.Equal(t, float64(8), changes["quantity"].After)
+	}
+
+	entries, err = as.AuditEntries(biolog.AuditFilter{}, biolog.Page{Limit: 10, Offset: 2})
+	if assert.NoError(t, err) && assert.Len(t, entries, 1) {
+		assert.Equal(t, biolog.AuditUpdate, *entries[0].Action)
+		assert.Equal(t, biolog.AuditObservation, *entries[0].EntityType)
+	}
+}
+
+// TestAuditUserMasked preveri, da revizijska sled uporabnika ne vsebuje osebnih podatkov
+func TestAuditUserMasked(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	as := &memory.AuditService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+
+	email := "novi@fakemail.com"
+	assert.NoError(t, us.UpdateUser(*u.ID, biolog.User{Email: &email}, biolog.Actor{User: *u.ID}))
+	_, err := us.DeleteUser(*u.ID, biolog.Actor{User: *u.ID})
+	assert.NoError(t, err)
+
+	entity := biolog.AuditUser
+	entries, err := as.AuditEntries(biolog.AuditFilter{EntityType: &entity}, biolog.Page{Limit: 10})
+	if assert.NoError(t, err) && assert.Len(t, entries, 2) {
+		for _, e := range entries {
+			assert.NotContains(t, string(e.Changes), email)
+			assert.NotContains(t, string(e.Changes), *u.Email)
+		}
+		assert.Contains(t, string(entries[1].Changes), `"email":{"before":"***","after":"***"}`)
+	}
+}
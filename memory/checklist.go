@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// Checklist vrne popis z dolocenim ID skupaj z vsemi opazanji na njem
+func (s *SpeciesService) Checklist(id int) (*biolog.Checklist, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	c, ok := s.Store.checklists[id]
+	if !ok {
+		return nil, errors.New("Popis s tem ID ne obstaja")
+	}
+	checklist := clone(c).(*biolog.Checklist)
+	checklist.Observations = []biolog.Observation{}
+	for _, o := range s.Store.observations {
+		if o.Checklist != nil && *o.Checklist == id {
+			checklist.Observations = append(checklist.Observations, *clone(o).(*biolog.Observation))
+		}
+	}
+	sort.Slice(checklist.Observations, func(i, j int) bool {
+		return *checklist.Observations[i].ID < *checklist.Observations[j].ID
+	})
+	return checklist, nil
+}
+
+// Checklists vrne stran popisov uporabnika (brez opazanj), urejenih od najnovejsega naprej
+func (s *SpeciesService) Checklists(userID int, p biolog.Page) ([]biolog.Checklist, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	all := []biolog.Checklist{}
+	for _, c := range s.Store.checklists {
+		if *c.User == userID {
+			all = append(all, *clone(c).(*biolog.Checklist))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].StartTime.Equal(*all[j].StartTime) {
+			return all[i].StartTime.After(*all[j].StartTime)
+		}
+		return *all[i].ID > *all[j].ID
+	})
+
+	if p.Offset >= len(all) {
+		return []biolog.Checklist{}, nil
+	}
+	all = all[p.Offset:]
+	if p.Limit < len(all) {
+		all = all[:p.Limit]
+	}
+	return all, nil
+}
+
+// CreateChecklist shrani popis in vsa njegova opazanja. Vse se preveri vnaprej,
+// zato se ob napaki ne shrani nic (enako kot transakcija v bazi)
+func (s *SpeciesService) CreateChecklist(c *biolog.Checklist) (*biolog.Checklist, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if c.User == nil || c.StartTime == nil || c.Location == nil || c.Protocol == nil {
+		return nil, errors.New("Manjkajo obvezni podatki o popisu")
+	}
+	if !biolog.ValidProtocol(*c.Protocol) {
+		return nil, errors.New("Neveljaven protokol popisa")
+	}
+	if c.Duration != nil && *c.Duration < 0 {
+		return nil, errors.New("Trajanje popisa ne more biti negativno")
+	}
+	if _, ok := s.Store.users[*c.User]; !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	for i := range c.Observations {
+		o := c.Observations[i]
+		o.Checklist = nil
+		if err := s.checkObservation(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	checklist := clone(c).(*biolog.Checklist)
+	checklist.ID = intPtr(s.Store.nextChecklistID)
+	if checklist.Complete == nil {
+		checklist.Complete = boolPtr(false)
+	}
+	checklist.Observations = nil
+	s.Store.nextChecklistID++
+	s.Store.checklists[*checklist.ID] = checklist
+
+	created := clone(checklist).(*biolog.Checklist)
+	created.Observations = []biolog.Observation{}
+	for _, o := range c.Observations {
+		o.Checklist = checklist.ID
+		created.Observations = append(created.Observations, *s.createObservation(&o))
+	}
+	return created, nil
+}
+
+// UpdateChecklist posodobi podatke o popisu, opazanja na njem se ne spremenijo
+func (s *SpeciesService) UpdateChecklist(id int, c biolog.Checklist) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.checklists[id]
+	if !ok {
+		return errors.New("Popis s tem ID ne obstaja")
+	}
+	if c.Protocol != nil && !biolog.ValidProtocol(*c.Protocol) {
+		return errors.New("Neveljaven protokol popisa")
+	}
+	c.Observations = nil
+	mergeNonNil(existing, &c)
+	return nil
+}
+
+// DeleteChecklist zbrise popis, opazanja na njem se izbrisejo mehko. Izbrisana opazanja ostanejo brez popisa
+// (ON DELETE SET NULL v bazi)
+func (s *SpeciesService) DeleteChecklist(id int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.checklists[id]; !ok {
+		return errors.New("Popis s tem ID ne obstaja")
+	}
+	delete(s.Store.checklists, id)
+	for _, o := range s.Store.observations {
+		if o.Checklist != nil && *o.Checklist == id {
+			s.softDeleteObservation(o, by)
+		}
+	}
+	for _, o := range s.Store.deletedObservations {
+		if o.Checklist != nil && *o.Checklist == id {
+			o.Checklist = nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// testChecklist vrne popis z dvema opazanjema uporabnika
+func testChecklist(user int, species *int) *biolog.Checklist {
+	start, duration, loc, protocol := time.Date(2018, 6, 4, 7, 30, 0, 0, time.UTC), 90, "POINT(14.5058 46.0569)", biolog.ProtocolStationary
+	c := &biolog.Checklist{User: &user, StartTime: &start, Duration: &duration, Location: &loc, Protocol: &protocol}
+	for _, quantity := range []int{2, 5} {
+		q, public := quantity, true
+		c.Observations = append(c.Observations, biolog.Observation{SightingTime: &start, SightingLocation: &loc,
+			Quantity: &q, PublicVisibility: &public, User: &user, Species: species})
+	}
+	return c
+}
+
+// TestChecklists preveri kreiranje popisa z opazanji, urejanje in brisanje skupaj z opazanji (mehko)
+func TestChecklists(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+
+	c, err := ss.CreateChecklist(testChecklist(*u.ID, &testSpeciesID))
+	if !assert.NoError(t, err) || !assert.Len(t, c.Observations, 2) {
+		return
+	}
+	assert.False(t, *c.Complete)
+	assert.Equal(t, *c.ID, *c.Observations[0].Checklist)
+
+	got, err := ss.Checklist(*c.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, c.Observations, got.Observations)
+	}
+
+	// Ob napaki pri enem opazanju se ne shrani nic
+	unknown := 1
+	bad := testChecklist(*u.ID, &testSpeciesID)
+	bad.Observations[1].Species = &unknown
+	_, err = ss.CreateChecklist(bad)
+	assert.Error(t, err)
+	list, _ := ss.Checklists(*u.ID, biolog.Page{Limit: 10})
+	assert.Len(t, list, 1)
+	obs, _ := ss.Observations(biolog.ObservationFilter{})
+	assert.Len(t, obs, 2)
+
+	complete := true
+	assert.NoError(t, ss.UpdateChecklist(*c.ID, biolog.Checklist{Complete: &complete}))
+	got, _ = ss.Checklist(*c.ID)
+	assert.True(t, *got.Complete)
+	assert.Equal(t, biolog.ProtocolStationary, *got.Protocol)
+	traveling := "driving"
+	assert.Error(t, ss.UpdateChecklist(*c.ID, biolog.Checklist{Protocol: &traveling}))
+
+	// Opazanja se izbrisejo skupaj s popisom, obnoviti jih je mogoce brez popisa
+	by := biolog.Actor{User: *u.ID}
+	assert.NoError(t, ss.DeleteChecklist(*c.ID, by))
+	obs, _ = ss.Observations(biolog.ObservationFilter{})
+	assert.Len(t, obs, 0)
+	deleted, err := ss.DeletedObservation(*c.Observations[0].ID)
+	if assert.NoError(t, err) {
+		assert.Nil(t, deleted.Checklist)
+		assert.Equal(t, *u.ID, *deleted.DeletedBy)
+	}
+	assert.NoError(t, ss.RestoreObservation(*c.Observations[0].ID, by))
+	assert.Error(t, ss.DeleteChecklist(*c.ID, by))
+	_, err = ss.Checklist(*c.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// Comment vrne komentar z dolocenim ID
+func (s *SpeciesService) Comment(id int) (*biolog.Comment, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	c, ok := s.Store.comments[id]
+	if !ok {
+		return nil, errors.New("Komentar s tem ID ne obstaja")
+	}
+	return clone(c).(*biolog.Comment), nil
+}
+
+// Comments vrne stran komentarjev za doloceno opazanje, urejenih od najstarejsega naprej
+func (s *SpeciesService) Comments(observationID int, p biolog.Page) ([]biolog.Comment, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	all := []biolog.Comment{}
+	for _, c := range s.Store.comments {
+		if *c.Observation == observationID {
+			all = append(all, *clone(c).(*biolog.Comment))
+		}
+	}
+	sort.Slice(all, func(a, b int) bool { return *all[a].ID < *all[b].ID })
+
+	if p.Offset >= len(all) {
+		return []biolog.Comment{}, nil
+	}
+	all = all[p.Offset:]
+	if p.Limit < len(all) {
+		all = all[:p.Limit]
+	}
+	return all, nil
+}
+
+// CreateComment shrani nov komentar pod opazanjem
+func (s *SpeciesService) CreateComment(c *biolog.Comment) (*biolog.Comment, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if c.Observation == nil || c.User == nil || c.Body == nil {
+		return nil, errors.New("Komentar mora imeti opazanje, avtorja in besedilo")
+	}
+	if _, ok := s.Store.observations[*c.Observation]; !ok {
+		return nil, errors.New("Opazanje s tem ID ne obstaja")
+	}
+	if _, ok := s.Store.users[*c.User]; !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+
+	newComment := clone(c).(*biolog.Comment)
+	now := time.Now()
+	newComment.ID = intPtr(s.Store.nextCommentID)
+	newComment.CreatedAt = &now
+	newComment.UpdatedAt = &now
+	s.Store.nextCommentID++
+	s.Store.comments[*newComment.ID] = newComment
+
+	return clone(newComment).(*biolog.Comment), nil
+}
+
+// UpdateComment zamenja besedilo komentarja in posodobi cas urejanja
+func (s *SpeciesService) UpdateComment(id int, body string) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	c, ok := s.Store.comments[id]
+	if !ok {
+		return errors.New("Komentar s tem ID ne obstaja")
+	}
+	now := time.Now()
+	c.Body = &body
+	c.UpdatedAt = &now
+	return nil
+}
+
+// DeleteComment zbrise komentar
+func (s *SpeciesService) DeleteComment(id int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.comments[id]; !ok {
+		return errors.New("Komentar s tem ID ne obstaja")
+	}
+	delete(s.Store.comments, id)
+	return nil
+}
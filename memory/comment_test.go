@@ -0,0 +1,56 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComments preveri kreiranje, ostranjevanje, urejanje in brisanje komentarjev
+func TestComments(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	assert.Equal(t, biolog.RoleUser, *u.Role)
+	o := createTestObservation(t, ss, *u.ID, true)
+
+	for _, text := range []string{"prvi", "drugi", "tretji"} {
+		body := text
+		c, err := ss.CreateComment(&biolog.Comment{Observation: o.ID, User: u.ID, Body: &body})
+		if assert.NoError(t, err) {
+			assert.NotNil(t, c.CreatedAt)
+		}
+	}
+
+	page, _ := ss.Comments(*o.ID, biolog.Page{Limit: 2})
+	if assert.Len(t, page, 2) {
+		assert.Equal(t, "prvi", *page[0].Body)
+	}
+	page, _ = ss.Comments(*o.ID, biolog.Page{Limit: 2, Offset: 2})
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, "tretji", *page[0].Body)
+	}
+	page, _ = ss.Comments(*o.ID, biolog.Page{Limit: 2, Offset: 5})
+	assert.Len(t, page, 0)
+
+	assert.NoError(t, ss.UpdateComment(1, "popravljen"))
+	c, _ := ss.Comment(1)
+	assert.Equal(t, "popravljen", *c.Body)
+	assert.True(t, !c.UpdatedAt.Before(*c.CreatedAt))
+
+	assert.NoError(t, ss.DeleteComment(1))
+	assert.Error(t, ss.DeleteComment(1))
+
+	// Komentarji ostanejo pri izbrisanem opazanju in se odstranijo sele, ko se opazanje trajno odstrani
+	assert.NoError(t, ss.DeleteObservation(*o.ID, biolog.Actor{User: *o.User}))
+	_, err := ss.Comment(2)
+	assert.NoError(t, err)
+	_, err = ss.PurgeObservations(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	_, err = ss.Comment(2)
+	assert.Error(t, err)
+}
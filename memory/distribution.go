@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// Distribution zdruzi javna opazanja v celice izbrane mreze (enako kot postgres.SpeciesService.Distribution,
+// le da se celice izracunajo v Go). Opazanja z lokacijo, ki ni oblike POINT(lon lat), se ne upostevajo
+func (s *SpeciesService) Distribution(cell string, f biolog.ObservationFilter) ([]biolog.DistributionCell, error) {
+	if !biolog.ValidDistributionCell(cell) {
+		return nil, errors.New("Neveljavna mreza")
+	}
+
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	cells := make(map[string]*biolog.DistributionCell)
+	for _, o := range s.Store.observations {
+		if !s.isPublic(o) || !s.matchesFilter(o, f) || o.SightingLocation == nil {
+			continue
+		}
+		lon, lat, ok := s.location(o, f)
+		if !ok {
+			continue
+		}
+
+		var key string
+		var ring [][2]float64
+		if cell == biolog.CellGeohash5 {
+			key, ring = geohashCell(lon, lat, 5)
+		} else {
+			key, ring = eeaCell(lon, lat, 10000)
+		}
+
+		c, ok := cells[key]
+		if !ok {
+			geometry, _ := json.Marshal(map[string]interface{}{"type": "Polygon", "coordinates": [][][2]float64{ring}})
+			c = &biolog.DistributionCell{Cell: key, Geometry: geometry}
+			cells[key] = c
+		}
+		c.Observations++
+		if o.SightingTime != nil {
+			if c.FirstSighting == nil || o.SightingTime.Before(*c.FirstSighting) {
+				t := *o.SightingTime
+				c.FirstSighting = &t
+			}
+			if c.LastSighting == nil || o.SightingTime.After(*c.LastSighting) {
+				t := *o.SightingTime
+				c.LastSighting = &t
+			}
+		}
+	}
+
+	result := []biolog.DistributionCell{}
+	for _, c := range cells {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Cell < result[j].Cell })
+	return result, nil
+}
+
+// bbox vrne obroc poligona za pravokotnik (v smeri urinega kazalca kot pri ST_GeomFromGeoHash)
+func bbox(minX, minY, maxX, maxY float64) [][2]float64 {
+	return [][2]float64{{minX, minY}, {minX, maxY}, {maxX, maxY}, {maxX, minY}, {minX, minY}}
+}
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashCell vrne geohash podane dolzine za tocko in obroc poligona celice
+func geohashCell(lon, lat float64, precision int) (string, [][2]float64) {
+	minLon, maxLon, minLat, maxLat := -180.0, 180.0, -90.0, 90.0
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, ch := 0, 0
+	for len(hash) < precision {
+		// Biti se izmenjujejo, zacne se z geografsko dolzino
+		if even {
+			mid := (minLon + maxLon) / 2
+			if lon >= mid {
+				ch = ch<<1 | 1
+				minLon = mid
+			} else {
+				ch = ch << 1
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch = ch << 1
+				maxLat = mid
+			}
+		}
+		even = !even
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash), bbox(minLon, minLat, maxLon, maxLat)
+}
+
+// eeaCell vrne kodo celice evropske referencne mreze podane velikosti (v metrih) za tocko
+// in obroc poligona celice v WGS84
+func eeaCell(lon, lat float64, size float64) (string, [][2]float64) {
+	x, y := biolog.ToLAEA(lon, lat)
+	minX, minY := math.Floor(x/size)*size, math.Floor(y/size)*size
+
+	ring := bbox(minX, minY, minX+size, minY+size)
+	for i, corner := range ring {
+		ring[i][0], ring[i][1] = biolog.FromLAEA(corner[0], corner[1])
+	}
+	key := fmt.Sprintf("%dkmE%dN%d", int(size/1000), int(minX/size), int(minY/size))
+	return key, ring
+}
@@ -0,0 +1,79 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDistribution preveri zdruzevanje opazanj v celice obeh mrez
+func TestDistribution(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+
+	// Dve opazanji v isti celici, eno drugje in eno zasebno
+	first := time.Date(2017, 4, 12, 8, 30, 0, 0, time.UTC)
+	last := time.Date(2018, 6, 4, 11, 7, 37, 0, time.UTC)
+	for _, ob := range []struct {
+		loc    string
+		time   time.Time
+		public bool
+	}{
+		{"POINT(-5.6 42.6)", last, true},
+		{"POINT(-5.6001 42.6001)", first, true},
+		{"POINT(14.5058 46.0569)", first, true},
+		{"POINT(-5.6 42.6)", first, false},
+	} {
+		loc, sightingTime, public, quantity := ob.loc, ob.time, ob.public, 1
+		_, err := ss.CreateObservation(&biolog.Observation{SightingTime: &sightingTime, SightingLocation: &loc,
+			Quantity: &quantity, PublicVisibility: &public, User: u.ID, Species: &testSpeciesID})
+		assert.NoError(t, err)
+	}
+
+	cells, err := ss.Distribution(biolog.CellGeohash5, biolog.ObservationFilter{Species: &testSpeciesID})
+	if assert.NoError(t, err) && assert.Len(t, cells, 2) {
+		assert.Equal(t, "ezs42", cells[0].Cell)
+		assert.Equal(t, 2, cells[0].Observations)
+		assert.Equal(t, first, *cells[0].FirstSighting)
+		assert.Equal(t, last, *cells[0].LastSighting)
+
+		var geometry struct {
+			Type        string
+			Coordinates [][][2]float64
+		}
+		if assert.NoError(t, json.Unmarshal(cells[0].Geometry, &geometry)) {
+			assert.Equal(t, "Polygon", geometry.Type)
+			ring := geometry.Coordinates[0]
+			assert.True(t, ring[0][0] <= -5.6 && ring[2][0] >= -5.6)
+			assert.True(t, ring[0][1] <= 42.6 && ring[2][1] >= 42.6)
+		}
+	}
+
+	// Ljubljana lezi v celici 10kmE466N255 evropske referencne mreze
+	cells, err = ss.Distribution(biolog.Cell10km, biolog.ObservationFilter{Species: &testSpeciesID})
+	if assert.NoError(t, err) && assert.Len(t, cells, 2) {
+		assert.Equal(t, "10kmE466N255", cells[1].Cell)
+		assert.Equal(t, 1, cells[1].Observations)
+	}
+
+	// Posplosena lokacija ostane v isti 10 km celici
+	policy := biolog.DefaultSensitivityPolicy([]int{testSpeciesID})
+	obscured, err := ss.Distribution(biolog.Cell10km, biolog.ObservationFilter{Species: &testSpeciesID, Obscure: &policy})
+	if assert.NoError(t, err) {
+		assert.Equal(t, cells, obscured)
+	}
+
+	other := 1
+	cells, _ = ss.Distribution(biolog.CellGeohash5, biolog.ObservationFilter{Species: &other})
+	assert.Len(t, cells, 0)
+
+	_, err = ss.Distribution("1km", biolog.ObservationFilter{})
+	assert.Error(t, err)
+}
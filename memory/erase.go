@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// EraseUser trajno izbrise uporabniski racun. Osebni podatki (imena, email, slika in zunanji ID) se odstranijo,
+// zapis ostane kot anonimen "izbrisan uporabnik". Ce je keepObservations true, se ohranijo javna opazanja ter
+// identifikacije in komentarji uporabnika, sicer se zbrise vsa njegova vsebina
+func (s *UserService) EraseUser(id int, keepObservations bool, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	u, ok := s.Store.users[id]
+	if !ok {
+		u, ok = s.Store.deletedUsers[id]
+	}
+	if !ok || u.ErasedAt != nil {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	ss := &SpeciesService{Store: s.Store}
+
+	// Zasebna in izbrisana opazanja se vedno zbrisejo, javna (tudi na ravni racuna) le ce jih uporabnik ne zeli ohraniti
+	keepPublic := keepObservations && (u.PublicObservations == nil || *u.PublicObservations)
+	for oid, o := range s.Store.observations {
+		if *o.User == id && !(keepPublic && *o.PublicVisibility) {
+			ss.deleteObservation(oid)
+		}
+	}
+	for oid, o := range s.Store.deletedObservations {
+		if *o.User == id {
+			ss.deleteObservation(oid)
+		}
+	}
+
+	if !keepObservations {
+		// Identifikacije na opazanjih drugih uporabnikov vplivajo na soglasje skupnosti, ki se ponovno izracuna
+		affected := make(map[int]bool)
+		for iid, i := range s.Store.identifications {
+			if *i.User == id {
+				affected[*i.Observation] = true
+				delete(s.Store.identifications, iid)
+			}
+		}
+		for oid := range affected {
+			if o, ok := s.Store.observations[oid]; ok {
+				ss.updateConsensus(o)
+			} else if o, ok := s.Store.deletedObservations[oid]; ok {
+				ss.updateConsensus(o)
+			}
+		}
+		for cid, c := range s.Store.comments {
+			if *c.User == id {
+				delete(s.Store.comments, cid)
+			}
+		}
+		for cid, c := range s.Store.checklists {
+			if *c.User == id {
+				delete(s.Store.checklists, cid)
+			}
+		}
+	}
+	for _, members := range s.Store.projectMembers {
+		delete(members, id)
+	}
+	delete(s.Store.follows, id)
+	for _, followed := range s.Store.follows {
+		delete(followed, id)
+	}
+	delete(s.Store.watches, id)
+
+	now := time.Now()
+	name, role := biolog.DeletedUserName, biolog.RoleUser
+	erased := &biolog.User{ID: u.ID, PublicObservations: u.PublicObservations, DisplayName: &name, Role: &role,
+		ErasedAt: &now}
+	delete(s.Store.deletedUsers, id)
+	s.Store.users[id] = erased
+	// Osebni podatki se ne prepisejo v revizijsko sled, zapise se le anonimizirano stanje
+	s.Store.audit(by, biolog.AuditErase, biolog.AuditUser, id, nil, erased)
+	return nil
+}
@@ -0,0 +1,59 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEraseUser preveri trajni izbris racuna z ohranitvijo javnih opazanj in brez nje
+func TestEraseUser(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	other, _ := us.CreateUser(newTestUser(2))
+	public := createTestObservation(t, ss, *owner.ID, true)
+	private := createTestObservation(t, ss, *owner.ID, false)
+	foreign := createTestObservation(t, ss, *other.ID, true)
+	species := 5231190
+	_, err := ss.CreateIdentification(&biolog.Identification{Observation: foreign.ID, User: owner.ID, Species: &species})
+	assert.NoError(t, err)
+
+	by := biolog.Actor{User: *owner.ID}
+	if !assert.NoError(t, us.EraseUser(*owner.ID, true, by)) {
+		return
+	}
+	erased, err := us.User(*owner.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, biolog.DeletedUserName, *erased.DisplayName)
+		assert.Nil(t, erased.Email)
+		assert.Nil(t, erased.ExternalID)
+		assert.NotNil(t, erased.ErasedAt)
+	}
+	users, _ := us.Users()
+	assert.Len(t, users, 1)
+	_, err = ss.Observation(*public.ID)
+	assert.NoError(t, err)
+	_, err = ss.Observation(*private.ID)
+	assert.Error(t, err)
+	ids, _ := ss.Identifications(*foreign.ID)
+	assert.Len(t, ids, 1)
+
+	// Izbrisanega racuna ni mogoce posodobiti ali ponovno izbrisati
+	assert.Error(t, us.UpdateUser(*owner.ID, biolog.User{Email: other.Email}, by))
+	assert.Error(t, us.EraseUser(*owner.ID, false, by))
+
+	// Brez ohranitve se zbrisejo tudi javna opazanja in identifikacije na tujih opazanjih
+	third, _ := us.CreateUser(newTestUser(3))
+	public = createTestObservation(t, ss, *third.ID, true)
+	_, err = ss.CreateIdentification(&biolog.Identification{Observation: foreign.ID, User: third.ID, Species: &species})
+	assert.NoError(t, err)
+	assert.NoError(t, us.EraseUser(*third.ID, false, biolog.Actor{User: *third.ID}))
+	_, err = ss.Observation(*public.ID)
+	assert.Error(t, err)
+	ids, _ = ss.Identifications(*foreign.ID)
+	assert.Len(t, ids, 1)
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// PersonalData zbere vse podatke uporabnika za izvoz: profil, opazanja (tudi zasebna), komentarje in identifikacije
+func (s *UserService) PersonalData(id int) (*biolog.PersonalData, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	u, ok := s.Store.users[id]
+	if !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	d := &biolog.PersonalData{User: *clone(u).(*biolog.User), Observations: []biolog.Observation{},
+		Comments: []biolog.Comment{}, Identifications: []biolog.Identification{}}
+
+	for _, o := range s.Store.observations {
+		if *o.User == id {
+			d.Observations = append(d.Observations, *clone(o).(*biolog.Observation))
+		}
+	}
+	for _, c := range s.Store.comments {
+		if *c.User == id {
+			d.Comments = append(d.Comments, *clone(c).(*biolog.Comment))
+		}
+	}
+	for _, i := range s.Store.identifications {
+		if *i.User == id {
+			d.Identifications = append(d.Identifications, *clone(i).(*biolog.Identification))
+		}
+	}
+	// IDji so dodeljeni narascajoce, zato je vrstni red enak kot v bazi
+	sort.Slice(d.Observations, func(a, b int) bool { return *d.Observations[a].ID < *d.Observations[b].ID })
+	sort.Slice(d.Comments, func(a, b int) bool { return *d.Comments[a].ID < *d.Comments[b].ID })
+	sort.Slice(d.Identifications, func(a, b int) bool { return *d.Identifications[a].ID < *d.Identifications[b].ID })
+
+	return d, nil
+}
+
+// CreateExport shrani nov izvoz osebnih podatkov
+func (s *UserService) CreateExport(e biolog.Export) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.users[e.User]; !ok {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	if _, ok := s.Store.exports[e.ID]; ok {
+		return errors.New("Izvoz s tem ID ze obstaja")
+	}
+	s.Store.exports[e.ID] = clone(&e).(*biolog.Export)
+	return nil
+}
+
+// Export vrne stanje izvoza z dolocenim identifikatorjem
+func (s *UserService) Export(id string) (*biolog.Export, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	e, ok := s.Store.exports[id]
+	if !ok {
+		return nil, errors.New("Izvoz ne obstaja")
+	}
+	return clone(e).(*biolog.Export), nil
+}
+
+// UpdateExport posodobi stanje, pot do datoteke in cas poteka izvoza
+func (s *UserService) UpdateExport(e biolog.Export) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.exports[e.ID]
+	if !ok {
+		return errors.New("Izvoz ne obstaja")
+	}
+	existing.Status, existing.Path = e.Status, e.Path
+	if e.ExpiresAt != nil {
+		expires := *e.ExpiresAt
+		existing.ExpiresAt = &expires
+	}
+	return nil
+}
+
+// DeleteExpiredExports odstrani izvoze, ki so potekli pred now, in jih vrne (za brisanje datotek)
+func (s *UserService) DeleteExpiredExports(now time.Time) ([]biolog.Export, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	expired := []biolog.Export{}
+	for id, e := range s.Store.exports {
+		if e.ExpiresAt != nil && e.ExpiresAt.Before(now) {
+			expired = append(expired, *e)
+			delete(s.Store.exports, id)
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,72 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPersonalData preveri, da izvoz vsebuje le podatke uporabnika, tudi zasebna opazanja
+func TestPersonalData(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	other, _ := us.CreateUser(newTestUser(2))
+	public := createTestObservation(t, ss, *owner.ID, true)
+	private := createTestObservation(t, ss, *owner.ID, false)
+	createTestObservation(t, ss, *other.ID, true)
+
+	d, err := us.PersonalData(*owner.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *owner.Email, *d.User.Email)
+		if assert.Len(t, d.Observations, 2) {
+			assert.Equal(t, *public.ID, *d.Observations[0].ID)
+			assert.Equal(t, *private.ID, *d.Observations[1].ID)
+		}
+		assert.Len(t, d.Comments, 0)
+		assert.Len(t, d.Identifications, 0)
+	}
+
+	_, err = us.PersonalData(99999999)
+	assert.Error(t, err)
+}
+
+// TestExports preveri shranjevanje, posodabljanje in odstranjevanje poteklih izvozov
+func TestExports(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	e := biolog.Export{ID: "abc", User: *u.ID, Status: biolog.ExportPending, CreatedAt: now, ExpiresAt: &expires}
+	assert.NoError(t, us.CreateExport(e))
+	assert.Error(t, us.CreateExport(e))
+	e.User = 99999999
+	e.ID = "def"
+	assert.Error(t, us.CreateExport(e))
+
+	ready := biolog.Export{ID: "abc", Status: biolog.ExportReady, Path: "/tmp/biolog-export.zip"}
+	assert.NoError(t, us.UpdateExport(ready))
+	got, err := us.Export("abc")
+	if assert.NoError(t, err) {
+		assert.Equal(t, biolog.ExportReady, got.Status)
+		assert.Equal(t, ready.Path, got.Path)
+		assert.Equal(t, expires, *got.ExpiresAt)
+	}
+	assert.Error(t, us.UpdateExport(biolog.Export{ID: "def"}))
+
+	expired, err := us.DeleteExpiredExports(now)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	expired, err = us.DeleteExpiredExports(expires.Add(time.Second))
+	if assert.NoError(t, err) && assert.Len(t, expired, 1) {
+		assert.Equal(t, ready.Path, expired[0].Path)
+	}
+	_, err = us.Export("abc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// WatchSpecies doda vrsto med vrste, ki jih uporabnik opazuje. Pri sinonimu se shrani sprejeta vrsta
+func (s *SpeciesService) WatchSpecies(userID, gbifKey int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	sp, ok := s.Store.species[gbifKey]
+	if !ok {
+		return errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	if _, ok := s.Store.users[userID]; !ok {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	if sp.AcceptedKey != nil {
+		gbifKey = *sp.AcceptedKey
+	}
+	if s.Store.watches[userID] == nil {
+		s.Store.watches[userID] = make(map[int]bool)
+	}
+	s.Store.watches[userID][gbifKey] = true
+	return nil
+}
+
+// UnwatchSpecies odstrani vrsto iz vrst, ki jih uporabnik opazuje
+func (s *SpeciesService) UnwatchSpecies(userID, gbifKey int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if sp, ok := s.Store.species[gbifKey]; ok && sp.AcceptedKey != nil {
+		gbifKey = *sp.AcceptedKey
+	}
+	if !s.Store.watches[userID][gbifKey] {
+		return errors.New("Uporabnik te vrste ne opazuje")
+	}
+	delete(s.Store.watches[userID], gbifKey)
+	return nil
+}
+
+// WatchedSpecies vrne vrste, ki jih uporabnik opazuje, urejene po kanonicnem imenu
+func (s *SpeciesService) WatchedSpecies(userID int) ([]biolog.Species, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	sps := []biolog.Species{}
+	for key := range s.Store.watches[userID] {
+		if sp, ok := s.Store.species[key]; ok {
+			sps = append(sps, *clone(sp).(*biolog.Species))
+		}
+	}
+	sort.Slice(sps, func(i, j int) bool {
+		if *sps[i].CanonicalName != *sps[j].CanonicalName {
+			return *sps[i].CanonicalName < *sps[j].CanonicalName
+		}
+		return *sps[i].ID < *sps[j].ID
+	})
+	return sps, nil
+}
+
+// Feed vrne najvec limit najnovejsih dogodkov za polozajem before: javna opazanja in identifikacije spremljanih
+// uporabnikov in opazovanih vrst ter komentarje spremljanih uporabnikov (lastni dogodki niso del vira)
+func (s *SpeciesService) Feed(userID int, before biolog.FeedCursor, limit int) ([]biolog.FeedItem, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	followed, watched := s.Store.follows[userID], s.Store.watches[userID]
+	isFollowed := func(id int) bool {
+		_, ok := followed[id]
+		return ok && id != userID
+	}
+	// Dogodki so vidni le na javnih, neizbrisanih opazanjih
+	visible := func(observationID int) (*biolog.Observation, bool) {
+		o, ok := s.Store.observations[observationID]
+		return o, ok && s.isPublic(o)
+	}
+
+	items := []biolog.FeedItem{}
+	for _, o := range s.Store.observations {
+		if o.CreatedAt != nil && (isFollowed(*o.User) || watched[*o.Species] && *o.User != userID) && s.isPublic(o) {
+			items = append(items, biolog.FeedItem{Type: biolog.FeedObservation, CreatedAt: *o.CreatedAt, User: *o.User,
+				Observation: clone(o).(*biolog.Observation)})
+		}
+	}
+	for _, i := range s.Store.identifications {
+		if !(isFollowed(*i.User) || watched[*i.Species] && *i.User != userID) {
+			continue
+		}
+		if o, ok := visible(*i.Observation); ok {
+			items = append(items, biolog.FeedItem{Type: biolog.FeedIdentification, CreatedAt: *i.CreatedAt, User: *i.User,
+				Observation: clone(o).(*biolog.Observation), Identification: clone(i).(*biolog.Identification)})
+		}
+	}
+	for _, c := range s.Store.comments {
+		if !isFollowed(*c.User) {
+			continue
+		}
+		if o, ok := visible(*c.Observation); ok {
+			items = append(items, biolog.FeedItem{Type: biolog.FeedComment, CreatedAt: *c.CreatedAt, User: *c.User,
+				Observation: clone(o).(*biolog.Observation), Comment: clone(c).(*biolog.Comment)})
+		}
+	}
+
+	// Enak vrstni red kot v bazi: od najnovejsega naprej, pri enakem casu po vrsti dogodka in ID zapisa
+	page := items[:0]
+	for _, item := range items {
+		if before.Before(item.Position()) {
+			page = append(page, item)
+		}
+	}
+	items = page
+	sort.Slice(items, func(a, b int) bool { return items[a].Position().Before(items[b].Position()) })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
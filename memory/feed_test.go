@@ -0,0 +1,89 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFollowUser preveri spremljanje in prenehanje spremljanja uporabnikov
+func TestFollowUser(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+	me, _ := us.CreateUser(newTestUser(1))
+	first, _ := us.CreateUser(newTestUser(2))
+	second, _ := us.CreateUser(newTestUser(3))
+
+	assert.Error(t, us.FollowUser(*me.ID, *me.ID))
+	assert.Error(t, us.FollowUser(*me.ID, 99999999))
+	assert.NoError(t, us.FollowUser(*me.ID, *first.ID))
+	assert.NoError(t, us.FollowUser(*me.ID, *second.ID))
+	// Ponovno spremljanje ni napaka
+	assert.NoError(t, us.FollowUser(*me.ID, *first.ID))
+
+	following, err := us.Following(*me.ID)
+	if assert.NoError(t, err) && assert.Len(t, following, 2) {
+		assert.Equal(t, *second.ID, *following[0].ID)
+	}
+
+	assert.NoError(t, us.UnfollowUser(*me.ID, *second.ID))
+	assert.Error(t, us.UnfollowUser(*me.ID, *second.ID))
+	following, _ = us.Following(*me.ID)
+	assert.Len(t, following, 1)
+}
+
+// TestFeed preveri vsebino in vrstni red vira dejavnosti ter ostranjevanje s polozajem
+func TestFeed(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	me, _ := us.CreateUser(newTestUser(1))
+	followed, _ := us.CreateUser(newTestUser(2))
+	stranger, _ := us.CreateUser(newTestUser(3))
+
+	assert.NoError(t, us.FollowUser(*me.ID, *followed.ID))
+	public := createTestObservation(t, ss, *followed.ID, true)
+	createTestObservation(t, ss, *followed.ID, false)
+	own := createTestObservation(t, ss, *me.ID, true)
+	foreign := createTestObservation(t, ss, *stranger.ID, true)
+	body := "Lep posnetek"
+	_, err := ss.CreateComment(&biolog.Comment{Observation: own.ID, User: followed.ID, Body: &body})
+	assert.NoError(t, err)
+
+	// Brez opazovanih vrst so v viru le javna opazanja in komentarji spremljanih uporabnikov
+	items, err := ss.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Second)}, 20)
+	if assert.NoError(t, err) && assert.Len(t, items, 2) {
+		assert.Equal(t, biolog.FeedComment, items[0].Type)
+		assert.Equal(t, *own.ID, *items[0].Observation.ID)
+		assert.Equal(t, biolog.FeedObservation, items[1].Type)
+		assert.Equal(t, *public.ID, *items[1].Observation.ID)
+	}
+
+	// Z opazovano vrsto so v viru tudi opazanja drugih uporabnikov (lastna ne)
+	assert.Error(t, ss.WatchSpecies(*me.ID, 1))
+	assert.NoError(t, ss.WatchSpecies(*me.ID, *foreign.Species))
+	watched, _ := ss.WatchedSpecies(*me.ID)
+	assert.Len(t, watched, 1)
+	items, _ = ss.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Second)}, 20)
+	if assert.Len(t, items, 3) {
+		for _, item := range items {
+			assert.NotEqual(t, *me.ID, item.User)
+		}
+	}
+
+	// Ostranjevanje: naslednja stran se zacne za polozajem zadnjega dogodka
+	items, _ = ss.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Second)}, 1)
+	if assert.Len(t, items, 1) {
+		rest, _ := ss.Feed(*me.ID, items[0].Position(), 20)
+		if assert.Len(t, rest, 2) {
+			assert.True(t, items[0].Position().Before(rest[0].Position()))
+		}
+	}
+
+	assert.NoError(t, ss.UnwatchSpecies(*me.ID, *foreign.Species))
+	assert.Error(t, ss.UnwatchSpecies(*me.ID, *foreign.Species))
+	items, _ = ss.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Second)}, 20)
+	assert.Len(t, items, 2)
+}
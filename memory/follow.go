@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// FollowUser doda uporabnika userID med uporabnike, ki jih followerID spremlja. Ponovno spremljanje ni napaka
+func (s *UserService) FollowUser(followerID, userID int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if followerID == userID {
+		return errors.New("Uporabnik ne more spremljati samega sebe")
+	}
+	if _, ok := s.Store.users[followerID]; !ok {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	// Izbrisanih (tudi trajno) uporabnikov ni mogoce spremljati
+	if u, ok := s.Store.users[userID]; !ok || u.DeletedAt != nil || u.ErasedAt != nil {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	if s.Store.follows[followerID] == nil {
+		s.Store.follows[followerID] = make(map[int]time.Time)
+	}
+	if _, ok := s.Store.follows[followerID][userID]; !ok {
+		s.Store.follows[followerID][userID] = time.Now()
+	}
+	return nil
+}
+
+// UnfollowUser preneha spremljati uporabnika userID
+func (s *UserService) UnfollowUser(followerID, userID int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.follows[followerID][userID]; !ok {
+		return errors.New("Uporabnik tega uporabnika ne spremlja")
+	}
+	delete(s.Store.follows[followerID], userID)
+	return nil
+}
+
+// Following vrne uporabnike, ki jih uporabnik spremlja, od nazadnje dodanega naprej
+func (s *UserService) Following(userID int) ([]biolog.User, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	us := []biolog.User{}
+	for id := range s.Store.follows[userID] {
+		if u, ok := s.Store.users[id]; ok {
+			us = append(us, *clone(u).(*biolog.User))
+		}
+	}
+	since := s.Store.follows[userID]
+	sort.Slice(us, func(i, j int) bool {
+		a, b := since[*us[i].ID], since[*us[j].ID]
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return *us[i].ID < *us[j].ID
+	})
+	return us, nil
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// Identification vrne predlog vrste z dolocenim ID
+func (s *SpeciesService) Identification(id int) (*biolog.Identification, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	i, ok := s.Store.identifications[id]
+	if !ok {
+		return nil, errors.New("Identifikacija s tem ID ne obstaja")
+	}
+	return clone(i).(*biolog.Identification), nil
+}
+
+// Identifications vrne vse predloge vrst za doloceno opazanje, urejene po vrstnem redu vnosa
+func (s *SpeciesService) Identifications(observationID int) ([]biolog.Identification, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	ids := []biolog.Identification{}
+	for _, i := range s.Store.identifications {
+		if *i.Observation == observationID {
+			ids = append(ids, *clone(i).(*biolog.Identification))
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool { return *ids[a].ID < *ids[b].ID })
+	return ids, nil
+}
+
+// CreateIdentification shrani nov predlog vrste. Prejsnji predlog istega uporabnika za isto
+// opazanje ni vec trenuten, vrsta skupnosti in stopnja kakovosti se ponovno izracunata
+func (s *SpeciesService) CreateIdentification(i *biolog.Identification) (*biolog.Identification, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if i.Observation == nil || i.User == nil || i.Species == nil {
+		return nil, errors.New("Identifikacija mora imeti opazanje, uporabnika in vrsto")
+	}
+	ob, ok := s.Store.observations[*i.Observation]
+	if !ok {
+		return nil, errors.New("Opazanje s tem ID ne obstaja")
+	}
+	if err := s.checkReferences(&biolog.Observation{User: i.User, Species: i.Species}); err != nil {
+		return nil, err
+	}
+
+	for _, other := range s.Store.identifications {
+		if *other.Observation == *i.Observation && *other.User == *i.User {
+			other.Current = boolPtr(false)
+		}
+	}
+
+	newID := clone(i).(*biolog.Identification)
+	now := time.Now()
+	newID.ID = intPtr(s.Store.nextIdentificationID)
+	newID.Current = boolPtr(true)
+	newID.CreatedAt = &now
+	s.Store.nextIdentificationID++
+	s.Store.identifications[*newID.ID] = newID
+
+	s.updateConsensus(ob)
+	return clone(newID).(*biolog.Identification), nil
+}
+
+// WithdrawIdentification umakne predlog vrste (ni vec trenuten) in ponovno izracuna soglasje
+func (s *SpeciesService) WithdrawIdentification(id int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	i, ok := s.Store.identifications[id]
+	if !ok {
+		return errors.New("Identifikacija s tem ID ne obstaja")
+	}
+	i.Current = boolPtr(false)
+	if ob, ok := s.Store.observations[*i.Observation]; ok {
+		s.updateConsensus(ob)
+	}
+	return nil
+}
+
+// updateConsensus ponovno izracuna vrsto skupnosti in stopnjo kakovosti opazanja,
+// klicatelj mora drzati kljucavnico za pisanje
+func (s *SpeciesService) updateConsensus(ob *biolog.Observation) {
+	ids := []biolog.Identification{}
+	for _, i := range s.Store.identifications {
+		if *i.Observation == *ob.ID {
+			ids = append(ids, *i)
+		}
+	}
+	taxon, grade := biolog.Consensus(*ob, ids)
+	ob.CommunityTaxon = taxon
+	ob.QualityGrade = &grade
+}
@@ -0,0 +1,57 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdentifications preveri, da identifikacije spremenijo stopnjo kakovosti opazanja
+// in da jo lahko uporabimo kot filter pri seznamu opazanj
+func TestIdentifications(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	expert, _ := us.CreateUser(newTestUser(2))
+	o := createTestObservation(t, ss, *owner.ID, true)
+	assert.Equal(t, biolog.GradeNeedsID, *o.QualityGrade)
+
+	research := biolog.GradeResearch
+	obs, _ := ss.Observations(biolog.ObservationFilter{QualityGrade: &research})
+	assert.Len(t, obs, 0)
+
+	// Strokovnjak potrdi vrsto
+	i, err := ss.CreateIdentification(&biolog.Identification{Observation: o.ID, User: expert.ID, Species: &testSpeciesID})
+	if assert.NoError(t, err) {
+		assert.True(t, *i.Current)
+	}
+	updated, _ := ss.Observation(*o.ID)
+	assert.Equal(t, research, *updated.QualityGrade)
+	assert.Equal(t, testSpeciesID, *updated.CommunityTaxon)
+
+	obs, _ = ss.Observations(biolog.ObservationFilter{QualityGrade: &research})
+	assert.Len(t, obs, 1)
+
+	// Nova identifikacija istega uporabnika zamenja prejsnjo
+	_, err = ss.CreateIdentification(&biolog.Identification{Observation: o.ID, User: expert.ID, Species: &testSpeciesID})
+	assert.NoError(t, err)
+	ids, _ := ss.Identifications(*o.ID)
+	if assert.Len(t, ids, 2) {
+		assert.False(t, *ids[0].Current)
+		assert.True(t, *ids[1].Current)
+	}
+
+	// Umik identifikacije vrne opazanje med tista, ki potrebujejo identifikacijo
+	assert.NoError(t, ss.WithdrawIdentification(*ids[1].ID))
+	updated, _ = ss.Observation(*o.ID)
+	assert.Equal(t, biolog.GradeNeedsID, *updated.QualityGrade)
+	assert.Nil(t, updated.CommunityTaxon)
+
+	// Opazanja, ki ne obstaja, ni mogoce identificirati
+	missing := 999
+	_, err = ss.CreateIdentification(&biolog.Identification{Observation: &missing, User: expert.ID, Species: &testSpeciesID})
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+// Package memory vsebuje implementacije servicev, ki podatke hranijo v pomnilniku.
+// Uporabljajo se pri testiranju in pri razvoju frontenda, ko PostgreSQL ni na voljo.
+// Vsi servici si delijo en Store, zato so pravila (npr. vidnost opazanj) enaka kot v bazi.
+package memory
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// Obseg IDjev za uporabnike (8 mestni ID, enako kot external_user_id_seq v bazi)
+const (
+	minUserID = 10000000
+	maxUserID = 99999999
+)
+
+// Store hrani vse podatke v pomnilniku in je varen za socasno uporabo
+type Store struct {
+	mu sync.RWMutex
+
+	users         map[int]*biolog.User
+	authProviders map[int]*biolog.AuthProvider
+	species       map[int]*biolog.Species
+	observations  map[int]*biolog.Observation
+	statuses      map[int]*biolog.ConservationStatus
+
+	identifications map[int]*biolog.Identification
+	comments        map[int]*biolog.Comment
+	regions         map[int]*biolog.Region
+	regionShapes    map[int]multiPolygon
+	checklists      map[int]*biolog.Checklist
+	projects        map[int]*biolog.Project
+	projectShapes   map[int]multiPolygon
+	// Clani projektov: projekt -> uporabnik -> vloga
+	projectMembers map[int]map[int]string
+	assessments    map[int]*biolog.Assessment
+	merges         map[int]*biolog.TaxonMerge
+	// Revizije opazanj po vrsti, indeks v rezini je stevilka revizije - 1
+	revisions map[int][]*biolog.ObservationRevision
+	// Spremljanje uporabnikov: uporabnik -> spremljan uporabnik -> cas zacetka spremljanja
+	follows map[int]map[int]time.Time
+	// Opazovane vrste: uporabnik -> vrsta
+	watches map[int]map[int]bool
+	// Izbrisani (mehko) zapisi so loceni od ostalih, da jih branja ne vidijo
+	deletedUsers        map[int]*biolog.User
+	deletedObservations map[int]*biolog.Observation
+	// Revizijska sled, zapisi se le dodajajo
+	auditLog []*biolog.AuditEntry
+	// Narocnine na dogodke in vrsta posiljanj
+	webhooks   map[int]*biolog.Webhook
+	deliveries map[int]*biolog.WebhookDelivery
+	// Razposiljanje novih javnih opazanj toku opazanj
+	observationHub *biolog.ObservationHub
+	// Vedra omejevalnika zahtev imajo lastno kljucavnico, da zahteve ne cakajo na ostale podatke
+	bucketsMu sync.Mutex
+	buckets   map[string]*biolog.TokenBucket
+	// Izvozi osebnih podatkov po identifikatorju
+	exports map[string]*biolog.Export
+
+	// Naslednji prosti IDji (enako kot sekvence v bazi)
+	nextUserID           int
+	nextObservationID    int
+	nextIdentificationID int
+	nextCommentID        int
+	nextRegionID         int
+	nextChecklistID      int
+	nextProjectID        int
+	nextAssessmentID     int
+	nextMergeID          int
+	nextWebhookID        int
+	nextDeliveryID       int
+}
+
+// NewStore ustvari nov prazen Store, v katerem so ze vnaprej doloceni podatki
+// (statusi ogrozenosti in ponudniki avtentikacije, glej scripts/sample-data.sql)
+func NewStore() *Store {
+	s := &Store{
+		users:             make(map[int]*biolog.User),
+		authProviders:     make(map[int]*biolog.AuthProvider),
+		species:           make(map[int]*biolog.Species),
+		observations:      make(map[int]*biolog.Observation),
+		statuses:          make(map[int]*biolog.ConservationStatus),
+		nextUserID:        minUserID,
+		nextObservationID: 1,
+
+		identifications:      make(map[int]*biolog.Identification),
+		nextIdentificationID: 1,
+		comments:             make(map[int]*biolog.Comment),
+		nextCommentID:        1,
+		regions:              make(map[int]*biolog.Region),
+		regionShapes:         make(map[int]multiPolygon),
+		nextRegionID:         1,
+		checklists:           make(map[int]*biolog.Checklist),
+		nextChecklistID:      1,
+		projects:             make(map[int]*biolog.Project),
+		projectShapes:        make(map[int]multiPolygon),
+		projectMembers:       make(map[int]map[int]string),
+		nextProjectID:        1,
+		assessments:          make(map[int]*biolog.Assessment),
+		nextAssessmentID:     1,
+		merges:               make(map[int]*biolog.TaxonMerge),
+		nextMergeID:          1,
+		revisions:            make(map[int][]*biolog.ObservationRevision),
+		follows:              make(map[int]map[int]time.Time),
+		watches:              make(map[int]map[int]bool),
+		deletedUsers:         make(map[int]*biolog.User),
+		deletedObservations:  make(map[int]*biolog.Observation),
+		webhooks:             make(map[int]*biolog.Webhook),
+		nextWebhookID:        1,
+		deliveries:           make(map[int]*biolog.WebhookDelivery),
+		nextDeliveryID:       1,
+		observationHub:       biolog.NewObservationHub(),
+		buckets:              make(map[string]*biolog.TokenBucket),
+		exports:              make(map[string]*biolog.Export),
+	}
+
+	s.authProviders[1] = &biolog.AuthProvider{ID: 1, Name: "Google"}
+
+	statuses := []biolog.ConservationStatus{
+		{Acronym: "EX", NameEN: "Extinct", NameSI: "Izumrle"},
+		{Acronym: "EW", NameEN: "Extinct in the Wild", NameSI: "V naravi izumrle"},
+		{Acronym: "CR", NameEN: "Critically Endangered", NameSI: "Skrajno ogrožene"},
+		{Acronym: "EN", NameEN: "Endangered", NameSI: "Ogrožene"},
+		{Acronym: "VU", NameEN: "Vulnerable", NameSI: "Ranljive"},
+		{Acronym: "NT", NameEN: "Near Threatened", NameSI: "Potencialno ogrožene"},
+		{Acronym: "CD", NameEN: "Conservation Dependent", NameSI: "Varstveno odvisne"},
+		{Acronym: "LC", NameEN: "Least Concern", NameSI: "Najmanj ogrožene"},
+		{Acronym: "DD", NameEN: "Data deficient", NameSI: "Premalo podatkov"},
+		{Acronym: "NE", NameEN: "Not evaluated", NameSI: "Neopredeljene"},
+	}
+	for i := range statuses {
+		statuses[i].ID = i + 1
+		s.statuses[i+1] = &statuses[i]
+	}
+
+	return s
+}
+
+// clone naredi globoko kopijo strukture, katere polja so kazalci na osnovne vrednosti
+// (npr. biolog.User). Tako klicatelj ne more spreminjati podatkov v Store mimo servica
+func clone(src interface{}) interface{} {
+	srcVal := reflect.ValueOf(src).Elem()
+	dst := reflect.New(srcVal.Type())
+
+	for i := 0; i < srcVal.NumField(); i++ {
+		field := srcVal.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			copied := reflect.New(field.Type().Elem())
+			copied.Elem().Set(field.Elem())
+			dst.Elem().Field(i).Set(copied)
+		} else {
+			dst.Elem().Field(i).Set(field)
+		}
+	}
+	return dst.Interface()
+}
+
+// mergeNonNil prepise vsa polja iz src, ki niso nil, v dst (enako kot UPDATE v postgres
+// paketu posodobi le podana polja). ID se nikoli ne prepise
+func mergeNonNil(dst, src interface{}) {
+	dstVal := reflect.ValueOf(dst).Elem()
+	srcVal := reflect.ValueOf(src).Elem()
+	cloned := reflect.ValueOf(clone(src)).Elem()
+
+	for i := 0; i < srcVal.NumField(); i++ {
+		if srcVal.Type().Field(i).Name == "ID" {
+			continue
+		}
+		field := srcVal.Field(i)
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			continue
+		}
+		dstVal.Field(i).Set(cloned.Field(i))
+	}
+}
+
+// Pomozni funkciji za kazalce na vrednosti
+func intPtr(i int) *int    { return &i }
+func boolPtr(b bool) *bool { return &b }
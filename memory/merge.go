@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// MergeSpecies zdruzi vrsto fromKey v sprejeto vrsto intoKey: preusmeri opazanja in identifikacije, ponovno
+// izracuna soglasje skupnosti, oznaci staro vrsto (in njene sinonime) kot sinonim ter shrani zapis o zdruzitvi
+// in revizijsko sled
+func (s *SpeciesService) MergeSpecies(fromKey, intoKey int, by biolog.Actor) (*biolog.TaxonMerge, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if fromKey == intoKey {
+		return nil, errors.New("Vrste ni mogoce zdruziti same vase")
+	}
+	from, fromOK := s.Store.species[fromKey]
+	into, intoOK := s.Store.species[intoKey]
+	if !fromOK || !intoOK {
+		return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	if into.AcceptedKey != nil {
+		return nil, errors.New("Ciljna vrsta je sinonim, zdruzi v sprejeto vrsto")
+	}
+	if _, ok := s.Store.users[by.User]; !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+
+	// Opazanja, pri katerih se lahko spremeni soglasje skupnosti (tudi izbrisana, da jih je mogoce obnoviti)
+	affected := make(map[int]bool)
+	observations, identifications := 0, 0
+	for _, obs := range []map[int]*biolog.Observation{s.Store.observations, s.Store.deletedObservations} {
+		for id, o := range obs {
+			if *o.Species == fromKey {
+				o.Species = intPtr(intoKey)
+				observations++
+				affected[id] = true
+			}
+			if o.CommunityTaxon != nil && *o.CommunityTaxon == fromKey {
+				affected[id] = true
+			}
+		}
+	}
+	for _, i := range s.Store.identifications {
+		if *i.Species == fromKey {
+			i.Species = intPtr(intoKey)
+			identifications++
+			affected[*i.Observation] = true
+		}
+	}
+	// Uporabniki, ki opazujejo staro vrsto, odslej opazujejo sprejeto vrsto
+	for _, watched := range s.Store.watches {
+		if watched[fromKey] {
+			delete(watched, fromKey)
+			watched[intoKey] = true
+		}
+	}
+	for id := range affected {
+		if o, ok := s.Store.observations[id]; ok {
+			s.updateConsensus(o)
+		} else if o, ok := s.Store.deletedObservations[id]; ok {
+			s.updateConsensus(o)
+		}
+	}
+
+	before := clone(from)
+	from.AcceptedKey = intPtr(intoKey)
+	for _, sp := range s.Store.species {
+		if sp.AcceptedKey != nil && *sp.AcceptedKey == fromKey {
+			sp.AcceptedKey = intPtr(intoKey)
+		}
+	}
+
+	s.Store.audit(by, biolog.AuditMerge, biolog.AuditSpecies, fromKey, before, from)
+
+	now := time.Now()
+	merge := &biolog.TaxonMerge{ID: intPtr(s.Store.nextMergeID), From: intPtr(fromKey), Into: intPtr(intoKey),
+		User: intPtr(by.User), Observations: &observations, Identifications: &identifications, MergedAt: &now}
+	s.Store.nextMergeID++
+	s.Store.merges[*merge.ID] = merge
+	return clone(merge).(*biolog.TaxonMerge), nil
+}
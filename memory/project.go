@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// Project vrne projekt z dolocenim ID skupaj z mejo
+func (s *SpeciesService) Project(id int) (*biolog.Project, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	p, ok := s.Store.projects[id]
+	if !ok {
+		return nil, errors.New("Projekt s tem ID ne obstaja")
+	}
+	project := clone(p).(*biolog.Project)
+	project.Boundary = append(json.RawMessage(nil), p.Boundary...)
+	return project, nil
+}
+
+// Projects vrne stran projektov (brez meje), urejenih od najnovejsega naprej
+func (s *SpeciesService) Projects(p biolog.Page) ([]biolog.Project, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	all := []biolog.Project{}
+	for _, project := range s.Store.projects {
+		cloned := *clone(project).(*biolog.Project)
+		cloned.Boundary = nil
+		all = append(all, cloned)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].StartDate.Equal(*all[j].StartDate) {
+			return all[i].StartDate.After(*all[j].StartDate)
+		}
+		return *all[i].ID > *all[j].ID
+	})
+
+	if p.Offset >= len(all) {
+		return []biolog.Project{}, nil
+	}
+	all = all[p.Offset:]
+	if p.Limit < len(all) {
+		all = all[:p.Limit]
+	}
+	return all, nil
+}
+
+// checkProject preveri vrednosti projekta (CHECK omejitve v bazi) in vrne obliko meje, ce je podana
+func checkProject(p *biolog.Project) (multiPolygon, error) {
+	if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+		return nil, errors.New("Projekt se ne more koncati pred zacetkom")
+	}
+	if p.TaxonRank != nil && !biolog.ValidTaxonRank(*p.TaxonRank) {
+		return nil, errors.New("Neveljavna taksonomska stopnja")
+	}
+	if len(p.Boundary) == 0 {
+		return nil, nil
+	}
+	return parseMultiPolygon(p.Boundary)
+}
+
+// CreateProject shrani projekt in njegovega avtorja doda med administratorje projekta
+func (s *SpeciesService) CreateProject(p *biolog.Project) (*biolog.Project, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if p.Name == nil || p.StartDate == nil || p.CreatedBy == nil {
+		return nil, errors.New("Manjkajo obvezni podatki o projektu")
+	}
+	if (p.TaxonRank == nil) != (p.TaxonName == nil) {
+		return nil, errors.New("Skupina vrst mora imeti taksonomsko stopnjo in ime")
+	}
+	shape, err := checkProject(p)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.Store.users[*p.CreatedBy]; !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+
+	project := clone(p).(*biolog.Project)
+	project.ID = intPtr(s.Store.nextProjectID)
+	if project.MembersOnly == nil {
+		project.MembersOnly = boolPtr(false)
+	}
+	project.Boundary = append(json.RawMessage(nil), p.Boundary...)
+	s.Store.nextProjectID++
+	s.Store.projects[*project.ID] = project
+	if shape != nil {
+		s.Store.projectShapes[*project.ID] = shape
+	}
+	s.Store.projectMembers[*project.ID] = map[int]string{*p.CreatedBy: biolog.ProjectRoleAdmin}
+
+	created := clone(project).(*biolog.Project)
+	created.Boundary = append(json.RawMessage(nil), project.Boundary...)
+	return created, nil
+}
+
+// UpdateProject posodobi podana polja projekta in mejo, ce je podana
+func (s *SpeciesService) UpdateProject(id int, p biolog.Project) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.projects[id]
+	if !ok {
+		return errors.New("Projekt s tem ID ne obstaja")
+	}
+	// Avtorja projekta ni mogoce spremeniti
+	p.CreatedBy = nil
+	merged := *clone(existing).(*biolog.Project)
+	mergeNonNil(&merged, &p)
+	if (merged.TaxonRank == nil) != (merged.TaxonName == nil) {
+		return errors.New("Skupina vrst mora imeti taksonomsko stopnjo in ime")
+	}
+	shape, err := checkProject(&merged)
+	if err != nil {
+		return err
+	}
+
+	boundary := existing.Boundary
+	mergeNonNil(existing, &p)
+	existing.Boundary = boundary
+	if shape != nil {
+		existing.Boundary = append(json.RawMessage(nil), p.Boundary...)
+		s.Store.projectShapes[id] = shape
+	}
+	return nil
+}
+
+// DeleteProject zbrise projekt in clanstva v njem, opazanja ostanejo
+func (s *SpeciesService) DeleteProject(id int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.projects[id]; !ok {
+		return errors.New("Projekt s tem ID ne obstaja")
+	}
+	delete(s.Store.projects, id)
+	delete(s.Store.projectShapes, id)
+	delete(s.Store.projectMembers, id)
+	return nil
+}
+
+// ProjectMembers vrne vse clane projekta, najprej administratorje
+func (s *SpeciesService) ProjectMembers(projectID int) ([]biolog.ProjectMember, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	members := []biolog.ProjectMember{}
+	for userID, role := range s.Store.projectMembers[projectID] {
+		if _, ok := s.Store.users[userID]; !ok {
+			continue
+		}
+		role := role
+		members = append(members, biolog.ProjectMember{Project: intPtr(projectID), User: intPtr(userID), Role: &role})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if *members[i].Role != *members[j].Role {
+			return *members[i].Role < *members[j].Role
+		}
+		return *members[i].User < *members[j].User
+	})
+	return members, nil
+}
+
+// SetProjectMember doda uporabnika v projekt ali mu spremeni vlogo
+func (s *SpeciesService) SetProjectMember(m biolog.ProjectMember) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if m.Project == nil || m.User == nil || m.Role == nil || !biolog.ValidProjectRole(*m.Role) {
+		return errors.New("Clan projekta mora imeti projekt, uporabnika in veljavno vlogo")
+	}
+	if _, ok := s.Store.projects[*m.Project]; !ok {
+		return errors.New("Projekt s tem ID ne obstaja")
+	}
+	if _, ok := s.Store.users[*m.User]; !ok {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	s.Store.projectMembers[*m.Project][*m.User] = *m.Role
+	return nil
+}
+
+// RemoveProjectMember odstrani uporabnika iz projekta
+func (s *SpeciesService) RemoveProjectMember(projectID, userID int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.projectMembers[projectID][userID]; !ok {
+		return errors.New("Uporabnik ni clan projekta")
+	}
+	delete(s.Store.projectMembers[projectID], userID)
+	return nil
+}
+
+// ProjectLeaderboard vrne uporabnike z javnimi opazanji v projektu, urejene po stevilu opazanj in vrst.
+// Ce obscure ni nil, se meja projekta preveri na posplosenih lokacijah obcutljivih vrst
+func (s *SpeciesService) ProjectLeaderboard(projectID int, obscure *biolog.SensitivityPolicy) ([]biolog.LeaderboardEntry, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	entries := make(map[int]*biolog.LeaderboardEntry)
+	species := make(map[int]map[int]bool)
+	for _, o := range s.Store.observations {
+		if !s.isPublic(o) || !s.inProject(o, projectID, biolog.ObservationFilter{Obscure: obscure}) {
+			continue
+		}
+		e, ok := entries[*o.User]
+		if !ok {
+			e = &biolog.LeaderboardEntry{User: *o.User}
+			entries[*o.User] = e
+			species[*o.User] = make(map[int]bool)
+		}
+		e.Observations++
+		species[*o.User][*o.Species] = true
+	}
+
+	leaderboard := []biolog.LeaderboardEntry{}
+	for userID, e := range entries {
+		e.Species = len(species[userID])
+		leaderboard = append(leaderboard, *e)
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		a, b := leaderboard[i], leaderboard[j]
+		if a.Observations != b.Observations {
+			return a.Observations > b.Observations
+		}
+		if a.Species != b.Species {
+			return a.Species > b.Species
+		}
+		return a.User < b.User
+	})
+	return leaderboard, nil
+}
+
+// inProject preveri ali opazanje ustreza pravilom projekta (enako kot projectRules v paketu postgres).
+// Meja se preveri na lokaciji iz s.location, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) inProject(o *biolog.Observation, projectID int, f biolog.ObservationFilter) bool {
+	p, ok := s.Store.projects[projectID]
+	if !ok || o.SightingTime == nil || !p.Active(*o.SightingTime) {
+		return false
+	}
+	if shape, ok := s.Store.projectShapes[projectID]; ok {
+		lon, lat, ok := s.location(o, f)
+		if !ok || !shape.contains(lon, lat) {
+			return false
+		}
+	}
+	if p.TaxonRank != nil {
+		sp, ok := s.Store.species[*o.Species]
+		if !ok {
+			return false
+		}
+		taxon := sp.Taxon(*p.TaxonRank)
+		if taxon == nil || *taxon != *p.TaxonName {
+			return false
+		}
+	}
+	if p.MembersOnly != nil && *p.MembersOnly {
+		if _, ok := s.Store.projectMembers[projectID][*o.User]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProjects preveri pravila projekta (obdobje, meja, skupina vrst, clanstvo) in lestvico
+func TestProjects(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	first, _ := us.CreateUser(newTestUser(1))
+	second, _ := us.CreateUser(newTestUser(2))
+	createTestSpecies(t, ss)
+
+	name := "Bioblitz Ljubljana"
+	start, end := time.Date(2018, 6, 2, 0, 0, 0, 0, time.UTC), time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC)
+	boundary := json.RawMessage(`{"type":"Polygon","coordinates":[[[14.4,46.0],[14.6,46.0],[14.6,46.1],[14.4,46.1],[14.4,46.0]]]}`)
+	p, err := ss.CreateProject(&biolog.Project{Name: &name, StartDate: &start, EndDate: &end, CreatedBy: first.ID,
+		Boundary: boundary})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, *p.MembersOnly)
+	members, _ := ss.ProjectMembers(*p.ID)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, biolog.ProjectRoleAdmin, *members[0].Role)
+	}
+
+	inside, outside := "POINT(14.5058 46.0569)", "POINT(15.6459 46.5547)"
+	lastDay, after := time.Date(2018, 6, 3, 23, 0, 0, 0, time.UTC), time.Date(2018, 6, 4, 0, 0, 0, 0, time.UTC)
+	for _, ob := range []struct {
+		user *int
+		loc  string
+		time time.Time
+	}{
+		{first.ID, inside, start},
+		{first.ID, inside, lastDay},
+		{second.ID, inside, lastDay},
+		{second.ID, outside, lastDay},
+		{second.ID, inside, after},
+	} {
+		loc, sightingTime, quantity, public := ob.loc, ob.time, 1, true
+		_, err := ss.CreateObservation(&biolog.Observation{SightingTime: &sightingTime, SightingLocation: &loc,
+			Quantity: &quantity, PublicVisibility: &public, User: ob.user, Species: &testSpeciesID})
+		assert.NoError(t, err)
+	}
+
+	obs, _ := ss.Observations(biolog.ObservationFilter{Project: p.ID})
+	assert.Len(t, obs, 3)
+	leaderboard, err := ss.ProjectLeaderboard(*p.ID, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []biolog.LeaderboardEntry{
+			{User: *first.ID, Observations: 2, Species: 1},
+			{User: *second.ID, Observations: 1, Species: 1},
+		}, leaderboard)
+	}
+
+	// Le opazanja clanov
+	membersOnly := true
+	assert.NoError(t, ss.UpdateProject(*p.ID, biolog.Project{MembersOnly: &membersOnly}))
+	obs, _ = ss.Observations(biolog.ObservationFilter{Project: p.ID})
+	assert.Len(t, obs, 2)
+	role := biolog.ProjectRoleMember
+	assert.NoError(t, ss.SetProjectMember(biolog.ProjectMember{Project: p.ID, User: second.ID, Role: &role}))
+	obs, _ = ss.Observations(biolog.ObservationFilter{Project: p.ID})
+	assert.Len(t, obs, 3)
+
+	// Skupina vrst, ki ji testna vrsta ne pripada
+	rank, taxon := biolog.TaxonClass, "Insecta"
+	assert.NoError(t, ss.UpdateProject(*p.ID, biolog.Project{TaxonRank: &rank, TaxonName: &taxon}))
+	obs, _ = ss.Observations(biolog.ObservationFilter{Project: p.ID})
+	assert.Len(t, obs, 0)
+
+	got, _ := ss.Project(*p.ID)
+	assert.JSONEq(t, string(boundary), string(got.Boundary))
+
+	invalid := "species"
+	assert.Error(t, ss.UpdateProject(*p.ID, biolog.Project{TaxonRank: &invalid}))
+	assert.NoError(t, ss.RemoveProjectMember(*p.ID, *second.ID))
+	assert.Error(t, ss.RemoveProjectMember(*p.ID, *second.ID))
+	assert.NoError(t, ss.DeleteProject(*p.ID))
+	_, err = ss.Project(*p.ID)
+	assert.Error(t, err)
+}
+
+// TestProjectObscuredBoundary preveri, da se meja projekta za obcutljive vrste preveri na posploseni lokaciji
+func TestProjectObscuredBoundary(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	user, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+
+	// Meja okoli natancne lokacije opazanja, sredisce celice mreze je zunaj nje
+	name, start := "Ozko obmocje", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	boundary := json.RawMessage(`{"type":"Polygon","coordinates":[[[14.50,46.05],[14.51,46.05],[14.51,46.06],[14.50,46.06],[14.50,46.05]]]}`)
+	p, err := ss.CreateProject(&biolog.Project{Name: &name, StartDate: &start, CreatedBy: user.ID, Boundary: boundary})
+	if !assert.NoError(t, err) {
+		return
+	}
+	loc, sightingTime, quantity, public := "POINT(14.5058 46.0569)", time.Date(2018, 6, 2, 0, 0, 0, 0, time.UTC), 1, true
+	_, err = ss.CreateObservation(&biolog.Observation{SightingTime: &sightingTime, SightingLocation: &loc,
+		Quantity: &quantity, PublicVisibility: &public, User: user.ID, Species: &testSpeciesID})
+	assert.NoError(t, err)
+
+	policy := biolog.DefaultSensitivityPolicy([]int{testSpeciesID})
+	obs, _ := ss.Observations(biolog.ObservationFilter{Project: p.ID})
+	assert.Len(t, obs, 1)
+	obs, _ = ss.Observations(biolog.ObservationFilter{Project: p.ID, Obscure: &policy})
+	assert.Len(t, obs, 0)
+	leaderboard, _ := ss.ProjectLeaderboard(*p.ID, nil)
+	assert.Len(t, leaderboard, 1)
+	leaderboard, _ = ss.ProjectLeaderboard(*p.ID, &policy)
+	assert.Len(t, leaderboard, 0)
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// RateLimiter predstavlja implementacijo biolog.RateLimiter v pomnilniku. Stevci veljajo le za to
+// instanco streznika
+type RateLimiter struct {
+	Store *Store
+}
+
+// Preveri ali RateLimiter implementira vse metode
+var _ biolog.RateLimiter = &RateLimiter{}
+
+// Take iz vedra key vzame en zeton, ce ga ima, in vrne stanje vedra po zahtevi
+func (s *RateLimiter) Take(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error) {
+	s.Store.bucketsMu.Lock()
+	defer s.Store.bucketsMu.Unlock()
+
+	b, ok := s.Store.buckets[key]
+	if !ok {
+		b = &biolog.TokenBucket{}
+		s.Store.buckets[key] = b
+	}
+	return b.Take(limit, time.Now()), nil
+}
+
+// Prune odstrani vedra, ki niso bila uporabljena od before
+func (s *RateLimiter) Prune(before time.Time) (int, error) {
+	s.Store.bucketsMu.Lock()
+	defer s.Store.bucketsMu.Unlock()
+
+	n := 0
+	for key, b := range s.Store.buckets {
+		if b.Updated.Before(before) {
+			delete(s.Store.buckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,32 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimiter preveri locena vedra po kljucih in odstranjevanje neuporabljenih veder
+func TestRateLimiter(t *testing.T) {
+	rl := &memory.RateLimiter{Store: memory.NewStore()}
+	limit := biolog.RateLimit{Burst: 2, Period: time.Minute}
+
+	for _, allowed := range []bool{true, true, false} {
+		res, err := rl.Take("login:ip:192.0.2.1", limit)
+		assert.NoError(t, err)
+		assert.Equal(t, allowed, res.Allowed)
+	}
+	res, _ := rl.Take("login:ip:192.0.2.2", limit)
+	assert.True(t, res.Allowed)
+
+	n, err := rl.Prune(time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	n, _ = rl.Prune(time.Now().Add(time.Second))
+	assert.Equal(t, 2, n)
+	res, _ = rl.Take("login:ip:192.0.2.1", limit)
+	assert.Equal(t, 1, res.Remaining)
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// RegionService predstavlja implementacijo biolog.RegionService v pomnilniku
+type RegionService struct {
+	Store *Store
+}
+
+// Preveri ali RegionService implementira vse metode
+var _ biolog.RegionService = &RegionService{}
+
+// multiPolygon so poligoni obmocja, vsak poligon je seznam obrocev (prvi je zunanji, ostali so luknje)
+type multiPolygon [][][][2]float64
+
+// Region vrne obmocje z dolocenim ID skupaj z geometrijo
+func (s *RegionService) Region(id int) (*biolog.Region, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	r, ok := s.Store.regions[id]
+	if !ok {
+		return nil, errors.New("Obmocje s tem ID ne obstaja")
+	}
+	region := *clone(r).(*biolog.Region)
+	region.Geometry = append(json.RawMessage(nil), r.Geometry...)
+	return &region, nil
+}
+
+// Regions vrne vsa obmocja podane vrste (ali vseh vrst, ce je kind prazen) brez geometrije
+func (s *RegionService) Regions(kind string) ([]biolog.Region, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	regions := []biolog.Region{}
+	for _, r := range s.Store.regions {
+		if kind == "" || *r.Kind == kind {
+			region := *clone(r).(*biolog.Region)
+			region.Geometry = nil
+			regions = append(regions, region)
+		}
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		if *regions[i].Kind != *regions[j].Kind {
+			return *regions[i].Kind < *regions[j].Kind
+		}
+		return *regions[i].Name < *regions[j].Name
+	})
+	return regions, nil
+}
+
+// ImportRegions shrani obmocja, obstojeca z enako vrsto in kodo posodobi. Opazanja se obmocjem
+// dodelijo ob filtriranju, zato jih ni treba ponovno dodeljevati
+func (s *RegionService) ImportRegions(regions []biolog.Region) (int, error) {
+	// Najprej preveri vsa obmocja, da se ob napaki ne shrani le del
+	shapes := make([]multiPolygon, len(regions))
+	for i, r := range regions {
+		if r.Kind == nil || r.Code == nil || r.Name == nil || len(r.Geometry) == 0 {
+			return 0, errors.New("Obmocje mora imeti vrsto, kodo, ime in geometrijo")
+		}
+		shape, err := parseMultiPolygon(r.Geometry)
+		if err != nil {
+			return 0, err
+		}
+		shapes[i] = shape
+	}
+
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for i, r := range regions {
+		id := 0
+		for existingID, existing := range s.Store.regions {
+			if *existing.Kind == *r.Kind && *existing.Code == *r.Code {
+				id = existingID
+			}
+		}
+		if id == 0 {
+			id = s.Store.nextRegionID
+			s.Store.nextRegionID++
+		}
+
+		region := clone(&r).(*biolog.Region)
+		region.ID = intPtr(id)
+		region.Geometry = append(json.RawMessage(nil), r.Geometry...)
+		s.Store.regions[id] = region
+		s.Store.regionShapes[id] = shapes[i]
+	}
+	return len(regions), nil
+}
+
+// parseMultiPolygon prebere GeoJSON geometrijo tipa Polygon ali MultiPolygon
+func parseMultiPolygon(geometry json.RawMessage) (multiPolygon, error) {
+	var g struct {
+		Type        string
+		Coordinates json.RawMessage
+	}
+	if err := json.Unmarshal(geometry, &g); err != nil {
+		return nil, err
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var p [][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return nil, err
+		}
+		return multiPolygon{p}, nil
+	case "MultiPolygon":
+		var mp multiPolygon
+		if err := json.Unmarshal(g.Coordinates, &mp); err != nil {
+			return nil, err
+		}
+		return mp, nil
+	}
+	return nil, errors.New("Geometrija obmocja mora biti Polygon ali MultiPolygon")
+}
+
+// inRegion preveri ali tocka lezi v obmocju, klicatelj mora drzati kljucavnico
+func (s *Store) inRegion(lon, lat float64, regionID int) bool {
+	shape, ok := s.regionShapes[regionID]
+	return ok && shape.contains(lon, lat)
+}
+
+// contains preveri ali tocka lezi znotraj katerega izmed poligonov in ne v njegovi luknji
+func (shape multiPolygon) contains(lon, lat float64) bool {
+	for _, polygon := range shape {
+		if len(polygon) == 0 || !inRing(lon, lat, polygon[0]) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if inRing(lon, lat, hole) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// inRing preveri ali tocka lezi znotraj obroca (metoda ray casting)
+func inRing(x, y float64, ring [][2]float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi, xj, yj := ring[i][0], ring[i][1], ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+// regionsOf vrne ID vseh obmocij, v katerih lezi tocka, klicatelj mora drzati kljucavnico
+func (s *Store) regionsOf(lon, lat float64) []int {
+	ids := []int{}
+	for id := range s.regionShapes {
+		if s.inRegion(lon, lat, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,101 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRegion vrne obmocje s pravokotnim poligonom
+func testRegion(kind, code, name string, minLon, minLat, maxLon, maxLat float64) biolog.Region {
+	geometry, _ := json.Marshal(map[string]interface{}{"type": "Polygon", "coordinates": [][][2]float64{{
+		{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}, {minLon, minLat}}}})
+	return biolog.Region{Kind: &kind, Code: &code, Name: &name, Geometry: geometry}
+}
+
+// TestRegions preveri uvoz obmocij ter filter in statistiko po obmocjih
+func TestRegions(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	rs := &memory.RegionService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+
+	n, err := rs.ImportRegions([]biolog.Region{
+		testRegion("obcina", "61", "Ljubljana", 14.3, 45.9, 14.8, 46.2),
+		testRegion("obcina", "70", "Maribor", 15.5, 46.4, 15.8, 46.7),
+		testRegion("regija", "SI04", "Slovenija", 13.3, 45.4, 16.7, 46.9),
+	})
+	if !assert.NoError(t, err) || !assert.Equal(t, 3, n) {
+		return
+	}
+
+	// Ponoven uvoz z enako vrsto in kodo obmocje posodobi
+	_, err = rs.ImportRegions([]biolog.Region{testRegion("obcina", "61", "Mestna obcina Ljubljana", 14.3, 45.9, 14.8, 46.2)})
+	assert.NoError(t, err)
+	regions, _ := rs.Regions("obcina")
+	if assert.Len(t, regions, 2) {
+		assert.Equal(t, "Maribor", *regions[0].Name)
+		assert.Equal(t, "Mestna obcina Ljubljana", *regions[1].Name)
+		assert.Nil(t, regions[0].Geometry)
+	}
+	all, _ := rs.Regions("")
+	assert.Len(t, all, 3)
+
+	ljubljana := *regions[1].ID
+	region, err := rs.Region(ljubljana)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(region.Geometry), "Polygon")
+	}
+	_, err = rs.Region(100)
+	assert.Error(t, err)
+
+	// Geometrija mora biti poligon
+	bad := testRegion("obcina", "1", "Tocka", 0, 0, 1, 1)
+	bad.Geometry = json.RawMessage(`{"type":"Point","coordinates":[0,0]}`)
+	_, err = rs.ImportRegions([]biolog.Region{bad})
+	assert.Error(t, err)
+
+	now, quantity, public := time.Now(), 2, true
+	for _, loc := range []string{"POINT(14.5058 46.0569)", "POINT(15.6459 46.5547)", "POINT(15.6 46.6)"} {
+		loc := loc
+		_, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &loc,
+			Quantity: &quantity, PublicVisibility: &public, User: u.ID, Species: &testSpeciesID})
+		assert.NoError(t, err)
+	}
+
+	obs, _ := ss.Observations(biolog.ObservationFilter{Region: &ljubljana})
+	assert.Len(t, obs, 1)
+
+	stats, err := ss.ObservationStats(biolog.StatsByRegion, biolog.ObservationFilter{})
+	if assert.NoError(t, err) {
+		maribor := strconv.Itoa(*regions[0].ID)
+		assert.Equal(t, []biolog.ObservationStat{
+			{Key: strconv.Itoa(ljubljana), Observations: 1, Quantity: 2},
+			{Key: maribor, Observations: 2, Quantity: 4},
+			{Key: strconv.Itoa(*all[2].ID), Observations: 3, Quantity: 6},
+		}, stats)
+	}
+
+	// Obmocje, manjse od celice posplosene lokacije, ne izda natancne lokacije obcutljive vrste
+	_, err = rs.ImportRegions([]biolog.Region{testRegion("tocka", "1", "Tromostovje", 14.5048, 46.0559, 14.5068, 46.0579)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	small, _ := rs.Regions("tocka")
+	policy := biolog.DefaultSensitivityPolicy([]int{testSpeciesID})
+	obs, _ = ss.Observations(biolog.ObservationFilter{Region: small[0].ID})
+	assert.Len(t, obs, 1)
+	obs, _ = ss.Observations(biolog.ObservationFilter{Region: small[0].ID, Obscure: &policy})
+	assert.Len(t, obs, 0)
+	stats, _ = ss.ObservationStats(biolog.StatsByRegion, biolog.ObservationFilter{Obscure: &policy})
+	for _, s := range stats {
+		assert.NotEqual(t, strconv.Itoa(*small[0].ID), s.Key)
+	}
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// ObservationRevisions vrne vse revizije opazanja, od najstarejse naprej
+func (s *SpeciesService) ObservationRevisions(observationID int) ([]biolog.ObservationRevision, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	revisions := []biolog.ObservationRevision{}
+	for _, r := range s.Store.revisions[observationID] {
+		revisions = append(revisions, *cloneRevision(r))
+	}
+	return revisions, nil
+}
+
+// ObservationRevision vrne doloceno revizijo opazanja
+func (s *SpeciesService) ObservationRevision(observationID, revision int) (*biolog.ObservationRevision, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	r, err := s.revision(observationID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return cloneRevision(r), nil
+}
+
+// RevertObservation povrne opazanje na stanje iz revizije. Povrnejo se le polja, ki jih ureja avtor, vrsta
+// pa se zamenja s sprejeto vrsto, ce je bila medtem zdruzena. Povrnitev doda novo revizijo
+func (s *SpeciesService) RevertObservation(id, revision int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.observations[id]
+	if !ok {
+		return errors.New("Opazanje s tem ID ne obstaja")
+	}
+	r, err := s.revision(id, revision)
+	if err != nil {
+		return err
+	}
+	snap := clone(r.Snapshot).(*biolog.Observation)
+	if snap.Species != nil {
+		if sp, ok := s.Store.species[*snap.Species]; ok && sp.AcceptedKey != nil {
+			snap.Species = intPtr(*sp.AcceptedKey)
+		}
+	}
+	if err := s.checkReferences(snap); err != nil {
+		return err
+	}
+
+	before := clone(existing).(*biolog.Observation)
+	existing.SightingTime, existing.SightingLocation, existing.Quantity = snap.SightingTime, snap.SightingLocation, snap.Quantity
+	existing.PublicVisibility, existing.Species, existing.Checklist = snap.PublicVisibility, snap.Species, snap.Checklist
+	s.updateConsensus(existing)
+	s.enqueueWebhooks(biolog.WebhookObservationUpdated, existing)
+	s.saveRevision(existing, by.User)
+	s.Store.audit(by, biolog.AuditRevert, biolog.AuditObservation, id, before, existing)
+	return nil
+}
+
+// revision vrne revizijo iz Store, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) revision(observationID, revision int) (*biolog.ObservationRevision, error) {
+	revisions := s.Store.revisions[observationID]
+	if revision < 1 || revision > len(revisions) {
+		return nil, errors.New("Revizija opazanja ne obstaja")
+	}
+	return revisions[revision-1], nil
+}
+
+// saveRevision shrani kopijo stanja opazanja kot naslednjo revizijo, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) saveRevision(ob *biolog.Observation, user int) {
+	now := time.Now()
+	revisions := s.Store.revisions[*ob.ID]
+	r := &biolog.ObservationRevision{Observation: intPtr(*ob.ID), Revision: intPtr(len(revisions) + 1),
+		User: intPtr(user), Snapshot: clone(ob).(*biolog.Observation), CreatedAt: &now}
+	s.Store.revisions[*ob.ID] = append(revisions, r)
+}
+
+// saveFirstRevision shrani zacetno stanje opazanja (avtor je lastnik), ce opazanje se nima revizij
+func (s *SpeciesService) saveFirstRevision(ob *biolog.Observation) {
+	if len(s.Store.revisions[*ob.ID]) == 0 {
+		s.saveRevision(ob, *ob.User)
+	}
+}
+
+// cloneRevision naredi globoko kopijo revizije skupaj s stanjem opazanja
+func cloneRevision(r *biolog.ObservationRevision) *biolog.ObservationRevision {
+	c := clone(r).(*biolog.ObservationRevision)
+	c.Snapshot = clone(r.Snapshot).(*biolog.Observation)
+	return c
+}
@@ -0,0 +1,50 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationRevisions preveri revizije ob posodobitvah in povrnitev na starejso revizijo
+func TestObservationRevisions(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	o := createTestObservation(t, ss, *owner.ID, true)
+
+	// Opazanje brez posodobitev nima revizij
+	revisions, err := ss.ObservationRevisions(*o.ID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 0)
+
+	quantity, later := 8, 12
+	by := biolog.Actor{User: *owner.ID}
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Quantity: &quantity}, by))
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Quantity: &later}, by))
+	revisions, err = ss.ObservationRevisions(*o.ID)
+	if assert.NoError(t, err) && assert.Len(t, revisions, 3) {
+		assert.Equal(t, *o.Quantity, *revisions[0].Snapshot.Quantity)
+		assert.Equal(t, quantity, *revisions[1].Snapshot.Quantity)
+		assert.Equal(t, later, *revisions[2].Snapshot.Quantity)
+		assert.Equal(t, 3, *revisions[2].Revision)
+	}
+
+	// Spremembe vrnjene revizije ne smejo vplivati na shranjeno
+	r, err := ss.ObservationRevision(*o.ID, 2)
+	if assert.NoError(t, err) {
+		*r.Snapshot.Quantity = 99
+	}
+	assert.NoError(t, ss.RevertObservation(*o.ID, 2, by))
+	reverted, _ := ss.Observation(*o.ID)
+	assert.Equal(t, quantity, *reverted.Quantity)
+	revisions, _ = ss.ObservationRevisions(*o.ID)
+	assert.Len(t, revisions, 4)
+
+	_, err = ss.ObservationRevision(*o.ID, 5)
+	assert.Error(t, err)
+	assert.Error(t, ss.RevertObservation(*o.ID, 0, by))
+}
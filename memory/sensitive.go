@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"github.com/rubinda/biolog"
+)
+
+// conservationAcronym vrne kratico statusa ogrozenosti vrste (prazno, ce ga ni). Klicatelj mora drzati kljucavnico
+func (s *SpeciesService) conservationAcronym(species int) string {
+	sp, ok := s.Store.species[species]
+	if !ok || sp.ConservationStatus == nil {
+		return ""
+	}
+	cs, ok := s.Store.statuses[*sp.ConservationStatus]
+	if !ok {
+		return ""
+	}
+	return cs.Acronym
+}
+
+// sensitivity vrne pravilo za obcutljive vrste (privzeto, ce ni doloceno)
+func (s *SpeciesService) sensitivity() biolog.SensitivityPolicy {
+	if s.Sensitivity == nil {
+		return biolog.DefaultSensitivityPolicy(nil)
+	}
+	return *s.Sensitivity
+}
+
+// location vrne koordinate opazanja. Ce filter doloca pravilo za obcutljive vrste, so koordinate teh vrst
+// posplosene (enako kot observationLocation v paketu postgres). Klicatelj mora drzati kljucavnico
+func (s *SpeciesService) location(o *biolog.Observation, f biolog.ObservationFilter) (float64, float64, bool) {
+	if o.SightingLocation == nil {
+		return 0, 0, false
+	}
+	loc := *o.SightingLocation
+	if f.Obscure != nil && f.Obscure.Sensitive(*o.Species, s.conservationAcronym(*o.Species)) {
+		obscured, ok := biolog.ObscureLocation(loc)
+		if !ok {
+			return 0, 0, false
+		}
+		loc = obscured
+	}
+	return biolog.ParsePoint(loc)
+}
@@ -0,0 +1,395 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// SpeciesService predstavlja implementacijo od biolog.SpeciesService v pomnilniku
+type SpeciesService struct {
+	Store *Store
+	// Pravilo za obcutljive vrste, po katerem se preverijo filtri narocnin in posplosi lokacija v dogodkih.
+	// Ce je nil, velja biolog.DefaultSensitivityPolicy brez dodatnih vrst
+	Sensitivity *biolog.SensitivityPolicy
+}
+
+// Preveri ob prevajanju, da SpeciesService implementira biolog.SpeciesService
+var _ biolog.SpeciesService = &SpeciesService{}
+
+// Species vrne doloceno vrsto skupaj s trenutnimi ocenami ogrozenosti, sklicujemo se na GBIF id
+func (s *SpeciesService) Species(id int) (*biolog.Species, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	sp, ok := s.Store.species[id]
+	if !ok {
+		return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	species := clone(sp).(*biolog.Species)
+	species.Assessments = biolog.CurrentAssessments(s.assessments(id))
+	return species, nil
+}
+
+// AllSpecies vrne vse shranjene vrste skupaj s trenutnimi ocenami ogrozenosti, urejene po ID
+func (s *SpeciesService) AllSpecies() ([]biolog.Species, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	sps := []biolog.Species{}
+	for _, sp := range s.Store.species {
+		species := *clone(sp).(*biolog.Species)
+		species.Assessments = biolog.CurrentAssessments(s.assessments(*sp.ID))
+		sps = append(sps, species)
+	}
+	sort.Slice(sps, func(i, j int) bool { return *sps[i].ID < *sps[j].ID })
+	return sps, nil
+}
+
+// CreateSpecies shrani podatke o doloceni vrsti. ID je GBIF kljuc in ga mora podati klicatelj
+func (s *SpeciesService) CreateSpecies(sp *biolog.Species) (*biolog.Species, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if sp.ID == nil {
+		return nil, errors.New("Vrsta mora imeti GBIF ID")
+	}
+	if _, ok := s.Store.species[*sp.ID]; ok {
+		return nil, errors.New("Vrsta s tem GBIF ID ze obstaja")
+	}
+	if sp.ConservationStatus != nil {
+		if _, ok := s.Store.statuses[*sp.ConservationStatus]; !ok {
+			return nil, errors.New("Status ogrozenosti ne obstaja")
+		}
+	}
+	if sp.AcceptedKey != nil {
+		if _, ok := s.Store.species[*sp.AcceptedKey]; !ok {
+			return nil, errors.New("Sprejeta vrsta s tem GBIF ID ne obstaja")
+		}
+	}
+
+	newSp := clone(sp).(*biolog.Species)
+	newSp.Assessments = nil
+	s.Store.species[*newSp.ID] = newSp
+	return clone(newSp).(*biolog.Species), nil
+}
+
+// UpdateSpecies posodobi vrsto s podanim ID glede na nove (non-nil) podatke podane v sp
+func (s *SpeciesService) UpdateSpecies(gbifKey int, sp biolog.Species, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.species[gbifKey]
+	if !ok {
+		return errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	if sp.ConservationStatus != nil {
+		if _, ok := s.Store.statuses[*sp.ConservationStatus]; !ok {
+			return errors.New("Status ogrozenosti ne obstaja")
+		}
+	}
+	if sp.AcceptedKey != nil {
+		if _, ok := s.Store.species[*sp.AcceptedKey]; !ok {
+			return errors.New("Sprejeta vrsta s tem GBIF ID ne obstaja")
+		}
+	}
+	before := clone(existing)
+	mergeNonNil(existing, &sp)
+	existing.Assessments = nil
+	s.Store.audit(by, biolog.AuditUpdate, biolog.AuditSpecies, gbifKey, before, existing)
+	return nil
+}
+
+// DeleteSpecies zbrise doloceno vrsto (ce ni navedena v nobenem izmed opazovanj)
+func (s *SpeciesService) DeleteSpecies(gbifKey int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	for _, observations := range []map[int]*biolog.Observation{s.Store.observations, s.Store.deletedObservations} {
+		for _, o := range observations {
+			if o.Species != nil && *o.Species == gbifKey {
+				return errors.New("Vrsta je navedena v opazovanjih")
+			}
+		}
+	}
+	for _, sp := range s.Store.species {
+		if sp.AcceptedKey != nil && *sp.AcceptedKey == gbifKey {
+			return errors.New("Vrsta ima sinonime")
+		}
+	}
+	if sp, ok := s.Store.species[gbifKey]; ok {
+		s.Store.audit(by, biolog.AuditDelete, biolog.AuditSpecies, gbifKey, sp, nil)
+	}
+	delete(s.Store.species, gbifKey)
+	for id, a := range s.Store.assessments {
+		if *a.Species == gbifKey {
+			delete(s.Store.assessments, id)
+		}
+	}
+	return nil
+}
+
+// Observation vrne zapis z dolocenim ID
+func (s *SpeciesService) Observation(id int) (*biolog.Observation, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	o, ok := s.Store.observations[id]
+	if !ok {
+		return nil, errors.New("Opazanje s tem ID ne obstaja")
+	}
+	return clone(o).(*biolog.Observation), nil
+}
+
+// Observations vrne vsa javna opazanja. Opazanje je javno, ce ima nastavljeno PublicVisibility
+// in ce uporabnik, ki ga je ustvaril, dovoli javen dostop do svojih opazanj (PublicObservations)
+func (s *SpeciesService) Observations(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	obs := []biolog.Observation{}
+	for _, o := range s.Store.observations {
+		if s.isPublic(o) && s.matchesFilter(o, f) {
+			obs = append(obs, *clone(o).(*biolog.Observation))
+		}
+	}
+	sort.Slice(obs, func(i, j int) bool { return *obs[i].ID < *obs[j].ID })
+	if f.Limit > 0 && len(obs) > f.Limit {
+		obs = obs[:f.Limit]
+	}
+	return obs, nil
+}
+
+// matchesFilter preveri ali opazanje ustreza vsem pogojem iz filtra, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) matchesFilter(o *biolog.Observation, f biolog.ObservationFilter) bool {
+	if f.QualityGrade != nil && (o.QualityGrade == nil || *o.QualityGrade != *f.QualityGrade) {
+		return false
+	}
+	if f.Species != nil && (o.Species == nil || *o.Species != *f.Species) {
+		return false
+	}
+	if f.Region != nil {
+		// Obmocje se preveri na lokaciji, ki jo uporabnik vidi (glej ObservationFilter.Obscure)
+		lon, lat, ok := s.location(o, f)
+		if !ok || !s.Store.inRegion(lon, lat, *f.Region) {
+			return false
+		}
+	}
+	if f.Project != nil && !s.inProject(o, *f.Project, f) {
+		return false
+	}
+	if f.AfterID != nil && *o.ID <= *f.AfterID {
+		return false
+	}
+	if f.CreatedSince != nil && (o.CreatedAt == nil || o.CreatedAt.Before(*f.CreatedSince)) {
+		return false
+	}
+	if len(f.BBox) == 4 {
+		lon, lat, ok := s.location(o, f)
+		return ok && biolog.InBBox(f.BBox, lon, lat)
+	}
+	return true
+}
+
+// isPublic preveri pravila vidnosti za opazanje (glej biolog.Observation.IsPublic), klicatelj mora drzati kljucavnico
+func (s *SpeciesService) isPublic(o *biolog.Observation) bool {
+	if o.User == nil {
+		return false
+	}
+	return o.IsPublic(s.Store.users[*o.User])
+}
+
+// CreateObservation kreira nov zapis o opazeni vrsti
+func (s *SpeciesService) CreateObservation(o *biolog.Observation) (*biolog.Observation, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if err := s.checkObservation(o); err != nil {
+		return nil, err
+	}
+	return s.createObservation(o), nil
+}
+
+// checkObservation preveri obvezna polja (NOT NULL v bazi) in reference opazanja, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) checkObservation(o *biolog.Observation) error {
+	if o.SightingTime == nil || o.SightingLocation == nil || o.Quantity == nil ||
+		o.PublicVisibility == nil || o.User == nil || o.Species == nil {
+		return errors.New("Manjkajo obvezni podatki o opazanju")
+	}
+	return s.checkReferences(o)
+}
+
+// createObservation shrani preverjeno opazanje, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) createObservation(o *biolog.Observation) *biolog.Observation {
+	ob := clone(o).(*biolog.Observation)
+	now := time.Now()
+	ob.ID = intPtr(s.Store.nextObservationID)
+	ob.CommunityTaxon, ob.QualityGrade, ob.CreatedAt = nil, nil, &now
+	s.Store.nextObservationID++
+	s.Store.observations[*ob.ID] = ob
+	s.updateConsensus(ob)
+	s.enqueueWebhooks(biolog.WebhookObservationCreated, ob)
+	if s.isPublic(ob) {
+		s.Store.observationHub.Publish(*clone(ob).(*biolog.Observation))
+	}
+
+	return clone(ob).(*biolog.Observation)
+}
+
+// checkReferences preveri ali obstajata uporabnik in vrsta, na katera se sklicuje opazanje
+// (enako kot tuja kljuca v bazi), klicatelj mora drzati kljucavnico
+func (s *SpeciesService) checkReferences(o *biolog.Observation) error {
+	if o.User != nil {
+		if _, ok := s.Store.users[*o.User]; !ok {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+	}
+	if o.Species != nil {
+		if _, ok := s.Store.species[*o.Species]; !ok {
+			return errors.New("Vrsta s tem GBIF ID ne obstaja")
+		}
+	}
+	if o.Checklist != nil {
+		if _, ok := s.Store.checklists[*o.Checklist]; !ok {
+			return errors.New("Popis s tem ID ne obstaja")
+		}
+	}
+	return nil
+}
+
+// DeleteObservation oznaci opazanje kot izbrisano (mehko brisanje), by.User je uporabnik, ki ga je izbrisal.
+// Identifikacije in komentarji ostanejo za morebitno obnovitev
+func (s *SpeciesService) DeleteObservation(id int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	ob, ok := s.Store.observations[id]
+	if !ok {
+		return errors.New("Opazanje s tem ID ne obstaja")
+	}
+	s.softDeleteObservation(ob, by)
+	return nil
+}
+
+// softDeleteObservation oznaci opazanje kot izbrisano in ga zapise v revizijsko sled, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) softDeleteObservation(ob *biolog.Observation, by biolog.Actor) {
+	s.Store.audit(by, biolog.AuditDelete, biolog.AuditObservation, *ob.ID, ob, nil)
+	now := time.Now()
+	ob.DeletedAt, ob.DeletedBy = &now, intPtr(by.User)
+	delete(s.Store.observations, *ob.ID)
+	s.Store.deletedObservations[*ob.ID] = ob
+}
+
+// DeletedObservation vrne izbrisano opazanje z dolocenim ID (npr. za preverjanje lastnika pred obnovitvijo)
+func (s *SpeciesService) DeletedObservation(id int) (*biolog.Observation, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	ob, ok := s.Store.deletedObservations[id]
+	if !ok {
+		return nil, errors.New("Izbrisano opazanje s tem ID ne obstaja")
+	}
+	return clone(ob).(*biolog.Observation), nil
+}
+
+// RestoreObservation obnovi izbrisano opazanje
+func (s *SpeciesService) RestoreObservation(id int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	ob, ok := s.Store.deletedObservations[id]
+	if !ok {
+		return errors.New("Izbrisano opazanje s tem ID ne obstaja")
+	}
+	ob.DeletedAt, ob.DeletedBy = nil, nil
+	delete(s.Store.deletedObservations, id)
+	s.Store.observations[id] = ob
+	s.Store.audit(by, biolog.AuditRestore, biolog.AuditObservation, id, nil, ob)
+	return nil
+}
+
+// PurgeObservations trajno odstrani opazanja, ki so bila izbrisana pred before (skupaj z identifikacijami in
+// komentarji). Vrne stevilo odstranjenih opazanj
+func (s *SpeciesService) PurgeObservations(before time.Time) (int64, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	var purged int64
+	for id, ob := range s.Store.deletedObservations {
+		if ob.DeletedAt.Before(before) {
+			s.deleteObservation(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// deleteObservation trajno zbrise (tudi izbrisano) opazanje in vse, kar je vezano nanj, klicatelj mora drzati kljucavnico
+func (s *SpeciesService) deleteObservation(id int) {
+	delete(s.Store.observations, id)
+	delete(s.Store.deletedObservations, id)
+	// Identifikacije, komentarji in revizije se zbrisejo skupaj z opazanjem (ON DELETE CASCADE v bazi)
+	delete(s.Store.revisions, id)
+	for iid, i := range s.Store.identifications {
+		if *i.Observation == id {
+			delete(s.Store.identifications, iid)
+		}
+	}
+	for cid, c := range s.Store.comments {
+		if *c.Observation == id {
+			delete(s.Store.comments, cid)
+		}
+	}
+}
+
+// UpdateObservation posodobi opazovalni list, ki ima enak ID
+func (s *SpeciesService) UpdateObservation(id int, ob biolog.Observation, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.observations[id]
+	if !ok {
+		return errors.New("Opazanje s tem ID ne obstaja")
+	}
+	if err := s.checkReferences(&ob); err != nil {
+		return err
+	}
+	// Izracunanih polj in casa shranjevanja ni mogoce posodabljati neposredno
+	ob.CommunityTaxon, ob.QualityGrade, ob.CreatedAt = nil, nil, nil
+	before := clone(existing).(*biolog.Observation)
+	s.saveFirstRevision(before)
+	mergeNonNil(existing, &ob)
+	s.updateConsensus(existing)
+	s.enqueueWebhooks(biolog.WebhookObservationUpdated, existing)
+	s.saveRevision(existing, by.User)
+	s.Store.audit(by, biolog.AuditUpdate, biolog.AuditObservation, id, before, existing)
+	return nil
+}
+
+// ConservationStatus vrne podatke o dolocenem statusu ogrozenosti
+func (s *SpeciesService) ConservationStatus(id int) (*biolog.ConservationStatus, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	cs, ok := s.Store.statuses[id]
+	if !ok {
+		return nil, errors.New("Status ogrozenosti ne obstaja")
+	}
+	status := *cs
+	return &status, nil
+}
+
+// ConservationStatuses vrne vsa mozna stanja ogrozenosti
+func (s *SpeciesService) ConservationStatuses() ([]biolog.ConservationStatus, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	css := []biolog.ConservationStatus{}
+	for _, cs := range s.Store.statuses {
+		css = append(css, *cs)
+	}
+	sort.Slice(css, func(i, j int) bool { return css[i].ID < css[j].ID })
+	return css, nil
+}
@@ -0,0 +1,196 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// Vrsta, na katero se sklicujejo testna opazanja
+var testSpeciesID = 5231190
+
+// createTestSpecies shrani vrsto Passer domesticus (enako kot scripts/sample-data.sql)
+func createTestSpecies(t *testing.T, ss *memory.SpeciesService) {
+	name, kingdom, family, class := "Passer domesticus", "Animalia", "Passeridae", "Aves"
+	phylum, order, genus := "Chordata", "Passeriformes", "Passer"
+	scientific, status := "Passer domesticus (Linnaeus, 1758)", 8
+	_, err := ss.CreateSpecies(&biolog.Species{ID: &testSpeciesID, Species: &name, Kingdom: &kingdom,
+		Family: &family, Class: &class, Phylum: &phylum, Order: &order, Genus: &genus,
+		ScientificName: &scientific, CanonicalName: &name, ConservationStatus: &status})
+	assert.NoError(t, err)
+}
+
+// createTestObservation ustvari opazanje za podanega uporabnika (vrsto ustvari, ce je se ni)
+func createTestObservation(t *testing.T, ss *memory.SpeciesService, user int, public bool) *biolog.Observation {
+	if _, err := ss.Species(testSpeciesID); err != nil {
+		createTestSpecies(t, ss)
+	}
+	now := time.Now()
+	loc, quantity := "46.33061, 15.48705", 3
+	o, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &loc,
+		Quantity: &quantity, PublicVisibility: &public, User: &user, Species: &testSpeciesID})
+	assert.NoError(t, err)
+	return o
+}
+
+// TestSpecies preveri kreiranje, posodabljanje in brisanje vrste
+func TestSpecies(t *testing.T) {
+	ss := &memory.SpeciesService{Store: memory.NewStore()}
+	createTestSpecies(t, ss)
+
+	// GBIF ID se ne sme ponoviti
+	_, err := ss.CreateSpecies(&biolog.Species{ID: &testSpeciesID})
+	assert.Error(t, err)
+
+	genus := "Passerus"
+	if assert.NoError(t, ss.UpdateSpecies(testSpeciesID, biolog.Species{Genus: &genus}, biolog.Actor{})) {
+		sp, _ := ss.Species(testSpeciesID)
+		assert.Equal(t, genus, *sp.Genus)
+		assert.Equal(t, "Aves", *sp.Class)
+	}
+
+	sps, _ := ss.AllSpecies()
+	assert.Len(t, sps, 1)
+
+	assert.NoError(t, ss.DeleteSpecies(testSpeciesID, biolog.Actor{}))
+	_, err = ss.Species(testSpeciesID)
+	assert.Error(t, err)
+}
+
+// TestObservations preveri pravila vidnosti: opazanje je javno le, ce je PublicVisibility
+// nastavljen in ce uporabnik dovoli javna opazanja
+func TestObservations(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+
+	public, _ := us.CreateUser(newTestUser(1))
+	private, _ := us.CreateUser(newTestUser(2))
+	no := false
+	us.UpdateUser(*private.ID, biolog.User{PublicObservations: &no}, biolog.Actor{})
+
+	visible := createTestObservation(t, ss, *public.ID, true)
+	createTestObservation(t, ss, *public.ID, false)
+	createTestObservation(t, ss, *private.ID, true)
+
+	obs, err := ss.Observations(biolog.ObservationFilter{})
+	if assert.NoError(t, err) && assert.Len(t, obs, 1) {
+		assert.Equal(t, *visible, obs[0])
+	}
+
+	// Sklic na neobstojecega uporabnika
+	createErr := func() error {
+		_, err := ss.CreateObservation(&biolog.Observation{})
+		return err
+	}()
+	assert.Error(t, createErr)
+
+	// Vrste z opazanji ni mogoce zbrisati
+	assert.Error(t, ss.DeleteSpecies(testSpeciesID, biolog.Actor{}))
+}
+
+// TestUpdateObservation preveri posodabljanje in brisanje opazanja
+func TestUpdateObservation(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	o := createTestObservation(t, ss, *u.ID, true)
+
+	quantity := 12
+	if assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Quantity: &quantity}, biolog.Actor{})) {
+		updated, _ := ss.Observation(*o.ID)
+		assert.Equal(t, quantity, *updated.Quantity)
+		assert.Equal(t, *o.SightingLocation, *updated.SightingLocation)
+	}
+
+	bad := 99999999
+	assert.Error(t, ss.UpdateObservation(*o.ID, biolog.Observation{User: &bad}, biolog.Actor{}))
+
+	assert.NoError(t, ss.DeleteObservation(*o.ID, biolog.Actor{User: *u.ID}))
+	_, err := ss.Observation(*o.ID)
+	assert.Error(t, err)
+	assert.Error(t, ss.DeleteObservation(*o.ID, biolog.Actor{User: *u.ID}))
+	obs, _ := ss.Observations(biolog.ObservationFilter{})
+	assert.Len(t, obs, 0)
+
+	// Izbrisano opazanje je mogoce obnoviti, dokler ni trajno odstranjeno
+	deleted, err := ss.DeletedObservation(*o.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *u.ID, *deleted.DeletedBy)
+	}
+	assert.NoError(t, ss.RestoreObservation(*o.ID, biolog.Actor{}))
+	restored, err := ss.Observation(*o.ID)
+	if assert.NoError(t, err) {
+		assert.Nil(t, restored.DeletedAt)
+	}
+	assert.Error(t, ss.RestoreObservation(*o.ID, biolog.Actor{}))
+
+	assert.NoError(t, ss.DeleteObservation(*o.ID, biolog.Actor{User: *u.ID}))
+	purged, err := ss.PurgeObservations(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+	purged, err = ss.PurgeObservations(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Error(t, ss.RestoreObservation(*o.ID, biolog.Actor{}))
+}
+
+// TestConservationStatuses preveri vnaprej dolocene statuse ogrozenosti
+func TestConservationStatuses(t *testing.T) {
+	ss := &memory.SpeciesService{Store: memory.NewStore()}
+	css, err := ss.ConservationStatuses()
+	if assert.NoError(t, err) {
+		assert.Len(t, css, 10)
+	}
+	cs, err := ss.ConservationStatus(5)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "VU", cs.Acronym)
+	}
+}
+
+// TestMergeSpecies preveri preusmeritev opazanj in identifikacij ter oznacitev sinonima
+func TestMergeSpecies(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	o := createTestObservation(t, ss, *u.ID, true)
+
+	// Stara vrsta s sinonimom, ki se zdruzi v testno vrsto
+	oldKey, synonymKey, name := 5231191, 5231192, "Passer domesticus"
+	_, err := ss.CreateSpecies(&biolog.Species{ID: &oldKey, Species: &name})
+	assert.NoError(t, err)
+	_, err = ss.CreateSpecies(&biolog.Species{ID: &synonymKey, Species: &name, AcceptedKey: &oldKey})
+	assert.NoError(t, err)
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Species: &oldKey}, biolog.Actor{}))
+	u2, _ := us.CreateUser(newTestUser(2))
+	_, err = ss.CreateIdentification(&biolog.Identification{Observation: o.ID, User: u2.ID, Species: &oldKey})
+	assert.NoError(t, err)
+	got, _ := ss.Observation(*o.ID)
+	assert.Equal(t, oldKey, *got.CommunityTaxon)
+
+	merge, err := ss.MergeSpecies(oldKey, testSpeciesID, biolog.Actor{User: *u.ID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, *merge.Observations)
+	assert.Equal(t, 1, *merge.Identifications)
+
+	got, _ = ss.Observation(*o.ID)
+	assert.Equal(t, testSpeciesID, *got.Species)
+	assert.Equal(t, testSpeciesID, *got.CommunityTaxon)
+	for _, key := range []int{oldKey, synonymKey} {
+		sp, _ := ss.Species(key)
+		assert.Equal(t, testSpeciesID, *sp.AcceptedKey)
+	}
+
+	// Ciljna vrsta ne sme biti sinonim
+	_, err = ss.MergeSpecies(testSpeciesID, oldKey, biolog.Actor{User: *u.ID})
+	assert.Error(t, err)
+	_, err = ss.MergeSpecies(testSpeciesID, testSpeciesID, biolog.Actor{User: *u.ID})
+	assert.Error(t, err)
+}
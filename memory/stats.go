@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/rubinda/biolog"
+)
+
+// ObservationStats presteje javna opazanja in sesteje kolicino osebkov po skupinah, urejenih po kljucu
+func (s *SpeciesService) ObservationStats(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error) {
+	if !biolog.ValidStatsGroup(groupBy) {
+		return nil, errors.New("Neveljaven nacin zdruzevanja")
+	}
+
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	groups := make(map[string]*biolog.ObservationStat)
+	for _, o := range s.Store.observations {
+		if !s.isPublic(o) || !s.matchesFilter(o, f) {
+			continue
+		}
+		for _, key := range s.statsKeys(groupBy, o, f) {
+			g, ok := groups[key]
+			if !ok {
+				g = &biolog.ObservationStat{Key: key}
+				groups[key] = g
+			}
+			g.Observations++
+			if o.Quantity != nil {
+				g.Quantity += *o.Quantity
+			}
+		}
+	}
+
+	stats := []biolog.ObservationStat{}
+	for _, g := range groups {
+		stats = append(stats, *g)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats, nil
+}
+
+// statsKeys vrne kljuce skupin za opazanje (enako kot statsKeys v paketu postgres). Opazanje spada
+// v vec skupin le pri zdruzevanju po obmocjih, ki se dolocijo na lokaciji, ki jo uporabnik vidi
+func (s *SpeciesService) statsKeys(groupBy string, o *biolog.Observation, f biolog.ObservationFilter) []string {
+	if groupBy == biolog.StatsByRegion {
+		keys := []string{}
+		lon, lat, ok := s.location(o, f)
+		if !ok {
+			return keys
+		}
+		for _, id := range s.Store.regionsOf(lon, lat) {
+			keys = append(keys, strconv.Itoa(id))
+		}
+		return keys
+	}
+	if key, ok := s.statsKey(groupBy, o); ok {
+		return []string{key}
+	}
+	return nil
+}
+
+// statsKey vrne kljuc skupine za opazanje. Opazanja brez potrebnih podatkov se ne stejejo
+// (kot pri JOIN v bazi). Klicatelj mora drzati kljucavnico
+func (s *SpeciesService) statsKey(groupBy string, o *biolog.Observation) (string, bool) {
+	switch groupBy {
+	case biolog.StatsBySpecies:
+		return strconv.Itoa(*o.Species), true
+	case biolog.StatsByMonth, biolog.StatsByYear:
+		if o.SightingTime == nil {
+			return "", false
+		}
+		if groupBy == biolog.StatsByMonth {
+			return o.SightingTime.Format("2006-01"), true
+		}
+		return o.SightingTime.Format("2006"), true
+	case biolog.StatsByUser:
+		return strconv.Itoa(*o.User), true
+	case biolog.StatsByConservationStatus:
+		acronym := s.conservationAcronym(*o.Species)
+		return acronym, acronym != ""
+	}
+	return "", false
+}
@@ -0,0 +1,43 @@
+package memory_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationStats preveri stetje javnih opazanj po skupinah
+func TestObservationStats(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	createTestObservation(t, ss, *u.ID, true)
+	createTestObservation(t, ss, *u.ID, true)
+	// Zasebno opazanje se ne steje
+	createTestObservation(t, ss, *u.ID, false)
+
+	stats, err := ss.ObservationStats(biolog.StatsBySpecies, biolog.ObservationFilter{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []biolog.ObservationStat{{Key: strconv.Itoa(testSpeciesID), Observations: 2, Quantity: 6}}, stats)
+	}
+
+	stats, _ = ss.ObservationStats(biolog.StatsByYear, biolog.ObservationFilter{})
+	assert.Equal(t, []biolog.ObservationStat{{Key: time.Now().Format("2006"), Observations: 2, Quantity: 6}}, stats)
+
+	// Testna vrsta ima status 8 (LC)
+	stats, _ = ss.ObservationStats(biolog.StatsByConservationStatus, biolog.ObservationFilter{})
+	assert.Equal(t, []biolog.ObservationStat{{Key: "LC", Observations: 2, Quantity: 6}}, stats)
+
+	// Filtri so enaki kot pri seznamu opazanj
+	research := biolog.GradeResearch
+	stats, _ = ss.ObservationStats(biolog.StatsByUser, biolog.ObservationFilter{QualityGrade: &research})
+	assert.Len(t, stats, 0)
+
+	_, err = ss.ObservationStats("day", biolog.ObservationFilter{})
+	assert.Error(t, err)
+}
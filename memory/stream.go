@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"github.com/rubinda/biolog"
+)
+
+// ObservationStream predstavlja implementacijo biolog.ObservationStream v pomnilniku. Nova javna opazanja
+// razposlje SpeciesService ob kreiranju, zato tok vidi le opazanja te instance streznika
+type ObservationStream struct {
+	Store *Store
+}
+
+// Preveri ali ObservationStream implementira vse metode
+var _ biolog.ObservationStream = &ObservationStream{}
+
+// Subscribe vrne kanal z novimi javnimi opazanji in funkcijo za odjavo
+func (s *ObservationStream) Subscribe() (<-chan biolog.Observation, func()) {
+	return s.Store.observationHub.Subscribe()
+}
@@ -0,0 +1,74 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationStream preveri, da tok dobi le nova javna opazanja, ter filtre za nadaljevanje toka
+func TestObservationStream(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	stream := &memory.ObservationStream{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+	observations, unsubscribe := stream.Subscribe()
+	defer unsubscribe()
+
+	now, quantity, public, private := time.Now(), 1, true, false
+	ljubljana, rome := "POINT(14.5058 46.0569)", "POINT(12.4964 41.9028)"
+	_, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &ljubljana, Quantity: &quantity,
+		PublicVisibility: &private, User: owner.ID, Species: &testSpeciesID})
+	assert.NoError(t, err)
+	first, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &ljubljana, Quantity: &quantity,
+		PublicVisibility: &public, User: owner.ID, Species: &testSpeciesID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	second, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &rome, Quantity: &quantity,
+		PublicVisibility: &public, User: owner.ID, Species: &testSpeciesID})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Zasebno opazanje se ne razposlje
+	if o := <-observations; assert.NotNil(t, o.ID) {
+		assert.Equal(t, *first.ID, *o.ID)
+	}
+	if o := <-observations; assert.NotNil(t, o.ID) {
+		assert.Equal(t, *second.ID, *o.ID)
+	}
+	select {
+	case o := <-observations:
+		t.Errorf("Nepricakovano opazanje %d", *o.ID)
+	default:
+	}
+
+	obs, _ := ss.Observations(biolog.ObservationFilter{AfterID: first.ID})
+	if assert.Len(t, obs, 1) {
+		assert.Equal(t, *second.ID, *obs[0].ID)
+	}
+	obs, _ = ss.Observations(biolog.ObservationFilter{BBox: []float64{13.3, 45.4, 16.6, 46.9}})
+	if assert.Len(t, obs, 1) {
+		assert.Equal(t, *first.ID, *obs[0].ID)
+	}
+	// Obcutljiva vrsta je v pravokotniku le, ce je v njem posplosena lokacija
+	policy := biolog.DefaultSensitivityPolicy([]int{testSpeciesID})
+	obs, _ = ss.Observations(biolog.ObservationFilter{BBox: []float64{14.5048, 46.0559, 14.5068, 46.0579}, Obscure: &policy})
+	assert.Len(t, obs, 0)
+
+	// Z omejitvijo se vrnejo opazanja z najmanjsim ID, urejena narascajoce
+	since := now.Add(-time.Minute)
+	obs, _ = ss.Observations(biolog.ObservationFilter{CreatedSince: &since, Limit: 1})
+	if assert.Len(t, obs, 1) {
+		assert.Equal(t, *first.ID, *obs[0].ID)
+	}
+	since = time.Now().Add(time.Minute)
+	obs, _ = ss.Observations(biolog.ObservationFilter{CreatedSince: &since})
+	assert.Len(t, obs, 0)
+}
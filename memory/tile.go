@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/rubinda/biolog"
+)
+
+// ObservationTile vrne vektorsko ploscico (Mapbox Vector Tile 2.1) z javnimi opazanji. Sloj in atributi so
+// enaki kot pri postgres.SpeciesService.ObservationTile, ploscica se zakodira rocno (le tocke)
+func (s *SpeciesService) ObservationTile(z, x, y int, f biolog.ObservationFilter) ([]byte, error) {
+	if !biolog.ValidTile(z, x, y) {
+		return nil, errors.New("Ploscica ne obstaja")
+	}
+	minX, minY, maxX, maxY := biolog.TileBounds(z, x, y)
+
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	obs := []*biolog.Observation{}
+	for _, o := range s.Store.observations {
+		if s.isPublic(o) && s.matchesFilter(o, f) && o.SightingLocation != nil {
+			obs = append(obs, o)
+		}
+	}
+	sort.Slice(obs, func(i, j int) bool { return *obs[i].ID < *obs[j].ID })
+
+	layer := newMVTLayer(biolog.TileLayer)
+	for _, o := range obs {
+		lon, lat, ok := s.location(o, f)
+		if !ok {
+			continue
+		}
+		mx, my := biolog.WebMercator(lon, lat)
+		if mx < minX || mx >= maxX || my <= minY || my > maxY {
+			continue
+		}
+		// Koordinate ploscice, os y gre od zgoraj navzdol
+		px := int(math.Floor((mx - minX) / (maxX - minX) * biolog.TileExtent))
+		py := int(math.Floor((maxY - my) / (maxY - minY) * biolog.TileExtent))
+
+		attrs := map[string]interface{}{"species": int64(*o.Species)}
+		if acronym := s.conservationAcronym(*o.Species); acronym != "" {
+			attrs["conservation_status"] = acronym
+		}
+		if o.SightingTime != nil {
+			attrs["sighting_date"] = o.SightingTime.Format("2006-01-02")
+		}
+		layer.addPoint(uint64(*o.ID), px, py, attrs)
+	}
+
+	if len(layer.features) == 0 {
+		return []byte{}, nil
+	}
+	return layer.encode(), nil
+}
+
+// mvtLayer je sloj vektorske ploscice, kljuci in vrednosti atributov se ne ponavljajo
+type mvtLayer struct {
+	name     string
+	keys     []string
+	keyIndex map[string]int
+	values   []interface{}
+	valIndex map[interface{}]int
+	features [][]byte
+}
+
+// newMVTLayer ustvari prazen sloj s podanim imenom
+func newMVTLayer(name string) *mvtLayer {
+	return &mvtLayer{name: name, keyIndex: make(map[string]int), valIndex: make(map[interface{}]int)}
+}
+
+// addPoint doda tocko s koordinatami ploscice in atributi (vrednosti so string ali int64)
+func (l *mvtLayer) addPoint(id uint64, x, y int, attrs map[string]interface{}) {
+	names := make([]string, 0, len(attrs))
+	for k := range attrs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var tags []byte
+	for _, k := range names {
+		ki, ok := l.keyIndex[k]
+		if !ok {
+			ki = len(l.keys)
+			l.keyIndex[k] = ki
+			l.keys = append(l.keys, k)
+		}
+		vi, ok := l.valIndex[attrs[k]]
+		if !ok {
+			vi = len(l.values)
+			l.valIndex[attrs[k]] = vi
+			l.values = append(l.values, attrs[k])
+		}
+		tags = appendVarint(appendVarint(tags, uint64(ki)), uint64(vi))
+	}
+
+	// Ukaz MoveTo (1) z enim parom koordinat, zamik od (0, 0)
+	geometry := appendVarint(nil, 1|1<<3)
+	geometry = appendVarint(geometry, zigzag(x))
+	geometry = appendVarint(geometry, zigzag(y))
+
+	var feature []byte
+	feature = appendVarint(appendVarint(feature, 1<<3|0), id) // id
+	feature = appendBytes(feature, 2, tags)                   // tags
+	feature = appendVarint(appendVarint(feature, 3<<3|0), 1)  // type = POINT
+	feature = appendBytes(feature, 4, geometry)               // geometry
+	l.features = append(l.features, feature)
+}
+
+// encode zakodira ploscico z enim slojem
+func (l *mvtLayer) encode() []byte {
+	var layer []byte
+	layer = appendVarint(appendVarint(layer, 15<<3|0), 2) // version
+	layer = appendBytes(layer, 1, []byte(l.name))
+	for _, f := range l.features {
+		layer = appendBytes(layer, 2, f)
+	}
+	for _, k := range l.keys {
+		layer = appendBytes(layer, 3, []byte(k))
+	}
+	for _, v := range l.values {
+		var value []byte
+		switch v := v.(type) {
+		case string:
+			value = appendBytes(value, 1, []byte(v))
+		case int64:
+			value = appendVarint(appendVarint(value, 4<<3|0), uint64(v))
+		}
+		layer = appendBytes(layer, 4, value)
+	}
+	layer = appendVarint(appendVarint(layer, 5<<3|0), biolog.TileExtent) // extent
+
+	return appendBytes(nil, 3, layer)
+}
+
+// appendVarint doda stevilo v protobuf varint zapisu
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// appendBytes doda protobuf polje z dolzino (wire type 2)
+func appendBytes(b []byte, field int, data []byte) []byte {
+	b = appendVarint(b, uint64(field<<3|2))
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// zigzag zakodira predznaceno koordinato kot pri MVT geometriji
+func zigzag(n int) uint64 {
+	return uint64(uint32((int32(n) << 1) ^ (int32(n) >> 31)))
+}
@@ -0,0 +1,39 @@
+package memory_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationTile preveri, da ploscica vsebuje javna opazanja z atributi in da so ploscice drugje prazne
+func TestObservationTile(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	u, _ := us.CreateUser(newTestUser(1))
+	o := createTestObservation(t, ss, *u.ID, true)
+	loc := "POINT(14.5058 46.0569)"
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{SightingLocation: &loc}, biolog.Actor{}))
+
+	// Ploscica na povecavi 0 pokrije cel svet
+	tile, err := ss.ObservationTile(0, 0, 0, biolog.ObservationFilter{})
+	if assert.NoError(t, err) {
+		for _, s := range []string{biolog.TileLayer, "species", "conservation_status", "LC", "sighting_date",
+			o.SightingTime.Format("2006-01-02")} {
+			assert.True(t, bytes.Contains(tile, []byte(s)), s)
+		}
+	}
+
+	// Ljubljana je na povecavi 1 v ploscici 1/1/0, na zahodu ploscica ostane prazna
+	tile, _ = ss.ObservationTile(1, 1, 0, biolog.ObservationFilter{})
+	assert.NotEmpty(t, tile)
+	tile, _ = ss.ObservationTile(1, 0, 0, biolog.ObservationFilter{})
+	assert.Empty(t, tile)
+
+	_, err = ss.ObservationTile(1, 2, 0, biolog.ObservationFilter{})
+	assert.Error(t, err)
+}
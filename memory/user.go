@@ -0,0 +1,260 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// UserService predstavlja implementacijo od biolog.UserService v pomnilniku
+type UserService struct {
+	Store *Store
+}
+
+// Preveri ob prevajanju, da UserService implementira biolog.UserService
+var _ biolog.UserService = &UserService{}
+
+// User vrne uporabnika, ki pripada podanemu ID
+func (s *UserService) User(id int) (*biolog.User, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	u, ok := s.Store.users[id]
+	if !ok {
+		return nil, errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	return clone(u).(*biolog.User), nil
+}
+
+// Users vrne vse uporabnike, ki niso trajno izbrisani, urejene po ID
+func (s *UserService) Users() ([]biolog.User, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	us := []biolog.User{}
+	for _, u := range s.Store.users {
+		if u.ErasedAt == nil {
+			us = append(us, *clone(u).(*biolog.User))
+		}
+	}
+	sort.Slice(us, func(i, j int) bool { return *us[i].ID < *us[j].ID })
+	return us, nil
+}
+
+// UserByEmail vrne uporabnika, ki ima enak email
+func (s *UserService) UserByEmail(email string) (*biolog.User, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	for _, u := range s.Store.users {
+		if u.Email != nil && *u.Email == email {
+			return clone(u).(*biolog.User), nil
+		}
+	}
+	for _, u := range s.Store.deletedUsers {
+		if u.Email != nil && *u.Email == email {
+			return nil, errors.New(biolog.DeletedAccountError)
+		}
+	}
+	// Handler pri prijavi preverja prav to sporocilo (enako kot postgres.UserService)
+	return nil, errors.New("Not found")
+}
+
+// CreateUser ustvari novega uporabnika in mu dodeli naslednji prosti 8 mestni ID
+func (s *UserService) CreateUser(u biolog.User) (*biolog.User, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	// Preveri obvezna polja (NOT NULL v bazi)
+	if u.ExternalID == nil || u.GivenName == nil || u.FamilyName == nil || u.Email == nil || u.ExternalAuthProvider == nil {
+		return nil, errors.New("Manjkajo obvezni podatki o uporabniku")
+	}
+	if _, ok := s.Store.authProviders[*u.ExternalAuthProvider]; !ok {
+		return nil, errors.New("Ponudnik avtentikacije ne obstaja")
+	}
+	// Email je unikaten tudi med izbrisanimi uporabniki (UNIQUE v bazi)
+	for _, users := range []map[int]*biolog.User{s.Store.users, s.Store.deletedUsers} {
+		for _, existing := range users {
+			if existing.Email != nil && *existing.Email == *u.Email {
+				return nil, errors.New("Uporabnik s tem emailom ze obstaja")
+			}
+		}
+	}
+	if s.Store.nextUserID > maxUserID {
+		return nil, errors.New("Zmanjkalo je prostih ID za uporabnike")
+	}
+
+	newUser := clone(&u).(*biolog.User)
+	newUser.ID = intPtr(s.Store.nextUserID)
+	if newUser.PublicObservations == nil {
+		newUser.PublicObservations = boolPtr(true)
+	}
+	if newUser.Role == nil {
+		role := biolog.RoleUser
+		newUser.Role = &role
+	}
+	s.Store.nextUserID++
+	s.Store.users[*newUser.ID] = newUser
+
+	return clone(newUser).(*biolog.User), nil
+}
+
+// DeleteUser oznaci uporabnika kot izbrisanega (mehko brisanje), by.User je uporabnik, ki ga je izbrisal.
+// Javi napako, ce ima uporabnik zapise o opazanjih. Clanstva v projektih ostanejo za morebitno obnovitev
+func (s *UserService) DeleteUser(id int, by biolog.Actor) (int64, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	u, ok := s.Store.users[id]
+	if !ok || u.ErasedAt != nil {
+		return 0, nil
+	}
+	for _, o := range s.Store.observations {
+		if o.User != nil && *o.User == id {
+			return -1, errors.New("Uporabnik ima zapise o opazanjih")
+		}
+	}
+	s.Store.audit(by, biolog.AuditDelete, biolog.AuditUser, id, u, nil)
+	now := time.Now()
+	u.DeletedAt, u.DeletedBy = &now, intPtr(by.User)
+	delete(s.Store.users, id)
+	s.Store.deletedUsers[id] = u
+	return 1, nil
+}
+
+// RestoreUser obnovi izbrisanega uporabnika
+func (s *UserService) RestoreUser(id int, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	u, ok := s.Store.deletedUsers[id]
+	if !ok {
+		return errors.New("Izbrisan uporabnik s tem ID ne obstaja")
+	}
+	u.DeletedAt, u.DeletedBy = nil, nil
+	delete(s.Store.deletedUsers, id)
+	s.Store.users[id] = u
+	s.Store.audit(by, biolog.AuditRestore, biolog.AuditUser, id, nil, u)
+	return nil
+}
+
+// PurgeUsers trajno odstrani uporabnike, ki so bili izbrisani pred before. Uporabniki, na katere se se
+// sklicujejo drugi zapisi, ostanejo izbrisani (enako kot tuji kljuci v bazi). Vrne stevilo odstranjenih
+func (s *UserService) PurgeUsers(before time.Time) (int64, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	var purged int64
+	for id, u := range s.Store.deletedUsers {
+		if !u.DeletedAt.Before(before) || s.userReferenced(id) {
+			continue
+		}
+		delete(s.Store.deletedUsers, id)
+		for _, members := range s.Store.projectMembers {
+			delete(members, id)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// userReferenced pove ali se na uporabnika sklicuje kak zapis, klicatelj mora drzati kljucavnico
+func (s *UserService) userReferenced(id int) bool {
+	for _, observations := range []map[int]*biolog.Observation{s.Store.observations, s.Store.deletedObservations} {
+		for _, o := range observations {
+			if *o.User == id {
+				return true
+			}
+		}
+	}
+	for _, i := range s.Store.identifications {
+		if *i.User == id {
+			return true
+		}
+	}
+	for _, c := range s.Store.comments {
+		if *c.User == id {
+			return true
+		}
+	}
+	for _, c := range s.Store.checklists {
+		if *c.User == id {
+			return true
+		}
+	}
+	for _, p := range s.Store.projects {
+		if *p.CreatedBy == id {
+			return true
+		}
+	}
+	for _, m := range s.Store.merges {
+		if *m.User == id {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateUser delno posodobi podatke o uporabniku (le polja, ki niso nil)
+func (s *UserService) UpdateUser(id int, u biolog.User, by biolog.Actor) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	existing, ok := s.Store.users[id]
+	// Trajno izbrisanemu uporabniku ni mogoce ponovno dodati osebnih podatkov
+	if !ok || existing.ErasedAt != nil {
+		return errors.New("Uporabnik s tem ID ne obstaja")
+	}
+	if u.Email != nil {
+		for otherID, other := range s.Store.users {
+			if otherID != id && other.Email != nil && *other.Email == *u.Email {
+				return errors.New("Uporabnik s tem emailom ze obstaja")
+			}
+		}
+	}
+	before := clone(existing)
+	mergeNonNil(existing, &u)
+	s.Store.audit(by, biolog.AuditUpdate, biolog.AuditUser, id, before, existing)
+	return nil
+}
+
+// UserByExtID vrne uporabnika glede na ID zunanjega avtentikatorja
+func (s *UserService) UserByExtID(id string) (*biolog.User, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	for _, u := range s.Store.users {
+		if u.ExternalID != nil && *u.ExternalID == id {
+			return clone(u).(*biolog.User), nil
+		}
+	}
+	return nil, errors.New("Uporabnika s tem ID ni mogoče najti")
+}
+
+// AuthProvider vrne podrobnosti o dolocenem ponudniku avtentikacije
+func (s *UserService) AuthProvider(id int) (*biolog.AuthProvider, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	p, ok := s.Store.authProviders[id]
+	if !ok {
+		return nil, errors.New("Ponudnik avtentikacije s tem ID ne obstaja")
+	}
+	authPro := *p
+	return &authPro, nil
+}
+
+// AuthProviders vrne vse ponudnike avtentikacije
+func (s *UserService) AuthProviders() ([]biolog.AuthProvider, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	authPros := []biolog.AuthProvider{}
+	for _, p := range s.Store.authProviders {
+		authPros = append(authPros, *p)
+	}
+	sort.Slice(authPros, func(i, j int) bool { return authPros[i].ID < authPros[j].ID })
+	return authPros, nil
+}
@@ -0,0 +1,163 @@
+package memory_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var globalOne = 1
+
+// newTestUser vrne uporabnika z vsemi obveznimi polji, email je odvisen od n
+func newTestUser(n int) biolog.User {
+	gn, fn, dn := "River", "Tam", "ms. River Tam"
+	email := fmt.Sprintf("river.tam%d@fakemail.com", n)
+	extID := fmt.Sprintf("76584922647812352039%02d", n)
+	return biolog.User{DisplayName: &dn, GivenName: &gn, FamilyName: &fn, Email: &email,
+		ExternalID: &extID, ExternalAuthProvider: &globalOne}
+}
+
+// TestCreateUser preveri dodeljevanje IDjev in privzetih vrednosti pri kreiranju uporabnikov
+func TestCreateUser(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+
+	first, err := us.CreateUser(newTestUser(1))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10000000, *first.ID)
+		assert.True(t, *first.PublicObservations)
+	}
+	second, err := us.CreateUser(newTestUser(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10000001, *second.ID)
+	}
+
+	// Email mora biti unikaten
+	_, err = us.CreateUser(newTestUser(1))
+	assert.Error(t, err)
+
+	// Manjka obvezno polje
+	_, err = us.CreateUser(biolog.User{})
+	assert.Error(t, err)
+}
+
+// TestUserLookups preveri iskanje uporabnikov po ID, emailu in zunanjem ID
+func TestUserLookups(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+	u, _ := us.CreateUser(newTestUser(1))
+
+	byID, err := us.User(*u.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, u, byID)
+	}
+	byEmail, err := us.UserByEmail(*u.Email)
+	if assert.NoError(t, err) {
+		assert.Equal(t, u, byEmail)
+	}
+	byExtID, err := us.UserByExtID(*u.ExternalID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, u, byExtID)
+	}
+
+	// Prijava preverja natanko to napako
+	_, err = us.UserByEmail("nobody@fakemail.com")
+	assert.EqualError(t, err, "Not found")
+
+	// Spreminjanje vrnjene vrednosti ne sme vplivati na shranjene podatke
+	*byID.DisplayName = "changed"
+	again, _ := us.User(*u.ID)
+	assert.Equal(t, "ms. River Tam", *again.DisplayName)
+}
+
+// TestUpdateUser preveri delno posodabljanje uporabnika
+func TestUpdateUser(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+	u, _ := us.CreateUser(newTestUser(1))
+
+	dn := "River"
+	if assert.NoError(t, us.UpdateUser(*u.ID, biolog.User{DisplayName: &dn}, biolog.Actor{})) {
+		updated, _ := us.User(*u.ID)
+		assert.Equal(t, dn, *updated.DisplayName)
+		assert.Equal(t, *u.Email, *updated.Email)
+	}
+	assert.Error(t, us.UpdateUser(99999999, biolog.User{DisplayName: &dn}, biolog.Actor{}))
+}
+
+// TestDeleteUser preveri brisanje uporabnika, ki ima ali nima opazanj
+func TestDeleteUser(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+
+	free, _ := us.CreateUser(newTestUser(1))
+	observer, _ := us.CreateUser(newTestUser(2))
+	createTestObservation(t, ss, *observer.ID, true)
+
+	deleted, err := us.DeleteUser(*free.ID, biolog.Actor{User: *free.ID})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), deleted)
+	}
+	_, err = us.User(*free.ID)
+	assert.Error(t, err)
+	// Prijava v izbrisan racun se zavrne
+	_, err = us.UserByEmail(*free.Email)
+	if assert.Error(t, err) {
+		assert.Equal(t, biolog.DeletedAccountError, err.Error())
+	}
+	_, err = us.DeleteUser(*observer.ID, biolog.Actor{User: *observer.ID})
+	assert.Error(t, err)
+	_, err = us.User(*observer.ID)
+	assert.NoError(t, err)
+
+	// Izbrisan racun je mogoce obnoviti, po obdobju hrambe pa se trajno odstrani
+	assert.NoError(t, us.RestoreUser(*free.ID, biolog.Actor{}))
+	_, err = us.User(*free.ID)
+	assert.NoError(t, err)
+	assert.Error(t, us.RestoreUser(*free.ID, biolog.Actor{}))
+	_, err = us.DeleteUser(*free.ID, biolog.Actor{User: *free.ID})
+	assert.NoError(t, err)
+	purged, err := us.PurgeUsers(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Error(t, us.RestoreUser(*free.ID, biolog.Actor{}))
+	_, err = us.CreateUser(newTestUser(1))
+	assert.NoError(t, err)
+}
+
+// TestAuthProviders preveri vnaprej dolocene ponudnike avtentikacije
+func TestAuthProviders(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+	ps, err := us.AuthProviders()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []biolog.AuthProvider{{ID: 1, Name: "Google"}}, ps)
+	}
+	_, err = us.AuthProvider(2)
+	assert.Error(t, err)
+}
+
+// TestConcurrentCreateUser preveri, da socasno kreiranje ne podeli istega ID vec uporabnikom
+func TestConcurrentCreateUser(t *testing.T) {
+	us := &memory.UserService{Store: memory.NewStore()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			_, err := us.CreateUser(newTestUser(n))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	users, _ := us.Users()
+	if assert.Len(t, users, 50) {
+		for i, u := range users {
+			assert.Equal(t, 10000000+i, *u.ID)
+		}
+	}
+}
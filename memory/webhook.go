@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// WebhookService predstavlja implementacijo biolog.WebhookService v pomnilniku
+type WebhookService struct {
+	Store *Store
+}
+
+// Preveri ali WebhookService implementira vse metode
+var _ biolog.WebhookService = &WebhookService{}
+
+// Webhook vrne narocnino z dolocenim ID (brez skrivnosti)
+func (s *WebhookService) Webhook(id int) (*biolog.Webhook, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	wh, ok := s.Store.webhooks[id]
+	if !ok {
+		return nil, errors.New("Narocnina s tem ID ne obstaja")
+	}
+	found := clone(wh).(*biolog.Webhook)
+	found.Secret = nil
+	return found, nil
+}
+
+// Webhooks vrne vse narocnine (brez skrivnosti), urejene po ID
+func (s *WebhookService) Webhooks() ([]biolog.Webhook, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	whs := []biolog.Webhook{}
+	for _, wh := range s.Store.webhooks {
+		found := *clone(wh).(*biolog.Webhook)
+		found.Secret = nil
+		whs = append(whs, found)
+	}
+	sort.Slice(whs, func(i, j int) bool { return *whs[i].ID < *whs[j].ID })
+	return whs, nil
+}
+
+// CreateWebhook shrani novo narocnino in jo vrne skupaj s skrivnostjo
+func (s *WebhookService) CreateWebhook(wh *biolog.Webhook) (*biolog.Webhook, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if wh.URL == nil || wh.Secret == nil {
+		return nil, errors.New("Manjkata naslov ali skrivnost narocnine")
+	}
+	created := clone(wh).(*biolog.Webhook)
+	now := time.Now()
+	created.ID, created.CreatedAt = intPtr(s.Store.nextWebhookID), &now
+	// Filtri se kopirajo, da jih klicatelj ne more spreminjati mimo servica
+	created.Species = append([]int{}, wh.Species...)
+	created.Regions = append([]int{}, wh.Regions...)
+	created.BBox = append([]float64{}, wh.BBox...)
+	s.Store.nextWebhookID++
+	s.Store.webhooks[*created.ID] = created
+
+	return clone(created).(*biolog.Webhook), nil
+}
+
+// DeleteWebhook izbrise narocnino skupaj z vsemi njenimi posiljanji
+func (s *WebhookService) DeleteWebhook(id int) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	if _, ok := s.Store.webhooks[id]; !ok {
+		return errors.New("Narocnina s tem ID ne obstaja")
+	}
+	delete(s.Store.webhooks, id)
+	for deliveryID, d := range s.Store.deliveries {
+		if *d.Webhook == id {
+			delete(s.Store.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// WebhookDeliveries vrne stran posiljanj narocnine, od najnovejsega naprej
+func (s *WebhookService) WebhookDeliveries(webhookID int, p biolog.Page) ([]biolog.WebhookDelivery, error) {
+	s.Store.mu.RLock()
+	defer s.Store.mu.RUnlock()
+
+	ds := []biolog.WebhookDelivery{}
+	for _, d := range s.Store.deliveries {
+		if *d.Webhook == webhookID {
+			ds = append(ds, *clone(d).(*biolog.WebhookDelivery))
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool { return *ds[i].ID > *ds[j].ID })
+	if p.Offset >= len(ds) {
+		return []biolog.WebhookDelivery{}, nil
+	}
+	ds = ds[p.Offset:]
+	if p.Limit < len(ds) {
+		ds = ds[:p.Limit]
+	}
+	return ds, nil
+}
+
+// ClaimDeliveries prevzame najvec limit posiljanj, katerih cas je potekel, in jim za lease odlozi naslednji poskus
+func (s *WebhookService) ClaimDeliveries(limit int, lease time.Duration) ([]biolog.WebhookDelivery, error) {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	now := time.Now()
+	due := []*biolog.WebhookDelivery{}
+	for _, d := range s.Store.deliveries {
+		if *d.Status == biolog.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return *due[i].ID < *due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	ds := []biolog.WebhookDelivery{}
+	for _, d := range due {
+		next := now.Add(lease)
+		d.NextAttemptAt = &next
+		claimed := *clone(d).(*biolog.WebhookDelivery)
+		wh := s.Store.webhooks[*d.Webhook]
+		claimed.URL, claimed.Secret = *wh.URL, *wh.Secret
+		ds = append(ds, claimed)
+	}
+	return ds, nil
+}
+
+// RecordAttempt zapise izid poskusa posiljanja in doloci novo stanje posiljanja
+func (s *WebhookService) RecordAttempt(id int, a biolog.WebhookAttempt) error {
+	s.Store.mu.Lock()
+	defer s.Store.mu.Unlock()
+
+	d, ok := s.Store.deliveries[id]
+	if !ok || *d.Status != biolog.DeliveryPending {
+		return errors.New("Posiljanje s tem ID ne caka na posiljanje")
+	}
+	*d.Attempts++
+	d.LastStatusCode, d.LastError = nil, nil
+	if a.StatusCode != 0 {
+		d.LastStatusCode = intPtr(a.StatusCode)
+	}
+	status := biolog.DeliveryDelivered
+	switch {
+	case a.Error == "":
+		now := time.Now()
+		d.NextAttemptAt, d.DeliveredAt = nil, &now
+	case a.NextAttemptAt == nil:
+		status = biolog.DeliveryFailed
+		d.NextAttemptAt = nil
+	default:
+		status = biolog.DeliveryPending
+		next := *a.NextAttemptAt
+		d.NextAttemptAt = &next
+	}
+	if a.Error != "" {
+		msg := a.Error
+		d.LastError = &msg
+	}
+	d.Status = &status
+	return nil
+}
+
+// enqueueWebhooks doda v vrsto dogodek za vse narocnine, katerih filtrom ustreza opazanje. Dogodki se posljejo
+// le za javna opazanja, lokacija obcutljive vrste je v telesu dogodka posplosena. Klicatelj mora drzati kljucavnico
+func (s *SpeciesService) enqueueWebhooks(event string, o *biolog.Observation) {
+	if len(s.Store.webhooks) == 0 || !s.isPublic(o) {
+		return
+	}
+	policy := s.sensitivity()
+	sent := *o
+	if policy.Sensitive(*o.Species, s.conservationAcronym(*o.Species)) {
+		sent.Obscure()
+	}
+	// Opazanje je sestavljeno iz osnovnih tipov, zato pretvorba v JSON ne more spodleteti
+	payload, _ := json.Marshal(biolog.WebhookEvent{Event: event, CreatedAt: time.Now(), Observation: &sent})
+	for _, wh := range s.Store.webhooks {
+		if !s.webhookMatches(wh, o, policy) {
+			continue
+		}
+		now, status := time.Now(), biolog.DeliveryPending
+		s.Store.deliveries[s.Store.nextDeliveryID] = &biolog.WebhookDelivery{ID: intPtr(s.Store.nextDeliveryID),
+			Webhook: intPtr(*wh.ID), Event: &event, Payload: payload, Status: &status, Attempts: intPtr(0),
+			NextAttemptAt: &now, CreatedAt: &now}
+		s.Store.nextDeliveryID++
+	}
+}
+
+// webhookMatches preveri ali opazanje ustreza vsem filtrom narocnine. Obmocja in pravokotnik se preverijo na
+// lokaciji, posploseni po pravilu policy (enako kot enqueueWebhooks v paketu postgres). Klicatelj mora drzati kljucavnico
+func (s *SpeciesService) webhookMatches(wh *biolog.Webhook, o *biolog.Observation, policy biolog.SensitivityPolicy) bool {
+	if len(wh.Species) > 0 && !matchesAny(wh.Species, func(id int) bool { return id == *o.Species }) {
+		return false
+	}
+	if len(wh.Regions) == 0 && len(wh.BBox) == 0 {
+		return true
+	}
+	lon, lat, ok := s.location(o, biolog.ObservationFilter{Obscure: &policy})
+	if !ok {
+		return false
+	}
+	if len(wh.Regions) > 0 && !matchesAny(wh.Regions, func(id int) bool { return s.Store.inRegion(lon, lat, id) }) {
+		return false
+	}
+	return len(wh.BBox) == 0 || wh.InBBox(lon, lat)
+}
+
+// matchesAny pove ali kateri izmed IDjev ustreza pogoju
+func matchesAny(ids []int, match func(id int) bool) bool {
+	for _, id := range ids {
+		if match(id) {
+			return true
+		}
+	}
+	return false
+}
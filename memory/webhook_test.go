@@ -0,0 +1,148 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookDeliveries preveri dodajanje dogodkov v vrsto glede na filtre narocnin, prevzem in zapis poskusov
+func TestWebhookDeliveries(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	ss := &memory.SpeciesService{Store: store}
+	ws := &memory.WebhookService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+
+	url, secret := "https://example.org/hook", "skrivnost"
+	bySpecies, err := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Species: []int{testSpeciesID}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, secret, *bySpecies.Secret)
+	other, _ := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Species: []int{1}})
+	inRome, _ := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, BBox: []float64{12.2, 41.6, 12.8, 42.1}})
+	found, err := ws.Webhook(*bySpecies.ID)
+	if assert.NoError(t, err) {
+		assert.Nil(t, found.Secret)
+	}
+	_, err = ws.Webhook(99)
+	assert.Error(t, err)
+
+	// Zasebna opazanja se ne posljejo, javno opazanje pa le narocninam, katerih filtrom ustreza
+	now, loc, quantity, public, private := time.Now(), "POINT(14.5058 46.0569)", 1, true, false
+	_, err = ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &loc, Quantity: &quantity,
+		PublicVisibility: &private, User: owner.ID, Species: &testSpeciesID})
+	assert.NoError(t, err)
+	o, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &loc, Quantity: &quantity,
+		PublicVisibility: &public, User: owner.ID, Species: &testSpeciesID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	page := biolog.Page{Limit: 20}
+	ds, _ := ws.WebhookDeliveries(*bySpecies.ID, page)
+	if assert.Len(t, ds, 1) {
+		assert.Equal(t, biolog.WebhookObservationCreated, *ds[0].Event)
+		assert.Equal(t, biolog.DeliveryPending, *ds[0].Status)
+		var event biolog.WebhookEvent
+		if assert.NoError(t, json.Unmarshal(ds[0].Payload, &event)) {
+			assert.Equal(t, *o.ID, *event.Observation.ID)
+		}
+	}
+	ds, _ = ws.WebhookDeliveries(*other.ID, page)
+	assert.Len(t, ds, 0)
+	ds, _ = ws.WebhookDeliveries(*inRome.ID, page)
+	assert.Len(t, ds, 0)
+
+	quantity = 4
+	assert.NoError(t, ss.UpdateObservation(*o.ID, biolog.Observation{Quantity: &quantity}, biolog.Actor{User: *owner.ID}))
+	ds, _ = ws.WebhookDeliveries(*bySpecies.ID, page)
+	if assert.Len(t, ds, 2) {
+		assert.Equal(t, biolog.WebhookObservationUpdated, *ds[0].Event)
+	}
+
+	// Prevzeta posiljanja se do izteka najema ne prevzamejo ponovno
+	claimed, err := ws.ClaimDeliveries(1, time.Minute)
+	if !assert.NoError(t, err) || !assert.Len(t, claimed, 1) {
+		return
+	}
+	assert.Equal(t, url, claimed[0].URL)
+	assert.Equal(t, secret, claimed[0].Secret)
+	rest, _ := ws.ClaimDeliveries(10, time.Minute)
+	assert.Len(t, rest, 1)
+	none, _ := ws.ClaimDeliveries(10, time.Minute)
+	assert.Len(t, none, 0)
+
+	// Neuspesen poskus z naslednjim poskusom ostane v vrsti, brez njega pa dokoncno ne uspe
+	next := time.Now().Add(-time.Second)
+	assert.NoError(t, ws.RecordAttempt(*claimed[0].ID, biolog.WebhookAttempt{StatusCode: 503, Error: "HTTP 503", NextAttemptAt: &next}))
+	retried, _ := ws.ClaimDeliveries(10, time.Minute)
+	if assert.Len(t, retried, 1) {
+		assert.Equal(t, 1, *retried[0].Attempts)
+		assert.Equal(t, 503, *retried[0].LastStatusCode)
+	}
+	assert.NoError(t, ws.RecordAttempt(*claimed[0].ID, biolog.WebhookAttempt{Error: "timeout"}))
+	assert.NoError(t, ws.RecordAttempt(*rest[0].ID, biolog.WebhookAttempt{StatusCode: 200}))
+	assert.Error(t, ws.RecordAttempt(*rest[0].ID, biolog.WebhookAttempt{StatusCode: 200}))
+	ds, _ = ws.WebhookDeliveries(*bySpecies.ID, page)
+	statuses := map[int]string{}
+	for _, d := range ds {
+		statuses[*d.ID] = *d.Status
+	}
+	assert.Equal(t, biolog.DeliveryFailed, statuses[*claimed[0].ID])
+	assert.Equal(t, biolog.DeliveryDelivered, statuses[*rest[0].ID])
+
+	assert.NoError(t, ws.DeleteWebhook(*bySpecies.ID))
+	assert.Error(t, ws.DeleteWebhook(*bySpecies.ID))
+	ds, _ = ws.WebhookDeliveries(*bySpecies.ID, page)
+	assert.Len(t, ds, 0)
+	whs, _ := ws.Webhooks()
+	assert.Len(t, whs, 2)
+}
+
+// TestWebhookSensitiveLocation preveri, da narocnina z majhnim obmocjem ali pravokotnikom ne izda natancne lokacije
+// obcutljive vrste in da je lokacija v telesu dogodka posplosena
+func TestWebhookSensitiveLocation(t *testing.T) {
+	store := memory.NewStore()
+	us := &memory.UserService{Store: store}
+	policy := biolog.DefaultSensitivityPolicy([]int{testSpeciesID})
+	ss := &memory.SpeciesService{Store: store, Sensitivity: &policy}
+	rs := &memory.RegionService{Store: store}
+	ws := &memory.WebhookService{Store: store}
+	owner, _ := us.CreateUser(newTestUser(1))
+	createTestSpecies(t, ss)
+	if _, err := rs.ImportRegions([]biolog.Region{testRegion("tocka", "1", "Tromostovje", 14.5048, 46.0559, 14.5068, 46.0579)}); !assert.NoError(t, err) {
+		return
+	}
+	small, _ := rs.Regions("tocka")
+
+	url, secret := "https://example.org/hook", "skrivnost"
+	bySpecies, _ := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Species: []int{testSpeciesID}})
+	inBBox, _ := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, BBox: []float64{14.5048, 46.0559, 14.5068, 46.0579}})
+	inRegion, _ := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Regions: []int{*small[0].ID}})
+
+	now, loc, quantity, public := time.Now(), "POINT(14.5058 46.0569)", 1, true
+	_, err := ss.CreateObservation(&biolog.Observation{SightingTime: &now, SightingLocation: &loc, Quantity: &quantity,
+		PublicVisibility: &public, User: owner.ID, Species: &testSpeciesID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	page := biolog.Page{Limit: 20}
+	ds, _ := ws.WebhookDeliveries(*bySpecies.ID, page)
+	if assert.Len(t, ds, 1) {
+		var event biolog.WebhookEvent
+		if assert.NoError(t, json.Unmarshal(ds[0].Payload, &event)) {
+			assert.True(t, *event.Observation.Obscured)
+			assert.NotEqual(t, loc, *event.Observation.SightingLocation)
+		}
+	}
+	ds, _ = ws.WebhookDeliveries(*inBBox.ID, page)
+	assert.Len(t, ds, 0)
+	ds, _ = ws.WebhookDeliveries(*inRegion.ID, page)
+	assert.Len(t, ds, 0)
+}
@@ -0,0 +1,51 @@
+package biolog
+
+import (
+	"time"
+)
+
+// TaxonMerge (zdruzitev vrst)
+//
+// Zapis o tem, da je moderator vrsto zdruzil v drugo (npr. ko GBIF vrsto prestevilci in stari kljuc
+// postane sinonim). Vsa opazanja in identifikacije stare vrste se preusmerijo na sprejeto vrsto
+//
+// swagger:model taxonMerge
+type TaxonMerge struct {
+	// Identifikator zdruzitve
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Vrsta, ki je postala sinonim (GBIF kljuc)
+	//
+	// required: true
+	// example: 5231191
+	From *int `db:"from_species" json:"from"`
+
+	// Sprejeta vrsta, v katero je bila vrsta zdruzena (GBIF kljuc)
+	//
+	// required: true
+	// example: 5231190
+	Into *int `db:"into_species" json:"into"`
+
+	// Moderator, ki je vrsti zdruzil
+	//
+	// required: true
+	// example: 10000000
+	User *int `db:"biolog_user" json:"user"`
+
+	// Stevilo preusmerjenih opazanj
+	// example: 12
+	Observations *int `json:"observations"`
+
+	// Stevilo preusmerjenih identifikacij
+	// example: 30
+	Identifications *int `json:"identifications"`
+
+	// Cas zdruzitve
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:07:37+00:00
+	MergedAt *time.Time `db:"merged_at" json:"mergedAt"`
+}
@@ -0,0 +1,133 @@
+// Package metrics vsebuje Prometheus metrike aplikacije. Metrike so v lastnem registru, ki ga
+// izpostavi locen streznik (glej NewServer), da /metrics ni dostopen skozi javni API
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry je register vseh metrik aplikacije
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests steje odgovore po metodi, vzorcu poti (chi) in statusu
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "biolog", Subsystem: "http", Name: "requests_total",
+		Help: "Stevilo HTTP zahtev po metodi, vzorcu poti in statusu odgovora.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPDuration meri trajanje zahtev po metodi in vzorcu poti
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "biolog", Subsystem: "http", Name: "request_duration_seconds",
+		Help:    "Trajanje HTTP zahtev po metodi in vzorcu poti.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// QueryDuration meri trajanje poizvedb po vrsti poizvedbe in tabeli (glej QueryName)
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "biolog", Subsystem: "db", Name: "query_duration_seconds",
+		Help:    "Trajanje poizvedb v PostgreSQL po vrsti poizvedbe in tabeli.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query"})
+
+	// QueryErrors steje neuspesne poizvedbe
+	QueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "biolog", Subsystem: "db", Name: "query_errors_total",
+		Help: "Stevilo neuspesnih poizvedb v PostgreSQL po vrsti poizvedbe in tabeli.",
+	}, []string{"query"})
+
+	// ObservationsCreated steje nova opazanja po viru (posamezno opazanje ali popis)
+	ObservationsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "biolog", Name: "observations_created_total",
+		Help: "Stevilo novih opazanj po viru (observation ali checklist).",
+	}, []string{"source"})
+
+	// Logins steje prijave po ponudniku avtentikacije in izidu (success ali failure)
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "biolog", Subsystem: "auth", Name: "logins_total",
+		Help: "Stevilo prijav po ponudniku avtentikacije in izidu.",
+	}, []string{"provider", "result"})
+
+	// JWTFailures steje zavrnjene JWT tokene po razlogu
+	JWTFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "biolog", Subsystem: "auth", Name: "jwt_failures_total",
+		Help: "Stevilo zavrnjenih JWT tokenov po razlogu.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration, QueryDuration, QueryErrors, ObservationsCreated, Logins, JWTFailures,
+	)
+}
+
+// RegisterDB doda statistiko bazena povezav do baze (odprte, zasedene in cakajoce povezave)
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveQuery zabelezi trajanje poizvedbe, ki se je zacela ob start, in morebitno napako
+func ObserveQuery(query string, start time.Time, err error) {
+	name := QueryName(query)
+	QueryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		QueryErrors.WithLabelValues(name).Inc()
+	}
+}
+
+// QueryName vrne ime poizvedbe za oznako metrike: vrsto poizvedbe in glavno tabelo, npr. "select observation".
+// Vrednosti parametrov niso del poizvedbe, zato je stevilo razlicnih oznak omejeno s stevilom tabel
+func QueryName(query string) string {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return "unknown"
+	}
+	verb, after := words[0], ""
+	switch verb {
+	case "insert":
+		after = "into"
+	case "update":
+		if len(words) > 1 {
+			return verb + " " + tableName(words[1])
+		}
+	default:
+		after = "from"
+	}
+	// Pri podpoizvedbi (FROM (SELECT ...)) se isce naprej
+	for i := 1; i < len(words)-1; i++ {
+		if table := tableName(words[i+1]); words[i] == after && table != "" {
+			return verb + " " + table
+		}
+	}
+	return verb
+}
+
+// tableName odstrani shemo in locila okoli imena tabele
+func tableName(word string) string {
+	word = strings.TrimPrefix(word, "public.")
+	if i := strings.IndexAny(word, "(),;"); i >= 0 {
+		word = word[:i]
+	}
+	return word
+}
+
+// Handler vrne handler, ki metrike izpise v obliki za Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// NewServer kreira streznik, ki na addr na poti /metrics izpostavi metrike
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return &http.Server{Addr: addr, Handler: mux}
+}
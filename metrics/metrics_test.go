@@ -0,0 +1,44 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rubinda/biolog/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQueryName preveri oznake poizvedb
+func TestQueryName(t *testing.T) {
+	cases := map[string]string{
+		`SELECT * FROM observation WHERE id = $1`:                              "select observation",
+		`INSERT INTO public.webhook (url) VALUES ($1) RETURNING *`:             "insert webhook",
+		"UPDATE biolog_user\n\tSET role = $1 WHERE id = $2":                    "update biolog_user",
+		`DELETE FROM rate_limit WHERE updated_at < $1`:                         "delete rate_limit",
+		`SELECT ST_AsMVT(tile) FROM (SELECT id FROM observation) AS tile`:      "select observation",
+		`SELECT count(*) FROM observation_region, region WHERE region.id = $1`: "select observation_region",
+		`SELECT 1`: "select",
+		``:         "unknown",
+	}
+	for query, name := range cases {
+		assert.Equal(t, name, metrics.QueryName(query), query)
+	}
+}
+
+// TestObserveQuery preveri belezenje trajanja in napak poizvedb ter izpis metrik
+func TestObserveQuery(t *testing.T) {
+	errors0 := testutil.ToFloat64(metrics.QueryErrors.WithLabelValues("select species"))
+	metrics.ObserveQuery(`SELECT * FROM species`, time.Now(), nil)
+	metrics.ObserveQuery(`SELECT * FROM species`, time.Now(), errors.New("napaka"))
+	assert.Equal(t, errors0+1, testutil.ToFloat64(metrics.QueryErrors.WithLabelValues("select species")))
+
+	rec := httptest.NewRecorder()
+	metrics.NewServer(":0").Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `biolog_db_query_duration_seconds_count{query="select species"}`)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
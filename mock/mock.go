@@ -5,17 +5,47 @@
 package mock
 
 import (
+	"time"
+
 	"github.com/rubinda/biolog"
 )
 
+// Preveri ob prevajanju, da mocki implementirajo vse metode servicev
+var (
+	_ biolog.UserService    = &UserService{}
+	_ biolog.SpeciesService = &SpeciesService{}
+	_ biolog.RegionService  = &RegionService{}
+	_ biolog.AuditService   = &AuditService{}
+	_ biolog.WebhookService = &WebhookService{}
+
+	_ biolog.ObservationStream = &ObservationStream{}
+	_ biolog.RateLimiter       = &RateLimiter{}
+)
+
 // UserService predstavlja mock za biolog.UserService
 type UserService struct {
-	UserFn       func(id int) (*biolog.User, error)
-	UsersFn      func() ([]*biolog.User, error)
-	CreateUserFn func(u *biolog.User) error
-	DeleteUserFn func(id int) error
+	UserFn         func(id int) (*biolog.User, error)
+	UsersFn        func() ([]biolog.User, error)
+	UserByEmailFn  func(email string) (*biolog.User, error)
+	CreateUserFn   func(u biolog.User) (*biolog.User, error)
+	DeleteUserFn   func(id int, by biolog.Actor) (int64, error)
+	RestoreUserFn  func(id int, by biolog.Actor) error
+	PurgeUsersFn   func(before time.Time) (int64, error)
+	EraseUserFn    func(id int, keepObservations bool, by biolog.Actor) error
+	FollowUserFn   func(followerID, userID int) error
+	UnfollowUserFn func(followerID, userID int) error
+	FollowingFn    func(userID int) ([]biolog.User, error)
+	UpdateUserFn   func(id int, u biolog.User, by biolog.Actor) error
+	UserByExtIDFn  func(id string) (*biolog.User, error)
+	PersonalDataFn func(id int) (*biolog.PersonalData, error)
+
+	CreateExportFn         func(e biolog.Export) error
+	ExportFn               func(id string) (*biolog.Export, error)
+	UpdateExportFn         func(e biolog.Export) error
+	DeleteExpiredExportsFn func(now time.Time) ([]biolog.Export, error)
 
-	AuthProviderFn func(id int) (*biolog.AuthProvider, error)
+	AuthProviderFn  func(id int) (*biolog.AuthProvider, error)
+	AuthProvidersFn func() ([]biolog.AuthProvider, error)
 }
 
 // User mock za vracanje uporabnika preko ID
@@ -24,18 +54,88 @@ func (s *UserService) User(id int) (*biolog.User, error) {
 }
 
 // Users mock za vracanje vseh uporabnikov
-func (s *UserService) Users() ([]*biolog.User, error) {
+func (s *UserService) Users() ([]biolog.User, error) {
 	return s.UsersFn()
 }
 
+// UserByEmail mock za vracanje uporabnika preko emaila
+func (s *UserService) UserByEmail(email string) (*biolog.User, error) {
+	return s.UserByEmailFn(email)
+}
+
 // CreateUser mock za ustvarjanje novega uporabnika
-func (s *UserService) CreateUser(u *biolog.User) error {
+func (s *UserService) CreateUser(u biolog.User) (*biolog.User, error) {
 	return s.CreateUserFn(u)
 }
 
 // DeleteUser mock za brisanje uporabnika
-func (s *UserService) DeleteUser(id int) error {
-	return s.DeleteUserFn(id)
+func (s *UserService) DeleteUser(id int, by biolog.Actor) (int64, error) {
+	return s.DeleteUserFn(id, by)
+}
+
+// RestoreUser mock za obnovitev izbrisanega uporabnika
+func (s *UserService) RestoreUser(id int, by biolog.Actor) error {
+	return s.RestoreUserFn(id, by)
+}
+
+// PurgeUsers mock za trajno odstranjevanje izbrisanih uporabnikov
+func (s *UserService) PurgeUsers(before time.Time) (int64, error) {
+	return s.PurgeUsersFn(before)
+}
+
+// EraseUser mock za trajni izbris uporabnika
+func (s *UserService) EraseUser(id int, keepObservations bool, by biolog.Actor) error {
+	return s.EraseUserFn(id, keepObservations, by)
+}
+
+// FollowUser mock za spremljanje uporabnika
+func (s *UserService) FollowUser(followerID, userID int) error {
+	return s.FollowUserFn(followerID, userID)
+}
+
+// UnfollowUser mock za prenehanje spremljanja uporabnika
+func (s *UserService) UnfollowUser(followerID, userID int) error {
+	return s.UnfollowUserFn(followerID, userID)
+}
+
+// Following mock za vracanje spremljanih uporabnikov
+func (s *UserService) Following(userID int) ([]biolog.User, error) {
+	return s.FollowingFn(userID)
+}
+
+// UpdateUser mock za posodabljanje uporabnika
+func (s *UserService) UpdateUser(id int, u biolog.User, by biolog.Actor) error {
+	return s.UpdateUserFn(id, u, by)
+}
+
+// UserByExtID mock za vracanje uporabnika preko ID zunanjega avtentikatorja
+func (s *UserService) UserByExtID(id string) (*biolog.User, error) {
+	return s.UserByExtIDFn(id)
+}
+
+// PersonalData mock za zbiranje podatkov uporabnika za izvoz
+func (s *UserService) PersonalData(id int) (*biolog.PersonalData, error) {
+	return s.PersonalDataFn(id)
+}
+
+// CreateExport mock za shranjevanje novega izvoza osebnih podatkov
+func (s *UserService) CreateExport(e biolog.Export) error {
+	return s.CreateExportFn(e)
+}
+
+// Export mock za vracanje stanja izvoza
+func (s *UserService) Export(id string) (*biolog.Export, error) {
+	return s.ExportFn(id)
+}
+
+// UpdateExport mock za posodabljanje stanja izvoza
+func (s *UserService) UpdateExport(e biolog.Export) error {
+	return s.UpdateExportFn(e)
+}
+
+// DeleteExpiredExports mock za odstranjevanje poteklih izvozov
+func (s *UserService) DeleteExpiredExports(now time.Time) ([]biolog.Export, error) {
+	return s.DeleteExpiredExportsFn(now)
 }
 
 // AuthProvider mock za pridobivanje podrobnosti o ponudniku avtentikacije
@@ -43,18 +143,75 @@ func (s *UserService) AuthProvider(id int) (*biolog.AuthProvider, error) {
 	return s.AuthProviderFn(id)
 }
 
+// AuthProviders mock za pridobivanje vseh ponudnikov avtentikacije
+func (s *UserService) AuthProviders() ([]biolog.AuthProvider, error) {
+	return s.AuthProvidersFn()
+}
+
 // SpeciesService predstavlja mock za biolog.SpeciesService
 type SpeciesService struct {
-	SpeciesFn func(id int) (*biolog.Species, error)
+	SpeciesFn       func(id int) (*biolog.Species, error)
+	AllSpeciesFn    func() ([]biolog.Species, error)
+	CreateSpeciesFn func(sp *biolog.Species) (*biolog.Species, error)
+	UpdateSpeciesFn func(gbifKey int, sp biolog.Species, by biolog.Actor) error
+	DeleteSpeciesFn func(gbifKey int, by biolog.Actor) error
+	MergeSpeciesFn  func(fromKey, intoKey int, by biolog.Actor) (*biolog.TaxonMerge, error)
+
+	ObservationFn          func(id int) (*biolog.Observation, error)
+	ObservationsFn         func(f biolog.ObservationFilter) ([]biolog.Observation, error)
+	ObservationStatsFn     func(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error)
+	DistributionFn         func(cell string, f biolog.ObservationFilter) ([]biolog.DistributionCell, error)
+	ObservationTileFn      func(z, x, y int, f biolog.ObservationFilter) ([]byte, error)
+	CreateObservationFn    func(o *biolog.Observation) (*biolog.Observation, error)
+	DeleteObservationFn    func(id int, by biolog.Actor) error
+	DeletedObservationFn   func(id int) (*biolog.Observation, error)
+	RestoreObservationFn   func(id int, by biolog.Actor) error
+	PurgeObservationsFn    func(before time.Time) (int64, error)
+	UpdateObservationFn    func(id int, ob biolog.Observation, by biolog.Actor) error
+	ObservationRevisionsFn func(observationID int) ([]biolog.ObservationRevision, error)
+	ObservationRevisionFn  func(observationID, revision int) (*biolog.ObservationRevision, error)
+	RevertObservationFn    func(id, revision int, by biolog.Actor) error
+
+	IdentificationFn         func(id int) (*biolog.Identification, error)
+	IdentificationsFn        func(observationID int) ([]biolog.Identification, error)
+	CreateIdentificationFn   func(i *biolog.Identification) (*biolog.Identification, error)
+	WithdrawIdentificationFn func(id int) error
+
+	CommentFn       func(id int) (*biolog.Comment, error)
+	CommentsFn      func(observationID int, p biolog.Page) ([]biolog.Comment, error)
+	CreateCommentFn func(c *biolog.Comment) (*biolog.Comment, error)
+	UpdateCommentFn func(id int, body string) error
+	DeleteCommentFn func(id int) error
+
+	ChecklistFn       func(id int) (*biolog.Checklist, error)
+	ChecklistsFn      func(userID int, p biolog.Page) ([]biolog.Checklist, error)
+	CreateChecklistFn func(c *biolog.Checklist) (*biolog.Checklist, error)
+	UpdateChecklistFn func(id int, c biolog.Checklist) error
+	DeleteChecklistFn func(id int, by biolog.Actor) error
 
-	ObservationFn       func(id int) (*biolog.Observation, error)
-	ObservationsFn      func() ([]*biolog.Observation, error)
-	CreateObservationFn func(o *biolog.Observation) error
-	DeleteObservationFn func(id int) error
-	UpdateObservationFn func(id int) error
+	ProjectFn             func(id int) (*biolog.Project, error)
+	ProjectsFn            func(p biolog.Page) ([]biolog.Project, error)
+	CreateProjectFn       func(p *biolog.Project) (*biolog.Project, error)
+	UpdateProjectFn       func(id int, p biolog.Project) error
+	DeleteProjectFn       func(id int) error
+	ProjectMembersFn      func(projectID int) ([]biolog.ProjectMember, error)
+	SetProjectMemberFn    func(m biolog.ProjectMember) error
+	RemoveProjectMemberFn func(projectID, userID int) error
+	ProjectLeaderboardFn  func(projectID int, obscure *biolog.SensitivityPolicy) ([]biolog.LeaderboardEntry, error)
+
+	WatchSpeciesFn   func(userID, gbifKey int) error
+	UnwatchSpeciesFn func(userID, gbifKey int) error
+	WatchedSpeciesFn func(userID int) ([]biolog.Species, error)
+	FeedFn           func(userID int, before biolog.FeedCursor, limit int) ([]biolog.FeedItem, error)
+
+	AssessmentFn       func(id int) (*biolog.Assessment, error)
+	AssessmentsFn      func(gbifKey int) ([]biolog.Assessment, error)
+	CreateAssessmentFn func(a *biolog.Assessment) (*biolog.Assessment, error)
+	UpdateAssessmentFn func(id int, a biolog.Assessment) error
+	DeleteAssessmentFn func(id int) error
 
 	ConservationStatusFn   func(id int) (*biolog.ConservationStatus, error)
-	ConservationStatusesFn func() ([]*biolog.ConservationStatus, error)
+	ConservationStatusesFn func() ([]biolog.ConservationStatus, error)
 }
 
 // Species mock za vracanje vrste preko ID
@@ -62,37 +219,371 @@ func (s *SpeciesService) Species(id int) (*biolog.Species, error) {
 	return s.SpeciesFn(id)
 }
 
+// AllSpecies mock za vracanje vseh vrst
+func (s *SpeciesService) AllSpecies() ([]biolog.Species, error) {
+	return s.AllSpeciesFn()
+}
+
+// CreateSpecies mock za kreiranje vrste
+func (s *SpeciesService) CreateSpecies(sp *biolog.Species) (*biolog.Species, error) {
+	return s.CreateSpeciesFn(sp)
+}
+
+// UpdateSpecies mock za posodabljanje vrste
+func (s *SpeciesService) UpdateSpecies(gbifKey int, sp biolog.Species, by biolog.Actor) error {
+	return s.UpdateSpeciesFn(gbifKey, sp, by)
+}
+
+// DeleteSpecies mock za brisanje vrste
+func (s *SpeciesService) DeleteSpecies(gbifKey int, by biolog.Actor) error {
+	return s.DeleteSpeciesFn(gbifKey, by)
+}
+
+// MergeSpecies mock za zdruzevanje vrst
+func (s *SpeciesService) MergeSpecies(fromKey, intoKey int, by biolog.Actor) (*biolog.TaxonMerge, error) {
+	return s.MergeSpeciesFn(fromKey, intoKey, by)
+}
+
 // Observation mock za vracanje opazovalnega lista preko ID
 func (s *SpeciesService) Observation(id int) (*biolog.Observation, error) {
 	return s.ObservationFn(id)
 }
 
 // Observations mock za vracanje vec opazovalnih listov
-func (s *SpeciesService) Observations() ([]*biolog.Observation, error) {
-	return s.ObservationsFn()
+func (s *SpeciesService) Observations(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+	return s.ObservationsFn(f)
+}
+
+// ObservationStats mock za vracanje statistike opazanj
+func (s *SpeciesService) ObservationStats(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error) {
+	return s.ObservationStatsFn(groupBy, f)
+}
+
+// Distribution mock za vracanje celic mreze razsirjenosti
+func (s *SpeciesService) Distribution(cell string, f biolog.ObservationFilter) ([]biolog.DistributionCell, error) {
+	return s.DistributionFn(cell, f)
+}
+
+// ObservationTile mock za vracanje vektorske ploscice z opazanji
+func (s *SpeciesService) ObservationTile(z, x, y int, f biolog.ObservationFilter) ([]byte, error) {
+	return s.ObservationTileFn(z, x, y, f)
 }
 
 // CreateObservation mock za kreiranje opazovalnega lista
-func (s *SpeciesService) CreateObservation(o *biolog.Observation) error {
+func (s *SpeciesService) CreateObservation(o *biolog.Observation) (*biolog.Observation, error) {
 	return s.CreateObservationFn(o)
 }
 
 // DeleteObservation mock za brisanje opazovalnega lista
-func (s *SpeciesService) DeleteObservation(id int) error {
-	return s.DeleteObservationFn(id)
+func (s *SpeciesService) DeleteObservation(id int, by biolog.Actor) error {
+	return s.DeleteObservationFn(id, by)
+}
+
+// DeletedObservation mock za vracanje izbrisanega opazovalnega lista
+func (s *SpeciesService) DeletedObservation(id int) (*biolog.Observation, error) {
+	return s.DeletedObservationFn(id)
+}
+
+// RestoreObservation mock za obnovitev izbrisanega opazovalnega lista
+func (s *SpeciesService) RestoreObservation(id int, by biolog.Actor) error {
+	return s.RestoreObservationFn(id, by)
+}
+
+// PurgeObservations mock za trajno odstranjevanje izbrisanih opazovalnih listov
+func (s *SpeciesService) PurgeObservations(before time.Time) (int64, error) {
+	return s.PurgeObservationsFn(before)
+}
+
+// UpdateObservation mock za posodabljanje opazovalnega lista
+func (s *SpeciesService) UpdateObservation(id int, ob biolog.Observation, by biolog.Actor) error {
+	return s.UpdateObservationFn(id, ob, by)
 }
 
-// ConservationStatus vrne podatke o dolocenem statusu ogrozenosti
+// ObservationRevisions mock za vracanje revizij opazovalnega lista
+func (s *SpeciesService) ObservationRevisions(observationID int) ([]biolog.ObservationRevision, error) {
+	return s.ObservationRevisionsFn(observationID)
+}
+
+// ObservationRevision mock za vracanje dolocene revizije opazovalnega lista
+func (s *SpeciesService) ObservationRevision(observationID, revision int) (*biolog.ObservationRevision, error) {
+	return s.ObservationRevisionFn(observationID, revision)
+}
+
+// RevertObservation mock za povrnitev opazovalnega lista na revizijo
+func (s *SpeciesService) RevertObservation(id, revision int, by biolog.Actor) error {
+	return s.RevertObservationFn(id, revision, by)
+}
+
+// Identification mock za vracanje predloga vrste preko ID
+func (s *SpeciesService) Identification(id int) (*biolog.Identification, error) {
+	return s.IdentificationFn(id)
+}
+
+// Identifications mock za vracanje predlogov vrst za opazanje
+func (s *SpeciesService) Identifications(observationID int) ([]biolog.Identification, error) {
+	return s.IdentificationsFn(observationID)
+}
+
+// CreateIdentification mock za kreiranje predloga vrste
+func (s *SpeciesService) CreateIdentification(i *biolog.Identification) (*biolog.Identification, error) {
+	return s.CreateIdentificationFn(i)
+}
+
+// WithdrawIdentification mock za umik predloga vrste
+func (s *SpeciesService) WithdrawIdentification(id int) error {
+	return s.WithdrawIdentificationFn(id)
+}
+
+// Comment mock za vracanje komentarja preko ID
+func (s *SpeciesService) Comment(id int) (*biolog.Comment, error) {
+	return s.CommentFn(id)
+}
+
+// Comments mock za vracanje strani komentarjev za opazanje
+func (s *SpeciesService) Comments(observationID int, p biolog.Page) ([]biolog.Comment, error) {
+	return s.CommentsFn(observationID, p)
+}
+
+// CreateComment mock za kreiranje komentarja
+func (s *SpeciesService) CreateComment(c *biolog.Comment) (*biolog.Comment, error) {
+	return s.CreateCommentFn(c)
+}
+
+// UpdateComment mock za urejanje komentarja
+func (s *SpeciesService) UpdateComment(id int, body string) error {
+	return s.UpdateCommentFn(id, body)
+}
+
+// DeleteComment mock za brisanje komentarja
+func (s *SpeciesService) DeleteComment(id int) error {
+	return s.DeleteCommentFn(id)
+}
+
+// Checklist mock za vracanje popisa z opazanji
+func (s *SpeciesService) Checklist(id int) (*biolog.Checklist, error) {
+	return s.ChecklistFn(id)
+}
+
+// Checklists mock za vracanje strani popisov uporabnika
+func (s *SpeciesService) Checklists(userID int, p biolog.Page) ([]biolog.Checklist, error) {
+	return s.ChecklistsFn(userID, p)
+}
+
+// CreateChecklist mock za kreiranje popisa z opazanji
+func (s *SpeciesService) CreateChecklist(c *biolog.Checklist) (*biolog.Checklist, error) {
+	return s.CreateChecklistFn(c)
+}
+
+// UpdateChecklist mock za posodabljanje popisa
+func (s *SpeciesService) UpdateChecklist(id int, c biolog.Checklist) error {
+	return s.UpdateChecklistFn(id, c)
+}
+
+// DeleteChecklist mock za brisanje popisa
+func (s *SpeciesService) DeleteChecklist(id int, by biolog.Actor) error {
+	return s.DeleteChecklistFn(id, by)
+}
+
+// Project mock za vracanje projekta
+func (s *SpeciesService) Project(id int) (*biolog.Project, error) {
+	return s.ProjectFn(id)
+}
+
+// Projects mock za vracanje strani projektov
+func (s *SpeciesService) Projects(p biolog.Page) ([]biolog.Project, error) {
+	return s.ProjectsFn(p)
+}
+
+// CreateProject mock za kreiranje projekta
+func (s *SpeciesService) CreateProject(p *biolog.Project) (*biolog.Project, error) {
+	return s.CreateProjectFn(p)
+}
+
+// UpdateProject mock za posodabljanje projekta
+func (s *SpeciesService) UpdateProject(id int, p biolog.Project) error {
+	return s.UpdateProjectFn(id, p)
+}
+
+// DeleteProject mock za brisanje projekta
+func (s *SpeciesService) DeleteProject(id int) error {
+	return s.DeleteProjectFn(id)
+}
+
+// ProjectMembers mock za vracanje clanov projekta
+func (s *SpeciesService) ProjectMembers(projectID int) ([]biolog.ProjectMember, error) {
+	return s.ProjectMembersFn(projectID)
+}
+
+// SetProjectMember mock za dodajanje clana projekta
+func (s *SpeciesService) SetProjectMember(m biolog.ProjectMember) error {
+	return s.SetProjectMemberFn(m)
+}
+
+// RemoveProjectMember mock za odstranjevanje clana projekta
+func (s *SpeciesService) RemoveProjectMember(projectID, userID int) error {
+	return s.RemoveProjectMemberFn(projectID, userID)
+}
+
+// ProjectLeaderboard mock za vracanje lestvice projekta
+func (s *SpeciesService) ProjectLeaderboard(projectID int, obscure *biolog.SensitivityPolicy) ([]biolog.LeaderboardEntry, error) {
+	return s.ProjectLeaderboardFn(projectID, obscure)
+}
+
+// WatchSpecies mock za opazovanje vrste
+func (s *SpeciesService) WatchSpecies(userID, gbifKey int) error {
+	return s.WatchSpeciesFn(userID, gbifKey)
+}
+
+// UnwatchSpecies mock za prenehanje opazovanja vrste
+func (s *SpeciesService) UnwatchSpecies(userID, gbifKey int) error {
+	return s.UnwatchSpeciesFn(userID, gbifKey)
+}
+
+// WatchedSpecies mock za vracanje opazovanih vrst
+func (s *SpeciesService) WatchedSpecies(userID int) ([]biolog.Species, error) {
+	return s.WatchedSpeciesFn(userID)
+}
+
+// Feed mock za vir dejavnosti
+func (s *SpeciesService) Feed(userID int, before biolog.FeedCursor, limit int) ([]biolog.FeedItem, error) {
+	return s.FeedFn(userID, before, limit)
+}
+
+// Assessment mock za vracanje ocene ogrozenosti
+func (s *SpeciesService) Assessment(id int) (*biolog.Assessment, error) {
+	return s.AssessmentFn(id)
+}
+
+// Assessments mock za vracanje zgodovine ocen ogrozenosti vrste
+func (s *SpeciesService) Assessments(gbifKey int) ([]biolog.Assessment, error) {
+	return s.AssessmentsFn(gbifKey)
+}
+
+// CreateAssessment mock za kreiranje ocene ogrozenosti
+func (s *SpeciesService) CreateAssessment(a *biolog.Assessment) (*biolog.Assessment, error) {
+	return s.CreateAssessmentFn(a)
+}
+
+// UpdateAssessment mock za posodabljanje ocene ogrozenosti
+func (s *SpeciesService) UpdateAssessment(id int, a biolog.Assessment) error {
+	return s.UpdateAssessmentFn(id, a)
+}
+
+// DeleteAssessment mock za brisanje ocene ogrozenosti
+func (s *SpeciesService) DeleteAssessment(id int) error {
+	return s.DeleteAssessmentFn(id)
+}
+
+// ConservationStatus mock za vracanje podatkov o dolocenem statusu ogrozenosti
 func (s *SpeciesService) ConservationStatus(id int) (*biolog.ConservationStatus, error) {
 	return s.ConservationStatusFn(id)
 }
 
-// ConservationStatuses vrne vsa mozna stanja ogrozenosti za doloceno vrsto
-func (s *SpeciesService) ConservationStatuses() ([]*biolog.ConservationStatus, error) {
+// ConservationStatuses mock za vracanje vseh stanj ogrozenosti
+func (s *SpeciesService) ConservationStatuses() ([]biolog.ConservationStatus, error) {
 	return s.ConservationStatusesFn()
 }
 
-// UpdateObservation mock za posodabljanje opazovalnega lista
-func (s *SpeciesService) UpdateObservation(id int) error {
-	return s.UpdateObservationFn(id)
+// RegionService predstavlja mock za biolog.RegionService
+type RegionService struct {
+	RegionFn        func(id int) (*biolog.Region, error)
+	RegionsFn       func(kind string) ([]biolog.Region, error)
+	ImportRegionsFn func(regions []biolog.Region) (int, error)
+}
+
+// Region mock za vracanje obmocja preko ID
+func (s *RegionService) Region(id int) (*biolog.Region, error) {
+	return s.RegionFn(id)
+}
+
+// Regions mock za vracanje vseh obmocij dolocene vrste
+func (s *RegionService) Regions(kind string) ([]biolog.Region, error) {
+	return s.RegionsFn(kind)
+}
+
+// ImportRegions mock za uvoz obmocij
+func (s *RegionService) ImportRegions(regions []biolog.Region) (int, error) {
+	return s.ImportRegionsFn(regions)
+}
+
+// AuditService predstavlja mock za biolog.AuditService
+type AuditService struct {
+	AuditEntriesFn func(f biolog.AuditFilter, p biolog.Page) ([]biolog.AuditEntry, error)
+}
+
+// AuditEntries mock za vracanje zapisov revizijske sledi
+func (s *AuditService) AuditEntries(f biolog.AuditFilter, p biolog.Page) ([]biolog.AuditEntry, error) {
+	return s.AuditEntriesFn(f, p)
+}
+
+// WebhookService predstavlja mock za biolog.WebhookService
+type WebhookService struct {
+	WebhookFn           func(id int) (*biolog.Webhook, error)
+	WebhooksFn          func() ([]biolog.Webhook, error)
+	CreateWebhookFn     func(wh *biolog.Webhook) (*biolog.Webhook, error)
+	DeleteWebhookFn     func(id int) error
+	WebhookDeliveriesFn func(webhookID int, p biolog.Page) ([]biolog.WebhookDelivery, error)
+	ClaimDeliveriesFn   func(limit int, lease time.Duration) ([]biolog.WebhookDelivery, error)
+	RecordAttemptFn     func(id int, a biolog.WebhookAttempt) error
+}
+
+// Webhook mock za vracanje narocnine preko ID
+func (s *WebhookService) Webhook(id int) (*biolog.Webhook, error) {
+	return s.WebhookFn(id)
+}
+
+// Webhooks mock za vracanje vseh narocnin
+func (s *WebhookService) Webhooks() ([]biolog.Webhook, error) {
+	return s.WebhooksFn()
+}
+
+// CreateWebhook mock za kreiranje narocnine
+func (s *WebhookService) CreateWebhook(wh *biolog.Webhook) (*biolog.Webhook, error) {
+	return s.CreateWebhookFn(wh)
+}
+
+// DeleteWebhook mock za brisanje narocnine
+func (s *WebhookService) DeleteWebhook(id int) error {
+	return s.DeleteWebhookFn(id)
+}
+
+// WebhookDeliveries mock za vracanje posiljanj narocnine
+func (s *WebhookService) WebhookDeliveries(webhookID int, p biolog.Page) ([]biolog.WebhookDelivery, error) {
+	return s.WebhookDeliveriesFn(webhookID, p)
+}
+
+// ClaimDeliveries mock za prevzem posiljanj iz vrste
+func (s *WebhookService) ClaimDeliveries(limit int, lease time.Duration) ([]biolog.WebhookDelivery, error) {
+	return s.ClaimDeliveriesFn(limit, lease)
+}
+
+// RecordAttempt mock za zapis izida poskusa posiljanja
+func (s *WebhookService) RecordAttempt(id int, a biolog.WebhookAttempt) error {
+	return s.RecordAttemptFn(id, a)
+}
+
+// ObservationStream predstavlja mock za biolog.ObservationStream
+type ObservationStream struct {
+	SubscribeFn func() (<-chan biolog.Observation, func())
+}
+
+// Subscribe mock za prijavo na tok novih opazanj
+func (s *ObservationStream) Subscribe() (<-chan biolog.Observation, func()) {
+	return s.SubscribeFn()
+}
+
+// RateLimiter predstavlja mock za biolog.RateLimiter
+type RateLimiter struct {
+	TakeFn  func(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error)
+	PruneFn func(before time.Time) (int, error)
+}
+
+// Take mock za porabo zetona iz vedra
+func (s *RateLimiter) Take(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error) {
+	return s.TakeFn(key, limit)
+}
+
+// Prune mock za odstranjevanje neuporabljenih veder
+func (s *RateLimiter) Prune(before time.Time) (int, error) {
+	return s.PruneFn(before)
 }
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// Assessment vrne oceno ogrozenosti z dolocenim ID
+func (s *SpeciesService) Assessment(id int) (*biolog.Assessment, error) {
+	a := &biolog.Assessment{}
+	if getErr := s.DB.GetContext(s.context(), a, `SELECT * FROM assessment WHERE id = $1`, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Ocena s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	return a, nil
+}
+
+// Assessments vrne vse ocene ogrozenosti vrste (zgodovino), urejene po sistemu, obmocju in od najnovejse naprej
+func (s *SpeciesService) Assessments(gbifKey int) ([]biolog.Assessment, error) {
+	stmt := `SELECT * FROM assessment WHERE species = $1 ORDER BY scheme, region, year DESC, id DESC`
+	assessments := []biolog.Assessment{}
+
+	if selErr := s.DB.SelectContext(s.context(), &assessments, stmt, gbifKey); selErr != nil {
+		return nil, selErr
+	}
+
+	return assessments, nil
+}
+
+// CreateAssessment shrani novo oceno ogrozenosti vrste
+func (s *SpeciesService) CreateAssessment(a *biolog.Assessment) (*biolog.Assessment, error) {
+	newAssessment := &biolog.Assessment{}
+
+	q, args := buildInsertUpdateQuery(buildInsert, "assessment", *a)
+	if getErr := s.DB.GetContext(s.context(), newAssessment, q, args...); getErr != nil {
+		return nil, getErr
+	}
+
+	return newAssessment, nil
+}
+
+// UpdateAssessment posodobi podana polja ocene ogrozenosti, vrste ocene ni mogoce spremeniti
+func (s *SpeciesService) UpdateAssessment(id int, a biolog.Assessment) error {
+	a.Species = nil
+	q, args := buildInsertUpdateQuery(buildUpdate, "assessment", a)
+	args = append(args, id)
+
+	result, err := s.DB.ExecContext(s.context(), q, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Ocena s tem ID ne obstaja")
+	}
+
+	return nil
+}
+
+// DeleteAssessment zbrise oceno ogrozenosti
+func (s *SpeciesService) DeleteAssessment(id int) error {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM assessment WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Ocena s tem ID ne obstaja")
+	}
+
+	return nil
+}
+
+// withAssessments doda vrstam njihove trenutne ocene ogrozenosti
+func (s *SpeciesService) withAssessments(sps []biolog.Species) error {
+	if len(sps) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(sps))
+	for i, sp := range sps {
+		ids[i] = int64(*sp.ID)
+	}
+	all := []biolog.Assessment{}
+	if selErr := s.DB.SelectContext(s.context(), &all, `SELECT * FROM assessment WHERE species = ANY($1)`, pq.Array(ids)); selErr != nil {
+		return selErr
+	}
+
+	bySpecies := make(map[int][]biolog.Assessment)
+	for _, a := range all {
+		bySpecies[*a.Species] = append(bySpecies[*a.Species], a)
+	}
+	for i := range sps {
+		sps[i].Assessments = biolog.CurrentAssessments(bySpecies[*sps[i].ID])
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssessments preveri shranjevanje ocen ogrozenosti in trenutne ocene pri vrsti
+func TestAssessments(t *testing.T) {
+	// Vrsta 1 je v scripts/sample-data.sql
+	species, scheme, region := 1, biolog.SchemeSloveniaRedList, "SI"
+	var created []*biolog.Assessment
+	for _, a := range []struct{ status, year int }{{5, 1992}, {4, 2002}} {
+		status, year := a.status, a.year
+		newAssessment, err := speciesServiceTest.CreateAssessment(&biolog.Assessment{Species: &species, Scheme: &scheme,
+			Region: &region, ConservationStatus: &status, Year: &year})
+		if !assert.NoError(t, err) {
+			return
+		}
+		created = append(created, newAssessment)
+	}
+
+	sp, err := speciesServiceTest.Species(species)
+	if assert.NoError(t, err) {
+		for _, a := range sp.Assessments {
+			if *a.Scheme == scheme && *a.Region == region {
+				assert.Equal(t, *created[1].ID, *a.ID)
+			}
+		}
+	}
+
+	for _, a := range created {
+		assert.NoError(t, speciesServiceTest.DeleteAssessment(*a.ID))
+	}
+	assert.Error(t, speciesServiceTest.DeleteAssessment(*created[0].ID))
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// AuditService predstavlja PostgreSQL implementacijo od biolog.AuditService
+type AuditService struct {
+	DB *sqlx.DB
+}
+
+// Preveri ali AuditService implementira vse metode
+var _ biolog.AuditService = &AuditService{}
+
+// AuditEntries vrne stran zapisov revizijske sledi, ki ustrezajo filtru, od najnovejsega naprej
+func (s *AuditService) AuditEntries(f biolog.AuditFilter, p biolog.Page) ([]biolog.AuditEntry, error) {
+	var where strings.Builder
+	var args []interface{}
+	where.WriteString(`WHERE TRUE`)
+	if f.EntityType != nil {
+		args = append(args, *f.EntityType)
+		fmt.Fprintf(&where, " AND entity_type = $%d", len(args))
+	}
+	if f.EntityID != nil {
+		args = append(args, *f.EntityID)
+		fmt.Fprintf(&where, " AND entity_id = $%d", len(args))
+	}
+	if f.Actor != nil {
+		args = append(args, *f.Actor)
+		fmt.Fprintf(&where, " AND actor = $%d", len(args))
+	}
+	args = append(args, p.Limit, p.Offset)
+	stmt := fmt.Sprintf(`SELECT * FROM audit_log %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		where.String(), len(args)-1, len(args))
+
+	entries := []biolog.AuditEntry{}
+	if selErr := s.DB.Select(&entries, stmt, args...); selErr != nil {
+		return nil, selErr
+	}
+
+	return entries, nil
+}
+
+// writeAudit zapise spremembo zapisa v revizijsko sled znotraj transakcije spremembe.
+// before in after sta zapisa pred in po spremembi (nil, ce zapis ni obstajal)
+func writeAudit(tx *sqlx.Tx, by biolog.Actor, action, entityType string, id int, before, after interface{}) error {
+	changes, err := biolog.AuditChanges(entityType, before, after)
+	if err != nil {
+		return err
+	}
+	var requestID *string
+	if by.RequestID != "" {
+		requestID = &by.RequestID
+	}
+
+	stmt := `INSERT INTO audit_log (actor, action, entity_type, entity_id, changes, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.Exec(stmt, by.User, action, entityType, id, string(changes), requestID)
+	return err
+}
+
+// lockRow prebere zapis z dolocenim ID in ga zaklene do konca transakcije (stanje za revizijsko sled)
+func lockRow(tx *sqlx.Tx, dest interface{}, table string, id int) error {
+	return tx.Get(dest, `SELECT * FROM `+table+` WHERE id = $1 FOR UPDATE`, id)
+}
+
+// redactAudit v starejsih zapisih revizijske sledi zapisa zakrije vrednosti polj z osebnimi podatki (z AuditMasked,
+// null ostane null). Zapisi so sicer nespremenljivi, sprememba je dovoljena le z nastavitvijo biolog.audit_redact,
+// ki velja do konca transakcije tx (glej scripts/migrations/018_audit_log_redact.sql)
+func redactAudit(tx *sqlx.Tx, entityType string, id int) error {
+	if _, err := tx.Exec(`SELECT set_config('biolog.audit_redact', 'on', true)`); err != nil {
+		return err
+	}
+	stmt := `UPDATE audit_log SET changes = (
+			SELECT jsonb_object_agg(key, CASE WHEN key = ANY($3) THEN jsonb_build_object(
+				'before', CASE WHEN value->'before' = 'null' THEN value->'before' ELSE to_jsonb($4::text) END,
+				'after', CASE WHEN value->'after' = 'null' THEN value->'after' ELSE to_jsonb($4::text) END)
+				ELSE value END)
+			FROM jsonb_each(changes))
+		WHERE entity_type = $1 AND entity_id = $2 AND changes ?| $3`
+	if _, err := tx.Exec(stmt, entityType, id, pq.Array(biolog.AuditMaskedFields(entityType)), biolog.AuditMasked); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`SELECT set_config('biolog.audit_redact', 'off', true)`)
+	return err
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/rubinda/biolog"
+)
+
+// Checklist vrne popis z dolocenim ID skupaj z vsemi opazanji na njem
+func (s *SpeciesService) Checklist(id int) (*biolog.Checklist, error) {
+	c := &biolog.Checklist{}
+	if getErr := s.DB.GetContext(s.context(), c, `SELECT * FROM checklist WHERE id = $1`, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Popis s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	c.Observations = []biolog.Observation{}
+	stmt := `SELECT * FROM observation WHERE checklist = $1 AND deleted_at IS NULL ORDER BY id`
+	if selErr := s.DB.SelectContext(s.context(), &c.Observations, stmt, id); selErr != nil {
+		return nil, selErr
+	}
+
+	return c, nil
+}
+
+// Checklists vrne stran popisov uporabnika (brez opazanj), urejenih od najnovejsega naprej
+func (s *SpeciesService) Checklists(userID int, p biolog.Page) ([]biolog.Checklist, error) {
+	stmt := `SELECT * FROM checklist WHERE biolog_user = $1
+		ORDER BY start_time DESC, id DESC LIMIT $2 OFFSET $3`
+	checklists := []biolog.Checklist{}
+
+	if selErr := s.DB.SelectContext(s.context(), &checklists, stmt, userID, p.Limit, p.Offset); selErr != nil {
+		return nil, selErr
+	}
+
+	return checklists, nil
+}
+
+// CreateChecklist shrani popis in vsa njegova opazanja v eni transakciji
+func (s *SpeciesService) CreateChecklist(c *biolog.Checklist) (*biolog.Checklist, error) {
+	newChecklist := biolog.Checklist{}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+	q, args := buildInsertUpdateQuery(buildInsert, "checklist", *c)
+	if getErr := tx.Get(&newChecklist, q, args...); getErr != nil {
+		tx.Rollback()
+		return nil, getErr
+	}
+
+	newChecklist.Observations = []biolog.Observation{}
+	for _, o := range c.Observations {
+		o.Checklist = newChecklist.ID
+		ob, err := insertObservation(tx, o, s.sensitivity())
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		newChecklist.Observations = append(newChecklist.Observations, *ob)
+	}
+
+	return &newChecklist, tx.Commit()
+}
+
+// UpdateChecklist posodobi podatke o popisu, opazanja na njem se ne spremenijo
+func (s *SpeciesService) UpdateChecklist(id int, c biolog.Checklist) error {
+	q, args := buildInsertUpdateQuery(buildUpdate, "checklist", c)
+	args = append(args, id)
+
+	result, err := s.DB.ExecContext(s.context(), q, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Popis s tem ID ne obstaja")
+	}
+
+	return nil
+}
+
+// DeleteChecklist v eni transakciji mehko izbrise opazanja na popisu (z zapisom v revizijsko sled) in zbrise
+// popis. Izbrisana opazanja ostanejo do trajne odstranitve brez popisa (ON DELETE SET NULL)
+func (s *SpeciesService) DeleteChecklist(id int, by biolog.Actor) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	c := &biolog.Checklist{}
+	if err := lockRow(tx, c, "checklist", id); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return errors.New("Popis s tem ID ne obstaja")
+		}
+		return err
+	}
+
+	var observations []int
+	stmt := `SELECT id FROM observation WHERE checklist = $1 AND deleted_at IS NULL ORDER BY id`
+	if err := tx.Select(&observations, stmt, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, ob := range observations {
+		if err := markObservationDeleted(tx, ob, true, by); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM checklist WHERE id = $1`, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,53 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChecklists preveri kreiranje popisa z opazanji v transakciji in brisanje skupaj z opazanji (mehko)
+func TestChecklists(t *testing.T) {
+	// Uporabnik 10000001 in vrsta 1 sta v scripts/sample-data.sql
+	user, species := 10000001, 1
+	start, duration, loc, protocol := time.Now().UTC().Truncate(time.Second), 45, "POINT(14.5058 46.0569)", biolog.ProtocolTraveling
+	quantity, public := 3, true
+	ob := biolog.Observation{SightingTime: &start, SightingLocation: &loc, Quantity: &quantity,
+		PublicVisibility: &public, User: &user, Species: &species}
+	c := &biolog.Checklist{User: &user, StartTime: &start, Duration: &duration, Location: &loc, Protocol: &protocol,
+		Observations: []biolog.Observation{ob, ob}}
+
+	created, err := speciesServiceTest.CreateChecklist(c)
+	if !assert.NoError(t, err) {
+		return
+	}
+	got, err := speciesServiceTest.Checklist(*created.ID)
+	if !assert.NoError(t, err) || !assert.Len(t, got.Observations, 2) {
+		return
+	}
+	assert.Equal(t, *created.ID, *got.Observations[0].Checklist)
+
+	// Neobstojeca vrsta razveljavi celoten popis
+	unknown := -1
+	c.Observations[1].Species = &unknown
+	before, _ := speciesServiceTest.Checklists(user, biolog.Page{Limit: 100})
+	_, err = speciesServiceTest.CreateChecklist(c)
+	assert.Error(t, err)
+	after, _ := speciesServiceTest.Checklists(user, biolog.Page{Limit: 100})
+	assert.Equal(t, len(before), len(after))
+
+	assert.NoError(t, speciesServiceTest.DeleteChecklist(*created.ID, biolog.Actor{User: user}))
+	_, err = speciesServiceTest.Observation(*got.Observations[0].ID)
+	assert.Error(t, err)
+	assert.Error(t, speciesServiceTest.DeleteChecklist(*created.ID, biolog.Actor{User: user}))
+	// Opazanja je do trajne odstranitve mogoce obnoviti, brez popisa
+	deleted, err := speciesServiceTest.DeletedObservation(*got.Observations[0].ID)
+	if assert.NoError(t, err) {
+		assert.Nil(t, deleted.Checklist)
+		assert.Equal(t, user, *deleted.DeletedBy)
+	}
+	assert.NoError(t, speciesServiceTest.RestoreObservation(*got.Observations[0].ID, biolog.Actor{User: user}))
+	assert.NoError(t, speciesServiceTest.DeleteObservation(*got.Observations[0].ID, biolog.Actor{User: user}))
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/rubinda/biolog"
+)
+
+// Comment vrne komentar z dolocenim ID
+func (s *SpeciesService) Comment(id int) (*biolog.Comment, error) {
+	stmt := `SELECT * FROM observation_comment WHERE id = $1`
+	c := &biolog.Comment{}
+
+	if getErr := s.DB.GetContext(s.context(), c, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Komentar s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	return c, nil
+}
+
+// Comments vrne stran komentarjev za doloceno opazanje, urejenih od najstarejsega naprej
+func (s *SpeciesService) Comments(observationID int, p biolog.Page) ([]biolog.Comment, error) {
+	stmt := `SELECT * FROM observation_comment WHERE observation = $1
+		ORDER BY created_at, id LIMIT $2 OFFSET $3`
+	comments := []biolog.Comment{}
+
+	if selErr := s.DB.SelectContext(s.context(), &comments, stmt, observationID, p.Limit, p.Offset); selErr != nil {
+		return nil, selErr
+	}
+
+	return comments, nil
+}
+
+// CreateComment shrani nov komentar pod opazanjem
+func (s *SpeciesService) CreateComment(c *biolog.Comment) (*biolog.Comment, error) {
+	if c.Observation == nil || c.User == nil || c.Body == nil {
+		return nil, errors.New("Komentar mora imeti opazanje, avtorja in besedilo")
+	}
+
+	newComment := biolog.Comment{}
+	stmt := `INSERT INTO observation_comment (observation, biolog_user, body) VALUES ($1, $2, $3) RETURNING *`
+	if err := s.DB.GetContext(s.context(), &newComment, stmt, *c.Observation, *c.User, *c.Body); err != nil {
+		return nil, err
+	}
+
+	return &newComment, nil
+}
+
+// UpdateComment zamenja besedilo komentarja in posodobi cas urejanja
+func (s *SpeciesService) UpdateComment(id int, body string) error {
+	stmt := `UPDATE observation_comment SET body = $1, updated_at = now() WHERE id = $2`
+	result, err := s.DB.ExecContext(s.context(), stmt, body, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Komentar s tem ID ne obstaja")
+	}
+
+	return nil
+}
+
+// DeleteComment zbrise komentar
+func (s *SpeciesService) DeleteComment(id int) error {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM observation_comment WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Komentar s tem ID ne obstaja")
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestComments preveri kreiranje, ostranjevanje, urejanje in brisanje komentarja
+func TestComments(t *testing.T) {
+	// Opazanje 2 je javno opazanje uporabnika 10000003, glej scripts/sample-data.sql
+	observation, author, body := 2, 10000001, "Je bila fotografija posneta pri krmilnici?"
+
+	c, createErr := speciesServiceTest.CreateComment(&biolog.Comment{Observation: &observation, User: &author, Body: &body})
+	if assert.NoError(t, createErr) {
+		comments, selErr := speciesServiceTest.Comments(observation, biolog.Page{Limit: 100})
+		if assert.NoError(t, selErr) {
+			assert.Contains(t, comments, *c)
+		}
+
+		if assert.NoError(t, speciesServiceTest.UpdateComment(*c.ID, "Ne, v gozdu.")) {
+			updated, getErr := speciesServiceTest.Comment(*c.ID)
+			if assert.NoError(t, getErr) {
+				assert.Equal(t, "Ne, v gozdu.", *updated.Body)
+			}
+		}
+
+		assert.NoError(t, speciesServiceTest.DeleteComment(*c.ID))
+		assert.Error(t, speciesServiceTest.DeleteComment(*c.ID))
+	}
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// distributionQueries so poizvedbe za posamezno mrezo, %[1]s se zamenja z izrazom iz observationLocation,
+// %[2]s pa z WHERE delom iz observationConditions.
+// Celice 10km so celice evropske referencne mreze v EPSG:3035: tocka se s ST_SnapToGrid premakne v
+// sredisce celice (mreza z izhodiscem 5000, 5000), iz sredisca pa dobimo kodo in geometrijo celice
+var distributionQueries = map[string]string{
+	biolog.CellGeohash5: `SELECT cell, ST_AsGeoJSON(ST_GeomFromGeoHash(cell)) AS geometry,
+			observations, first_sighting, last_sighting
+		FROM (
+			SELECT ST_GeoHash(%[1]s, 5) AS cell, count(*) AS observations,
+				min(observation.sighting_time) AS first_sighting, max(observation.sighting_time) AS last_sighting
+			FROM observation
+			JOIN species ON species.id = observation.species
+			LEFT JOIN conservation_status ON conservation_status.id = species.conservation_status
+			%[2]s GROUP BY 1
+		) cells ORDER BY cell`,
+	biolog.Cell10km: `SELECT '10kmE' || ((ST_X(center) - 5000) / 10000)::int || 'N' || ((ST_Y(center) - 5000) / 10000)::int AS cell,
+			ST_AsGeoJSON(ST_Transform(ST_Expand(center, 5000), 4326)) AS geometry,
+			observations, first_sighting, last_sighting
+		FROM (
+			SELECT ST_SnapToGrid(ST_Transform(%[1]s, 3035), 5000, 5000, 10000, 10000) AS center,
+				count(*) AS observations,
+				min(observation.sighting_time) AS first_sighting, max(observation.sighting_time) AS last_sighting
+			FROM observation
+			JOIN species ON species.id = observation.species
+			LEFT JOIN conservation_status ON conservation_status.id = species.conservation_status
+			%[2]s GROUP BY 1
+		) cells ORDER BY cell`,
+}
+
+// Distribution zdruzi javna opazanja v celice izbrane mreze. Za vsako celico vrne geometrijo,
+// stevilo opazanj ter cas prvega in zadnjega opazanja
+func (s *SpeciesService) Distribution(cell string, f biolog.ObservationFilter) ([]biolog.DistributionCell, error) {
+	query, ok := distributionQueries[cell]
+	if !ok {
+		return nil, errors.New("Neveljavna mreza")
+	}
+	where, args := observationConditions(f)
+	location, args := observationLocation(f, args)
+
+	// Geometrija pride iz baze kot besedilo, zato jo preberemo v vmesno strukturo
+	rows := []struct {
+		Cell          string
+		Geometry      string
+		Observations  int
+		FirstSighting *time.Time `db:"first_sighting"`
+		LastSighting  *time.Time `db:"last_sighting"`
+	}{}
+	if selErr := s.DB.SelectContext(s.context(), &rows, fmt.Sprintf(query, location, where), args...); selErr != nil {
+		return nil, selErr
+	}
+
+	cells := make([]biolog.DistributionCell, len(rows))
+	for i, r := range rows {
+		cells[i] = biolog.DistributionCell{Cell: r.Cell, Geometry: json.RawMessage(r.Geometry),
+			Observations: r.Observations, FirstSighting: r.FirstSighting, LastSighting: r.LastSighting}
+	}
+
+	return cells, nil
+}
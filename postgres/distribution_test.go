@@ -0,0 +1,35 @@
+package postgres_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDistribution preveri, da so vsa javna opazanja vrste zajeta v celicah in da je geometrija veljaven GeoJSON
+func TestDistribution(t *testing.T) {
+	// Vrsta 1 je opazena v scripts/sample-data.sql
+	species := 1
+	f := biolog.ObservationFilter{Species: &species}
+	obs, err := speciesServiceTest.Observations(f)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, cell := range []string{biolog.CellGeohash5, biolog.Cell10km} {
+		cells, distErr := speciesServiceTest.Distribution(cell, f)
+		if assert.NoError(t, distErr, cell) {
+			total := 0
+			for _, c := range cells {
+				total += c.Observations
+				var geometry map[string]interface{}
+				if assert.NoError(t, json.Unmarshal(c.Geometry, &geometry), cell) {
+					assert.Equal(t, "Polygon", geometry["type"], cell)
+				}
+			}
+			assert.Equal(t, len(obs), total, cell)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/rubinda/biolog"
+)
+
+// EraseUser v eni transakciji trajno izbrise uporabniski racun. Osebni podatki (imena, email, slika in zunanji ID)
+// se odstranijo, zapis ostane kot anonimen "izbrisan uporabnik". Ce je keepObservations true, se ohranijo javna
+// opazanja ter identifikacije in komentarji uporabnika, sicer se zbrise vsa njegova vsebina
+func (s *UserService) EraseUser(id int, keepObservations bool, by biolog.Actor) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	before, after := &biolog.User{}, &biolog.User{}
+	if err := lockRow(tx, before, "biolog_user", id); err != nil || before.ErasedAt != nil {
+		tx.Rollback()
+		if err == nil || err == sql.ErrNoRows {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+		return err
+	}
+
+	// Zasebna in izbrisana opazanja se vedno zbrisejo, javna (tudi na ravni racuna) le ce jih uporabnik ne zeli
+	// ohraniti. Identifikacije, komentarji in revizije opazanj se zbrisejo skupaj z njimi (ON DELETE CASCADE)
+	keepPublic := keepObservations && (before.PublicObservations == nil || *before.PublicObservations)
+	stmt := `DELETE FROM observation WHERE biolog_user = $1`
+	if keepPublic {
+		stmt += ` AND (NOT public_visibility OR deleted_at IS NOT NULL)`
+	}
+	if _, err := tx.Exec(stmt, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !keepObservations {
+		// Identifikacije na opazanjih drugih uporabnikov vplivajo na soglasje skupnosti, ki se ponovno izracuna
+		var affected []int
+		stmt = `WITH removed AS (DELETE FROM identification WHERE biolog_user = $1 RETURNING observation)
+			SELECT DISTINCT observation FROM removed`
+		if err := tx.Select(&affected, stmt, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, observationID := range affected {
+			if err := updateConsensus(tx, observationID); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		for _, stmt := range []string{`DELETE FROM observation_comment WHERE biolog_user = $1`,
+			`DELETE FROM checklist WHERE biolog_user = $1`} {
+			if _, err := tx.Exec(stmt, id); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	for _, stmt := range []string{`DELETE FROM project_member WHERE biolog_user = $1`,
+		`DELETE FROM user_follow WHERE follower = $1 OR followee = $1`,
+		`DELETE FROM species_watch WHERE biolog_user = $1`} {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	stmt = `UPDATE biolog_user SET display_name = $2, given_name = NULL, family_name = NULL, email = NULL, picture = NULL,
+		external_id = NULL, external_auth_provider = NULL, role = $3, deleted_at = NULL, deleted_by = NULL,
+		erased_at = now() WHERE id = $1`
+	if _, err := tx.Exec(stmt, id, biolog.DeletedUserName, biolog.RoleUser); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := lockRow(tx, after, "biolog_user", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := redactAudit(tx, biolog.AuditUser, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// Osebni podatki se ne prepisejo v revizijsko sled, zapise se le anonimizirano stanje
+	if err := writeAudit(tx, by, biolog.AuditErase, biolog.AuditUser, id, nil, after); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
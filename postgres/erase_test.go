@@ -0,0 +1,124 @@
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// createEraseTestUser ustvari uporabnika z javnim in zasebnim opazanjem ter komentarjem na opazanju 2
+// (javno opazanje uporabnika 10000003, glej scripts/sample-data.sql). Vrne uporabnika in obe opazanji
+func createEraseTestUser(t *testing.T, n int) (*biolog.User, *biolog.Observation, *biolog.Observation) {
+	gn, fn, dn := "Simon", "Tam", "dr. Simon Tam"
+	email, extID := fmt.Sprintf("simon.tam%d@fakemail.com", n), fmt.Sprintf("76584922647812352040%02d", n)
+	u, err := userServiceTest.CreateUser(biolog.User{DisplayName: &dn, GivenName: &gn, FamilyName: &fn, Email: &email,
+		ExternalID: &extID, ExternalAuthProvider: &globalOne})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var observations []*biolog.Observation
+	species, quantity, loc := 5231190, 1, "POINT(14.5058 46.0569)"
+	for _, public := range []bool{true, false} {
+		public, sightingTime := public, time.Now().UTC()
+		ob, err := speciesServiceTest.CreateObservation(&biolog.Observation{SightingTime: &sightingTime,
+			SightingLocation: &loc, Quantity: &quantity, PublicVisibility: &public, User: u.ID, Species: &species})
+		if err != nil {
+			t.Fatal(err)
+		}
+		observations = append(observations, ob)
+	}
+	observation, body := 2, "Lepo opazanje"
+	if _, err := speciesServiceTest.CreateComment(&biolog.Comment{Observation: &observation, User: u.ID, Body: &body}); err != nil {
+		t.Fatal(err)
+	}
+	return u, observations[0], observations[1]
+}
+
+// countRows vrne stevilo zapisov uporabnika v tabeli
+func countRows(t *testing.T, table string, user int) int {
+	var n int
+	if err := userServiceTest.DB.Get(&n, `SELECT count(*) FROM `+table+` WHERE biolog_user = $1`, user); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// TestEraseUser preveri trajni izbris racuna: odstranitev osebnih podatkov ter ohranitev ali izbris opazanj
+func TestEraseUser(t *testing.T) {
+	// Javna opazanja se ohranijo pod anonimnim uporabnikom, zasebna se zbrisejo
+	u, public, private := createEraseTestUser(t, 1)
+	// Zapis iz casa pred zakrivanjem osebnih podatkov v sledi
+	legacy := fmt.Sprintf(`{"email": {"before": null, "after": %q}, "givenName": {"before": null, "after": %q},
+		"familyName": {"before": %q, "after": null}, "externalID": {"before": null, "after": %q},
+		"role": {"before": null, "after": "user"}}`, *u.Email, *u.GivenName, *u.FamilyName, *u.ExternalID)
+	_, err := userServiceTest.DB.Exec(`INSERT INTO audit_log (actor, action, entity_type, entity_id, changes)
+		VALUES ($1, $2, $3, $1, $4)`, *u.ID, biolog.AuditUpdate, biolog.AuditUser, legacy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, userServiceTest.EraseUser(*u.ID, true, biolog.Actor{User: *u.ID})) {
+		return
+	}
+	erased, err := userServiceTest.User(*u.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, biolog.DeletedUserName, *erased.DisplayName)
+		assert.Nil(t, erased.Email)
+		assert.Nil(t, erased.GivenName)
+		assert.Nil(t, erased.FamilyName)
+		assert.Nil(t, erased.ExternalID)
+		assert.NotNil(t, erased.ErasedAt)
+	}
+	_, err = userServiceTest.UserByEmail(*u.Email)
+	assert.Error(t, err)
+	kept, err := speciesServiceTest.Observation(*public.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *u.ID, *kept.User)
+	}
+	_, err = speciesServiceTest.Observation(*private.ID)
+	assert.Error(t, err)
+	assert.Equal(t, 1, countRows(t, "observation_comment", *u.ID))
+
+	// Revizijska sled ne vsebuje osebnih podatkov
+	entityType := biolog.AuditUser
+	entries, err := (&postgres.AuditService{DB: userServiceTest.DB}).AuditEntries(biolog.AuditFilter{
+		EntityType: &entityType, EntityID: u.ID}, biolog.Page{Limit: 10})
+	if assert.NoError(t, err) && assert.NotEmpty(t, entries) {
+		assert.Equal(t, biolog.AuditErase, *entries[0].Action)
+	}
+	// Osebnih podatkov ni v nobenem zapisu sledi, zakrite vrednosti starejsih zapisov pa ostanejo
+	var changes []string
+	if assert.NoError(t, userServiceTest.DB.Select(&changes, `SELECT changes::text FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2`, biolog.AuditUser, *u.ID)) && assert.Len(t, changes, 2) {
+		for _, c := range changes {
+			for _, pii := range []string{*u.Email, *u.GivenName, *u.FamilyName, *u.ExternalID} {
+				assert.NotContains(t, c, pii)
+			}
+		}
+		assert.Contains(t, changes[0]+changes[1], `"after": "`+biolog.AuditMasked+`"`)
+		assert.Contains(t, changes[0]+changes[1], `"role": {"after": "user", "before": null}`)
+	}
+	// Izven izbrisa uporabnika sled ostane nespremenljiva
+	_, err = userServiceTest.DB.Exec(`UPDATE audit_log SET changes = '{}' WHERE entity_type = $1 AND entity_id = $2`,
+		biolog.AuditUser, *u.ID)
+	assert.Error(t, err)
+
+	// Racuna ni mogoce ponovno izbrisati ali mu dodati osebnih podatkov
+	assert.Error(t, userServiceTest.EraseUser(*u.ID, true, biolog.Actor{User: *u.ID}))
+	assert.Error(t, userServiceTest.UpdateUser(*u.ID, biolog.User{Email: u.Email}, biolog.Actor{User: *u.ID}))
+
+	// Brez ohranitve se zbrise vsa vsebina uporabnika
+	u, public, _ = createEraseTestUser(t, 2)
+	if assert.NoError(t, userServiceTest.EraseUser(*u.ID, false, biolog.Actor{User: *u.ID})) {
+		_, err = speciesServiceTest.Observation(*public.ID)
+		assert.Error(t, err)
+		assert.Equal(t, 0, countRows(t, "observation", *u.ID))
+		assert.Equal(t, 0, countRows(t, "observation_comment", *u.ID))
+	}
+
+	assert.Error(t, userServiceTest.EraseUser(99999999, true, biolog.Actor{User: 10000000}))
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/rubinda/biolog"
+)
+
+// PersonalData zbere vse podatke uporabnika za izvoz: profil, opazanja (tudi zasebna), komentarje in identifikacije
+func (s *UserService) PersonalData(id int) (*biolog.PersonalData, error) {
+	u, err := s.User(id)
+	if err != nil {
+		return nil, err
+	}
+	d := &biolog.PersonalData{User: *u, Observations: []biolog.Observation{}, Comments: []biolog.Comment{},
+		Identifications: []biolog.Identification{}}
+
+	stmt := `SELECT * FROM observation WHERE biolog_user = $1 AND deleted_at IS NULL ORDER BY id`
+	if selErr := s.DB.SelectContext(s.context(), &d.Observations, stmt, id); selErr != nil {
+		return nil, selErr
+	}
+	stmt = `SELECT * FROM observation_comment WHERE biolog_user = $1 ORDER BY created_at, id`
+	if selErr := s.DB.SelectContext(s.context(), &d.Comments, stmt, id); selErr != nil {
+		return nil, selErr
+	}
+	stmt = `SELECT * FROM identification WHERE biolog_user = $1 ORDER BY created_at, id`
+	if selErr := s.DB.SelectContext(s.context(), &d.Identifications, stmt, id); selErr != nil {
+		return nil, selErr
+	}
+
+	return d, nil
+}
+
+// exportColumns so stolpci tabele personal_export (povezava za prenos se sestavi v handlerju)
+const exportColumns = `id, biolog_user, status, path, created_at, expires_at`
+
+// CreateExport shrani nov izvoz osebnih podatkov
+func (s *UserService) CreateExport(e biolog.Export) error {
+	stmt := `INSERT INTO personal_export (` + exportColumns + `) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := s.DB.ExecContext(s.context(), stmt, e.ID, e.User, e.Status, e.Path, e.CreatedAt, e.ExpiresAt)
+	return err
+}
+
+// Export vrne stanje izvoza z dolocenim identifikatorjem
+func (s *UserService) Export(id string) (*biolog.Export, error) {
+	e := &biolog.Export{}
+	stmt := `SELECT ` + exportColumns + ` FROM personal_export WHERE id = $1`
+	if getErr := s.DB.GetContext(s.context(), e, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Izvoz ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	return e, nil
+}
+
+// UpdateExport posodobi stanje, pot do datoteke in cas poteka izvoza
+func (s *UserService) UpdateExport(e biolog.Export) error {
+	stmt := `UPDATE personal_export SET status = $2, path = $3, expires_at = COALESCE($4, expires_at) WHERE id = $1`
+	result, err := s.DB.ExecContext(s.context(), stmt, e.ID, e.Status, e.Path, e.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Izvoz ne obstaja")
+	}
+
+	return nil
+}
+
+// DeleteExpiredExports odstrani izvoze, ki so potekli pred now, in jih vrne (za brisanje datotek)
+func (s *UserService) DeleteExpiredExports(now time.Time) ([]biolog.Export, error) {
+	stmt := `DELETE FROM personal_export WHERE expires_at < $1 RETURNING ` + exportColumns
+	expired := []biolog.Export{}
+	if selErr := s.DB.SelectContext(s.context(), &expired, stmt, now); selErr != nil {
+		return nil, selErr
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,60 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPersonalData preveri zbiranje podatkov uporabnika za izvoz
+func TestPersonalData(t *testing.T) {
+	// Opazanje 1 je od uporabnika 10000003 v scripts/sample-data.sql
+	d, err := userServiceTest.PersonalData(10000003)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 10000003, *d.User.ID)
+		if assert.NotEmpty(t, d.Observations) {
+			assert.Equal(t, 1, *d.Observations[0].ID)
+		}
+		for _, o := range d.Observations {
+			assert.Equal(t, 10000003, *o.User)
+		}
+	}
+
+	_, err = userServiceTest.PersonalData(99999999)
+	assert.Error(t, err)
+}
+
+// TestExports preveri shranjevanje, posodabljanje in odstranjevanje poteklih izvozov
+func TestExports(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	expires := now.Add(time.Hour)
+	id := "test-" + now.Format("20060102150405.000000000")
+	e := biolog.Export{ID: id, User: 10000003, Status: biolog.ExportPending, CreatedAt: now, ExpiresAt: &expires}
+	if !assert.NoError(t, userServiceTest.CreateExport(e)) {
+		return
+	}
+	assert.Error(t, userServiceTest.CreateExport(e))
+
+	ready := biolog.Export{ID: id, Status: biolog.ExportReady, Path: "/tmp/biolog-export.zip"}
+	assert.NoError(t, userServiceTest.UpdateExport(ready))
+	got, err := userServiceTest.Export(id)
+	if assert.NoError(t, err) {
+		assert.Equal(t, biolog.ExportReady, got.Status)
+		assert.Equal(t, ready.Path, got.Path)
+		assert.True(t, expires.Equal(*got.ExpiresAt))
+	}
+	assert.Error(t, userServiceTest.UpdateExport(biolog.Export{ID: "ni-izvoza"}))
+
+	expired, err := userServiceTest.DeleteExpiredExports(expires.Add(time.Second))
+	if assert.NoError(t, err) {
+		paths := []string{}
+		for _, e := range expired {
+			paths = append(paths, e.Path)
+		}
+		assert.Contains(t, paths, ready.Path)
+	}
+	_, err = userServiceTest.Export(id)
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// WatchSpecies doda vrsto med vrste, ki jih uporabnik opazuje. Pri sinonimu se shrani sprejeta vrsta
+func (s *SpeciesService) WatchSpecies(userID, gbifKey int) error {
+	var accepted int
+	if err := s.DB.GetContext(s.context(), &accepted, `SELECT COALESCE(accepted_key, id) FROM species WHERE id = $1`, gbifKey); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("Vrsta s tem GBIF ID ne obstaja")
+		}
+		return err
+	}
+	stmt := `INSERT INTO species_watch (biolog_user, species) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := s.DB.ExecContext(s.context(), stmt, userID, accepted); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolation {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+		return err
+	}
+	return nil
+}
+
+// UnwatchSpecies odstrani vrsto iz vrst, ki jih uporabnik opazuje
+func (s *SpeciesService) UnwatchSpecies(userID, gbifKey int) error {
+	stmt := `DELETE FROM species_watch WHERE biolog_user = $1
+		AND species = (SELECT COALESCE(accepted_key, id) FROM species WHERE id = $2)`
+	result, err := s.DB.ExecContext(s.context(), stmt, userID, gbifKey)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Uporabnik te vrste ne opazuje")
+	}
+	return nil
+}
+
+// WatchedSpecies vrne vrste, ki jih uporabnik opazuje, urejene po kanonicnem imenu
+func (s *SpeciesService) WatchedSpecies(userID int) ([]biolog.Species, error) {
+	stmt := `SELECT species.* FROM species_watch JOIN species ON species.id = species_watch.species
+		WHERE species_watch.biolog_user = $1 ORDER BY species.canonical_name, species.id`
+	sps := []biolog.Species{}
+	if err := s.DB.SelectContext(s.context(), &sps, stmt, userID); err != nil {
+		return nil, err
+	}
+	return sps, nil
+}
+
+// feedRow je dogodek v viru dejavnosti, preden se mu dodajo opazanje, identifikacija ali komentar
+type feedRow struct {
+	Type        string
+	Item        int
+	Observation int
+	User        int       `db:"biolog_user"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// Dogodki virov dejavnosti. Vsak del poizvedbe po indeksu (uporabnik ali vrsta, cas) prebere le najnovejse
+// zapise za polozajem ($2, $3, $4) in jih omeji na limit, zato je poizvedba hitra tudi pri nekaj sto spremljanih
+// uporabnikih. Vidnost opazanja se preveri v vsakem delu posebej, da planer ne prebere vseh javnih opazanj.
+// Vidni so le dogodki na javnih, neizbrisanih opazanjih, lastni dogodki uporabnika niso del vira
+const feedQuery = `WITH followed AS (SELECT followee FROM user_follow WHERE follower = $1),
+	watched AS (SELECT species FROM species_watch WHERE biolog_user = $1)
+	(SELECT 'observation' AS type, id AS item, id AS observation, biolog_user, created_at FROM observation
+		WHERE (created_at, 'observation', id) < ($2, $3::text, $4) AND biolog_user <> $1
+			AND observation.deleted_at IS NULL AND ` + publicObservation + `
+			AND (biolog_user IN (SELECT followee FROM followed) OR species IN (SELECT species FROM watched))
+		ORDER BY created_at DESC, id DESC LIMIT $5)
+	UNION ALL
+	(SELECT 'identification', id, observation, biolog_user, created_at FROM identification
+		WHERE (created_at, 'identification', id) < ($2, $3::text, $4) AND biolog_user <> $1
+			AND (biolog_user IN (SELECT followee FROM followed) OR species IN (SELECT species FROM watched))
+			AND EXISTS (SELECT 1 FROM observation WHERE observation.id = identification.observation
+				AND observation.deleted_at IS NULL AND ` + publicObservation + `)
+		ORDER BY created_at DESC, id DESC LIMIT $5)
+	UNION ALL
+	(SELECT 'comment', id, observation, biolog_user, created_at FROM observation_comment
+		WHERE (created_at, 'comment', id) < ($2, $3::text, $4) AND biolog_user IN (SELECT followee FROM followed)
+			AND EXISTS (SELECT 1 FROM observation WHERE observation.id = observation_comment.observation
+				AND observation.deleted_at IS NULL AND ` + publicObservation + `)
+		ORDER BY created_at DESC, id DESC LIMIT $5)
+	ORDER BY created_at DESC, type DESC, item DESC
+	LIMIT $5`
+
+// Feed vrne najvec limit najnovejsih dogodkov za polozajem before: javna opazanja in identifikacije spremljanih
+// uporabnikov in opazovanih vrst ter komentarje spremljanih uporabnikov
+func (s *SpeciesService) Feed(userID int, before biolog.FeedCursor, limit int) ([]biolog.FeedItem, error) {
+	rows := []feedRow{}
+	if err := s.DB.SelectContext(s.context(), &rows, feedQuery, userID, before.CreatedAt, before.Type, before.Item, limit); err != nil {
+		return nil, err
+	}
+
+	// Opazanja, identifikacije in komentarje strani prebere z eno poizvedbo za vsako vrsto zapisa
+	var observationIDs, identificationIDs, commentIDs []int64
+	for _, r := range rows {
+		observationIDs = append(observationIDs, int64(r.Observation))
+		switch r.Type {
+		case biolog.FeedIdentification:
+			identificationIDs = append(identificationIDs, int64(r.Item))
+		case biolog.FeedComment:
+			commentIDs = append(commentIDs, int64(r.Item))
+		}
+	}
+	observations := []biolog.Observation{}
+	if err := s.DB.SelectContext(s.context(), &observations, `SELECT * FROM observation WHERE id = ANY($1)`, pq.Array(observationIDs)); err != nil {
+		return nil, err
+	}
+	identifications := []biolog.Identification{}
+	if err := s.DB.SelectContext(s.context(), &identifications, `SELECT * FROM identification WHERE id = ANY($1)`, pq.Array(identificationIDs)); err != nil {
+		return nil, err
+	}
+	comments := []biolog.Comment{}
+	if err := s.DB.SelectContext(s.context(), &comments, `SELECT * FROM observation_comment WHERE id = ANY($1)`, pq.Array(commentIDs)); err != nil {
+		return nil, err
+	}
+
+	byObservation := make(map[int]*biolog.Observation)
+	for i := range observations {
+		byObservation[*observations[i].ID] = &observations[i]
+	}
+	byIdentification := make(map[int]*biolog.Identification)
+	for i := range identifications {
+		byIdentification[*identifications[i].ID] = &identifications[i]
+	}
+	byComment := make(map[int]*biolog.Comment)
+	for i := range comments {
+		byComment[*comments[i].ID] = &comments[i]
+	}
+
+	items := []biolog.FeedItem{}
+	for _, r := range rows {
+		item := biolog.FeedItem{Type: r.Type, CreatedAt: r.CreatedAt, User: r.User, Observation: byObservation[r.Observation]}
+		switch r.Type {
+		case biolog.FeedIdentification:
+			item.Identification = byIdentification[r.Item]
+		case biolog.FeedComment:
+			item.Comment = byComment[r.Item]
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeed preveri spremljanje uporabnikov, opazovanje vrst in vsebino vira dejavnosti
+func TestFeed(t *testing.T) {
+	me, own, _ := createEraseTestUser(t, 20)
+	followed, public, private := createEraseTestUser(t, 21)
+	defer userServiceTest.EraseUser(*me.ID, false, biolog.Actor{User: *me.ID})
+	defer userServiceTest.EraseUser(*followed.ID, false, biolog.Actor{User: *followed.ID})
+
+	assert.Error(t, userServiceTest.FollowUser(*me.ID, *me.ID))
+	assert.Error(t, userServiceTest.FollowUser(*me.ID, 99999999))
+	if !assert.NoError(t, userServiceTest.FollowUser(*me.ID, *followed.ID)) {
+		return
+	}
+	assert.NoError(t, userServiceTest.FollowUser(*me.ID, *followed.ID))
+	following, err := userServiceTest.Following(*me.ID)
+	if assert.NoError(t, err) && assert.Len(t, following, 1) {
+		assert.Equal(t, *followed.ID, *following[0].ID)
+	}
+
+	// V viru so javno opazanje in komentar spremljanega uporabnika, zasebno opazanje in lastni dogodki ne
+	items, err := speciesServiceTest.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Minute)}, 100)
+	if assert.NoError(t, err) {
+		ids := map[string][]int{}
+		for _, item := range items {
+			assert.Equal(t, *followed.ID, item.User)
+			ids[item.Type] = append(ids[item.Type], *item.Observation.ID)
+		}
+		assert.Contains(t, ids[biolog.FeedObservation], *public.ID)
+		assert.NotContains(t, ids[biolog.FeedObservation], *private.ID)
+		assert.NotContains(t, ids[biolog.FeedObservation], *own.ID)
+		assert.Contains(t, ids[biolog.FeedComment], 2)
+	}
+
+	// Dogodki z enakim casom se na mejah strani ne izgubijo ali podvojijo
+	created := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	second, err := speciesServiceTest.CreateObservation(&biolog.Observation{SightingTime: public.SightingTime,
+		SightingLocation: public.SightingLocation, Quantity: public.Quantity, PublicVisibility: public.PublicVisibility,
+		User: followed.ID, Species: public.Species})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = speciesServiceTest.DB.Exec(`UPDATE observation SET created_at = $1 WHERE id IN ($2, $3)`,
+		created, *public.ID, *second.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	cursor := biolog.FeedCursor{CreatedAt: created.Add(time.Microsecond)}
+	var paged []int
+	for i := 0; i < 3; i++ {
+		page, err := speciesServiceTest.Feed(*me.ID, cursor, 1)
+		if !assert.NoError(t, err) || len(page) == 0 {
+			break
+		}
+		if page[0].CreatedAt.Equal(created) {
+			paged = append(paged, *page[0].Observation.ID)
+		}
+		cursor = page[0].Position()
+	}
+	assert.ElementsMatch(t, []int{*public.ID, *second.ID}, paged)
+
+	assert.NoError(t, userServiceTest.UnfollowUser(*me.ID, *followed.ID))
+	assert.Error(t, userServiceTest.UnfollowUser(*me.ID, *followed.ID))
+
+	// Opazovana vrsta doda opazanja drugih uporabnikov
+	assert.NoError(t, speciesServiceTest.WatchSpecies(*me.ID, *public.Species))
+	watched, err := speciesServiceTest.WatchedSpecies(*me.ID)
+	if assert.NoError(t, err) && assert.Len(t, watched, 1) {
+		assert.Equal(t, *public.Species, *watched[0].ID)
+	}
+	items, err = speciesServiceTest.Feed(*me.ID, biolog.FeedCursor{CreatedAt: time.Now().Add(time.Minute)}, 1)
+	if assert.NoError(t, err) && assert.Len(t, items, 1) {
+		assert.NotEqual(t, *me.ID, items[0].User)
+	}
+	assert.NoError(t, speciesServiceTest.UnwatchSpecies(*me.ID, *public.Species))
+}
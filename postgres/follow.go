@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// FollowUser doda uporabnika userID med uporabnike, ki jih followerID spremlja. Ponovno spremljanje ni napaka
+func (s *UserService) FollowUser(followerID, userID int) error {
+	if followerID == userID {
+		return errors.New("Uporabnik ne more spremljati samega sebe")
+	}
+	// Izbrisanih (tudi trajno) uporabnikov ni mogoce spremljati
+	stmt := `INSERT INTO user_follow (follower, followee)
+		SELECT $1, id FROM biolog_user WHERE id = $2 AND deleted_at IS NULL AND erased_at IS NULL
+		ON CONFLICT DO NOTHING`
+	result, err := s.DB.ExecContext(s.context(), stmt, followerID, userID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolation {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var exists bool
+		stmt = `SELECT EXISTS (SELECT 1 FROM user_follow WHERE follower = $1 AND followee = $2)`
+		if err := s.DB.GetContext(s.context(), &exists, stmt, followerID, userID); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+	}
+	return nil
+}
+
+// UnfollowUser preneha spremljati uporabnika userID
+func (s *UserService) UnfollowUser(followerID, userID int) error {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM user_follow WHERE follower = $1 AND followee = $2`, followerID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Uporabnik tega uporabnika ne spremlja")
+	}
+	return nil
+}
+
+// Following vrne uporabnike, ki jih uporabnik spremlja, od nazadnje dodanega naprej
+func (s *UserService) Following(userID int) ([]biolog.User, error) {
+	stmt := `SELECT biolog_user.* FROM user_follow JOIN biolog_user ON biolog_user.id = user_follow.followee
+		WHERE user_follow.follower = $1 AND biolog_user.deleted_at IS NULL
+		ORDER BY user_follow.created_at DESC, biolog_user.id`
+	us := []biolog.User{}
+	if err := s.DB.SelectContext(s.context(), &us, stmt, userID); err != nil {
+		return nil, err
+	}
+	return us, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rubinda/biolog"
+)
+
+// Identification vrne predlog vrste z dolocenim ID
+func (s *SpeciesService) Identification(id int) (*biolog.Identification, error) {
+	stmt := `SELECT * FROM identification WHERE id = $1`
+	i := &biolog.Identification{}
+
+	if getErr := s.DB.GetContext(s.context(), i, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Identifikacija s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	return i, nil
+}
+
+// Identifications vrne vse predloge vrst za doloceno opazanje, urejene po casu
+func (s *SpeciesService) Identifications(observationID int) ([]biolog.Identification, error) {
+	stmt := `SELECT * FROM identification WHERE observation = $1 ORDER BY created_at, id`
+	ids := []biolog.Identification{}
+
+	if selErr := s.DB.SelectContext(s.context(), &ids, stmt, observationID); selErr != nil {
+		return nil, selErr
+	}
+
+	return ids, nil
+}
+
+// CreateIdentification shrani nov predlog vrste. Prejsnji predlog istega uporabnika za isto
+// opazanje ni vec trenuten. Vrsta skupnosti in stopnja kakovosti opazanja se izracunata v isti transakciji
+func (s *SpeciesService) CreateIdentification(i *biolog.Identification) (*biolog.Identification, error) {
+	if i.Observation == nil || i.User == nil || i.Species == nil {
+		return nil, errors.New("Identifikacija mora imeti opazanje, uporabnika in vrsto")
+	}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prejsnji predlog uporabnika ni vec trenuten
+	stmt := `UPDATE identification SET current = FALSE WHERE observation = $1 AND biolog_user = $2 AND current`
+	if _, err := tx.Exec(stmt, *i.Observation, *i.User); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	newID := biolog.Identification{}
+	stmt = `INSERT INTO identification (observation, biolog_user, species, comment)
+		VALUES ($1, $2, $3, $4) RETURNING *`
+	if err := tx.Get(&newID, stmt, *i.Observation, *i.User, *i.Species, i.Comment); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := updateConsensus(tx, *i.Observation); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &newID, tx.Commit()
+}
+
+// WithdrawIdentification umakne predlog vrste (ni vec trenuten) in ponovno izracuna soglasje
+func (s *SpeciesService) WithdrawIdentification(id int) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+
+	var observationID int
+	stmt := `UPDATE identification SET current = FALSE WHERE id = $1 RETURNING observation`
+	if err := tx.Get(&observationID, stmt, id); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return errors.New("Identifikacija s tem ID ne obstaja")
+		}
+		return err
+	}
+
+	if err := updateConsensus(tx, observationID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateConsensus ponovno izracuna vrsto skupnosti in stopnjo kakovosti opazanja znotraj transakcije.
+// Vrstica opazanja se zaklene, da socasne identifikacije ne prepisejo rezultata druga drugi
+func updateConsensus(tx *sqlx.Tx, observationID int) error {
+	ob := biolog.Observation{}
+	if err := tx.Get(&ob, `SELECT * FROM observation WHERE id = $1 FOR UPDATE`, observationID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("Opazanje s tem ID ne obstaja")
+		}
+		return err
+	}
+
+	ids := []biolog.Identification{}
+	if err := tx.Select(&ids, `SELECT * FROM identification WHERE observation = $1 AND current`, observationID); err != nil {
+		return err
+	}
+
+	taxon, grade := biolog.Consensus(ob, ids)
+	_, err := tx.Exec(`UPDATE observation SET community_taxon = $1, quality_grade = $2 WHERE id = $3`,
+		taxon, grade, observationID)
+	return err
+}
@@ -0,0 +1,49 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCreateIdentification preveri, da se ob identifikaciji v isti transakciji
+// posodobita vrsta skupnosti in stopnja kakovosti opazanja
+func TestCreateIdentification(t *testing.T) {
+	// Opazanje 2 je javno opazanje uporabnika 10000003, glej scripts/sample-data.sql
+	observation, expert, species := 2, 10000001, 5231190
+
+	i, createErr := speciesServiceTest.CreateIdentification(&biolog.Identification{
+		Observation: &observation, User: &expert, Species: &species})
+	if assert.NoError(t, createErr) {
+		assert.True(t, *i.Current)
+
+		var grade string
+		getErr := speciesServiceTest.DB.Get(&grade, `SELECT quality_grade FROM observation WHERE id = $1`, observation)
+		if assert.NoError(t, getErr) {
+			assert.Equal(t, biolog.GradeResearch, grade)
+		}
+
+		// Po umiku identifikacije opazanje spet potrebuje identifikacijo
+		if assert.NoError(t, speciesServiceTest.WithdrawIdentification(*i.ID)) {
+			getErr = speciesServiceTest.DB.Get(&grade, `SELECT quality_grade FROM observation WHERE id = $1`, observation)
+			if assert.NoError(t, getErr) {
+				assert.Equal(t, biolog.GradeNeedsID, grade)
+			}
+		}
+	}
+}
+
+// TestIdentifications preveri pridobivanje vseh identifikacij za opazanje
+func TestIdentifications(t *testing.T) {
+	observation := 2
+	ids, getErr := speciesServiceTest.Identifications(observation)
+	if assert.NoError(t, getErr) {
+		actual := []biolog.Identification{}
+		selectErr := speciesServiceTest.DB.Select(&actual,
+			`SELECT * FROM identification WHERE observation = $1 ORDER BY created_at, id`, observation)
+		if assert.NoError(t, selectErr) {
+			assert.Equal(t, actual, ids)
+		}
+	}
+}
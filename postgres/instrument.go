@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog/metrics"
+	"github.com/rubinda/biolog/tracing"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ime gonilnika, ki ovije pq, meri trajanje vseh poizvedb in zanje ustvarja spane (glej Open)
+const instrumentedDriver = "postgres-instrumented"
+
+func init() {
+	sql.Register(instrumentedDriver, measuredDriver{})
+}
+
+// measuredDriver odpira povezave pq, ki merijo trajanje poizvedb
+type measuredDriver struct{}
+
+// Open odpre povezavo pq in jo ovije
+func (measuredDriver) Open(name string) (driver.Conn, error) {
+	cn, err := pq.Driver{}.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &measuredConn{Conn: cn}, nil
+}
+
+// measuredConn je povezava pq, ki ob vsaki poizvedbi zabelezi njeno trajanje in ustvari span. Neobvezne
+// vmesnike database/sql/driver poda povezavi pq, ce jih ta implementira (odvisno od razlicice pq), sicer se
+// obnasa enako kot database/sql pri povezavi brez njih
+type measuredConn struct {
+	driver.Conn
+	// Kontekst, s katerim se je zacela trenutna transakcija
+	txCtx context.Context
+}
+
+// QueryContext izvede poizvedbo in zabelezi njeno trajanje (brez branja vrstic)
+func (cn *measuredConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start, span := time.Now(), cn.startSpan(ctx, query)
+	rows, err := cn.query(ctx, query, args)
+	observe(query, start, span, err)
+	return rows, err
+}
+
+// query izvede poizvedbo na povezavi pq. Brez Queryer vrne driver.ErrSkip, da database/sql stavek pripravi
+func (cn *measuredConn) query(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := cn.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	queryer, ok := cn.Conn.(driver.Queryer)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return queryer.Query(query, values)
+}
+
+// ExecContext izvede stavek in zabelezi njegovo trajanje
+func (cn *measuredConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start, span := time.Now(), cn.startSpan(ctx, query)
+	res, err := cn.exec(ctx, query, args)
+	observe(query, start, span, err)
+	return res, err
+}
+
+// exec izvede stavek na povezavi pq. Brez Execer vrne driver.ErrSkip, da database/sql stavek pripravi
+func (cn *measuredConn) exec(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := cn.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	execer, ok := cn.Conn.(driver.Execer)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return execer.Exec(query, values)
+}
+
+// namedValues pretvori argumente za gonilnik brez podpore kontekstu, ki ne pozna poimenovanih parametrov
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("Gonilnik ne podpira poimenovanih parametrov")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// PrepareContext pripravi stavek, enako kot database/sql pri povezavi brez ConnPrepareContext
+func (cn *measuredConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := cn.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return cn.Conn.Prepare(query)
+}
+
+// BeginTx zacne transakcijo in si zapomni njen kontekst. database/sql stavkom v transakciji brez konteksta
+// (tx.Exec, tx.Get ...) poda prazen kontekst, zato so njihovi spani otroci konteksta transakcije
+func (cn *measuredConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := cn.begin(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	cn.txCtx = ctx
+	return &measuredTx{Tx: tx, cn: cn}, nil
+}
+
+// begin zacne transakcijo na povezavi pq. Brez ConnBeginTx so mozne le privzete nastavitve transakcije
+func (cn *measuredConn) begin(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := cn.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, errors.New("Gonilnik ne podpira nastavitev transakcije")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return cn.Conn.Begin()
+}
+
+// Ping preveri povezavo, ce jo povezava pq podpira
+func (cn *measuredConn) Ping(ctx context.Context) error {
+	if pinger, ok := cn.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession pripravi povezavo za ponovno uporabo, ce povezava pq to podpira
+func (cn *measuredConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := cn.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid pove ali je povezavo mogoce ponovno uporabiti. Povezava pq brez Validator velja za veljavno
+func (cn *measuredConn) IsValid() bool {
+	if validator, ok := cn.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// startSpan zacne span poizvedbe kot otroka spana v ctx oz. v kontekstu transakcije. Poizvedbe brez
+// starsa (npr. v ozadju) spanov nimajo, vrne nil
+func (cn *measuredConn) startSpan(ctx context.Context, query string) trace.Span {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if cn.txCtx == nil || !trace.SpanContextFromContext(cn.txCtx).IsValid() {
+			return nil
+		}
+		ctx = cn.txCtx
+	}
+	name := metrics.QueryName(query)
+	_, span := tracing.Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBOperationName(strings.Fields(name)[0]),
+		semconv.DBQueryText(query),
+	))
+	return span
+}
+
+// observe zabelezi trajanje poizvedbe in zakljuci njen span. driver.ErrSkip pomeni, da database/sql
+// poizvedbo izvede drugace, zato se ne steje
+func observe(query string, start time.Time, span trace.Span, err error) {
+	if err != driver.ErrSkip {
+		metrics.ObserveQuery(query, start, err)
+	}
+	if span == nil {
+		return
+	}
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// measuredTx ob koncu transakcije pozabi njen kontekst
+type measuredTx struct {
+	driver.Tx
+	cn *measuredConn
+}
+
+// Commit potrdi transakcijo
+func (tx *measuredTx) Commit() error {
+	tx.cn.txCtx = nil
+	return tx.Tx.Commit()
+}
+
+// Rollback razveljavi transakcijo
+func (tx *measuredTx) Rollback() error {
+	tx.cn.txCtx = nil
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/rubinda/biolog"
+)
+
+// MergeSpecies zdruzi vrsto fromKey v sprejeto vrsto intoKey v eni transakciji: preusmeri opazanja in
+// identifikacije, ponovno izracuna soglasje skupnosti, oznaci staro vrsto (in njene sinonime) kot sinonim
+// ter shrani zapis o zdruzitvi in revizijsko sled
+func (s *SpeciesService) MergeSpecies(fromKey, intoKey int, by biolog.Actor) (*biolog.TaxonMerge, error) {
+	if fromKey == intoKey {
+		return nil, errors.New("Vrste ni mogoce zdruziti same vase")
+	}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zaklene obe vrsti, da se med zdruzevanjem ne spremenita
+	var accepted []sql.NullInt64
+	stmt := `SELECT accepted_key FROM species WHERE id = $1 OR id = $2 ORDER BY id = $2 FOR UPDATE`
+	if err := tx.Select(&accepted, stmt, fromKey, intoKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(accepted) != 2 {
+		tx.Rollback()
+		return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
+	}
+	if accepted[1].Valid {
+		tx.Rollback()
+		return nil, errors.New("Ciljna vrsta je sinonim, zdruzi v sprejeto vrsto")
+	}
+
+	// Opazanja, pri katerih se lahko spremeni soglasje skupnosti
+	var affected []int64
+	stmt = `SELECT id FROM observation WHERE species = $1 OR community_taxon = $1
+		OR id IN (SELECT observation FROM identification WHERE species = $1)`
+	if err := tx.Select(&affected, stmt, fromKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	result, err := tx.Exec(`UPDATE observation SET species = $2 WHERE species = $1`, fromKey, intoKey)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	observations, _ := result.RowsAffected()
+	result, err = tx.Exec(`UPDATE identification SET species = $2 WHERE species = $1`, fromKey, intoKey)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	identifications, _ := result.RowsAffected()
+	// Uporabniki, ki opazujejo staro vrsto, odslej opazujejo sprejeto vrsto
+	stmt = `INSERT INTO species_watch (biolog_user, species, created_at)
+		SELECT biolog_user, $2, created_at FROM species_watch WHERE species = $1 ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(stmt, fromKey, intoKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM species_watch WHERE species = $1`, fromKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, id := range affected {
+		if err := updateConsensus(tx, int(id)); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	before, after := &biolog.Species{}, &biolog.Species{}
+	if err := lockRow(tx, before, "species", fromKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	stmt = `UPDATE species SET accepted_key = $2 WHERE id = $1 OR accepted_key = $1`
+	if _, err := tx.Exec(stmt, fromKey, intoKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := lockRow(tx, after, "species", fromKey); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, by, biolog.AuditMerge, biolog.AuditSpecies, fromKey, before, after); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	merge := &biolog.TaxonMerge{}
+	stmt = `INSERT INTO taxon_merge (from_species, into_species, biolog_user, observations, identifications)
+		VALUES ($1, $2, $3, $4, $5) RETURNING *`
+	if err := tx.Get(merge, stmt, fromKey, intoKey, by.User, observations, identifications); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return merge, tx.Commit()
+}
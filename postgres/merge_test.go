@@ -0,0 +1,34 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergeSpecies preveri zdruzitev vrste v sprejeto vrsto in zapis o zdruzitvi
+func TestMergeSpecies(t *testing.T) {
+	// Vrsta 1 in uporabnik 10000001 sta v scripts/sample-data.sql
+	into, user := 1, 10000001
+	from, name, status := 99999901, "Synonym testus", 1
+	_, err := speciesServiceTest.CreateSpecies(&biolog.Species{ID: &from, Species: &name, ConservationStatus: &status})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	merge, err := speciesServiceTest.MergeSpecies(from, into, biolog.Actor{User: user})
+	if assert.NoError(t, err) {
+		assert.Equal(t, from, *merge.From)
+		assert.Equal(t, into, *merge.Into)
+		assert.Equal(t, 0, *merge.Observations)
+	}
+	sp, err := speciesServiceTest.Species(from)
+	if assert.NoError(t, err) {
+		assert.Equal(t, into, *sp.AcceptedKey)
+	}
+
+	// Ciljna vrsta ne sme biti sinonim
+	_, err = speciesServiceTest.MergeSpecies(into, from, biolog.Actor{User: user})
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rubinda/biolog/metrics"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQueryMetrics preveri, da povezave iz Open belezijo poizvedbe in njihove napake
+func TestQueryMetrics(t *testing.T) {
+	db, err := postgres.Open(viper.GetString("database.username"), viper.GetString("database.password"),
+		viper.GetString("database.testdb"), viper.GetString("database.host"), viper.GetString("database.sslmode"),
+		viper.GetInt("database.port"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer postgres.Close(db)
+
+	// Parametri $n morajo delovati tudi z merjenim gonilnikom
+	var n int
+	assert.NoError(t, db.Get(&n, `SELECT count(*) FROM conservation_status WHERE id > $1`, 0))
+	assert.True(t, testutil.CollectAndCount(metrics.QueryDuration, "biolog_db_query_duration_seconds") > 0)
+
+	failed := metrics.QueryErrors.WithLabelValues("select missing_table")
+	before := testutil.ToFloat64(failed)
+	assert.Error(t, db.Get(&n, `SELECT count(*) FROM missing_table`))
+	assert.Equal(t, before+1, testutil.ToFloat64(failed))
+}
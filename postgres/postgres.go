@@ -3,6 +3,7 @@
 package postgres
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
@@ -15,12 +16,21 @@ import (
 const buildUpdate string = "UPDATE"
 const buildInsert string = "INSERT"
 
-// Open inicializira povezavo na podatkovno bazo PostgreSQL
+// Open inicializira povezavo na podatkovno bazo PostgreSQL. Povezave merijo trajanje poizvedb
+// (glej metrics.QueryDuration), sqlx pa jih obravnava kot postgres (parametri $1, $2 ...)
 func Open(user, password, dbname, host, sslmode string, port int) (*sqlx.DB, error) {
-	connString := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s",
+	connString := ConnString(user, password, dbname, host, sslmode, port)
+	db, error := sql.Open(instrumentedDriver, connString) // Inicializiraj povezavo na bazo
+	if error != nil {
+		return nil, error
+	}
+	return sqlx.NewDb(db, "postgres"), nil
+}
+
+// ConnString vrne niz za povezavo na podatkovno bazo PostgreSQL (npr. za NewObservationStream)
+func ConnString(user, password, dbname, host, sslmode string, port int) string {
+	return fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s",
 		user, password, dbname, host, port, sslmode)
-	DB, error := sqlx.Open("postgres", connString) // Inicializiraj povezavo na bazo
-	return DB, error
 }
 
 // Close zapre povezavo na podatkovno bazo in vraca morebitno napako pri zapiranju
@@ -132,6 +142,10 @@ func getNonNilFields(o interface{}) map[string]interface{} {
 		if fName == "" {
 			fName = strings.ToLower(field.Name)
 		}
+		// Skip fields that are not table columns
+		if fName == "-" {
+			continue
+		}
 
 		// Ignore the ID field
 		if fName == "id" {
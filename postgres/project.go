@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rubinda/biolog"
+)
+
+// projectColumns so stolpci tabele project brez meje, ki se bere posebej kot GeoJSON
+const projectColumns = `id, name, description, start_date, end_date, taxon_rank, taxon_name, members_only, created_by`
+
+// projectRules vrne SQL pogoj, ki preveri ali opazanje (observation) ustreza pravilom projekta (project).
+// Meja se preveri na lokaciji location (glej observationLocation). Enaka pravila preverja tudi inProject v paketu memory
+func projectRules(location string) string {
+	return `observation.sighting_time >= project.start_date
+		AND (project.end_date IS NULL OR observation.sighting_time < project.end_date + 1)
+		AND (project.boundary IS NULL OR ST_Intersects(project.boundary, ` + location + `))
+		AND (project.taxon_rank IS NULL OR EXISTS (SELECT 1 FROM species project_species
+			WHERE project_species.id = observation.species AND CASE project.taxon_rank
+				WHEN 'kingdom' THEN project_species.kingdom
+				WHEN 'phylum' THEN project_species.phylum
+				WHEN 'class' THEN project_species.species_class
+				WHEN 'order' THEN project_species.species_order
+				WHEN 'family' THEN project_species.species_family
+				WHEN 'genus' THEN project_species.genus END = project.taxon_name))
+		AND (NOT project.members_only OR EXISTS (SELECT 1 FROM project_member
+			WHERE project_member.project = project.id AND project_member.biolog_user = observation.biolog_user))`
+}
+
+// Project vrne projekt z dolocenim ID skupaj z mejo
+func (s *SpeciesService) Project(id int) (*biolog.Project, error) {
+	stmt := `SELECT ` + projectColumns + `, ST_AsGeoJSON(boundary) AS boundary FROM project WHERE id = $1`
+	row := struct {
+		biolog.Project
+		Boundary sql.NullString
+	}{}
+
+	if getErr := s.DB.GetContext(s.context(), &row, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Projekt s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	p := row.Project
+	if row.Boundary.Valid {
+		p.Boundary = []byte(row.Boundary.String)
+	}
+	return &p, nil
+}
+
+// Projects vrne stran projektov (brez meje), urejenih od najnovejsega naprej
+func (s *SpeciesService) Projects(p biolog.Page) ([]biolog.Project, error) {
+	stmt := `SELECT ` + projectColumns + ` FROM project ORDER BY start_date DESC, id DESC LIMIT $1 OFFSET $2`
+	projects := []biolog.Project{}
+
+	if selErr := s.DB.SelectContext(s.context(), &projects, stmt, p.Limit, p.Offset); selErr != nil {
+		return nil, selErr
+	}
+
+	return projects, nil
+}
+
+// CreateProject shrani projekt in njegovega avtorja doda med administratorje projekta v eni transakciji
+func (s *SpeciesService) CreateProject(p *biolog.Project) (*biolog.Project, error) {
+	if p.CreatedBy == nil {
+		return nil, errors.New("Projekt mora imeti avtorja")
+	}
+	row := struct {
+		biolog.Project
+		Boundary []byte
+	}{}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+	q, args := buildInsertUpdateQuery(buildInsert, "project", *p)
+	if getErr := tx.Get(&row, q, args...); getErr != nil {
+		tx.Rollback()
+		return nil, getErr
+	}
+	newProject := row.Project
+	if err := setProjectBoundary(tx, *newProject.ID, p.Boundary); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	stmt := `INSERT INTO project_member (project, biolog_user, role) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(stmt, *newProject.ID, *p.CreatedBy, biolog.ProjectRoleAdmin); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	newProject.Boundary = p.Boundary
+	return &newProject, tx.Commit()
+}
+
+// setProjectBoundary nastavi mejo projekta iz GeoJSON geometrije znotraj transakcije (nic, ce meja ni podana)
+func setProjectBoundary(tx *sqlx.Tx, id int, boundary []byte) error {
+	if len(boundary) == 0 {
+		return nil
+	}
+	stmt := `UPDATE project SET boundary = ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($1), 4326)) WHERE id = $2`
+	_, err := tx.Exec(stmt, string(boundary), id)
+	return err
+}
+
+// UpdateProject posodobi podana polja projekta in mejo, ce je podana
+func (s *SpeciesService) UpdateProject(id int, p biolog.Project) error {
+	if _, err := s.Project(id); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	// Avtorja projekta ni mogoce spremeniti
+	p.ID, p.CreatedBy = nil, nil
+	if len(getNonNilFields(p)) > 0 {
+		q, args := buildInsertUpdateQuery(buildUpdate, "project", p)
+		args = append(args, id)
+		if _, err := tx.Exec(q, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := setProjectBoundary(tx, id, p.Boundary); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteProject zbrise projekt in clanstva v njem, opazanja ostanejo
+func (s *SpeciesService) DeleteProject(id int) error {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM project WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Projekt s tem ID ne obstaja")
+	}
+
+	return nil
+}
+
+// ProjectMembers vrne vse clane projekta (brez izbrisanih uporabnikov), najprej administratorje
+func (s *SpeciesService) ProjectMembers(projectID int) ([]biolog.ProjectMember, error) {
+	stmt := `SELECT project_member.* FROM project_member
+		JOIN biolog_user ON biolog_user.id = project_member.biolog_user AND biolog_user.deleted_at IS NULL
+		WHERE project_member.project = $1 ORDER BY project_member.role, project_member.biolog_user`
+	members := []biolog.ProjectMember{}
+
+	if selErr := s.DB.SelectContext(s.context(), &members, stmt, projectID); selErr != nil {
+		return nil, selErr
+	}
+
+	return members, nil
+}
+
+// SetProjectMember doda uporabnika v projekt ali mu spremeni vlogo
+func (s *SpeciesService) SetProjectMember(m biolog.ProjectMember) error {
+	if m.Project == nil || m.User == nil || m.Role == nil || !biolog.ValidProjectRole(*m.Role) {
+		return errors.New("Clan projekta mora imeti projekt, uporabnika in veljavno vlogo")
+	}
+	stmt := `INSERT INTO project_member (project, biolog_user, role) VALUES ($1, $2, $3)
+		ON CONFLICT (project, biolog_user) DO UPDATE SET role = EXCLUDED.role`
+
+	_, err := s.DB.ExecContext(s.context(), stmt, *m.Project, *m.User, *m.Role)
+	return err
+}
+
+// RemoveProjectMember odstrani uporabnika iz projekta
+func (s *SpeciesService) RemoveProjectMember(projectID, userID int) error {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM project_member WHERE project = $1 AND biolog_user = $2`, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("Uporabnik ni clan projekta")
+	}
+
+	return nil
+}
+
+// ProjectLeaderboard vrne uporabnike z javnimi opazanji v projektu, urejene po stevilu opazanj in vrst.
+// Ce obscure ni nil, se meja projekta preveri na posplosenih lokacijah obcutljivih vrst
+func (s *SpeciesService) ProjectLeaderboard(projectID int, obscure *biolog.SensitivityPolicy) ([]biolog.LeaderboardEntry, error) {
+	where, args := observationConditions(biolog.ObservationFilter{Project: &projectID, Obscure: obscure})
+	stmt := fmt.Sprintf(`SELECT observation.biolog_user, count(*) AS observations,
+		count(DISTINCT observation.species) AS species
+		FROM observation %s GROUP BY 1 ORDER BY 2 DESC, 3 DESC, 1`, where)
+	entries := []biolog.LeaderboardEntry{}
+
+	if selErr := s.DB.SelectContext(s.context(), &entries, stmt, args...); selErr != nil {
+		return nil, selErr
+	}
+
+	return entries, nil
+}
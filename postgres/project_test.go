@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProjects preveri samodejno pripadnost opazanj projektu glede na obdobje in mejo ter lestvico
+func TestProjects(t *testing.T) {
+	// Uporabnik 10000001 in vrsta 1 sta v scripts/sample-data.sql
+	user, species := 10000001, 1
+	name := "Bioblitz"
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	boundary := json.RawMessage(`{"type":"Polygon","coordinates":[[[14.4,46.0],[14.6,46.0],[14.6,46.1],[14.4,46.1],[14.4,46.0]]]}`)
+	project, err := speciesServiceTest.CreateProject(&biolog.Project{Name: &name, StartDate: &start, CreatedBy: &user,
+		Boundary: boundary})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer speciesServiceTest.DeleteProject(*project.ID)
+
+	members, err := speciesServiceTest.ProjectMembers(*project.ID)
+	if assert.NoError(t, err) && assert.Len(t, members, 1) {
+		assert.Equal(t, biolog.ProjectRoleAdmin, *members[0].Role)
+	}
+
+	// Prvo opazanje je znotraj meje, drugo zunaj
+	quantity, public := 1, true
+	for _, loc := range []string{"POINT(14.5058 46.0569)", "POINT(15.6459 46.5547)"} {
+		loc, sightingTime := loc, time.Now().UTC()
+		ob, err := speciesServiceTest.CreateObservation(&biolog.Observation{SightingTime: &sightingTime, SightingLocation: &loc,
+			Quantity: &quantity, PublicVisibility: &public, User: &user, Species: &species})
+		if assert.NoError(t, err) {
+			defer speciesServiceTest.DeleteObservation(*ob.ID, biolog.Actor{User: user})
+		}
+	}
+
+	obs, err := speciesServiceTest.Observations(biolog.ObservationFilter{Project: project.ID})
+	if assert.NoError(t, err) {
+		assert.Len(t, obs, 1)
+	}
+	leaderboard, err := speciesServiceTest.ProjectLeaderboard(*project.ID, nil)
+	if assert.NoError(t, err) && assert.Len(t, leaderboard, 1) {
+		assert.Equal(t, biolog.LeaderboardEntry{User: user, Observations: 1, Species: 1}, leaderboard[0])
+	}
+
+	got, err := speciesServiceTest.Project(*project.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, name, *got.Name)
+		assert.NotEmpty(t, got.Boundary)
+	}
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rubinda/biolog"
+)
+
+// RateLimiter predstavlja PostgreSQL implementacijo od biolog.RateLimiter. Stevci so skupni vsem instancam
+// streznika, ki uporabljajo isto bazo
+type RateLimiter struct {
+	DB *sqlx.DB
+}
+
+// Preveri ali RateLimiter implementira vse metode
+var _ biolog.RateLimiter = &RateLimiter{}
+
+// Stevilo zetonov v obstojecem vedru ob tej zahtevi ($2 je velikost vedra, $3 hitrost polnjenja na sekundo)
+const refillTokens = `LEAST($2, rate_limit.tokens + GREATEST(EXTRACT(EPOCH FROM now() - rate_limit.updated_at), 0) * $3)`
+
+// Vedro se napolni in zmanjsa v enem stavku, zato si hkratne zahteve zetonov ne delijo (vrstico zaklene ON CONFLICT)
+var takeTokenStmt = strings.Replace(`INSERT INTO rate_limit (key, tokens, allowed) VALUES ($1, $2 - 1, TRUE)
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = {refill} - CASE WHEN {refill} >= 1 THEN 1 ELSE 0 END,
+		allowed = {refill} >= 1,
+		updated_at = now()
+	RETURNING tokens, allowed`, "{refill}", refillTokens, -1)
+
+// Take iz vedra key vzame en zeton, ce ga ima, in vrne stanje vedra po zahtevi
+func (s *RateLimiter) Take(key string, limit biolog.RateLimit) (biolog.RateLimitResult, error) {
+	var bucket struct {
+		Tokens  float64 `db:"tokens"`
+		Allowed bool    `db:"allowed"`
+	}
+	if getErr := s.DB.Get(&bucket, takeTokenStmt, key, float64(limit.Burst), limit.Rate()); getErr != nil {
+		return biolog.RateLimitResult{}, getErr
+	}
+
+	return limit.Result(bucket.Tokens, bucket.Allowed), nil
+}
+
+// Prune odstrani vedra, ki niso bila uporabljena od before
+func (s *RateLimiter) Prune(before time.Time) (int, error) {
+	res, err := s.DB.Exec(`DELETE FROM rate_limit WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
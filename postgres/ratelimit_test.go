@@ -0,0 +1,34 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimiter preveri skupne stevce v bazi: porabo zetonov, zavrnitev in odstranjevanje veder
+func TestRateLimiter(t *testing.T) {
+	rl := &postgres.RateLimiter{DB: speciesServiceTest.DB}
+	limit := biolog.RateLimit{Burst: 2, Period: time.Hour}
+	key := "test:ip:192.0.2.1"
+	defer rl.Prune(time.Now().Add(time.Minute))
+
+	for i, allowed := range []bool{true, true, false} {
+		res, err := rl.Take(key, limit)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, allowed, res.Allowed, "zahteva %d", i)
+	}
+	res, _ := rl.Take(key, limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.True(t, res.RetryAfter > 0 && res.RetryAfter <= 30*time.Minute)
+
+	n, err := rl.Prune(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
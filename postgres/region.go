@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// RegionService predstavlja PostgreSQL implementacijo od biolog.RegionService
+type RegionService struct {
+	DB *sqlx.DB
+}
+
+// Preveri ali RegionService implementira vse metode
+var _ biolog.RegionService = &RegionService{}
+
+// Region vrne obmocje z dolocenim ID skupaj z geometrijo
+func (s *RegionService) Region(id int) (*biolog.Region, error) {
+	stmt := `SELECT id, kind, code, name, ST_AsGeoJSON(geom) AS geometry FROM region WHERE id = $1`
+	row := struct {
+		biolog.Region
+		Geometry string
+	}{}
+
+	if getErr := s.DB.Get(&row, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Obmocje s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	r := row.Region
+	r.Geometry = []byte(row.Geometry)
+	return &r, nil
+}
+
+// Regions vrne vsa obmocja podane vrste (ali vseh vrst, ce je kind prazen) brez geometrije
+func (s *RegionService) Regions(kind string) ([]biolog.Region, error) {
+	stmt := `SELECT id, kind, code, name FROM region WHERE $1 = '' OR kind = $1 ORDER BY kind, name`
+	regions := []biolog.Region{}
+
+	if selErr := s.DB.Select(&regions, stmt, kind); selErr != nil {
+		return nil, selErr
+	}
+
+	return regions, nil
+}
+
+// ImportRegions shrani obmocja (obstojeca z enako vrsto in kodo posodobi) in ponovno dodeli
+// vsa opazanja uvozenim obmocjem. Vrne stevilo uvozenih obmocij
+func (s *RegionService) ImportRegions(regions []biolog.Region) (int, error) {
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := `INSERT INTO region (kind, code, name, geom)
+		VALUES ($1, $2, $3, ST_Multi(ST_SetSRID(ST_GeomFromGeoJSON($4), 4326)))
+		ON CONFLICT (kind, code) DO UPDATE SET name = EXCLUDED.name, geom = EXCLUDED.geom
+		RETURNING id`
+	ids := make([]int64, 0, len(regions))
+	for _, r := range regions {
+		if r.Kind == nil || r.Code == nil || r.Name == nil || len(r.Geometry) == 0 {
+			tx.Rollback()
+			return 0, errors.New("Obmocje mora imeti vrsto, kodo, ime in geometrijo")
+		}
+		var id int64
+		if err := tx.Get(&id, stmt, *r.Kind, *r.Code, *r.Name, string(r.Geometry)); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+
+	// Ponovno dodeli opazanja uvozenim obmocjem
+	if _, err := tx.Exec(`DELETE FROM observation_region WHERE region = ANY($1)`, pq.Array(ids)); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	stmt = `INSERT INTO observation_region (observation, region)
+		SELECT observation.id, region.id FROM observation
+		JOIN region ON ST_Intersects(region.geom, observation.sighting_location::geometry)
+		WHERE region.id = ANY($1)`
+	if _, err := tx.Exec(stmt, pq.Array(ids)); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return len(ids), tx.Commit()
+}
+
+// assignRegions ponovno doloci obmocja, v katerih lezi opazanje, znotraj transakcije
+func assignRegions(tx *sqlx.Tx, observationID int) error {
+	if _, err := tx.Exec(`DELETE FROM observation_region WHERE observation = $1`, observationID); err != nil {
+		return err
+	}
+	stmt := `INSERT INTO observation_region (observation, region)
+		SELECT observation.id, region.id FROM observation
+		JOIN region ON ST_Intersects(region.geom, observation.sighting_location::geometry)
+		WHERE observation.id = $1`
+	_, err := tx.Exec(stmt, observationID)
+	return err
+}
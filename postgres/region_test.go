@@ -0,0 +1,84 @@
+package postgres_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegions preveri uvoz obmocja, ki pokriva vso Slovenijo, in dodelitev obstojecih opazanj
+func TestRegions(t *testing.T) {
+	rs := &postgres.RegionService{DB: speciesServiceTest.DB}
+	kind, code, name := "test", "SI", "Slovenija"
+	geometry := []byte(`{"type":"Polygon","coordinates":[[[13.3,45.4],[16.7,45.4],[16.7,46.9],[13.3,46.9],[13.3,45.4]]]}`)
+
+	n, err := rs.ImportRegions([]biolog.Region{{Kind: &kind, Code: &code, Name: &name, Geometry: geometry}})
+	if !assert.NoError(t, err) || !assert.Equal(t, 1, n) {
+		return
+	}
+
+	regions, err := rs.Regions(kind)
+	if !assert.NoError(t, err) || !assert.Len(t, regions, 1) {
+		return
+	}
+	region, err := rs.Region(*regions[0].ID)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(region.Geometry), "MultiPolygon")
+	}
+
+	// Stevilo opazanj v obmocju se ujema s statistiko po obmocjih
+	obs, err := speciesServiceTest.Observations(biolog.ObservationFilter{Region: region.ID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	stats, err := speciesServiceTest.ObservationStats(biolog.StatsByRegion, biolog.ObservationFilter{})
+	if assert.NoError(t, err) {
+		for _, s := range stats {
+			if s.Key == strconv.Itoa(*region.ID) {
+				assert.Equal(t, len(obs), s.Observations)
+			}
+		}
+	}
+
+	// Obmocje, manjse od celice posplosene lokacije, ne izda natancne lokacije obcutljive vrste
+	u, public, _ := createEraseTestUser(t, 32)
+	defer userServiceTest.EraseUser(*u.ID, false, biolog.Actor{User: *u.ID})
+	code, name = "SI-T", "Tromostovje"
+	geometry = []byte(`{"type":"Polygon","coordinates":[[[14.5048,46.0559],[14.5068,46.0559],[14.5068,46.0579],[14.5048,46.0579],[14.5048,46.0559]]]}`)
+	if _, err := rs.ImportRegions([]biolog.Region{{Kind: &kind, Code: &code, Name: &name, Geometry: geometry}}); !assert.NoError(t, err) {
+		return
+	}
+	regions, _ = rs.Regions(kind)
+	var small int
+	for _, r := range regions {
+		if *r.Code == code {
+			small = *r.ID
+		}
+	}
+	ids := func(obs []biolog.Observation) []int {
+		var ids []int
+		for _, o := range obs {
+			ids = append(ids, *o.ID)
+		}
+		return ids
+	}
+	policy := biolog.DefaultSensitivityPolicy([]int{*public.Species})
+	obs, err = speciesServiceTest.Observations(biolog.ObservationFilter{Region: &small})
+	if assert.NoError(t, err) {
+		assert.Contains(t, ids(obs), *public.ID)
+	}
+	obs, err = speciesServiceTest.Observations(biolog.ObservationFilter{Region: &small, Obscure: &policy})
+	if assert.NoError(t, err) {
+		assert.NotContains(t, ids(obs), *public.ID)
+	}
+	stats, err = speciesServiceTest.ObservationStats(biolog.StatsByRegion, biolog.ObservationFilter{Species: public.Species,
+		Obscure: &policy})
+	if assert.NoError(t, err) {
+		for _, s := range stats {
+			assert.NotEqual(t, strconv.Itoa(small), s.Key)
+		}
+	}
+}
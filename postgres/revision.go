@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rubinda/biolog"
+)
+
+// revisionRow je vrstica tabele observation_revision, stanje opazanja je shranjeno kot JSON
+type revisionRow struct {
+	biolog.ObservationRevision
+	Data []byte `db:"data"`
+}
+
+// revision pretvori vrstico v revizijo z razclenjenim stanjem opazanja
+func (row revisionRow) revision() (*biolog.ObservationRevision, error) {
+	r := row.ObservationRevision
+	r.Snapshot = &biolog.Observation{}
+	if err := json.Unmarshal(row.Data, r.Snapshot); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ObservationRevisions vrne vse revizije opazanja, od najstarejse naprej
+func (s *SpeciesService) ObservationRevisions(observationID int) ([]biolog.ObservationRevision, error) {
+	stmt := `SELECT * FROM observation_revision WHERE observation = $1 ORDER BY revision`
+	rows := []revisionRow{}
+	if selErr := s.DB.SelectContext(s.context(), &rows, stmt, observationID); selErr != nil {
+		return nil, selErr
+	}
+
+	revisions := make([]biolog.ObservationRevision, 0, len(rows))
+	for _, row := range rows {
+		r, err := row.revision()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *r)
+	}
+	return revisions, nil
+}
+
+// ObservationRevision vrne doloceno revizijo opazanja
+func (s *SpeciesService) ObservationRevision(observationID, revision int) (*biolog.ObservationRevision, error) {
+	return observationRevision(s.context(), s.DB, observationID, revision)
+}
+
+// RevertObservation povrne opazanje na stanje iz revizije. Povrnejo se le polja, ki jih ureja avtor, vrsta
+// pa se zamenja s sprejeto vrsto, ce je bila medtem zdruzena. Povrnitev doda novo revizijo
+func (s *SpeciesService) RevertObservation(id, revision int, by biolog.Actor) error {
+	return s.changeObservation(id, biolog.AuditRevert, by, func(tx *sqlx.Tx) error {
+		r, err := observationRevision(s.context(), tx, id, revision)
+		if err != nil {
+			return err
+		}
+		ob := r.Snapshot
+		stmt := `UPDATE observation SET sighting_time = $2, sighting_location = $3, quantity = $4,
+			public_visibility = $5, species = COALESCE((SELECT accepted_key FROM species WHERE id = $6), $6),
+			checklist = $7
+			WHERE id = $1`
+		_, err = tx.Exec(stmt, id, ob.SightingTime, ob.SightingLocation, ob.Quantity, ob.PublicVisibility,
+			ob.Species, ob.Checklist)
+		return err
+	})
+}
+
+// observationRevision prebere doloceno revizijo opazanja (iz baze ali znotraj transakcije)
+func observationRevision(ctx context.Context, q sqlx.QueryerContext, observationID, revision int) (*biolog.ObservationRevision, error) {
+	stmt := `SELECT * FROM observation_revision WHERE observation = $1 AND revision = $2`
+	row := revisionRow{}
+	if getErr := sqlx.GetContext(ctx, q, &row, stmt, observationID, revision); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Revizija opazanja ne obstaja")
+		}
+		return nil, getErr
+	}
+	return row.revision()
+}
+
+// saveRevision shrani stanje opazanja kot naslednjo revizijo. Klicatelj mora imeti zaklenjeno vrstico opazanja,
+// da se stevilke revizij ne podvojijo
+func saveRevision(tx *sqlx.Tx, ob *biolog.Observation, user int) error {
+	data, err := json.Marshal(ob)
+	if err != nil {
+		return err
+	}
+	stmt := `INSERT INTO observation_revision (observation, revision, biolog_user, data)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3 FROM observation_revision WHERE observation = $1`
+	_, err = tx.Exec(stmt, *ob.ID, user, string(data))
+	return err
+}
+
+// saveFirstRevision shrani zacetno stanje opazanja (avtor je lastnik), ce opazanje se nima revizij
+func saveFirstRevision(tx *sqlx.Tx, ob *biolog.Observation) error {
+	var exists bool
+	stmt := `SELECT EXISTS (SELECT 1 FROM observation_revision WHERE observation = $1)`
+	if err := tx.Get(&exists, stmt, *ob.ID); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return saveRevision(tx, ob, *ob.User)
+}
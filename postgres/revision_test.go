@@ -0,0 +1,45 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationRevisions preveri shranjevanje revizij ob posodobitvah in povrnitev na starejso revizijo
+func TestObservationRevisions(t *testing.T) {
+	// Opazanje 1 je od uporabnika 10000003 v scripts/sample-data.sql
+	ID, user := 1, 10000003
+	by := biolog.Actor{User: user}
+	original, err := speciesServiceTest.Observation(ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	quantity := *original.Quantity + 5
+	if !assert.NoError(t, speciesServiceTest.UpdateObservation(ID, biolog.Observation{Quantity: &quantity}, by)) {
+		return
+	}
+	revisions, err := speciesServiceTest.ObservationRevisions(ID)
+	if assert.NoError(t, err) && assert.True(t, len(revisions) >= 2) {
+		last := revisions[len(revisions)-1]
+		assert.Equal(t, quantity, *last.Snapshot.Quantity)
+		assert.Equal(t, user, *last.User)
+	}
+
+	// Povrnitev na prvo revizijo doda novo revizijo z zacetnim stanjem
+	assert.NoError(t, speciesServiceTest.RevertObservation(ID, 1, by))
+	reverted, err := speciesServiceTest.Observation(ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, *original.Quantity, *reverted.Quantity)
+	}
+	after, err := speciesServiceTest.ObservationRevisions(ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, after, len(revisions)+1)
+	}
+
+	_, err = speciesServiceTest.ObservationRevision(ID, 9999)
+	assert.Error(t, err)
+	assert.Error(t, speciesServiceTest.RevertObservation(ID, 9999, by))
+}
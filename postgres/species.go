@@ -1,11 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // dodatek PostgreSQL
+	"github.com/lib/pq"
 	"github.com/rubinda/biolog"
 	//	log "github.com/sirupsen/logrus"
 )
@@ -13,39 +17,74 @@ import (
 // SpeciesService predstavlja PostgreSQL implementacijo od biolog.SpeciesService
 type SpeciesService struct {
 	DB *sqlx.DB
+	// Pravilo za obcutljive vrste, po katerem se preverijo filtri narocnin in posplosi lokacija v dogodkih.
+	// Ce je nil, velja biolog.DefaultSensitivityPolicy brez dodatnih vrst
+	Sensitivity *biolog.SensitivityPolicy
+	// Kontekst poizvedb (glej WithContext)
+	ctx context.Context
 }
 
-// Species vrne doloceno vrsto, ki je shranjena pri nas, sklicujemo se na GBIF id
+// sensitivity vrne pravilo za obcutljive vrste (privzeto, ce ni doloceno)
+func (s *SpeciesService) sensitivity() biolog.SensitivityPolicy {
+	if s.Sensitivity == nil {
+		return biolog.DefaultSensitivityPolicy(nil)
+	}
+	return *s.Sensitivity
+}
+
+// WithContext vrne kopijo servica, ki poizvedbe izvaja v kontekstu ctx, npr. zahteve, da so spani
+// poizvedb otroci spana zahteve. Preklic ctx poizvedb ne prekine, kot doslej
+func (s *SpeciesService) WithContext(ctx context.Context) biolog.SpeciesService {
+	c := *s
+	c.ctx = context.WithoutCancel(ctx)
+	return &c
+}
+
+// context vrne kontekst poizvedb servica
+func (s *SpeciesService) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// Species vrne doloceno vrsto skupaj s trenutnimi ocenami ogrozenosti, sklicujemo se na GBIF id
 func (s *SpeciesService) Species(id int) (*biolog.Species, error) {
 	stmt := `SELECT * FROM species WHERE id = $1 LIMIT 1`
-	spec := &biolog.Species{}
-	if getErr := s.DB.Get(spec, stmt, id); getErr != nil {
+	sps := make([]biolog.Species, 1)
+	if getErr := s.DB.GetContext(s.context(), &sps[0], stmt, id); getErr != nil {
 		if getErr == sql.ErrNoRows {
 			return nil, errors.New("Vrsta s tem GBIF ID ne obstaja")
 		}
 		return nil, getErr
 	}
-	return spec, nil
+	if err := s.withAssessments(sps); err != nil {
+		return nil, err
+	}
+	return &sps[0], nil
 }
 
-// AllSpecies vrne vse vrste, ki so shranjene pri nas
+// AllSpecies vrne vse vrste, ki so shranjene pri nas, skupaj s trenutnimi ocenami ogrozenosti
 func (s *SpeciesService) AllSpecies() ([]biolog.Species, error) {
 	stmt := `SELECT * FROM species`
 	sps := []biolog.Species{}
 
-	if selErr := s.DB.Select(&sps, stmt); selErr != nil {
+	if selErr := s.DB.SelectContext(s.context(), &sps, stmt); selErr != nil {
 		return nil, selErr
 	}
+	if err := s.withAssessments(sps); err != nil {
+		return nil, err
+	}
 
 	return sps, nil
 }
 
 // CreateSpecies shrani podatke o doloceni vrsti v naso bazo in vrne dodeljen id
 func (s *SpeciesService) CreateSpecies(sp *biolog.Species) (*biolog.Species, error) {
-	stmt := `INSERT INTO species (id, species, kingdom, species_family, species_class, phylum, species_order, genus, scientific_name, canonical_name, conservation_status)
-		VALUES (:id, :species, :kingdom, :species_family, :species_class, :phylum, :species_order, :genus, :scientific_name, :canonical_name, :conservation_status)`
+	stmt := `INSERT INTO species (id, species, kingdom, species_family, species_class, phylum, species_order, genus, scientific_name, canonical_name, conservation_status, accepted_key)
+		VALUES (:id, :species, :kingdom, :species_family, :species_class, :phylum, :species_order, :genus, :scientific_name, :canonical_name, :conservation_status, :accepted_key)`
 
-	_, err := s.DB.NamedExec(stmt, sp)
+	_, err := s.DB.NamedExecContext(s.context(), stmt, sp)
 	if err != nil {
 		return nil, err
 	}
@@ -55,92 +94,425 @@ func (s *SpeciesService) CreateSpecies(sp *biolog.Species) (*biolog.Species, err
 }
 
 // UpdateSpecies posodobi vrsto s podanim ID glede na nove (non-nil) podatke podane v sp
-func (s *SpeciesService) UpdateSpecies(gbifKey int, sp biolog.Species) error {
+func (s *SpeciesService) UpdateSpecies(gbifKey int, sp biolog.Species, by biolog.Actor) error {
 	q, args := buildInsertUpdateQuery(buildUpdate, "species", sp)
 	args = append(args, gbifKey)
 
-	if _, err := s.DB.Exec(q, args...); err != nil {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	before, after := &biolog.Species{}, &biolog.Species{}
+	if err := lockRow(tx, before, "species", gbifKey); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return errors.New("Vrsta s tem GBIF ID ne obstaja")
+		}
+		return err
+	}
+	if _, err := tx.Exec(q, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := lockRow(tx, after, "species", gbifKey); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, by, biolog.AuditUpdate, biolog.AuditSpecies, gbifKey, before, after); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // DeleteSpecies zbrise doloceno vrsto (ce ni navedena v nobenem izmed opazovanj)
-func (s *SpeciesService) DeleteSpecies(gbifKey int) error {
-	stmt := `DELETE FROM species WHERE id = $1`
-
-	if _, err := s.DB.Exec(stmt, gbifKey); err != nil {
+func (s *SpeciesService) DeleteSpecies(gbifKey int, by biolog.Actor) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	before := &biolog.Species{}
+	if err := lockRow(tx, before, "species", gbifKey); err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return errors.New("Vrsta s tem GBIF ID ne obstaja")
+		}
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM species WHERE id = $1`, gbifKey); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, by, biolog.AuditDelete, biolog.AuditSpecies, gbifKey, before, nil); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// Observation vrne zapis z dolocenim ID
+// Observation vrne zapis z dolocenim ID (izbrisana opazanja niso vidna)
 func (s *SpeciesService) Observation(id int) (*biolog.Observation, error) {
-	stmt := `SELECT * FROM observation WHERE id = $1`
+	stmt := `SELECT * FROM observation WHERE id = $1 AND deleted_at IS NULL`
 	ob := &biolog.Observation{}
 
-	if getErr := s.DB.Get(ob, stmt, id); getErr != nil {
+	if getErr := s.DB.GetContext(s.context(), ob, stmt, id); getErr != nil {
 		return nil, getErr
 	}
 
 	return ob, nil
 }
 
-// Observations vrne vse podane zapise o opazenih vrstah (vse, ki so javni)
-// TODO:
-// 	- preveri za override nad public_observations pri User
+// Observations vrne vse podane zapise o opazenih vrstah (vse, ki so javni, glej publicObservation)
 // FIXME:
 // 	- vracanje lokacije kot koordinate, comma separated (trenutno je HEX)
-func (s *SpeciesService) Observations() ([]biolog.Observation, error) {
-	stmt := `SELECT * FROM observation WHERE public_visibility = TRUE`
-	obs := []biolog.Observation{}
+func (s *SpeciesService) Observations(f biolog.ObservationFilter) ([]biolog.Observation, error) {
+	where, args := observationConditions(f)
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		where += fmt.Sprintf(" ORDER BY observation.id LIMIT $%d", len(args))
+	}
 
-	if selErr := s.DB.Select(&obs, stmt); selErr != nil {
+	obs := []biolog.Observation{}
+	if selErr := s.DB.SelectContext(s.context(), &obs, `SELECT * FROM observation `+where, args...); selErr != nil {
 		return nil, selErr
 	}
 
 	return obs, nil
 }
 
-// CreateObservation kreira nov zapis o opazeni vrsti
+// publicObservation je pogoj, ko je opazanje (observation) javno: oznaceno je kot javno in njegov avtor dovoli
+// javen dostop do svojih opazanj. Enako pravilo preverja biolog.Observation.IsPublic
+const publicObservation = `observation.public_visibility AND EXISTS (SELECT 1 FROM biolog_user AS owner
+	WHERE owner.id = observation.biolog_user AND owner.public_observations)`
+
+// observationConditions zgradi WHERE del poizvedbe nad javnimi opazanji iz filtra in vrne pripadajoce argumente.
+// Izbrisana opazanja so vedno izpuscena. Stolpci so oznaceni s tabelo observation, da se pogoji lahko uporabijo tudi pri JOIN
+func observationConditions(f biolog.ObservationFilter) (string, []interface{}) {
+	var where strings.Builder
+	var args []interface{}
+	where.WriteString(`WHERE ` + publicObservation + ` AND observation.deleted_at IS NULL`)
+
+	// Dodaj pogoje iz filtra
+	if f.QualityGrade != nil {
+		args = append(args, *f.QualityGrade)
+		fmt.Fprintf(&where, " AND observation.quality_grade = $%d", len(args))
+	}
+	if f.Species != nil {
+		args = append(args, *f.Species)
+		fmt.Fprintf(&where, " AND observation.species = $%d", len(args))
+	}
+	if f.Region != nil {
+		args = append(args, *f.Region)
+		if f.Obscure == nil {
+			fmt.Fprintf(&where, ` AND EXISTS (SELECT 1 FROM observation_region
+				WHERE observation_region.observation = observation.id AND observation_region.region = $%d)`, len(args))
+		} else {
+			// observation_region je dolocen z natancno lokacijo, zato se obmocje preveri na posploseni
+			region := len(args)
+			var location string
+			location, args = observationLocation(f, args)
+			fmt.Fprintf(&where, ` AND EXISTS (SELECT 1 FROM region %s
+				WHERE region.id = $%d AND ST_Intersects(region.geom, %s))`, obscureJoins, region, location)
+		}
+	}
+	if f.Project != nil {
+		args = append(args, *f.Project)
+		project := len(args)
+		// Posplosena lokacija potrebuje vrsto in status ogrozenosti opazanja
+		from := `project`
+		if f.Obscure != nil {
+			from += obscureJoins
+		}
+		var location string
+		location, args = observationLocation(f, args)
+		fmt.Fprintf(&where, " AND EXISTS (SELECT 1 FROM %s WHERE project.id = $%d AND %s)", from, project, projectRules(location))
+	}
+	if len(f.BBox) == 4 {
+		args = append(args, f.BBox[0], f.BBox[1], f.BBox[2], f.BBox[3])
+		envelope := fmt.Sprintf(`ST_MakeEnvelope($%d, $%d, $%d, $%d, 4326)`, len(args)-3, len(args)-2, len(args)-1, len(args))
+		if f.Obscure == nil {
+			fmt.Fprintf(&where, " AND ST_Intersects(observation.sighting_location::geometry, %s)", envelope)
+		} else {
+			var location string
+			location, args = observationLocation(f, args)
+			fmt.Fprintf(&where, " AND EXISTS (SELECT 1 FROM (VALUES (1)) AS point %s WHERE ST_Intersects(%s, %s))",
+				obscureJoins, location, envelope)
+		}
+	}
+	if f.AfterID != nil {
+		args = append(args, *f.AfterID)
+		fmt.Fprintf(&where, " AND observation.id > $%d", len(args))
+	}
+	if f.CreatedSince != nil {
+		args = append(args, *f.CreatedSince)
+		fmt.Fprintf(&where, " AND observation.created_at >= $%d", len(args))
+	}
+
+	return where.String(), args
+}
+
+// obscureJoins so tabele, ki jih potrebuje posplosena lokacija (observationLocation) v poizvedbi nad opazanjem
+const obscureJoins = `
+	LEFT JOIN species ON species.id = observation.species
+	LEFT JOIN conservation_status ON conservation_status.id = species.conservation_status`
+
+// observationLocation vrne SQL izraz za lokacijo opazanja (geometry v WGS84). Ce filter doloca pravilo za
+// obcutljive vrste, se lokacije teh vrst premaknejo v sredisce celice evropske referencne mreze (enako kot
+// biolog.ObscureLocation). Izraz potrebuje JOIN na species in conservation_status, argumenti se dodajo k args
+func observationLocation(f biolog.ObservationFilter, args []interface{}) (string, []interface{}) {
+	if f.Obscure == nil {
+		return `observation.sighting_location::geometry`, args
+	}
+	args = append(args, pq.Array(f.Obscure.Statuses), pq.Array(f.Obscure.Species))
+	return fmt.Sprintf(`CASE WHEN conservation_status.acronym = ANY($%d) OR observation.species = ANY($%d)
+		THEN ST_Transform(ST_SnapToGrid(ST_Transform(observation.sighting_location::geometry, 3035), %d, %d, %d, %d), 4326)
+		ELSE observation.sighting_location::geometry END`, len(args)-1, len(args),
+		biolog.ObscureCellSize/2, biolog.ObscureCellSize/2, biolog.ObscureCellSize, biolog.ObscureCellSize), args
+}
+
+// statsKeys so SQL izrazi za kljuc skupine pri posameznem nacinu zdruzevanja
+var statsKeys = map[string]string{
+	biolog.StatsBySpecies:            `observation.species::text`,
+	biolog.StatsByMonth:              `to_char(observation.sighting_time, 'YYYY-MM')`,
+	biolog.StatsByYear:               `to_char(observation.sighting_time, 'YYYY')`,
+	biolog.StatsByUser:               `observation.biolog_user::text`,
+	biolog.StatsByConservationStatus: `conservation_status.acronym`,
+	biolog.StatsByRegion:             `observation_region.region::text`,
+}
+
+// statsJoins so tabele, ki jih potrebujejo posamezni nacini zdruzevanja
+var statsJoins = map[string]string{
+	biolog.StatsByConservationStatus: `
+		JOIN species ON species.id = observation.species
+		JOIN conservation_status ON conservation_status.id = species.conservation_status`,
+	biolog.StatsByRegion: `
+		JOIN observation_region ON observation_region.observation = observation.id`,
+}
+
+// ObservationStats presteje javna opazanja in sesteje kolicino osebkov po skupinah.
+// Zdruzevanje se izvede v bazi, skupine so urejene po kljucu
+func (s *SpeciesService) ObservationStats(groupBy string, f biolog.ObservationFilter) ([]biolog.ObservationStat, error) {
+	key, ok := statsKeys[groupBy]
+	if !ok {
+		return nil, errors.New("Neveljaven nacin zdruzevanja")
+	}
+
+	from := `observation` + statsJoins[groupBy]
+	where, args := observationConditions(f)
+	if groupBy == biolog.StatsByRegion && f.Obscure != nil {
+		// observation_region je dolocen z natancno lokacijo, zato se obmocja dolocijo na posploseni
+		var location string
+		location, args = observationLocation(f, args)
+		key = `region.id::text`
+		from = `observation` + obscureJoins + ` JOIN region ON ST_Intersects(region.geom, ` + location + `)`
+	}
+
+	stmt := `SELECT ` + key + ` AS key, count(*) AS observations, COALESCE(sum(observation.quantity), 0) AS quantity
+		FROM ` + from + ` ` + where + ` GROUP BY 1 ORDER BY 1`
+	stats := []biolog.ObservationStat{}
+	if selErr := s.DB.SelectContext(s.context(), &stats, stmt, args...); selErr != nil {
+		return nil, selErr
+	}
+
+	return stats, nil
+}
+
+// CreateObservation kreira nov zapis o opazeni vrsti. Stopnja kakovosti se doloci iz
+// vrste, ki jo je vnesel uporabnik (drugih identifikacij se ni), opazanje se dodeli obmocjem glede na lokacijo
 func (s *SpeciesService) CreateObservation(o *biolog.Observation) (*biolog.Observation, error) {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+	ob, err := insertObservation(tx, *o, s.sensitivity())
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return ob, tx.Commit()
+}
+
+// insertObservation shrani opazanje znotraj transakcije, izracuna stopnjo kakovosti, mu dodeli obmocja,
+// doda dogodek za narocnine, katerih filtrom ustreza (po pravilu policy za obcutljive vrste), in o njem obvesti tok opazanj
+func insertObservation(tx *sqlx.Tx, newOb biolog.Observation, policy biolog.SensitivityPolicy) (*biolog.Observation, error) {
 	ob := biolog.Observation{}
 
-	q, args := buildInsertUpdateQuery(buildInsert, "observation", o)
-	if getErr := s.DB.Get(&ob, q, args...); getErr != nil {
+	newOb.CommunityTaxon, newOb.QualityGrade, newOb.CreatedAt = nil, nil, nil
+	_, grade := biolog.Consensus(newOb, nil)
+	newOb.QualityGrade = &grade
+
+	q, args := buildInsertUpdateQuery(buildInsert, "observation", newOb)
+	if getErr := tx.Get(&ob, q, args...); getErr != nil {
 		return nil, getErr
 	}
+	if err := assignRegions(tx, *ob.ID); err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhooks(tx, biolog.WebhookObservationCreated, &ob, policy); err != nil {
+		return nil, err
+	}
+	if err := notifyObservation(tx, *ob.ID); err != nil {
+		return nil, err
+	}
 
 	return &ob, nil
 }
 
-// DeleteObservation zbrise dolocen zapis o opazeni vrsti
-func (s *SpeciesService) DeleteObservation(id int) error {
-	stmt := `DELETE FROM observation WHERE id = $1`
+// DeleteObservation oznaci opazanje kot izbrisano (mehko brisanje), by.User je uporabnik, ki ga je izbrisal
+func (s *SpeciesService) DeleteObservation(id int, by biolog.Actor) error {
+	return s.setObservationDeleted(id, true, by)
+}
+
+// DeletedObservation vrne izbrisano opazanje z dolocenim ID (npr. za preverjanje lastnika pred obnovitvijo)
+func (s *SpeciesService) DeletedObservation(id int) (*biolog.Observation, error) {
+	stmt := `SELECT * FROM observation WHERE id = $1 AND deleted_at IS NOT NULL`
+	ob := &biolog.Observation{}
+
+	if getErr := s.DB.GetContext(s.context(), ob, stmt, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Izbrisano opazanje s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	return ob, nil
+}
+
+// RestoreObservation obnovi izbrisano opazanje
+func (s *SpeciesService) RestoreObservation(id int, by biolog.Actor) error {
+	return s.setObservationDeleted(id, false, by)
+}
 
-	_, err := s.DB.Exec(stmt, id)
+// setObservationDeleted oznaci opazanje kot izbrisano ali ga obnovi in spremembo zapise v revizijsko sled
+func (s *SpeciesService) setObservationDeleted(id int, deleted bool, by biolog.Actor) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
 	if err != nil {
 		return err
 	}
+	if err := markObservationDeleted(tx, id, deleted, by); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-	return nil
+	return tx.Commit()
+}
+
+// markObservationDeleted v transakciji tx oznaci opazanje kot izbrisano ali ga obnovi in spremembo zapise v
+// revizijsko sled
+func markObservationDeleted(tx *sqlx.Tx, id int, deleted bool, by biolog.Actor) error {
+	ob := &biolog.Observation{}
+	if err := lockRow(tx, ob, "observation", id); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	var result sql.Result
+	var err error
+	action, notFound := biolog.AuditDelete, "Opazanje s tem ID ne obstaja"
+	if deleted {
+		stmt := `UPDATE observation SET deleted_at = now(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`
+		result, err = tx.Exec(stmt, id, by.User)
+	} else {
+		action, notFound = biolog.AuditRestore, "Izbrisano opazanje s tem ID ne obstaja"
+		stmt := `UPDATE observation SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+		result, err = tx.Exec(stmt, id)
+	}
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New(notFound)
+	}
+
+	// Izbrisano opazanje za odjemalce ne obstaja, zato se v sledi belezi kot izginotje (ali ponovni pojav) zapisa
+	before, after := interface{}(ob), interface{}(nil)
+	if !deleted {
+		before, after = nil, ob
+	}
+	return writeAudit(tx, by, action, biolog.AuditObservation, id, before, after)
+}
+
+// PurgeObservations trajno odstrani opazanja, ki so bila izbrisana pred before (skupaj z identifikacijami in
+// komentarji). Vrne stevilo odstranjenih opazanj
+func (s *SpeciesService) PurgeObservations(before time.Time) (int64, error) {
+	result, err := s.DB.ExecContext(s.context(), `DELETE FROM observation WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
 }
 
 // UpdateObservation posodobi opazovalni list, ki ima enak ID
 // Nove podatke preberemo iz slovarja, pri cemer so kljuci enaki imenom atributov
-func (s *SpeciesService) UpdateObservation(id int, ob biolog.Observation) error {
+// Vrsta skupnosti in stopnja kakovosti se po posodobitvi ponovno izracunata, ob spremembi lokacije tudi obmocja
+func (s *SpeciesService) UpdateObservation(id int, ob biolog.Observation, by biolog.Actor) error {
+	// Izracunanih polj in casa shranjevanja ni mogoce posodabljati neposredno
+	ob.CommunityTaxon, ob.QualityGrade, ob.CreatedAt = nil, nil, nil
 	q, args := buildInsertUpdateQuery(buildUpdate, "observation", ob)
 	// Dodaj ID na konec seznama argumentov za query
 	args = append(args, id)
 
-	if _, err := s.DB.Exec(q, args...); err != nil {
+	return s.changeObservation(id, biolog.AuditUpdate, by, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(q, args...)
+		return err
+	})
+}
+
+// changeObservation v eni transakciji spremeni opazanje s funkcijo change, ponovno izracuna soglasje skupnosti
+// (ob spremembi lokacije tudi obmocja), doda dogodek za narocnine ter shrani novo revizijo in zapis v revizijsko sled
+func (s *SpeciesService) changeObservation(id int, action string, by biolog.Actor, change func(tx *sqlx.Tx) error) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	before, after := &biolog.Observation{}, &biolog.Observation{}
+	if err := lockRow(tx, before, "observation", id); err != nil || before.DeletedAt != nil {
+		tx.Rollback()
+		if err == nil || err == sql.ErrNoRows {
+			return errors.New("Opazanje s tem ID ne obstaja")
+		}
+		return err
+	}
+	// Opazanje, ki se posodablja prvic, dobi revizijo z zacetnim stanjem
+	if err := saveFirstRevision(tx, before); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := change(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := updateConsensus(tx, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := lockRow(tx, after, "observation", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if before.SightingLocation == nil || after.SightingLocation == nil || *after.SightingLocation != *before.SightingLocation {
+		if err := assignRegions(tx, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := enqueueWebhooks(tx, biolog.WebhookObservationUpdated, after, s.sensitivity()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := saveRevision(tx, after, by.User); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, by, action, biolog.AuditObservation, id, before, after); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // ConservationStatus vrne podatke o dolocenem statusu ogrozenosti
@@ -148,7 +520,7 @@ func (s *SpeciesService) ConservationStatus(id int) (*biolog.ConservationStatus,
 	stmt := `SELECT * FROM conservation_status WHERE id = $1`
 	cs := &biolog.ConservationStatus{}
 
-	if getErr := s.DB.Get(cs, stmt, id); getErr != nil {
+	if getErr := s.DB.GetContext(s.context(), cs, stmt, id); getErr != nil {
 		return nil, getErr
 	}
 
@@ -160,7 +532,7 @@ func (s *SpeciesService) ConservationStatuses() ([]biolog.ConservationStatus, er
 	stmt := `SELECT * FROM conservation_status`
 	css := []biolog.ConservationStatus{}
 
-	if selErr := s.DB.Select(&css, stmt); selErr != nil {
+	if selErr := s.DB.SelectContext(s.context(), &css, stmt); selErr != nil {
 		return nil, selErr
 	}
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,7 +71,7 @@ func TestObservation(t *testing.T) {
 // TestObservations vrne vsa javna opazanja (Javna opazanja so tista, pri katerih ima uporabnik PublicObservations
 // nastavljen na true, prav tako pa posamezno opazanje rabi PublicVisibility enak true)
 func TestObservations(t *testing.T) {
-	o, getErr := speciesServiceTest.Observations()
+	o, getErr := speciesServiceTest.Observations(biolog.ObservationFilter{})
 	if assert.NoError(t, getErr) {
 		actualO := &[]biolog.Observation{}
 		selectErr := speciesServiceTest.DB.Select(actualO, `SELECT o.id, o.quantity, ST_AsText(o.sighting_location) as sighting_location, o.sighting_time, o.quantity, o.biolog_user, o.species FROM observation AS o, biolog_user AS bu
@@ -106,16 +107,31 @@ func TestObservations(t *testing.T) {
 	}
 }
 */
-// TestDeleteObservation preveri brisanje dolocenega zapisa o opazanju
+// TestDeleteObservation preveri mehko brisanje dolocenega zapisa o opazanju in njegovo obnovitev
 func TestDeleteObservation(t *testing.T) {
-	ID := 1
-	delErr := speciesServiceTest.DeleteObservation(ID)
+	// Opazanje 1 je od uporabnika 10000003 v scripts/sample-data.sql
+	ID, user := 1, 10000003
+	by := biolog.Actor{User: user, RequestID: "test-delete-observation"}
+	delErr := speciesServiceTest.DeleteObservation(ID, by)
 	if assert.NoError(t, delErr) {
-		var oExists bool
-		getErr := speciesServiceTest.DB.Get(oExists, `SELECT EXISTS (SELECT 1 FROM observation WHERE id = $1)`, ID)
+		_, getErr := speciesServiceTest.Observation(ID)
+		assert.Error(t, getErr)
+		deleted, getErr := speciesServiceTest.DeletedObservation(ID)
 		if assert.NoError(t, getErr) {
-			assert.Equal(t, false, oExists)
+			assert.Equal(t, user, *deleted.DeletedBy)
 		}
+		assert.NoError(t, speciesServiceTest.RestoreObservation(ID, by))
+	}
+	assert.Error(t, speciesServiceTest.RestoreObservation(ID, by))
+
+	// Brisanje in obnovitev sta zapisana v revizijski sledi
+	as := &postgres.AuditService{DB: speciesServiceTest.DB}
+	entity := biolog.AuditObservation
+	entries, err := as.AuditEntries(biolog.AuditFilter{EntityType: &entity, EntityID: &ID, Actor: &user}, biolog.Page{Limit: 2})
+	if assert.NoError(t, err) && assert.Len(t, entries, 2) {
+		assert.Equal(t, biolog.AuditRestore, *entries[0].Action)
+		assert.Equal(t, biolog.AuditDelete, *entries[1].Action)
+		assert.Equal(t, by.RequestID, *entries[1].RequestID)
 	}
 }
 
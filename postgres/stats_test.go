@@ -0,0 +1,31 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationStats preveri, da se stevilo opazanj po skupinah ujema s seznamom javnih opazanj
+func TestObservationStats(t *testing.T) {
+	obs, err := speciesServiceTest.Observations(biolog.ObservationFilter{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, groupBy := range []string{biolog.StatsBySpecies, biolog.StatsByMonth, biolog.StatsByYear,
+		biolog.StatsByUser, biolog.StatsByConservationStatus} {
+		stats, statsErr := speciesServiceTest.ObservationStats(groupBy, biolog.ObservationFilter{})
+		if assert.NoError(t, statsErr, groupBy) {
+			total := 0
+			for _, s := range stats {
+				total += s.Observations
+			}
+			assert.Equal(t, len(obs), total, groupBy)
+		}
+	}
+
+	_, err = speciesServiceTest.ObservationStats("day", biolog.ObservationFilter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// Kanal, na katerega se ob kreiranju javnega opazanja poslje njegov ID
+const observationChannel = "observation_created"
+
+// ObservationStream predstavlja PostgreSQL implementacijo od biolog.ObservationStream. Nova opazanja prejme
+// z LISTEN na kanalu observation_created, zato dobi tudi opazanja, ki so jih shranile druge instance streznika
+type ObservationStream struct {
+	DB       *sqlx.DB
+	listener *pq.Listener
+	hub      *biolog.ObservationHub
+}
+
+// Preveri ali ObservationStream implementira vse metode
+var _ biolog.ObservationStream = &ObservationStream{}
+
+// NewObservationStream odpre lastno povezavo za LISTEN (connString je enak kot pri Open) in zacne
+// razposiljati nova opazanja. Prekinjeno povezavo listener sam ponovno vzpostavi
+func NewObservationStream(db *sqlx.DB, connString string) (*ObservationStream, error) {
+	s := &ObservationStream{DB: db, hub: biolog.NewObservationHub()}
+	s.listener = pq.NewListener(connString, 10*time.Second, time.Minute, nil)
+	if err := s.listener.Listen(observationChannel); err != nil {
+		s.listener.Close()
+		return nil, err
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// run prebere opazanje za vsako obvestilo in ga razposlje narocnikom. Obvestila med prekinitvijo povezave
+// se izgubijo (nil na kanalu), odjemalci manjkajoca opazanja dobijo ob ponovni povezavi z Last-Event-ID
+func (s *ObservationStream) run() {
+	for n := range s.listener.Notify {
+		if n == nil {
+			continue
+		}
+		id, err := strconv.Atoi(n.Extra)
+		if err != nil {
+			continue
+		}
+		ob := biolog.Observation{}
+		// Vidnost se preveri enako kot pri branju zamujenih opazanj, avtor jo je lahko medtem spremenil
+		stmt := `SELECT * FROM observation WHERE id = $1 AND deleted_at IS NULL AND ` + publicObservation
+		if err := s.DB.Get(&ob, stmt, id); err != nil {
+			continue
+		}
+		s.hub.Publish(ob)
+	}
+}
+
+// Subscribe vrne kanal z novimi javnimi opazanji in funkcijo za odjavo
+func (s *ObservationStream) Subscribe() (<-chan biolog.Observation, func()) {
+	return s.hub.Subscribe()
+}
+
+// Close zapre povezavo za LISTEN
+func (s *ObservationStream) Close() error {
+	return s.listener.Close()
+}
+
+// notifyObservation znotraj transakcije obvesti poslusalce o novem opazanju, ce je javno.
+// NOTIFY se poslje sele ob potrditvi transakcije, zato poslusalci opazanje ze lahko preberejo
+func notifyObservation(tx *sqlx.Tx, observationID int) error {
+	stmt := `SELECT pg_notify($2, observation.id::text) FROM observation
+		WHERE observation.id = $1 AND ` + publicObservation
+	_, err := tx.Exec(stmt, observationID, observationChannel)
+	return err
+}
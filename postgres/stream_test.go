@@ -0,0 +1,66 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationStream preveri, da tok z LISTEN/NOTIFY prejme le nova javna opazanja, ter filtre za nadaljevanje toka
+func TestObservationStream(t *testing.T) {
+	stream, err := postgres.NewObservationStream(speciesServiceTest.DB, postgres.ConnString(
+		viper.GetString("database.username"), viper.GetString("database.password"), viper.GetString("database.testdb"),
+		viper.GetString("database.host"), viper.GetString("database.sslmode"), viper.GetInt("database.port")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Close()
+	observations, unsubscribe := stream.Subscribe()
+	defer unsubscribe()
+
+	u, public, private := createEraseTestUser(t, 31)
+	defer userServiceTest.EraseUser(*u.ID, false, biolog.Actor{User: *u.ID})
+	select {
+	case o := <-observations:
+		assert.Equal(t, *public.ID, *o.ID)
+	case <-time.After(5 * time.Second):
+		t.Error("Tok ni prejel novega opazanja")
+	}
+	select {
+	case o := <-observations:
+		t.Errorf("Nepricakovano opazanje %d", *o.ID)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Zasebno opazanje se tudi ob nadaljevanju toka ne vrne
+	before := *public.ID - 1
+	obs, err := speciesServiceTest.Observations(biolog.ObservationFilter{AfterID: &before})
+	if assert.NoError(t, err) && assert.Len(t, obs, 1) {
+		assert.Equal(t, *public.ID, *obs[0].ID)
+	}
+	obs, _ = speciesServiceTest.Observations(biolog.ObservationFilter{AfterID: private.ID})
+	assert.Len(t, obs, 0)
+
+	// Nadaljevanje toka: opazanja, shranjena po danem casu, od najmanjsega ID naprej
+	since := public.CreatedAt.Add(-time.Second)
+	obs, err = speciesServiceTest.Observations(biolog.ObservationFilter{CreatedSince: &since, AfterID: &before, Limit: 1})
+	if assert.NoError(t, err) && assert.Len(t, obs, 1) {
+		assert.Equal(t, *public.ID, *obs[0].ID)
+	}
+
+	// Obcutljiva vrsta je v pravokotniku le, ce je v njem posplosena lokacija
+	bbox := []float64{14.5048, 46.0559, 14.5068, 46.0579}
+	obs, err = speciesServiceTest.Observations(biolog.ObservationFilter{BBox: bbox, AfterID: &before})
+	if assert.NoError(t, err) {
+		assert.Len(t, obs, 1)
+	}
+	policy := biolog.DefaultSensitivityPolicy([]int{*public.Species})
+	obs, err = speciesServiceTest.Observations(biolog.ObservationFilter{BBox: bbox, AfterID: &before, Obscure: &policy})
+	if assert.NoError(t, err) {
+		assert.Len(t, obs, 0)
+	}
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rubinda/biolog"
+)
+
+// ObservationTile vrne vektorsko ploscico (Mapbox Vector Tile) z javnimi opazanji, ki jo zgradi ST_AsMVT.
+// Vsako opazanje nosi vrsto, kratico statusa ogrozenosti vrste in datum opazanja
+func (s *SpeciesService) ObservationTile(z, x, y int, f biolog.ObservationFilter) ([]byte, error) {
+	if !biolog.ValidTile(z, x, y) {
+		return nil, errors.New("Ploscica ne obstaja")
+	}
+	where, args := observationConditions(f)
+	location, args := observationLocation(f, args)
+
+	// Obseg ploscice se doda za argumenti filtra
+	minX, minY, maxX, maxY := biolog.TileBounds(z, x, y)
+	n := len(args)
+	args = append(args, minX, minY, maxX, maxY)
+	envelope := fmt.Sprintf(`ST_MakeEnvelope($%d, $%d, $%d, $%d, 3857)`, n+1, n+2, n+3, n+4)
+	// Posplosena lokacija je lahko na drugi ploscici kot natancna, zato se obseg za indeks razsiri za dve
+	// velikosti celice (polovica diagonale celice v metrih Web Mercator do priblizno 65. vzporednika)
+	bounds := envelope
+	if f.Obscure != nil {
+		bounds = fmt.Sprintf(`ST_Expand(%s, %d)`, envelope, 2*biolog.ObscureCellSize)
+	}
+
+	// Izraz ST_Transform(...) se ujema z indeksom observation_location_3857_idx
+	stmt := fmt.Sprintf(`SELECT ST_AsMVT(tile, '%s', %d, 'geom') FROM (
+			SELECT observation.id, observation.species, conservation_status.acronym AS conservation_status,
+				to_char(observation.sighting_time, 'YYYY-MM-DD') AS sighting_date,
+				ST_AsMVTGeom(ST_Transform(%s, 3857), %s, %d, 64, true) AS geom
+			FROM observation
+			JOIN species ON species.id = observation.species
+			LEFT JOIN conservation_status ON conservation_status.id = species.conservation_status
+			%s AND ST_Transform(observation.sighting_location::geometry, 3857) && %s
+		) tile WHERE geom IS NOT NULL`,
+		biolog.TileLayer, biolog.TileExtent, location, envelope, biolog.TileExtent, where, bounds)
+
+	var mvt []byte
+	if err := s.DB.GetContext(s.context(), &mvt, stmt, args...); err != nil {
+		return nil, err
+	}
+
+	return mvt, nil
+}
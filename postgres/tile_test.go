@@ -0,0 +1,20 @@
+package postgres_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationTile preveri, da ploscica na povecavi 0 vsebuje sloj z opazanji
+func TestObservationTile(t *testing.T) {
+	tile, err := speciesServiceTest.ObservationTile(0, 0, 0, biolog.ObservationFilter{})
+	if assert.NoError(t, err) {
+		assert.True(t, bytes.Contains(tile, []byte(biolog.TileLayer)))
+	}
+
+	_, err = speciesServiceTest.ObservationTile(1, 2, 0, biolog.ObservationFilter{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/rubinda/biolog/tracing"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestQuerySpans preveri, da so poizvedbe servica, vezanega na kontekst, otroci spana v kontekstu,
+// tudi znotraj transakcije, poizvedbe brez konteksta pa spanov nimajo
+func TestQuerySpans(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)))
+
+	db, err := postgres.Open(viper.GetString("database.username"), viper.GetString("database.password"),
+		viper.GetString("database.testdb"), viper.GetString("database.host"), viper.GetString("database.sslmode"),
+		viper.GetInt("database.port"))
+	require.NoError(t, err)
+	defer postgres.Close(db)
+	us := &postgres.UserService{DB: db}
+	ss := &postgres.SpeciesService{DB: db}
+
+	_, err = us.Users()
+	assert.NoError(t, err)
+	assert.Empty(t, spans.GetSpans())
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "GET /api/v1/users/")
+	_, err = us.WithContext(ctx).Users()
+	assert.NoError(t, err)
+	_, err = ss.WithContext(ctx).AllSpecies()
+	assert.NoError(t, err)
+	// Stavki v transakciji (tx.Exec) dobijo kontekst transakcije
+	assert.Error(t, us.WithContext(ctx).RestoreUser(0, biolog.Actor{}))
+	parent.End()
+
+	names := []string{}
+	for _, s := range spans.GetSpans() {
+		if s.Name == "GET /api/v1/users/" {
+			continue
+		}
+		names = append(names, s.Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent.SpanID(), s.Name)
+	}
+	for _, name := range []string{"select biolog_user", "select species", "update biolog_user"} {
+		assert.Contains(t, names, name)
+	}
+
+	// Po koncu transakcije povezava konteksta transakcije ne uporablja vec
+	spans.Reset()
+	_, err = us.Users()
+	assert.NoError(t, err)
+	assert.Empty(t, spans.GetSpans())
+}
@@ -1,24 +1,47 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // Dodatek za PostgreSQL
+	"github.com/lib/pq"
 	"github.com/rubinda/biolog"
 )
 
+// foreignKeyViolation je koda napake PostgreSQL, ko se na zapis se sklicuje tuji kljuc
+const foreignKeyViolation = "23503"
+
 // UserService predstavlja PostgreSQL implementacijo od biolog.UserService
 type UserService struct {
 	DB *sqlx.DB
+	// Kontekst poizvedb (glej WithContext)
+	ctx context.Context
+}
+
+// WithContext vrne kopijo servica, ki poizvedbe izvaja v kontekstu ctx, npr. zahteve, da so spani
+// poizvedb otroci spana zahteve. Preklic ctx poizvedb ne prekine, kot doslej
+func (s *UserService) WithContext(ctx context.Context) biolog.UserService {
+	c := *s
+	c.ctx = context.WithoutCancel(ctx)
+	return &c
+}
+
+// context vrne kontekst poizvedb servica
+func (s *UserService) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
 }
 
-// User vrne uporabnika, ki pripada podanemu ID
+// User vrne uporabnika, ki pripada podanemu ID (izbrisani uporabniki niso vidni)
 func (s *UserService) User(id int) (*biolog.User, error) {
-	stmt := `SELECT * FROM biolog_user WHERE id = $1`
+	stmt := `SELECT * FROM biolog_user WHERE id = $1 AND deleted_at IS NULL`
 	u := &biolog.User{}
-	if getErr := s.DB.Get(u, stmt, id); getErr != nil {
+	if getErr := s.DB.GetContext(s.context(), u, stmt, id); getErr != nil {
 		if getErr == sql.ErrNoRows {
 			return nil, errors.New("Uporabnik s tem ID ne obstaja")
 		}
@@ -27,11 +50,11 @@ func (s *UserService) User(id int) (*biolog.User, error) {
 	return u, nil
 }
 
-// Users vrne vse uporabnike
+// Users vrne vse uporabnike, ki niso izbrisani (tudi trajno)
 func (s *UserService) Users() ([]biolog.User, error) {
-	stmt := `SELECT * FROM biolog_user`
+	stmt := `SELECT * FROM biolog_user WHERE deleted_at IS NULL AND erased_at IS NULL`
 	us := []biolog.User{}
-	if getErr := s.DB.Select(&us, stmt); getErr != nil {
+	if getErr := s.DB.SelectContext(s.context(), &us, stmt); getErr != nil {
 		return nil, getErr
 	}
 	return us, nil
@@ -41,12 +64,15 @@ func (s *UserService) Users() ([]biolog.User, error) {
 func (s *UserService) UserByEmail(email string) (*biolog.User, error) {
 	stmt := `SELECT * FROM biolog_user WHERE email = $1 LIMIT 1`
 	u := &biolog.User{}
-	if getErr := s.DB.Get(u, stmt, email); getErr != nil {
+	if getErr := s.DB.GetContext(s.context(), u, stmt, email); getErr != nil {
 		if getErr == sql.ErrNoRows {
 			return nil, errors.New("Not found")
 		}
 		return nil, getErr
 	}
+	if u.DeletedAt != nil {
+		return nil, errors.New(biolog.DeletedAccountError)
+	}
 	return u, nil
 }
 
@@ -56,24 +82,106 @@ func (s *UserService) CreateUser(u biolog.User) (*biolog.User, error) {
 
 	// Po koncani kreaciji naj se vrne nov dodeljen zapis o uporabniku
 	q, args := buildInsertUpdateQuery(buildInsert, "biolog_user", u)
-	if err := s.DB.Get(&newUser, q, args...); err != nil {
+	if err := s.DB.GetContext(s.context(), &newUser, q, args...); err != nil {
 		return nil, err
 	}
 
 	return &newUser, nil
 }
 
-// DeleteUser izbrise podanega uporabnika iz podatkovne baze. Javi napako, ce ima uporabnik zapise o opazanjih.
-// TODO:
-// 	- dodaj Cascade, ki zbrise se vse povezane zapise
-func (s *UserService) DeleteUser(id int) (int64, error) {
-	deleteUser := `DELETE FROM biolog_user WHERE ID = $1`
-	result, createErr := s.DB.Exec(deleteUser, id)
-	if createErr != nil {
-		return -1, createErr
+// DeleteUser oznaci uporabnika kot izbrisanega (mehko brisanje), by.User je uporabnik, ki ga je izbrisal.
+// Javi napako, ce ima uporabnik zapise o opazanjih. Vrne stevilo izbrisanih uporabnikov
+func (s *UserService) DeleteUser(id int, by biolog.Actor) (int64, error) {
+	var hasObservations bool
+	stmt := `SELECT EXISTS (SELECT 1 FROM observation WHERE biolog_user = $1 AND deleted_at IS NULL)`
+	if err := s.DB.GetContext(s.context(), &hasObservations, stmt, id); err != nil {
+		return -1, err
+	}
+	if hasObservations {
+		return -1, errors.New("Uporabnik ima zapise o opazanjih")
+	}
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return -1, err
+	}
+	before := &biolog.User{}
+	if err := lockRow(tx, before, "biolog_user", id); err != nil || before.ErasedAt != nil {
+		tx.Rollback()
+		if err == nil || err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return -1, err
+	}
+	stmt = `UPDATE biolog_user SET deleted_at = now(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`
+	result, err := tx.Exec(stmt, id, by.User)
+	if err != nil {
+		tx.Rollback()
+		return -1, err
 	}
 	rowsDeleted, _ := result.RowsAffected()
-	return rowsDeleted, nil
+	if rowsDeleted == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+	if err := writeAudit(tx, by, biolog.AuditDelete, biolog.AuditUser, id, before, nil); err != nil {
+		tx.Rollback()
+		return -1, err
+	}
+
+	return rowsDeleted, tx.Commit()
+}
+
+// RestoreUser obnovi izbrisanega uporabnika
+func (s *UserService) RestoreUser(id int, by biolog.Actor) error {
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	stmt := `UPDATE biolog_user SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := tx.Exec(stmt, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		tx.Rollback()
+		return errors.New("Izbrisan uporabnik s tem ID ne obstaja")
+	}
+	after := &biolog.User{}
+	if err := lockRow(tx, after, "biolog_user", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, by, biolog.AuditRestore, biolog.AuditUser, id, nil, after); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PurgeUsers trajno odstrani uporabnike, ki so bili izbrisani pred before. Uporabniki, na katere se se
+// sklicujejo drugi zapisi (identifikacije, komentarji ...), ostanejo izbrisani. Vrne stevilo odstranjenih
+func (s *UserService) PurgeUsers(before time.Time) (int64, error) {
+	var ids []int
+	if err := s.DB.SelectContext(s.context(), &ids, `SELECT id FROM biolog_user WHERE deleted_at < $1`, before); err != nil {
+		return 0, err
+	}
+
+	// Vsak uporabnik se odstrani posebej, da tuji kljuc pri enem ne ustavi ostalih
+	var purged int64
+	for _, id := range ids {
+		if _, err := s.DB.ExecContext(s.context(), `DELETE FROM biolog_user WHERE id = $1`, id); err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == foreignKeyViolation {
+				continue
+			}
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
 }
 
 // UpdateUser delno posodobi podatke o uporabniku
@@ -81,24 +189,47 @@ func (s *UserService) DeleteUser(id int) (int64, error) {
 // (?) Ali je lahko sporno da posodabljas podatke, ki so pridobljeni od zunanjega avtentikatorja?
 // FIXME:
 // 	- pripadajoci test
-func (s *UserService) UpdateUser(id int, u biolog.User) error {
+func (s *UserService) UpdateUser(id int, u biolog.User, by biolog.Actor) error {
 	query, args := buildInsertUpdateQuery(buildUpdate, "biolog_user", u)
 	// Dodaj ID v seznam argumentov
 	args = append(args, id)
-	if _, err := s.DB.Exec(query, args...); err != nil {
+
+	tx, err := s.DB.BeginTxx(s.context(), nil)
+	if err != nil {
+		return err
+	}
+	before, after := &biolog.User{}, &biolog.User{}
+	// Trajno izbrisanemu uporabniku ni mogoce ponovno dodati osebnih podatkov
+	if err := lockRow(tx, before, "biolog_user", id); err != nil || before.ErasedAt != nil {
+		tx.Rollback()
+		if err == nil || err == sql.ErrNoRows {
+			return errors.New("Uporabnik s tem ID ne obstaja")
+		}
+		return err
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := lockRow(tx, after, "biolog_user", id); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, by, biolog.AuditUpdate, biolog.AuditUser, id, before, after); err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 // UserByExtID vrne zunanjega uporabnika glede na ID zunanjega avtentikatorja
 func (s *UserService) UserByExtID(id string) (*biolog.User, error) {
-	stmt := `SELECT * FROM biolog_user WHERE external_id = $1`
+	stmt := `SELECT * FROM biolog_user WHERE external_id = $1 AND deleted_at IS NULL`
 	eu := &biolog.User{}
 
 	// Pozene poizvedbo in preveri za napake
-	if err := s.DB.Get(eu, stmt, id); err != nil {
+	if err := s.DB.GetContext(s.context(), eu, stmt, id); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("Uporabnika s tem ID ni mogoče najti")
 		}
@@ -112,7 +243,7 @@ func (s *UserService) AuthProvider(id int) (*biolog.AuthProvider, error) {
 	stmt := `SELECT * FROM external_auth_provider WHERE id = $1`
 	var authPro biolog.AuthProvider
 
-	if err := s.DB.Get(&authPro, stmt, id); err != nil {
+	if err := s.DB.GetContext(s.context(), &authPro, stmt, id); err != nil {
 		return nil, err
 	}
 
@@ -124,7 +255,7 @@ func (s *UserService) AuthProviders() ([]biolog.AuthProvider, error) {
 	stmt := `SELECT * FROM external_auth_provider`
 	var authPros []biolog.AuthProvider
 
-	if err := s.DB.Select(&authPros, stmt); err != nil {
+	if err := s.DB.SelectContext(s.context(), &authPros, stmt); err != nil {
 		return nil, err
 	}
 
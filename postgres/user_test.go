@@ -62,7 +62,7 @@ func TestUsers(t *testing.T) {
 	userList, err := userServiceTest.Users()
 	if assert.NoError(t, err) {
 		users := []biolog.User{}
-		selectErr := userServiceTest.DB.Select(&users, `SELECT * FROM biolog_user`)
+		selectErr := userServiceTest.DB.Select(&users, `SELECT * FROM biolog_user WHERE erased_at IS NULL`)
 		if assert.NoError(t, selectErr) {
 			assert.EqualValues(t, users, userList)
 		}
@@ -92,14 +92,27 @@ func TestDeleteUser(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
-		userServiceTest.DeleteUser(c.ID)
+		userServiceTest.DeleteUser(c.ID, biolog.Actor{User: c.ID})
 		var userExists bool
 		selectErr := userServiceTest.DB.QueryRow(`SELECT EXISTS
-			(SELECT 1 FROM biolog_user WHERE id = $1 LIMIT 1)`, c.ID).Scan(&userExists)
+			(SELECT 1 FROM biolog_user WHERE id = $1 AND deleted_at IS NULL LIMIT 1)`, c.ID).Scan(&userExists)
 		if assert.NoError(t, selectErr) {
 			assert.Equal(t, c.ShouldStay, userExists)
 		}
 	}
+
+	// Prijava v izbrisan racun se zavrne
+	var email string
+	if assert.NoError(t, userServiceTest.DB.Get(&email, `SELECT email FROM biolog_user WHERE id = 10000002`)) {
+		_, err := userServiceTest.UserByEmail(email)
+		if assert.Error(t, err) {
+			assert.Equal(t, biolog.DeletedAccountError, err.Error())
+		}
+	}
+
+	// Izbrisanega uporabnika je mogoce obnoviti
+	assert.NoError(t, userServiceTest.RestoreUser(10000002, biolog.Actor{User: 10000002}))
+	assert.Error(t, userServiceTest.RestoreUser(10000002, biolog.Actor{User: 10000002}))
 }
 
 // TestAuthProvider preveri pridobivanje podatkov o zunanjem ponudniku.
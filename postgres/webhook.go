@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rubinda/biolog"
+)
+
+// WebhookService predstavlja PostgreSQL implementacijo od biolog.WebhookService
+type WebhookService struct {
+	DB *sqlx.DB
+}
+
+// Preveri ali WebhookService implementira vse metode
+var _ biolog.WebhookService = &WebhookService{}
+
+// webhookRow je vrstica tabele webhook, filtri so shranjeni kot PostgreSQL polja
+type webhookRow struct {
+	ID          int             `db:"id"`
+	URL         string          `db:"url"`
+	Secret      string          `db:"secret"`
+	Description *string         `db:"description"`
+	Species     pq.Int64Array   `db:"species"`
+	Regions     pq.Int64Array   `db:"regions"`
+	BBox        pq.Float64Array `db:"bbox"`
+	CreatedBy   *int            `db:"created_by"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
+
+// webhook pretvori vrstico v narocnino, skrivnost se vrne le, ce je withSecret
+func (row webhookRow) webhook(withSecret bool) biolog.Webhook {
+	wh := biolog.Webhook{ID: &row.ID, URL: &row.URL, Description: row.Description, Species: []int{},
+		Regions: []int{}, BBox: []float64(row.BBox), CreatedBy: row.CreatedBy, CreatedAt: &row.CreatedAt}
+	for _, id := range row.Species {
+		wh.Species = append(wh.Species, int(id))
+	}
+	for _, id := range row.Regions {
+		wh.Regions = append(wh.Regions, int(id))
+	}
+	if wh.BBox == nil {
+		wh.BBox = []float64{}
+	}
+	if withSecret {
+		wh.Secret = &row.Secret
+	}
+	return wh
+}
+
+// Webhook vrne narocnino z dolocenim ID (brez skrivnosti)
+func (s *WebhookService) Webhook(id int) (*biolog.Webhook, error) {
+	row := webhookRow{}
+	if getErr := s.DB.Get(&row, `SELECT * FROM webhook WHERE id = $1`, id); getErr != nil {
+		if getErr == sql.ErrNoRows {
+			return nil, errors.New("Narocnina s tem ID ne obstaja")
+		}
+		return nil, getErr
+	}
+
+	wh := row.webhook(false)
+	return &wh, nil
+}
+
+// Webhooks vrne vse narocnine (brez skrivnosti), urejene po ID
+func (s *WebhookService) Webhooks() ([]biolog.Webhook, error) {
+	rows := []webhookRow{}
+	if selErr := s.DB.Select(&rows, `SELECT * FROM webhook ORDER BY id`); selErr != nil {
+		return nil, selErr
+	}
+
+	whs := make([]biolog.Webhook, len(rows))
+	for i, row := range rows {
+		whs[i] = row.webhook(false)
+	}
+	return whs, nil
+}
+
+// CreateWebhook shrani novo narocnino in jo vrne skupaj s skrivnostjo
+func (s *WebhookService) CreateWebhook(wh *biolog.Webhook) (*biolog.Webhook, error) {
+	if wh.URL == nil || wh.Secret == nil {
+		return nil, errors.New("Manjkata naslov ali skrivnost narocnine")
+	}
+	species, regions := make(pq.Int64Array, len(wh.Species)), make(pq.Int64Array, len(wh.Regions))
+	for i, id := range wh.Species {
+		species[i] = int64(id)
+	}
+	for i, id := range wh.Regions {
+		regions[i] = int64(id)
+	}
+	bbox := pq.Float64Array(wh.BBox)
+	if bbox == nil {
+		bbox = pq.Float64Array{}
+	}
+
+	stmt := `INSERT INTO webhook (url, secret, description, species, regions, bbox, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *`
+	row := webhookRow{}
+	if getErr := s.DB.Get(&row, stmt, *wh.URL, *wh.Secret, wh.Description, species, regions, bbox, wh.CreatedBy); getErr != nil {
+		return nil, getErr
+	}
+
+	created := row.webhook(true)
+	return &created, nil
+}
+
+// DeleteWebhook izbrise narocnino skupaj z vsemi njenimi posiljanji
+func (s *WebhookService) DeleteWebhook(id int) error {
+	res, err := s.DB.Exec(`DELETE FROM webhook WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("Narocnina s tem ID ne obstaja")
+	}
+	return nil
+}
+
+// WebhookDeliveries vrne stran posiljanj narocnine, od najnovejsega naprej
+func (s *WebhookService) WebhookDeliveries(webhookID int, p biolog.Page) ([]biolog.WebhookDelivery, error) {
+	stmt := `SELECT * FROM webhook_delivery WHERE webhook = $1 ORDER BY id DESC LIMIT $2 OFFSET $3`
+	ds := []biolog.WebhookDelivery{}
+
+	if selErr := s.DB.Select(&ds, stmt, webhookID, p.Limit, p.Offset); selErr != nil {
+		return nil, selErr
+	}
+
+	return ds, nil
+}
+
+// ClaimDeliveries prevzame najvec limit posiljanj, katerih cas je potekel, in jim za lease odlozi naslednji
+// poskus. Ce dispecer pred zapisom izida odpove, se posiljanje po lease ponovi. Hkratni dispecerji
+// si zaradi SKIP LOCKED posiljanj ne delijo
+func (s *WebhookService) ClaimDeliveries(limit int, lease time.Duration) ([]biolog.WebhookDelivery, error) {
+	stmt := `UPDATE webhook_delivery SET next_attempt_at = now() + $2::float8 * interval '1 second'
+		FROM webhook
+		WHERE webhook.id = webhook_delivery.webhook AND webhook_delivery.id IN (
+			SELECT id FROM webhook_delivery
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING webhook_delivery.*, webhook.url, webhook.secret`
+	ds := []biolog.WebhookDelivery{}
+
+	if selErr := s.DB.Select(&ds, stmt, limit, lease.Seconds()); selErr != nil {
+		return nil, selErr
+	}
+
+	return ds, nil
+}
+
+// RecordAttempt zapise izid poskusa posiljanja in doloci novo stanje posiljanja
+func (s *WebhookService) RecordAttempt(id int, a biolog.WebhookAttempt) error {
+	stmt := `UPDATE webhook_delivery SET attempts = attempts + 1,
+			last_status_code = NULLIF($2, 0), last_error = NULLIF($3, ''),
+			status = CASE WHEN $3 = '' THEN 'delivered' WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = CASE WHEN $3 = '' THEN NULL ELSE $4::timestamptz END,
+			delivered_at = CASE WHEN $3 = '' THEN now() END
+		WHERE id = $1 AND status = 'pending'`
+	var next *time.Time
+	if a.Error != "" {
+		next = a.NextAttemptAt
+	}
+
+	res, err := s.DB.Exec(stmt, id, a.StatusCode, a.Error, next)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("Posiljanje s tem ID ne caka na posiljanje")
+	}
+	return nil
+}
+
+// enqueueWebhooks znotraj transakcije doda v vrsto dogodek za vse narocnine, katerih filtrom ustreza opazanje.
+// Dogodki se posljejo le za javna opazanja. Obmocja in pravokotnik narocnine se preverijo na lokaciji, posploseni
+// po pravilu policy, v telesu dogodka pa se shrani opazanje s posploseno lokacijo, da narocnik ne izve natancne
+func enqueueWebhooks(tx *sqlx.Tx, event string, ob *biolog.Observation, policy biolog.SensitivityPolicy) error {
+	var status string
+	statusStmt := `SELECT COALESCE(conservation_status.acronym, '') FROM species
+		LEFT JOIN conservation_status ON conservation_status.id = species.conservation_status
+		WHERE species.id = $1`
+	if getErr := tx.Get(&status, statusStmt, *ob.Species); getErr != nil && getErr != sql.ErrNoRows {
+		return getErr
+	}
+	sent := *ob
+	if policy.Sensitive(*ob.Species, status) {
+		sent.Obscure()
+	}
+	payload, err := json.Marshal(biolog.WebhookEvent{Event: event, CreatedAt: time.Now(), Observation: &sent})
+	if err != nil {
+		return err
+	}
+
+	location, args := observationLocation(biolog.ObservationFilter{Obscure: &policy}, []interface{}{*ob.ID, event, string(payload)})
+	stmt := `INSERT INTO webhook_delivery (webhook, event, payload)
+		SELECT webhook.id, $2, $3 FROM webhook, observation` + obscureJoins + `
+		WHERE observation.id = $1 AND observation.deleted_at IS NULL AND ` + publicObservation + `
+			AND (cardinality(webhook.species) = 0 OR observation.species = ANY(webhook.species))
+			AND (cardinality(webhook.regions) = 0 OR EXISTS (SELECT 1 FROM region
+				WHERE region.id = ANY(webhook.regions) AND ST_Intersects(region.geom, ` + location + `)))
+			AND (cardinality(webhook.bbox) = 0 OR ST_Intersects(` + location + `,
+				ST_MakeEnvelope(webhook.bbox[1], webhook.bbox[2], webhook.bbox[3], webhook.bbox[4], 4326)))`
+	_, err = tx.Exec(stmt, args...)
+	return err
+}
@@ -0,0 +1,100 @@
+package postgres_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/rubinda/biolog/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookDeliveries preveri dodajanje dogodkov v vrsto ob kreiranju in posodobitvi opazanja,
+// prevzem posiljanj in zapis poskusov
+func TestWebhookDeliveries(t *testing.T) {
+	ws := &postgres.WebhookService{DB: speciesServiceTest.DB}
+	url, secret, species := "https://example.org/hook", "skrivnost", 5231190
+	wh, err := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Species: []int{species},
+		BBox: []float64{13.3, 45.4, 16.6, 46.9}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.DeleteWebhook(*wh.ID)
+	assert.Equal(t, secret, *wh.Secret)
+	found, err := ws.Webhook(*wh.ID)
+	if assert.NoError(t, err) {
+		assert.Nil(t, found.Secret)
+		assert.Equal(t, []int{species}, found.Species)
+		assert.Len(t, found.BBox, 4)
+	}
+
+	// Javno opazanje v Ljubljani ustreza filtrom, zasebno se ne poslje
+	u, public, _ := createEraseTestUser(t, 30)
+	defer userServiceTest.EraseUser(*u.ID, false, biolog.Actor{User: *u.ID})
+	quantity := 5
+	assert.NoError(t, speciesServiceTest.UpdateObservation(*public.ID, biolog.Observation{Quantity: &quantity},
+		biolog.Actor{User: *u.ID}))
+	ds, err := ws.WebhookDeliveries(*wh.ID, biolog.Page{Limit: 10})
+	if !assert.NoError(t, err) || !assert.Len(t, ds, 2) {
+		return
+	}
+	assert.Equal(t, biolog.WebhookObservationUpdated, *ds[0].Event)
+	assert.Equal(t, biolog.WebhookObservationCreated, *ds[1].Event)
+
+	claimed, err := ws.ClaimDeliveries(10, time.Minute)
+	if assert.NoError(t, err) && assert.Len(t, claimed, 2) {
+		assert.Equal(t, url, claimed[0].URL)
+		assert.Equal(t, secret, claimed[0].Secret)
+	}
+	again, _ := ws.ClaimDeliveries(10, time.Minute)
+	assert.Len(t, again, 0)
+
+	next := time.Now().Add(time.Hour)
+	assert.NoError(t, ws.RecordAttempt(*ds[0].ID, biolog.WebhookAttempt{StatusCode: 200}))
+	assert.NoError(t, ws.RecordAttempt(*ds[1].ID, biolog.WebhookAttempt{StatusCode: 503, Error: "HTTP 503", NextAttemptAt: &next}))
+	assert.Error(t, ws.RecordAttempt(*ds[0].ID, biolog.WebhookAttempt{StatusCode: 200}))
+	ds, _ = ws.WebhookDeliveries(*wh.ID, biolog.Page{Limit: 10})
+	if assert.Len(t, ds, 2) {
+		assert.Equal(t, biolog.DeliveryDelivered, *ds[0].Status)
+		assert.NotNil(t, ds[0].DeliveredAt)
+		assert.Equal(t, biolog.DeliveryPending, *ds[1].Status)
+		assert.Equal(t, 503, *ds[1].LastStatusCode)
+		assert.Equal(t, 1, *ds[1].Attempts)
+	}
+}
+
+// TestWebhookSensitiveLocation preveri, da narocnina z majhnim pravokotnikom ne izda natancne lokacije
+// obcutljive vrste in da je lokacija v telesu dogodka posplosena
+func TestWebhookSensitiveLocation(t *testing.T) {
+	ws := &postgres.WebhookService{DB: speciesServiceTest.DB}
+	u, public, _ := createEraseTestUser(t, 33)
+	defer userServiceTest.EraseUser(*u.ID, false, biolog.Actor{User: *u.ID})
+	policy := biolog.DefaultSensitivityPolicy([]int{*public.Species})
+	ss := &postgres.SpeciesService{DB: speciesServiceTest.DB, Sensitivity: &policy}
+
+	url, secret := "https://example.org/hook", "skrivnost"
+	bySpecies, err := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, Species: []int{*public.Species}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.DeleteWebhook(*bySpecies.ID)
+	inBBox, err := ws.CreateWebhook(&biolog.Webhook{URL: &url, Secret: &secret, BBox: []float64{14.5048, 46.0559, 14.5068, 46.0579}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ws.DeleteWebhook(*inBBox.ID)
+
+	quantity := 2
+	assert.NoError(t, ss.UpdateObservation(*public.ID, biolog.Observation{Quantity: &quantity}, biolog.Actor{User: *u.ID}))
+	ds, _ := ws.WebhookDeliveries(*bySpecies.ID, biolog.Page{Limit: 10})
+	if assert.Len(t, ds, 1) {
+		var event biolog.WebhookEvent
+		if assert.NoError(t, json.Unmarshal(ds[0].Payload, &event)) {
+			assert.True(t, *event.Observation.Obscured)
+			assert.NotEqual(t, *public.SightingLocation, *event.Observation.SightingLocation)
+		}
+	}
+	ds, _ = ws.WebhookDeliveries(*inBBox.ID, biolog.Page{Limit: 10})
+	assert.Len(t, ds, 0)
+}
@@ -0,0 +1,169 @@
+package biolog
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Vloge clanov projekta
+const (
+	// ProjectRoleAdmin lahko ureja projekt in njegove clane
+	ProjectRoleAdmin = "admin"
+	// ProjectRoleMember je navaden clan projekta
+	ProjectRoleMember = "member"
+)
+
+// ValidProjectRole preveri ali je podan niz ena izmed vlog clanov projekta
+func ValidProjectRole(role string) bool {
+	return role == ProjectRoleAdmin || role == ProjectRoleMember
+}
+
+// Taksonomske stopnje, po katerih lahko projekt omeji skupino vrst
+const (
+	TaxonKingdom = "kingdom"
+	TaxonPhylum  = "phylum"
+	TaxonClass   = "class"
+	TaxonOrder   = "order"
+	TaxonFamily  = "family"
+	TaxonGenus   = "genus"
+)
+
+// ValidTaxonRank preveri ali je podan niz ena izmed taksonomskih stopenj
+func ValidTaxonRank(rank string) bool {
+	switch rank {
+	case TaxonKingdom, TaxonPhylum, TaxonClass, TaxonOrder, TaxonFamily, TaxonGenus:
+		return true
+	}
+	return false
+}
+
+// Taxon vrne ime taksona vrste na podani taksonomski stopnji (nil pri neznani stopnji)
+func (sp Species) Taxon(rank string) *string {
+	switch rank {
+	case TaxonKingdom:
+		return sp.Kingdom
+	case TaxonPhylum:
+		return sp.Phylum
+	case TaxonClass:
+		return sp.Class
+	case TaxonOrder:
+		return sp.Order
+	case TaxonFamily:
+		return sp.Family
+	case TaxonGenus:
+		return sp.Genus
+	}
+	return nil
+}
+
+// Project (projekt ali bioblitz)
+//
+// Projekt zdruzuje javna opazanja, ki ustrezajo njegovim pravilom: opazena so v casovnem obdobju projekta,
+// znotraj meje (ce je podana), pripadajo skupini vrst (ce je podana) in, ce je tako doloceno, so jih
+// vnesli clani projekta. Opazanja se projektu ne dodelijo rocno, ampak ob vsakem branju
+//
+// swagger:model project
+type Project struct {
+	// Identifikator projekta
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Ime projekta
+	//
+	// required: true
+	// max length: 128
+	// example: Bioblitz Ljubljansko barje 2018
+	Name *string `json:"name"`
+
+	// Opis projekta
+	// example: Popis vseh vrst na Ljubljanskem barju v enem vikendu
+	Description *string `json:"description"`
+
+	// Prvi dan projekta
+	//
+	// required: true
+	// swagger:strfmt date
+	// example: 2018-06-02T00:00:00Z
+	StartDate *time.Time `db:"start_date" json:"startDate"`
+
+	// Zadnji dan projekta (nil pri projektih brez konca, npr. atlasih)
+	// swagger:strfmt date
+	// example: 2018-06-03T00:00:00Z
+	EndDate *time.Time `db:"end_date" json:"endDate"`
+
+	// Taksonomska stopnja skupine vrst (kingdom, phylum, class, order, family ali genus)
+	// example: class
+	TaxonRank *string `db:"taxon_rank" json:"taxonRank"`
+
+	// Ime taksona na stopnji TaxonRank, npr. Aves pri razredu
+	// max length: 64
+	// example: Aves
+	TaxonName *string `db:"taxon_name" json:"taxonName"`
+
+	// Pove ali se stejejo le opazanja clanov projekta
+	// example: false
+	MembersOnly *bool `db:"members_only" json:"membersOnly"`
+
+	// Uporabnik, ki je ustvaril projekt (postane njegov administrator)
+	// example: 10000000
+	CreatedBy *int `db:"created_by" json:"createdBy"`
+
+	// Meja projekta kot GeoJSON Polygon ali MultiPolygon (WGS84), vrne se le pri posameznem projektu
+	Boundary json.RawMessage `db:"-" json:"boundary,omitempty"`
+}
+
+// Active pove ali je cas t znotraj obdobja projekta (zadnji dan se steje v celoti)
+func (p Project) Active(t time.Time) bool {
+	if p.StartDate == nil || t.Before(*p.StartDate) {
+		return false
+	}
+	return p.EndDate == nil || t.Before(p.EndDate.AddDate(0, 0, 1))
+}
+
+// ProjectMember (clan projekta)
+//
+// swagger:model projectMember
+type ProjectMember struct {
+	// Projekt
+	//
+	// required: true
+	// example: 1
+	Project *int `json:"project"`
+
+	// Uporabnik, ki je clan projekta
+	//
+	// required: true
+	// example: 10000000
+	User *int `db:"biolog_user" json:"user"`
+
+	// Vloga clana (admin ali member)
+	//
+	// required: true
+	// example: member
+	Role *string `json:"role"`
+}
+
+// LeaderboardEntry (uvrstitev uporabnika na lestvici projekta)
+//
+// swagger:model leaderboardEntry
+type LeaderboardEntry struct {
+	// Uporabnik
+	//
+	// required: true
+	// example: 10000000
+	User int `db:"biolog_user" json:"user"`
+
+	// Stevilo opazanj uporabnika v projektu
+	//
+	// required: true
+	// example: 42
+	Observations int `json:"observations"`
+
+	// Stevilo razlicnih vrst, ki jih je uporabnik opazil v projektu
+	//
+	// required: true
+	// example: 17
+	Species int `json:"species"`
+}
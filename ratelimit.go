@@ -0,0 +1,87 @@
+package biolog
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimiter nudi interface za omejevanje stevila zahtev po algoritmu vedra z zetoni (token bucket).
+// Vsaka zahteva porabi en zeton, vedro pa se enakomerno polni do svoje velikosti
+type RateLimiter interface {
+	// Take iz vedra key vzame en zeton, ce ga ima, in vrne stanje vedra po zahtevi
+	Take(key string, limit RateLimit) (RateLimitResult, error)
+	// Prune odstrani vedra, ki niso bila uporabljena od before (ta so ze polna)
+	Prune(before time.Time) (int, error)
+}
+
+// RateLimit doloca velikost vedra in hitrost polnjenja
+type RateLimit struct {
+	// Najvecje stevilo zaporednih zahtev (velikost vedra)
+	Burst int
+	// Cas, v katerem se prazno vedro povsem napolni
+	Period time.Duration
+}
+
+// Enabled pove ali je omejitev nastavljena
+func (l RateLimit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// Rate vrne hitrost polnjenja vedra v zetonih na sekundo
+func (l RateLimit) Rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Refill vrne stevilo zetonov v vedru, ki je pred elapsed imelo tokens zetonov
+func (l RateLimit) Refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * l.Rate()
+	}
+	return math.Min(tokens, float64(l.Burst))
+}
+
+// Result vrne izid zahteve, po kateri je v vedru ostalo tokens zetonov
+func (l RateLimit) Result(tokens float64, allowed bool) RateLimitResult {
+	r := RateLimitResult{Allowed: allowed, Limit: l.Burst, Remaining: int(math.Floor(tokens))}
+	r.Reset = time.Duration((float64(l.Burst) - tokens) / l.Rate() * float64(time.Second))
+	if !allowed {
+		r.RetryAfter = time.Duration((1 - tokens) / l.Rate() * float64(time.Second))
+	}
+	return r
+}
+
+// RateLimitResult je stanje vedra po zahtevi
+type RateLimitResult struct {
+	// Ali je zahteva dovoljena
+	Allowed bool
+	// Velikost vedra
+	Limit int
+	// Stevilo preostalih zahtev
+	Remaining int
+	// Cas, po katerem bo vedro spet polno
+	Reset time.Duration
+	// Cas do naslednjega zetona (le ce zahteva ni dovoljena)
+	RetryAfter time.Duration
+}
+
+// TokenBucket je vedro z zetoni za omejevalnike, ki stanje hranijo v pomnilniku
+type TokenBucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Take ob casu now napolni vedro in iz njega vzame zeton, ce ga ima. Novo vedro je polno
+func (b *TokenBucket) Take(limit RateLimit, now time.Time) RateLimitResult {
+	if b.Updated.IsZero() {
+		b.Tokens = float64(limit.Burst)
+	} else {
+		b.Tokens = limit.Refill(b.Tokens, now.Sub(b.Updated))
+	}
+	b.Updated = now
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+	return limit.Result(b.Tokens, allowed)
+}
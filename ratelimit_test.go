@@ -0,0 +1,42 @@
+package biolog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTokenBucket preveri porabo zetonov, zavrnitev praznega vedra in polnjenje s casom
+func TestTokenBucket(t *testing.T) {
+	limit := biolog.RateLimit{Burst: 3, Period: 3 * time.Second}
+	assert.True(t, limit.Enabled())
+	assert.False(t, biolog.RateLimit{Burst: 3}.Enabled())
+
+	var b biolog.TokenBucket
+	now := time.Now()
+	for i := 2; i >= 0; i-- {
+		res := b.Take(limit, now)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+	res := b.Take(limit, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+
+	// Po pol sekunde zeton se ni poln, po sekundi je
+	res = b.Take(limit, now.Add(500*time.Millisecond))
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	res = b.Take(limit, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+
+	// Vedro se ne napolni cez svojo velikost
+	res = b.Take(limit, now.Add(time.Hour))
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+}
@@ -0,0 +1,83 @@
+package biolog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RegionService nudi interface za delo z obmocji (obcine, statisticne regije ...)
+type RegionService interface {
+	Region(id int) (*Region, error)
+	Regions(kind string) ([]Region, error)
+	ImportRegions(regions []Region) (int, error)
+}
+
+// Region (obmocje)
+//
+// Poligon upravnega ali statisticnega obmocja, opazanja se obmocjem dodelijo glede na lokacijo
+//
+// swagger:model region
+type Region struct {
+	// Identifikator obmocja
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Vrsta obmocja (sloj, iz katerega je bilo uvozeno)
+	//
+	// required: true
+	// max length: 32
+	// example: obcina
+	Kind *string `json:"kind"`
+
+	// Koda obmocja iz vira (unikatna znotraj vrste)
+	//
+	// required: true
+	// max length: 32
+	// example: 61
+	Code *string `json:"code"`
+
+	// Ime obmocja
+	//
+	// required: true
+	// max length: 128
+	// example: Ljubljana
+	Name *string `json:"name"`
+
+	// Geometrija obmocja kot GeoJSON (WGS84), vrne se le pri posameznem obmocju
+	Geometry json.RawMessage `db:"-" json:"geometry,omitempty"`
+}
+
+// RegionsFromGeoJSON prebere obmocja iz GeoJSON FeatureCollection. Ime in koda obmocja se
+// vzameta iz lastnosti (properties) nameProperty in codeProperty vsakega elementa
+func RegionsFromGeoJSON(r io.Reader, kind, nameProperty, codeProperty string) ([]Region, error) {
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry   json.RawMessage
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, errors.New("GeoJSON mora biti FeatureCollection")
+	}
+
+	regions := make([]Region, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		name, nameOK := f.Properties[nameProperty]
+		code, codeOK := f.Properties[codeProperty]
+		if !nameOK || !codeOK || len(f.Geometry) == 0 {
+			return nil, fmt.Errorf("Element %d nima imena, kode ali geometrije", i)
+		}
+		k, n, c := kind, fmt.Sprint(name), fmt.Sprint(code)
+		regions = append(regions, Region{Kind: &k, Name: &n, Code: &c, Geometry: f.Geometry})
+	}
+
+	return regions, nil
+}
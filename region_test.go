@@ -0,0 +1,32 @@
+package biolog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegionsFromGeoJSON preveri branje obmocij iz GeoJSON FeatureCollection
+func TestRegionsFromGeoJSON(t *testing.T) {
+	geojson := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","properties":{"OB_UIME":"Ljubljana","OB_MID":61},
+		 "geometry":{"type":"Polygon","coordinates":[[[14.3,45.9],[14.8,45.9],[14.8,46.2],[14.3,45.9]]]}}]}`
+
+	regions, err := biolog.RegionsFromGeoJSON(strings.NewReader(geojson), "obcina", "OB_UIME", "OB_MID")
+	if assert.NoError(t, err) && assert.Len(t, regions, 1) {
+		assert.Equal(t, "obcina", *regions[0].Kind)
+		assert.Equal(t, "Ljubljana", *regions[0].Name)
+		assert.Equal(t, "61", *regions[0].Code)
+		assert.Contains(t, string(regions[0].Geometry), "Polygon")
+	}
+
+	// Manjkajoca lastnost
+	_, err = biolog.RegionsFromGeoJSON(strings.NewReader(geojson), "obcina", "name", "OB_MID")
+	assert.Error(t, err)
+
+	// Le FeatureCollection je podprt
+	_, err = biolog.RegionsFromGeoJSON(strings.NewReader(`{"type":"Feature"}`), "obcina", "name", "code")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package biolog
+
+import (
+	"time"
+)
+
+// ObservationRevision (revizija opazanja)
+//
+// Celotno stanje opazanja po eni izmed posodobitev. Revizija 1 je stanje pred prvo posodobitvijo,
+// vsaka nadaljnja posodobitev (tudi povrnitev) doda novo revizijo
+//
+// swagger:model observationRevision
+type ObservationRevision struct {
+	// Opazanje, kateremu pripada revizija
+	//
+	// required: true
+	// example: 1
+	Observation *int `json:"observation"`
+
+	// Zaporedna stevilka revizije znotraj opazanja
+	//
+	// required: true
+	// example: 2
+	Revision *int `json:"revision"`
+
+	// Uporabnik, ki je naredil to razlicico opazanja
+	// example: 10000000
+	User *int `db:"biolog_user" json:"user"`
+
+	// Stanje opazanja v tej reviziji
+	//
+	// required: true
+	Snapshot *Observation `db:"-" json:"snapshot"`
+
+	// Cas, ko je bila revizija shranjena
+	//
+	// swagger:strfmt date-time
+	// example: 2018-06-04T11:07:37+00:00
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,30 @@
+-- Identifikacije (predlogi vrst) za opazanja in izracunana vrsta skupnosti ter stopnja kakovosti.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/001_identification.sql
+
+BEGIN;
+
+CREATE TABLE public.identification (
+    id serial PRIMARY KEY,
+    observation integer NOT NULL REFERENCES public.observation(id) ON DELETE CASCADE,
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id),
+    species integer NOT NULL REFERENCES public.species(id),
+    comment character varying(255),
+    current boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX identification_observation_idx ON public.identification USING btree (observation);
+-- Vsak uporabnik ima za opazanje najvec en trenutni predlog
+CREATE UNIQUE INDEX identification_current_uindex ON public.identification USING btree (observation, biolog_user) WHERE current;
+
+ALTER TABLE public.observation
+    ADD COLUMN community_taxon integer REFERENCES public.species(id),
+    ADD COLUMN quality_grade character varying(16) DEFAULT 'needs_id' NOT NULL
+        CHECK (quality_grade IN ('casual', 'needs_id', 'research'));
+
+CREATE INDEX observation_quality_grade_idx ON public.observation USING btree (quality_grade);
+
+-- Zasebna opazanja skupnost ne more preveriti
+UPDATE public.observation SET quality_grade = 'casual' WHERE NOT public_visibility;
+
+COMMIT;
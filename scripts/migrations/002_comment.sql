@@ -0,0 +1,21 @@
+-- Vloge uporabnikov (moderatorji) in komentarji pod opazanji.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/002_comment.sql
+
+BEGIN;
+
+ALTER TABLE public.biolog_user
+    ADD COLUMN role character varying(16) DEFAULT 'user' NOT NULL
+        CHECK (role IN ('user', 'moderator', 'admin'));
+
+CREATE TABLE public.observation_comment (
+    id serial PRIMARY KEY,
+    observation integer NOT NULL REFERENCES public.observation(id) ON DELETE CASCADE,
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id),
+    body character varying(2000) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX observation_comment_observation_idx ON public.observation_comment USING btree (observation, created_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Prostorski indeks za vektorske ploscice z opazanji (poizvedbe v EPSG:3857, glej postgres/tile.go).
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/003_observation_tiles.sql
+
+BEGIN;
+
+CREATE INDEX observation_location_3857_idx ON public.observation
+    USING gist (ST_Transform(sighting_location::geometry, 3857));
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Obmocja (obcine, statisticne regije ...) in dodelitev opazanj obmocjem.
+-- Obmocja se uvozijo iz GeoJSON z ukazom: go run cmd/biolog/main.go --import-regions=obcine.geojson --region-kind=obcina
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/004_region.sql
+
+BEGIN;
+
+CREATE TABLE public.region (
+    id serial PRIMARY KEY,
+    kind character varying(32) NOT NULL,
+    code character varying(32) NOT NULL,
+    name character varying(128) NOT NULL,
+    geom geometry(MultiPolygon, 4326) NOT NULL,
+    UNIQUE (kind, code)
+);
+
+CREATE INDEX region_geom_idx ON public.region USING gist (geom);
+
+-- Obmocja, v katerih lezi opazanje (izracuna se ob vnosu in posodobitvi opazanja ter ob uvozu obmocij)
+CREATE TABLE public.observation_region (
+    observation integer NOT NULL REFERENCES public.observation(id) ON DELETE CASCADE,
+    region integer NOT NULL REFERENCES public.region(id) ON DELETE CASCADE,
+    PRIMARY KEY (observation, region)
+);
+
+CREATE INDEX observation_region_region_idx ON public.observation_region USING btree (region);
+
+COMMIT;
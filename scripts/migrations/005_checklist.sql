@@ -0,0 +1,25 @@
+-- Popisi, ki zdruzujejo opazanja z enega obiska terena.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/005_checklist.sql
+
+BEGIN;
+
+CREATE TABLE public.checklist (
+    id serial PRIMARY KEY,
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id),
+    start_time timestamp with time zone NOT NULL,
+    duration integer CHECK (duration >= 0),
+    location public.geography(Point, 4326) NOT NULL,
+    protocol character varying(16) NOT NULL
+        CHECK (protocol IN ('incidental', 'stationary', 'traveling')),
+    complete boolean DEFAULT false NOT NULL
+);
+
+CREATE INDEX checklist_biolog_user_idx ON public.checklist USING btree (biolog_user, start_time);
+
+-- Opazanja se zbrisejo skupaj s popisom
+ALTER TABLE public.observation
+    ADD COLUMN checklist integer REFERENCES public.checklist(id) ON DELETE CASCADE;
+
+CREATE INDEX observation_checklist_idx ON public.observation USING btree (checklist);
+
+COMMIT;
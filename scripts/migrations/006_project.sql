@@ -0,0 +1,33 @@
+-- Projekti (bioblitz, atlasi) in njihovi clani. Opazanja se projektom ne dodelijo vnaprej,
+-- ampak se pravila projekta (obdobje, meja, skupina vrst) preverijo ob vsaki poizvedbi.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/006_project.sql
+
+BEGIN;
+
+CREATE TABLE public.project (
+    id serial PRIMARY KEY,
+    name character varying(128) NOT NULL,
+    description text,
+    start_date date NOT NULL,
+    end_date date CHECK (end_date >= start_date),
+    taxon_rank character varying(16)
+        CHECK (taxon_rank IN ('kingdom', 'phylum', 'class', 'order', 'family', 'genus')),
+    taxon_name character varying(64),
+    members_only boolean DEFAULT false NOT NULL,
+    created_by integer NOT NULL REFERENCES public.biolog_user(id),
+    boundary geometry(MultiPolygon, 4326),
+    CHECK ((taxon_rank IS NULL) = (taxon_name IS NULL))
+);
+
+CREATE INDEX project_boundary_idx ON public.project USING gist (boundary);
+
+CREATE TABLE public.project_member (
+    project integer NOT NULL REFERENCES public.project(id) ON DELETE CASCADE,
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id) ON DELETE CASCADE,
+    role character varying(16) DEFAULT 'member' NOT NULL CHECK (role IN ('admin', 'member')),
+    PRIMARY KEY (project, biolog_user)
+);
+
+CREATE INDEX project_member_biolog_user_idx ON public.project_member USING btree (biolog_user);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Ocene ogrozenosti vrst po sistemih (IUCN, Rdeci seznam Slovenije ...) in obmocjih, skupaj z zgodovino.
+-- Stolpec species.conservation_status ostane kot globalni status zaradi zdruzljivosti API v1.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/007_assessment.sql
+
+BEGIN;
+
+CREATE TABLE public.assessment (
+    id serial PRIMARY KEY,
+    species integer NOT NULL REFERENCES public.species(id) ON DELETE CASCADE,
+    scheme character varying(32) NOT NULL,
+    region character varying(32) DEFAULT 'global' NOT NULL,
+    conservation_status integer NOT NULL REFERENCES public.conservation_status(id),
+    year integer NOT NULL,
+    source character varying(255),
+    UNIQUE (species, scheme, region, year)
+);
+
+COMMIT;
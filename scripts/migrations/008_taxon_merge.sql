@@ -0,0 +1,23 @@
+-- Sinonimi vrst in zapisi o zdruzitvah vrst (npr. ko GBIF vrsto prestevilci).
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/008_taxon_merge.sql
+
+BEGIN;
+
+-- Sprejeta vrsta, ce je vrsta sinonim (NULL pri sprejetih vrstah)
+ALTER TABLE public.species
+    ADD COLUMN accepted_key integer REFERENCES public.species(id);
+
+CREATE INDEX species_accepted_key_idx ON public.species USING btree (accepted_key);
+
+CREATE TABLE public.taxon_merge (
+    id serial PRIMARY KEY,
+    from_species integer NOT NULL REFERENCES public.species(id),
+    into_species integer NOT NULL REFERENCES public.species(id),
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id),
+    observations integer NOT NULL,
+    identifications integer NOT NULL,
+    merged_at timestamp with time zone DEFAULT now() NOT NULL,
+    CHECK (from_species <> into_species)
+);
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- Mehko brisanje opazanj in uporabnikov: zapisi se le oznacijo kot izbrisani in jih je mogoce obnoviti,
+-- po obdobju hrambe (retention.deleted-days) jih trajno odstrani opravilo za ciscenje.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/009_soft_delete.sql
+
+BEGIN;
+
+ALTER TABLE public.observation
+    ADD COLUMN deleted_at timestamp with time zone,
+    ADD COLUMN deleted_by integer REFERENCES public.biolog_user(id) ON DELETE SET NULL;
+
+ALTER TABLE public.biolog_user
+    ADD COLUMN deleted_at timestamp with time zone,
+    ADD COLUMN deleted_by integer REFERENCES public.biolog_user(id) ON DELETE SET NULL;
+
+-- Delni indeksi za opravilo za ciscenje (izbrisanih zapisov je malo)
+CREATE INDEX observation_deleted_at_idx ON public.observation USING btree (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX biolog_user_deleted_at_idx ON public.biolog_user USING btree (deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
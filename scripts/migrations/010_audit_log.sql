@@ -0,0 +1,31 @@
+-- Revizijska sled sprememb vrst, opazanj in uporabnikov. Zapisi se dodajo v isti transakciji kot sprememba
+-- in jih ni mogoce spreminjati ali brisati. Uporabnik nima tujega kljuca, da sled ostane tudi po odstranitvi racuna.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/010_audit_log.sql
+
+BEGIN;
+
+CREATE TABLE public.audit_log (
+    id bigserial PRIMARY KEY,
+    actor integer,
+    action character varying(16) NOT NULL,
+    entity_type character varying(16) NOT NULL,
+    entity_id integer NOT NULL,
+    changes jsonb NOT NULL,
+    request_id character varying(64),
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX audit_log_entity_idx ON public.audit_log USING btree (entity_type, entity_id);
+CREATE INDEX audit_log_actor_idx ON public.audit_log USING btree (actor);
+
+-- Sled je le za dodajanje
+CREATE FUNCTION public.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON public.audit_log
+    FOR EACH ROW EXECUTE PROCEDURE public.audit_log_append_only();
+
+COMMIT;
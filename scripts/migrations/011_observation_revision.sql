@@ -0,0 +1,15 @@
+-- Revizije opazanj: celotno stanje opazanja (JSON) po vsaki posodobitvi, revizija 1 je stanje pred prvo posodobitvijo.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/011_observation_revision.sql
+
+BEGIN;
+
+CREATE TABLE public.observation_revision (
+    observation integer NOT NULL REFERENCES public.observation(id) ON DELETE CASCADE,
+    revision integer NOT NULL,
+    biolog_user integer REFERENCES public.biolog_user(id) ON DELETE SET NULL,
+    data jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (observation, revision)
+);
+
+COMMIT;
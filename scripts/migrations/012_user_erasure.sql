@@ -0,0 +1,15 @@
+-- Trajni izbris uporabniskih racunov: osebni podatki se odstranijo, zapis ostane kot anonimen "izbrisan uporabnik",
+-- na katerega se sklicujejo ohranjena javna opazanja, identifikacije in komentarji.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/012_user_erasure.sql
+
+BEGIN;
+
+ALTER TABLE public.biolog_user
+    ADD COLUMN erased_at timestamp with time zone,
+    ALTER COLUMN external_id DROP NOT NULL,
+    ALTER COLUMN given_name DROP NOT NULL,
+    ALTER COLUMN family_name DROP NOT NULL,
+    ALTER COLUMN email DROP NOT NULL,
+    ALTER COLUMN external_auth_provider DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,34 @@
+-- Spremljanje uporabnikov in opazovanje vrst za vir dejavnosti ter cas shranjevanja opazanj.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/013_follow_feed.sql
+
+BEGIN;
+
+ALTER TABLE public.observation
+    ADD COLUMN created_at timestamp with time zone DEFAULT now() NOT NULL;
+
+-- Obstojecim opazanjem se kot cas shranjevanja privzame cas opazanja
+UPDATE public.observation SET created_at = sighting_time;
+
+CREATE TABLE public.user_follow (
+    follower integer NOT NULL REFERENCES public.biolog_user(id) ON DELETE CASCADE,
+    followee integer NOT NULL REFERENCES public.biolog_user(id) ON DELETE CASCADE,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (follower, followee),
+    CHECK (follower <> followee)
+);
+
+CREATE TABLE public.species_watch (
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id) ON DELETE CASCADE,
+    species integer NOT NULL REFERENCES public.species(id) ON DELETE CASCADE,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (biolog_user, species)
+);
+
+-- Vir dejavnosti bere najnovejse zapise posameznih uporabnikov in vrst
+CREATE INDEX observation_biolog_user_created_idx ON public.observation USING btree (biolog_user, created_at);
+CREATE INDEX observation_species_created_idx ON public.observation USING btree (species, created_at);
+CREATE INDEX identification_biolog_user_created_idx ON public.identification USING btree (biolog_user, created_at);
+CREATE INDEX identification_species_created_idx ON public.identification USING btree (species, created_at);
+CREATE INDEX observation_comment_biolog_user_created_idx ON public.observation_comment USING btree (biolog_user, created_at);
+
+COMMIT;
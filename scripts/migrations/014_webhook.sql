@@ -0,0 +1,37 @@
+-- Narocnine partnerskih organizacij na dogodke (webhooki) in trajna vrsta posiljanj z dnevnikom poskusov.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/014_webhook.sql
+
+BEGIN;
+
+CREATE TABLE public.webhook (
+    id serial PRIMARY KEY,
+    url text NOT NULL,
+    secret text NOT NULL,
+    description text,
+    -- Prazen filter ustreza vsem opazanjem
+    species integer[] DEFAULT '{}' NOT NULL,
+    regions integer[] DEFAULT '{}' NOT NULL,
+    bbox double precision[] DEFAULT '{}' NOT NULL CHECK (cardinality(bbox) IN (0, 4)),
+    created_by integer REFERENCES public.biolog_user(id) ON DELETE SET NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE public.webhook_delivery (
+    id serial PRIMARY KEY,
+    webhook integer NOT NULL REFERENCES public.webhook(id) ON DELETE CASCADE,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    status text DEFAULT 'pending' NOT NULL CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp with time zone DEFAULT now(),
+    last_status_code integer,
+    last_error text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    delivered_at timestamp with time zone
+);
+
+-- Dispecer bere le posiljanja, ki cakajo
+CREATE INDEX webhook_delivery_pending_idx ON public.webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_delivery_webhook_idx ON public.webhook_delivery (webhook, id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Skupni stevci omejevanja zahtev (token bucket), da omejitve veljajo cez vec instanc streznika.
+-- Tabela ni zurnalirana, saj se stevci po izpadu baze lahko izgubijo.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/015_rate_limit.sql
+
+BEGIN;
+
+CREATE UNLOGGED TABLE public.rate_limit (
+    key text PRIMARY KEY,
+    -- Stevilo zetonov v vedru ob zadnji zahtevi
+    tokens double precision NOT NULL,
+    -- Ali je bila zadnja zahteva dovoljena
+    allowed boolean NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX rate_limit_updated_at_idx ON public.rate_limit (updated_at);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Brisanje popisa opazanja na njem izbrise mehko (glej DeleteChecklist), zato jih baza ne sme zbrisati
+-- skupaj s popisom. Izbrisana opazanja ostanejo do trajne odstranitve brez popisa.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/016_checklist_soft_delete.sql
+
+BEGIN;
+
+ALTER TABLE public.observation
+    DROP CONSTRAINT observation_checklist_fkey,
+    ADD CONSTRAINT observation_checklist_fkey FOREIGN KEY (checklist)
+        REFERENCES public.checklist(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Stanje izvozov osebnih podatkov, da je izvoz viden vsem instancam streznika in da se po
+-- poteku odstrani tudi, ce se instanca, ki ga je zacela, medtem ustavi.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/017_personal_export.sql
+
+BEGIN;
+
+CREATE TABLE public.personal_export (
+    -- Nakljucen identifikator, je tudi del javne povezave za prenos
+    id text PRIMARY KEY,
+    biolog_user integer NOT NULL REFERENCES public.biolog_user(id) ON DELETE CASCADE,
+    status character varying(16) NOT NULL CHECK (status IN ('pending', 'ready', 'failed')),
+    -- Pot do zip datoteke (v mapi export.dir), prazna dokler izvoz ni pripravljen
+    path text DEFAULT '' NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX personal_export_expires_at_idx ON public.personal_export (expires_at);
+
+COMMIT;
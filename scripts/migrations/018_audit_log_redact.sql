@@ -0,0 +1,20 @@
+-- Pri trajnem izbrisu uporabnika (glej EraseUser) se osebni podatki zakrijejo tudi v starejsih zapisih revizijske
+-- sledi. Sprememba je dovoljena le v transakciji z nastavitvijo biolog.audit_redact in le za polje changes,
+-- brisanje zapisov ostane prepovedano.
+-- Uporaba: psql -U biolog -d biolog -f scripts/migrations/018_audit_log_redact.sql
+
+BEGIN;
+
+CREATE OR REPLACE FUNCTION public.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND current_setting('biolog.audit_redact', true) = 'on'
+        AND NEW.id = OLD.id AND NEW.actor IS NOT DISTINCT FROM OLD.actor AND NEW.action = OLD.action
+        AND NEW.entity_type = OLD.entity_type AND NEW.entity_id = OLD.entity_id
+        AND NEW.request_id IS NOT DISTINCT FROM OLD.request_id AND NEW.created_at = OLD.created_at THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
@@ -0,0 +1,75 @@
+package biolog
+
+import (
+	"math"
+)
+
+// ObscureCellSize je velikost celice evropske referencne mreze (v metrih), v katere sredisce se
+// premakne javna lokacija opazanja obcutljive vrste
+const ObscureCellSize = 10000
+
+// SensitiveStatuses so kratice statusov ogrozenosti, pri katerih so vrste privzeto obcutljive
+var SensitiveStatuses = []string{"CR", "EN", "VU"}
+
+// SensitivityPolicy doloca obcutljive vrste, katerih natancne lokacije ne objavljamo. Lokacije teh
+// vrst se drugim uporabnikom posplosijo, natancne ostanejo vidne le avtorju opazanja in moderatorjem
+type SensitivityPolicy struct {
+	// Kratice statusov ogrozenosti (npr. CR), pri katerih je vrsta obcutljiva
+	Statuses []string
+	// Dodatne obcutljive vrste (GBIF kljuci) ne glede na status ogrozenosti
+	Species []int
+}
+
+// DefaultSensitivityPolicy vrne pravilo s privzetimi statusi in podanim seznamom dodatnih vrst
+func DefaultSensitivityPolicy(species []int) SensitivityPolicy {
+	return SensitivityPolicy{Statuses: SensitiveStatuses, Species: species}
+}
+
+// Sensitive pove ali je vrsta s podanim GBIF kljucem in kratico statusa ogrozenosti obcutljiva
+func (p SensitivityPolicy) Sensitive(species int, status string) bool {
+	for _, s := range p.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	for _, sp := range p.Species {
+		if sp == species {
+			return true
+		}
+	}
+	return false
+}
+
+// ObscureLocation premakne lokacijo v sredisce celice evropske referencne mreze velikosti ObscureCellSize.
+// Posplosena lokacija je zapisana v enaki obliki kot podana (WKT ali EWKB), ce lokacije ni mogoce
+// prebrati, vrne false
+func ObscureLocation(location string) (string, bool) {
+	lon, lat, ok := ParsePoint(location)
+	if !ok {
+		return "", false
+	}
+	x, y := ToLAEA(lon, lat)
+	x = math.Floor(x/ObscureCellSize)*ObscureCellSize + ObscureCellSize/2
+	y = math.Floor(y/ObscureCellSize)*ObscureCellSize + ObscureCellSize/2
+	lon, lat = FromLAEA(x, y)
+	return formatPoint(location, round(lon), round(lat)), true
+}
+
+// round zaokrozi koordinato na 6 decimalk (priblizno 10 cm), kar zadostuje za sredisce celice
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// Obscure posplosi lokacijo opazanja in ga oznaci kot posplosenega. Ce lokacije ni mogoce prebrati,
+// se odstrani, da natancna lokacija ne bi bila objavljena
+func (o *Observation) Obscure() {
+	if o.SightingLocation != nil {
+		if loc, ok := ObscureLocation(*o.SightingLocation); ok {
+			o.SightingLocation = &loc
+		} else {
+			o.SightingLocation = nil
+		}
+	}
+	obscured := true
+	o.Obscured = &obscured
+}
@@ -0,0 +1,61 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSensitivityPolicy preveri dolocanje obcutljivih vrst
+func TestSensitivityPolicy(t *testing.T) {
+	p := biolog.DefaultSensitivityPolicy([]int{2480537})
+	assert.True(t, p.Sensitive(5231190, "CR"))
+	assert.True(t, p.Sensitive(5231190, "VU"))
+	assert.False(t, p.Sensitive(5231190, "LC"))
+	assert.False(t, p.Sensitive(5231190, ""))
+	// Vrsta s seznama je obcutljiva ne glede na status
+	assert.True(t, p.Sensitive(2480537, "LC"))
+}
+
+// TestObscureLocation preveri premik lokacije v sredisce 10 km celice
+func TestObscureLocation(t *testing.T) {
+	// Dve tocki v Ljubljani lezita v isti celici (10kmE466N255)
+	a, ok := biolog.ObscureLocation("POINT(14.5058 46.0569)")
+	assert.True(t, ok)
+	b, _ := biolog.ObscureLocation("point(14.506 46.057)")
+	assert.Equal(t, a, b)
+
+	lon, lat, ok := biolog.ParsePoint(a)
+	if assert.True(t, ok) {
+		x, y := biolog.ToLAEA(lon, lat)
+		assert.InDelta(t, 4665000, x, 1)
+		assert.InDelta(t, 2555000, y, 1)
+	}
+
+	// EWKB, kot ga vrne PostGIS, ostane EWKB
+	ewkb := "0101000020E6100000508D976E12032D409EEFA7C64B074740"
+	lon, lat, ok = biolog.ParsePoint(ewkb)
+	if assert.True(t, ok) {
+		assert.InDelta(t, 14.506, lon, 1e-9)
+		assert.InDelta(t, 46.057, lat, 1e-9)
+	}
+	obscured, ok := biolog.ObscureLocation(ewkb)
+	if assert.True(t, ok) {
+		assert.Len(t, obscured, len(ewkb))
+		assert.Equal(t, ewkb[:18], obscured[:18])
+		lon, lat, _ = biolog.ParsePoint(obscured)
+		olon, olat, _ := biolog.ParsePoint(a)
+		assert.Equal(t, []float64{olon, olat}, []float64{lon, lat})
+	}
+
+	_, ok = biolog.ObscureLocation("46.33061, 15.48705")
+	assert.False(t, ok)
+
+	// Neberljiva lokacija se pri opazanju odstrani
+	loc := "46.33061, 15.48705"
+	ob := biolog.Observation{SightingLocation: &loc}
+	ob.Obscure()
+	assert.Nil(t, ob.SightingLocation)
+	assert.True(t, *ob.Obscured)
+}
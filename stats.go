@@ -0,0 +1,52 @@
+package biolog
+
+// Nacini zdruzevanja opazanj pri statistiki
+const (
+	// StatsBySpecies zdruzi opazanja po vrsti (kljuc je GBIF kljuc vrste)
+	StatsBySpecies = "species"
+	// StatsByMonth zdruzi opazanja po mesecu opazanja (kljuc je oblike 2018-06)
+	StatsByMonth = "month"
+	// StatsByYear zdruzi opazanja po letu opazanja (kljuc je oblike 2018)
+	StatsByYear = "year"
+	// StatsByUser zdruzi opazanja po uporabniku (kljuc je ID uporabnika)
+	StatsByUser = "user"
+	// StatsByConservationStatus zdruzi opazanja po statusu ogrozenosti vrste (kljuc je kratica statusa)
+	StatsByConservationStatus = "conservationStatus"
+	// StatsByRegion zdruzi opazanja po obmocju (kljuc je ID obmocja). Opazanje lezi v vec obmocjih
+	// razlicnih vrst (obcina, statisticna regija ...), zato se steje pri vsakem izmed njih
+	StatsByRegion = "region"
+)
+
+// ValidStatsGroup preveri ali je podan niz eden izmed nacinov zdruzevanja
+func ValidStatsGroup(groupBy string) bool {
+	switch groupBy {
+	case StatsBySpecies, StatsByMonth, StatsByYear, StatsByUser, StatsByConservationStatus, StatsByRegion:
+		return true
+	}
+	return false
+}
+
+// ObservationStat (statistika za skupino opazanj)
+//
+// Stevilo opazanj in sestevek opazenih osebkov za eno skupino (npr. eno vrsto ali en mesec)
+//
+// swagger:model observationStat
+type ObservationStat struct {
+	// Kljuc skupine, odvisen od nacina zdruzevanja
+	//
+	// required: true
+	// example: 2018-06
+	Key string `json:"key"`
+
+	// Stevilo opazanj v skupini
+	//
+	// required: true
+	// example: 12
+	Observations int `json:"observations"`
+
+	// Sestevek kolicine opazenih osebkov v skupini
+	//
+	// required: true
+	// example: 57
+	Quantity int `json:"quantity"`
+}
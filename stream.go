@@ -0,0 +1,57 @@
+package biolog
+
+import "sync"
+
+// Velikost medpomnilnika posameznega narocnika na tok opazanj
+const streamBuffer = 64
+
+// ObservationStream nudi interface za sprotno spremljanje novih javnih opazanj
+type ObservationStream interface {
+	// Subscribe vrne kanal, na katerega prihajajo nova javna opazanja, in funkcijo za odjavo.
+	// Kanal se zapre, ce narocnik opazanj ne bere dovolj hitro
+	Subscribe() (<-chan Observation, func())
+}
+
+// ObservationHub razposlje nova opazanja vsem narocnikom znotraj ene instance streznika.
+// Pocasnemu narocniku, katerega medpomnilnik je poln, se kanal zapre, da ne zadrzuje ostalih
+// (odjemalec se ponovno poveze in manjkajoca opazanja dobi z Last-Event-ID)
+type ObservationHub struct {
+	mu   sync.Mutex
+	subs map[chan Observation]bool
+}
+
+// NewObservationHub ustvari razposiljalnik brez narocnikov
+func NewObservationHub() *ObservationHub {
+	return &ObservationHub{subs: make(map[chan Observation]bool)}
+}
+
+// Subscribe doda narocnika in vrne njegov kanal ter funkcijo za odjavo
+func (h *ObservationHub) Subscribe() (<-chan Observation, func()) {
+	ch := make(chan Observation, streamBuffer)
+	h.mu.Lock()
+	h.subs[ch] = true
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.subs[ch] {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Publish poslje opazanje vsem narocnikom, ne da bi cakal na pocasne
+func (h *ObservationHub) Publish(o Observation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- o:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
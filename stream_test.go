@@ -0,0 +1,45 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObservationHub preveri razposiljanje opazanj, zaprtje kanala pocasnemu narocniku in odjavo
+func TestObservationHub(t *testing.T) {
+	hub := biolog.NewObservationHub()
+	fast, unsubscribeFast := hub.Subscribe()
+	slow, unsubscribeSlow := hub.Subscribe()
+	defer unsubscribeSlow()
+
+	for i := 1; i <= 100; i++ {
+		id := i
+		hub.Publish(biolog.Observation{ID: &id})
+		o := <-fast
+		assert.Equal(t, i, *o.ID)
+	}
+
+	// Pocasni narocnik dobi le opazanja, ki so sla v medpomnilnik, nato se mu kanal zapre
+	n := 0
+	for range slow {
+		n++
+	}
+	assert.Equal(t, 64, n)
+
+	unsubscribeFast()
+	_, open := <-fast
+	assert.False(t, open)
+	// Ponovna odjava in objava brez narocnikov ne povzrocita napake
+	unsubscribeFast()
+	hub.Publish(biolog.Observation{})
+}
+
+// TestInBBox preveri filter pravokotnika, prazen pravokotnik ustreza vsem tockam
+func TestInBBox(t *testing.T) {
+	assert.True(t, biolog.InBBox(nil, 14.5, 46.05))
+	slovenia := []float64{13.3, 45.4, 16.6, 46.9}
+	assert.True(t, biolog.InBBox(slovenia, 14.5, 46.05))
+	assert.False(t, biolog.InBBox(slovenia, 12.5, 41.9))
+}
@@ -0,0 +1,42 @@
+package biolog
+
+import (
+	"math"
+)
+
+// Nastavitve vektorskih ploscic (Mapbox Vector Tile)
+const (
+	// TileLayer je ime sloja z opazanji v vektorski ploscici
+	TileLayer = "observations"
+	// TileExtent je velikost ploscice v koordinatah ploscice
+	TileExtent = 4096
+	// MaxTileZoom je najvecja podprta stopnja povecave
+	MaxTileZoom = 22
+)
+
+// webMercatorBound je polovica sirine sveta v projekciji Web Mercator (EPSG:3857) v metrih
+const webMercatorBound = 20037508.342789244
+
+// ValidTile preveri ali ploscica z/x/y obstaja
+func ValidTile(z, x, y int) bool {
+	if z < 0 || z > MaxTileZoom {
+		return false
+	}
+	n := 1 << uint(z)
+	return x >= 0 && x < n && y >= 0 && y < n
+}
+
+// TileBounds vrne obseg ploscice z/x/y v projekciji Web Mercator (EPSG:3857), os y ploscic gre od severa proti jugu
+func TileBounds(z, x, y int) (minX, minY, maxX, maxY float64) {
+	size := 2 * webMercatorBound / float64(int(1)<<uint(z))
+	minX = -webMercatorBound + float64(x)*size
+	maxY = webMercatorBound - float64(y)*size
+	return minX, maxY - size, minX + size, maxY
+}
+
+// WebMercator pretvori WGS84 koordinate (v stopinjah) v EPSG:3857 (v metrih)
+func WebMercator(lon, lat float64) (float64, float64) {
+	x := lon * webMercatorBound / 180
+	y := math.Log(math.Tan((90+lat)*math.Pi/360)) * webMercatorBound / math.Pi
+	return x, y
+}
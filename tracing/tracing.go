@@ -0,0 +1,96 @@
+// Package tracing nastavi OpenTelemetry sledenje. Spani zahtev (glej http), odhodnih klicev (Transport)
+// in poizvedb (postgres) se izvozijo preko OTLP ali na standardni izhod
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ime instrumentacije, pod katerim nastanejo spani aplikacije
+const instrumentationName = "github.com/rubinda/biolog"
+
+// Izvozniki spanov (konfiguracija tracing.exporter)
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config doloca izvoz spanov
+type Config struct {
+	// Izvoznik: otlp, stdout ali none (sledenje je izklopljeno)
+	Exporter string
+	// Naslov OTLP/HTTP zbiralnika (npr. http://localhost:4318), prazen pomeni OTEL_EXPORTER_OTLP_* oz. privzeti naslov
+	Endpoint string
+	// Ime storitve v spanih
+	ServiceName string
+	// Delez zahtev, ki se sledijo (0 do 1), ce zahteva ne prinese odlocitve starsa (traceparent)
+	SampleRatio float64
+}
+
+// Setup nastavi globalnega ponudnika spanov in razsirjanje konteksta (W3C traceparent).
+// Vrnjena funkcija izvozi preostale spane in ustavi ponudnika
+func Setup(ctx context.Context, c Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exp sdktrace.SpanExporter
+	var err error
+	switch c.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if c.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(c.Endpoint))
+		}
+		exp, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, errors.New("Neznan izvoznik spanov: " + c.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(c.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer vrne sledilnik aplikacije iz globalnega ponudnika (brez Setup spani ne nastanejo)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Transport ovije base (nil pomeni http.DefaultTransport), da vsak odhodni klic ustvari span odjemalca
+// kot otroka spana v kontekstu zahteve in streznku poslje traceparent
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Host + r.URL.Path
+	}))
+}
@@ -0,0 +1,56 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rubinda/biolog/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestSetup preveri izbiro izvoznika
+func TestSetup(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = tracing.Setup(context.Background(), tracing.Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
+
+// TestTransport preveri, da je odhodni klic otrok spana v kontekstu zahteve in da streznik prejme traceparent
+func TestTransport(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "login")
+	req, err := http.NewRequest("GET", srv.URL+"/oauth2/v3/certs", nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: tracing.Transport(nil)}
+	resp, err := client.Do(req.WithContext(ctx))
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	require.Len(t, spans.GetSpans(), 2)
+	call := spans.GetSpans()[0]
+	assert.Equal(t, "GET "+req.URL.Host+"/oauth2/v3/certs", call.Name)
+	assert.Equal(t, trace.SpanKindClient, call.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), call.Parent.SpanID())
+	assert.Contains(t, traceparent, call.SpanContext.SpanID().String())
+}
@@ -0,0 +1,187 @@
+package biolog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// WebhookService nudi interface za narocnine partnerskih organizacij na dogodke (webhooke) in za vrsto
+// posiljanj. Posiljanja dodajata CreateObservation in UpdateObservation v isti transakciji kot spremembo
+type WebhookService interface {
+	Webhook(id int) (*Webhook, error)
+	Webhooks() ([]Webhook, error)
+	CreateWebhook(wh *Webhook) (*Webhook, error)
+	DeleteWebhook(id int) error
+	WebhookDeliveries(webhookID int, p Page) ([]WebhookDelivery, error)
+	ClaimDeliveries(limit int, lease time.Duration) ([]WebhookDelivery, error)
+	RecordAttempt(id int, a WebhookAttempt) error
+}
+
+// Dogodki, ki se posljejo narocnikom
+const (
+	WebhookObservationCreated = "observation.created"
+	WebhookObservationUpdated = "observation.updated"
+)
+
+// Stanja posiljanja dogodka
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook (narocnina na dogodke)
+//
+// Naslov partnerske organizacije, na katerega se posiljajo nova in posodobljena javna opazanja, ki ustrezajo
+// vsem podanim filtrom (prazen filter ustreza vsem opazanjem)
+//
+// swagger:model webhook
+type Webhook struct {
+	// Identifikator narocnine
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Naslov, na katerega se posljejo dogodki (POST z JSON telesom)
+	//
+	// required: true
+	// example: https://example.org/biolog/webhook
+	URL *string `json:"url"`
+
+	// Skrivnost za HMAC-SHA256 podpis telesa v glavi X-Biolog-Signature, vrne se le ob kreiranju
+	Secret *string `json:"secret,omitempty"`
+
+	// Opis narocnine (npr. ime partnerske organizacije)
+	// example: Drustvo za opazovanje ptic
+	Description *string `json:"description"`
+
+	// GBIF kljuci vrst, katerih opazanja se posljejo
+	// example: [5231190]
+	Species []int `json:"species"`
+
+	// Obmocja, v katerih morajo lezati opazanja
+	// example: [12]
+	Regions []int `json:"regions"`
+
+	// Pravokotnik [minLon, minLat, maxLon, maxLat], v katerem morajo lezati opazanja
+	// example: [13.3, 45.4, 16.6, 46.9]
+	BBox []float64 `json:"bbox"`
+
+	// Administrator, ki je ustvaril narocnino
+	CreatedBy *int `db:"created_by" json:"createdBy"`
+
+	// Cas kreiranja narocnine
+	// swagger:strfmt date-time
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+}
+
+// InBBox pove ali tocka lezi v pravokotniku narocnine (brez pravokotnika vedno)
+func (wh Webhook) InBBox(lon, lat float64) bool {
+	return InBBox(wh.BBox, lon, lat)
+}
+
+// WebhookEvent je telo zahteve, ki se poslje narocniku
+//
+// swagger:model webhookEvent
+type WebhookEvent struct {
+	// Dogodek (observation.created ali observation.updated)
+	//
+	// required: true
+	// example: observation.created
+	Event string `json:"event"`
+
+	// Cas dogodka
+	//
+	// required: true
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Opazanje po spremembi (lokacije obcutljivih vrst so posplosene)
+	//
+	// required: true
+	Observation *Observation `json:"observation"`
+}
+
+// WebhookDelivery (posiljanje dogodka)
+//
+// Dogodek v vrsti za posiljanje narocniku skupaj z izidom zadnjega poskusa (dnevnik posiljanj)
+//
+// swagger:model webhookDelivery
+type WebhookDelivery struct {
+	// Identifikator posiljanja (poslje se v glavi X-Biolog-Delivery)
+	//
+	// required: true
+	// example: 1
+	ID *int `json:"id"`
+
+	// Narocnina
+	//
+	// required: true
+	// example: 1
+	Webhook *int `json:"webhook"`
+
+	// Dogodek (observation.created ali observation.updated)
+	//
+	// required: true
+	// example: observation.created
+	Event *string `json:"event"`
+
+	// Telo dogodka (WebhookEvent)
+	Payload json.RawMessage `json:"payload"`
+
+	// Stanje (pending, delivered ali failed)
+	//
+	// required: true
+	// example: pending
+	Status *string `json:"status"`
+
+	// Stevilo opravljenih poskusov
+	// example: 2
+	Attempts *int `json:"attempts"`
+
+	// Cas naslednjega poskusa (le v stanju pending)
+	// swagger:strfmt date-time
+	NextAttemptAt *time.Time `db:"next_attempt_at" json:"nextAttemptAt"`
+
+	// HTTP status odgovora pri zadnjem poskusu
+	// example: 503
+	LastStatusCode *int `db:"last_status_code" json:"lastStatusCode"`
+
+	// Napaka pri zadnjem poskusu
+	// example: Narocnik je vrnil HTTP 503
+	LastError *string `db:"last_error" json:"lastError"`
+
+	// Cas dodajanja v vrsto
+	// swagger:strfmt date-time
+	CreatedAt *time.Time `db:"created_at" json:"createdAt"`
+
+	// Cas uspesnega posiljanja
+	// swagger:strfmt date-time
+	DeliveredAt *time.Time `db:"delivered_at" json:"deliveredAt"`
+
+	// Naslov in skrivnost narocnine, le za posiljanje
+	URL    string `db:"url" json:"-"`
+	Secret string `db:"secret" json:"-"`
+}
+
+// WebhookAttempt je izid poskusa posiljanja. Prazna napaka pomeni uspesno posiljanje,
+// ob napaki brez naslednjega poskusa posiljanje ni uspelo dokoncno
+type WebhookAttempt struct {
+	// HTTP status odgovora (0, ce odgovora ni bilo)
+	StatusCode int
+	// Napaka pri posiljanju
+	Error string
+	// Cas naslednjega poskusa
+	NextAttemptAt *time.Time
+}
+
+// SignWebhook vrne podpis telesa zahteve za glavo X-Biolog-Signature v obliki sha256=<hex HMAC-SHA256>
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
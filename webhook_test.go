@@ -0,0 +1,23 @@
+package biolog_test
+
+import (
+	"testing"
+
+	"github.com/rubinda/biolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignWebhook preveri podpis telesa dogodka z znanim HMAC-SHA256 primerom
+func TestSignWebhook(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		biolog.SignWebhook("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+// TestWebhookInBBox preveri filter pravokotnika narocnine
+func TestWebhookInBBox(t *testing.T) {
+	assert.True(t, biolog.Webhook{}.InBBox(14.5, 46.05))
+	slovenia := biolog.Webhook{BBox: []float64{13.3, 45.4, 16.6, 46.9}}
+	assert.True(t, slovenia.InBBox(14.5, 46.05))
+	assert.True(t, slovenia.InBBox(13.3, 45.4))
+	assert.False(t, slovenia.InBBox(12.5, 41.9))
+}